@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"transaction-service/config"
+	infradb "transaction-service/infra/db"
+	"transaction-service/infra/repository"
+	"transaction-service/internal/core/usecase"
+)
+
+func runBackfillKnownUsers(ctx context.Context, logger *slog.Logger, cfg *config.Config, args []string) error {
+	fs := newFlagSet("backfill-known-users")
+	batchSize := fs.Int("batch", 500, "events read per page from the users-service outbox")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := infradb.Connect(
+		cfg.Database.Host, cfg.Database.Port,
+		cfg.Database.User, cfg.Database.Password, cfg.Database.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	usersDB, err := infradb.Connect(
+		cfg.UsersDatabase.Host, cfg.UsersDatabase.Port,
+		cfg.UsersDatabase.User, cfg.UsersDatabase.Password, cfg.UsersDatabase.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("connect users database: %w", err)
+	}
+	defer usersDB.Close()
+
+	backfill := usecase.NewBackfillKnownUsersUseCase(
+		repository.NewUserEventSource(usersDB),
+		repository.NewKnownUserRepository(db),
+		logger,
+	)
+
+	_, err = backfill.Execute(ctx, usecase.BackfillInput{BatchSize: *batchSize})
+	return err
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"transaction-service/config"
+	infradb "transaction-service/infra/db"
+	"transaction-service/infra/repository"
+	"transaction-service/internal/core/usecase"
+)
+
+func runRebuildBalances(ctx context.Context, logger *slog.Logger, cfg *config.Config, args []string) error {
+	fs := newFlagSet("rebuild-balances")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := infradb.Connect(
+		cfg.Database.Host, cfg.Database.Port,
+		cfg.Database.User, cfg.Database.Password, cfg.Database.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	rebuild := usecase.NewRebuildBalancesUseCase(repository.NewLedgerRepository(db), logger)
+	_, err = rebuild.Execute(ctx)
+	return err
+}
+
+// runBalanceCheck prints the drift report as JSON on stdout and fails when
+// the projection disagrees with the postings.
+func runBalanceCheck(ctx context.Context, logger *slog.Logger, cfg *config.Config, args []string) error {
+	fs := newFlagSet("balance-check")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := infradb.Connect(
+		cfg.Database.Host, cfg.Database.Port,
+		cfg.Database.User, cfg.Database.Password, cfg.Database.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	check := usecase.NewCheckBalancesUseCase(repository.NewLedgerRepository(db), logger)
+	out, err := check.Execute(ctx)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(map[string]any{"drifts": out.Drifts}); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+
+	if len(out.Drifts) > 0 {
+		return fmt.Errorf("balances projection drifted for %d users; run rebuild-balances", len(out.Drifts))
+	}
+	return nil
+}
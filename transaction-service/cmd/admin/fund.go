@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"transaction-service/config"
+	infradb "transaction-service/infra/db"
+	"transaction-service/infra/repository"
+	"transaction-service/internal/core/usecase"
+)
+
+func runFund(ctx context.Context, logger *slog.Logger, cfg *config.Config, args []string) error {
+	fs := newFlagSet("fund")
+	userID := fs.String("user", "", "ID of the user whose account is credited")
+	amount := fs.Int64("amount", 0, "amount to deposit, in minor units of -currency")
+	currency := fs.String("currency", "", "ISO 4217 currency; defaults to USD")
+	description := fs.String("description", "", "description stored on the deposit")
+	operator := fs.String("operator", "", "who is paying the deposit in, recorded in its status history")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := infradb.Connect(
+		cfg.Database.Host, cfg.Database.Port,
+		cfg.Database.User, cfg.Database.Password, cfg.Database.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	fund := usecase.NewFundAccountUseCase(
+		repository.NewTransactionRepository(db),
+		repository.NewKnownUserRepository(db),
+		logger,
+	)
+	_, err = fund.Execute(ctx, usecase.FundInput{
+		UserID:      *userID,
+		Amount:      *amount,
+		Currency:    *currency,
+		Description: *description,
+		Operator:    *operator,
+	})
+	return err
+}
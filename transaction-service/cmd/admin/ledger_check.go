@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"transaction-service/config"
+	infradb "transaction-service/infra/db"
+	"transaction-service/infra/repository"
+	"transaction-service/internal/core/usecase"
+)
+
+// runLedgerCheck prints the imbalance report as JSON on stdout and fails when
+// it is not empty, so it can gate a cron job or a deploy.
+func runLedgerCheck(ctx context.Context, logger *slog.Logger, cfg *config.Config, args []string) error {
+	fs := newFlagSet("ledger-check")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := infradb.Connect(
+		cfg.Database.Host, cfg.Database.Port,
+		cfg.Database.User, cfg.Database.Password, cfg.Database.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	check := usecase.NewCheckLedgerUseCase(repository.NewLedgerRepository(db), logger)
+	out, err := check.Execute(ctx)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(map[string]any{"imbalances": out.Imbalances}); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+
+	if len(out.Imbalances) > 0 {
+		return fmt.Errorf("ledger has %d imbalanced transactions", len(out.Imbalances))
+	}
+	return nil
+}
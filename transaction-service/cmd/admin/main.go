@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"transaction-service/config"
+)
+
+// command is one operational subcommand of the admin binary.
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, logger *slog.Logger, cfg *config.Config, args []string) error
+}
+
+var commands = []command{
+	{
+		name:  "backfill-known-users",
+		usage: "project the users-service UserCreated outbox history into known_users",
+		run:   runBackfillKnownUsers,
+	},
+	{
+		name:  "fund",
+		usage: "deposit money into a user's account from the funding account",
+		run:   runFund,
+	},
+	{
+		name:  "review",
+		usage: "approve or reject a transaction the risk rules HELD",
+		run:   runReview,
+	},
+	{
+		name:  "replay",
+		usage: "republish PROCESSED outbox events with a replay=true header",
+		run:   runReplay,
+	},
+	{
+		name:  "ledger-check",
+		usage: "report transactions whose ledger postings are unbalanced or missing",
+		run:   runLedgerCheck,
+	},
+	{
+		name:  "rebuild-balances",
+		usage: "recompute the balances projection from the ledger postings",
+		run:   runRebuildBalances,
+	},
+	{
+		name:  "balance-check",
+		usage: "compare the balances projection with a full scan of the postings",
+		run:   runBalanceCheck,
+	},
+	{
+		name:  "reconcile",
+		usage: "check transactions against their outbox events and report stuck events",
+		run:   runReconcile,
+	},
+}
+
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	cfg := config.Load()
+
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, cmd := range commands {
+		if cmd.name != os.Args[1] {
+			continue
+		}
+		if err := cmd.run(ctx, logger, cfg, os.Args[2:]); err != nil {
+			logger.Error(cmd.name+" failed", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		return
+	}
+
+	printUsage()
+	os.Exit(2)
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: admin <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-24s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ContinueOnError)
+}
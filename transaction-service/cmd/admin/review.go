@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"transaction-service/config"
+	infradb "transaction-service/infra/db"
+	"transaction-service/infra/repository"
+	"transaction-service/internal/core/usecase"
+)
+
+func runReview(ctx context.Context, logger *slog.Logger, cfg *config.Config, args []string) error {
+	fs := newFlagSet("review")
+	id := fs.String("id", "", "ID of the HELD transaction to review")
+	approve := fs.Bool("approve", false, "approve the transaction")
+	reject := fs.Bool("reject", false, "reject the transaction")
+	reviewer := fs.String("reviewer", "", "who is reviewing the transaction, recorded in its status history")
+	reason := fs.String("reason", "", "note recorded with the decision")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *approve == *reject {
+		return errors.New("exactly one of -approve or -reject is required")
+	}
+
+	db, err := infradb.Connect(
+		cfg.Database.Host, cfg.Database.Port,
+		cfg.Database.User, cfg.Database.Password, cfg.Database.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	review := usecase.NewReviewHeldUseCase(repository.NewTransactionRepository(db), logger)
+	_, err = review.Execute(ctx, usecase.ReviewInput{
+		ID:       *id,
+		Approve:  *approve,
+		Reviewer: *reviewer,
+		Reason:   *reason,
+	})
+	return err
+}
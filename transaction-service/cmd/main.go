@@ -16,6 +16,7 @@ import (
 	"transaction-service/infra/repository"
 	"transaction-service/internal/core/broker"
 	"transaction-service/internal/core/handler"
+	"transaction-service/internal/core/risk"
 	"transaction-service/internal/core/usecase"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -56,13 +57,38 @@ func main() {
 		os.Exit(1)
 	}
 	defer rabbit.Close()
+
+	drifts, err := rabbit.DeclareTopology(broker.TransactionTopology(cfg.RabbitMQ.Exchange, broker.RetryPolicy{
+		Delay:       cfg.RabbitMQ.RetryDelay,
+		MaxAttempts: cfg.RabbitMQ.MaxAttempts,
+	}))
+	if err != nil {
+		logger.Error("failed to declare rabbitmq topology", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	for _, d := range drifts {
+		logger.Warn("rabbitmq topology drift",
+			slog.String("kind", d.Kind),
+			slog.String("name", d.Name),
+			slog.String("detail", d.Detail),
+		)
+	}
 	logger.Info("connected to rabbitmq")
 
 	txRepo := repository.NewTransactionRepository(db)
-	txHandler := handler.NewHandlerFactory(usecase.NewFactory(txRepo, logger))
+	usersRepo := repository.NewKnownUserRepository(db)
+	mandateRepo := repository.NewMandateRepository(db)
+	rules, err := risk.LoadRules(cfg.Risk.RulesFile)
+	if err != nil {
+		logger.Error("failed to load risk rules", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	riskEngine := risk.NewEngine(rules, repository.NewRiskHistoryRepository(db))
+	f := usecase.NewFactory(txRepo, mandateRepo, usersRepo, riskEngine, logger)
 
 	mux := http.NewServeMux()
-	txHandler.RegisterRoutes(mux)
+	handler.NewHandlerFactory(f).RegisterRoutes(mux)
+	handler.NewMandateHandlerFactory(f).RegisterRoutes(mux)
 	mux.Handle("/metrics", promhttp.Handler())
 	mux.Handle("/swagger/", httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
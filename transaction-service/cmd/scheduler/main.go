@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"transaction-service/config"
+	infradb "transaction-service/infra/db"
+	"transaction-service/infra/repository"
+	"transaction-service/internal/core/risk"
+	"transaction-service/internal/core/usecase"
+)
+
+const defaultInterval = 30 * time.Second
+
+// job is a unit of periodic work. Jobs only write to the database; their
+// events leave through the outbox worker.
+type job struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	cfg := config.Load()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := infradb.Connect(
+		cfg.Database.Host, cfg.Database.Port,
+		cfg.Database.User, cfg.Database.Password, cfg.Database.Name,
+	)
+	if err != nil {
+		logger.Error("failed to connect to database", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer db.Close()
+	logger.Info("connected to database")
+
+	rules, err := risk.LoadRules(cfg.Risk.RulesFile)
+	if err != nil {
+		logger.Error("failed to load risk rules", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	txRepo := repository.NewTransactionRepository(db)
+	expireHolds := usecase.NewExpireHoldsUseCase(txRepo, logger)
+	releaseKeys := usecase.NewReleaseIdempotencyKeysUseCase(txRepo, logger)
+	releaseScheduled := usecase.NewReleaseScheduledUseCase(txRepo, logger)
+	runMandates := usecase.NewRunMandatesUseCase(
+		repository.NewMandateRepository(db),
+		usecase.NewCreateTransactionUseCase(
+			txRepo,
+			repository.NewKnownUserRepository(db),
+			risk.NewEngine(rules, repository.NewRiskHistoryRepository(db)),
+			logger,
+		),
+		logger,
+	)
+
+	jobs := []job{
+		{name: "expire-holds", run: func(ctx context.Context) error {
+			_, err := expireHolds.Execute(ctx, usecase.ExpireHoldsInput{})
+			return err
+		}},
+		{name: "release-scheduled", run: func(ctx context.Context) error {
+			_, err := releaseScheduled.Execute(ctx, usecase.ReleaseScheduledInput{})
+			return err
+		}},
+		{name: "run-mandates", run: func(ctx context.Context) error {
+			_, err := runMandates.Execute(ctx, usecase.RunMandatesInput{})
+			return err
+		}},
+		{name: "release-idempotency-keys", run: func(ctx context.Context) error {
+			_, err := releaseKeys.Execute(ctx, usecase.ReleaseIdempotencyKeysInput{
+				Retention: cfg.Scheduler.IdempotencyKeyRetention,
+			})
+			return err
+		}},
+	}
+
+	interval := cfg.Scheduler.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	logger.Info("scheduler started", slog.Duration("interval", interval), slog.Int("jobs", len(jobs)))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		runJobs(ctx, logger, jobs)
+
+		select {
+		case <-ctx.Done():
+			logger.Info("scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runJobs(ctx context.Context, logger *slog.Logger, jobs []job) {
+	for _, j := range jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := j.run(ctx); err != nil {
+			logger.ErrorContext(ctx, "scheduled job failed",
+				slog.String("job", j.name),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
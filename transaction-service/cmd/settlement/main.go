@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"transaction-service/config"
+	infradb "transaction-service/infra/db"
+	"transaction-service/infra/repository"
+	"transaction-service/internal/core/broker"
+	apperrors "transaction-service/internal/core/errors"
+	"transaction-service/internal/core/risk"
+	"transaction-service/internal/core/usecase"
+)
+
+const prefetch = 10
+
+// queueName is the settlement queue declared by broker.TransactionTopology.
+var queueName = broker.SettlementSubscription.Queue()
+
+// createdEvent is the subset of the TransactionCreated payload settlement needs.
+type createdEvent struct {
+	TransactionID string `json:"transactionId"`
+}
+
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	cfg := config.Load()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := infradb.Connect(
+		cfg.Database.Host, cfg.Database.Port,
+		cfg.Database.User, cfg.Database.Password, cfg.Database.Name,
+	)
+	if err != nil {
+		logger.Error("failed to connect to database", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer db.Close()
+	logger.Info("connected to database")
+
+	rabbit := broker.NewRabbitMQ(cfg.RabbitMQ.URL)
+	if err := rabbit.Connect(); err != nil {
+		logger.Error("failed to connect to rabbitmq", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer rabbit.Close()
+
+	retry := broker.RetryPolicy{
+		Delay:       cfg.RabbitMQ.RetryDelay,
+		MaxAttempts: cfg.RabbitMQ.MaxAttempts,
+	}
+	drifts, err := rabbit.DeclareTopology(broker.TransactionTopology(cfg.RabbitMQ.Exchange, retry))
+	if err != nil {
+		logger.Error("failed to declare rabbitmq topology", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	for _, d := range drifts {
+		logger.Warn("rabbitmq topology drift",
+			slog.String("kind", d.Kind),
+			slog.String("name", d.Name),
+			slog.String("detail", d.Detail),
+		)
+	}
+	logger.Info("connected to rabbitmq", slog.String("queue", queueName))
+
+	// Settlement screens each transfer against the rules as they are now,
+	// so a party blocklisted after the transfer was accepted fails it.
+	rules, err := risk.LoadRules(cfg.Risk.RulesFile)
+	if err != nil {
+		logger.Error("failed to load risk rules", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	settle := usecase.NewSettleTransactionUseCase(
+		repository.NewTransactionRepository(db),
+		risk.NewEngine(rules, repository.NewRiskHistoryRepository(db)),
+		logger,
+	)
+	consumer := broker.NewRabbitMQConsumer(rabbit.Channel, queueName, prefetch, retry)
+
+	logger.Info("settlement consumer started")
+
+	if err := consumer.Consume(ctx, handleCreated(logger, settle)); err != nil {
+		logger.Error("settlement consumer stopped", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	logger.Info("settlement consumer stopped")
+}
+
+func handleCreated(logger *slog.Logger, settle *usecase.SettleTransactionUseCase) broker.MessageHandler {
+	return func(ctx context.Context, msg broker.Message) error {
+		var event createdEvent
+		if err := json.Unmarshal(msg.Body, &event); err != nil {
+			logger.ErrorContext(ctx, "malformed TransactionCreated payload",
+				slog.String("event_id", msg.ID),
+				slog.String("error", err.Error()),
+			)
+			return broker.Permanent(err)
+		}
+
+		if _, err := settle.Execute(ctx, usecase.SettleInput{TransactionID: event.TransactionID}); err != nil {
+			var exc *apperrors.Exception
+			if errors.As(err, &exc) && exc.Code < http.StatusInternalServerError {
+				return broker.Permanent(err)
+			}
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"transaction-service/config"
+	infradb "transaction-service/infra/db"
+	"transaction-service/infra/repository"
+	"transaction-service/internal/core/broker"
+	apperrors "transaction-service/internal/core/errors"
+	"transaction-service/internal/core/usecase"
+)
+
+const prefetch = 50
+
+// queueName is the known users queue declared by broker.UserEventsTopology.
+var queueName = broker.KnownUsersSubscription.Queue()
+
+// userCreatedEvent mirrors the users-service UserCreated payload.
+type userCreatedEvent struct {
+	UserID string `json:"userId"`
+}
+
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	cfg := config.Load()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := infradb.Connect(
+		cfg.Database.Host, cfg.Database.Port,
+		cfg.Database.User, cfg.Database.Password, cfg.Database.Name,
+	)
+	if err != nil {
+		logger.Error("failed to connect to database", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer db.Close()
+	logger.Info("connected to database")
+
+	rabbit := broker.NewRabbitMQ(cfg.RabbitMQ.URL)
+	if err := rabbit.Connect(); err != nil {
+		logger.Error("failed to connect to rabbitmq", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer rabbit.Close()
+
+	retry := broker.RetryPolicy{
+		Delay:       cfg.RabbitMQ.RetryDelay,
+		MaxAttempts: cfg.RabbitMQ.MaxAttempts,
+	}
+	drifts, err := rabbit.DeclareTopology(broker.UserEventsTopology(cfg.RabbitMQ.UsersExchange, retry))
+	if err != nil {
+		logger.Error("failed to declare rabbitmq topology", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	for _, d := range drifts {
+		logger.Warn("rabbitmq topology drift",
+			slog.String("kind", d.Kind),
+			slog.String("name", d.Name),
+			slog.String("detail", d.Detail),
+		)
+	}
+	logger.Info("connected to rabbitmq",
+		slog.String("exchange", cfg.RabbitMQ.UsersExchange),
+		slog.String("queue", queueName),
+	)
+
+	syncUser := usecase.NewSyncKnownUserUseCase(repository.NewKnownUserRepository(db), logger)
+	consumer := broker.NewRabbitMQConsumer(rabbit.Channel, queueName, prefetch, retry)
+
+	logger.Info("known users projector started")
+
+	if err := consumer.Consume(ctx, handleUserCreated(logger, syncUser)); err != nil {
+		logger.Error("known users projector stopped", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	logger.Info("known users projector stopped")
+}
+
+func handleUserCreated(logger *slog.Logger, syncUser *usecase.SyncKnownUserUseCase) broker.MessageHandler {
+	return func(ctx context.Context, msg broker.Message) error {
+		var event userCreatedEvent
+		if err := json.Unmarshal(msg.Body, &event); err != nil {
+			logger.ErrorContext(ctx, "malformed UserCreated payload",
+				slog.String("event_id", msg.ID),
+				slog.String("error", err.Error()),
+			)
+			return broker.Permanent(err)
+		}
+
+		if _, err := syncUser.Execute(ctx, usecase.SyncKnownUserInput{
+			UserID:     event.UserID,
+			EventID:    msg.ID,
+			OccurredAt: msg.Timestamp,
+		}); err != nil {
+			var exc *apperrors.Exception
+			if errors.As(err, &exc) && exc.Code < http.StatusInternalServerError {
+				return broker.Permanent(err)
+			}
+			return err
+		}
+		return nil
+	}
+}
@@ -47,14 +47,25 @@ func main() {
 	}
 	defer rabbit.Close()
 
-	if err := rabbit.Channel.ExchangeDeclare(cfg.RabbitMQ.Exchange, "topic", true, false, false, false, nil); err != nil {
-		logger.Error("failed to declare exchange", slog.String("error", err.Error()))
+	drifts, err := rabbit.DeclareTopology(broker.TransactionTopology(cfg.RabbitMQ.Exchange, broker.RetryPolicy{
+		Delay:       cfg.RabbitMQ.RetryDelay,
+		MaxAttempts: cfg.RabbitMQ.MaxAttempts,
+	}))
+	if err != nil {
+		logger.Error("failed to declare rabbitmq topology", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
+	for _, d := range drifts {
+		logger.Warn("rabbitmq topology drift",
+			slog.String("kind", d.Kind),
+			slog.String("name", d.Name),
+			slog.String("detail", d.Detail),
+		)
+	}
 	logger.Info("connected to rabbitmq", slog.String("exchange", cfg.RabbitMQ.Exchange))
 
 	outboxRepo := repository.NewOutboxRepository(db)
-	publisher := broker.NewRabbitMQPublisher(rabbit.Channel, cfg.RabbitMQ.Exchange, "transaction.created")
+	publisher := broker.NewRabbitMQPublisher(rabbit.Channel, cfg.RabbitMQ.Exchange)
 
 	logger.Info("outbox worker started", slog.Duration("interval", pollInterval))
 
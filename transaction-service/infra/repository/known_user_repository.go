@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+
+	"github.com/lib/pq"
+)
+
+type PostgresKnownUserRepository struct {
+	db *sql.DB
+}
+
+func NewKnownUserRepository(db *sql.DB) *PostgresKnownUserRepository {
+	return &PostgresKnownUserRepository{db: db}
+}
+
+func (r *PostgresKnownUserRepository) Save(ctx context.Context, user *entity.KnownUser) (bool, error) {
+	const query = `
+		INSERT INTO known_users (user_id, source_event_id, registered_at)
+		VALUES ($1, NULLIF($2, '')::uuid, $3)
+		ON CONFLICT (user_id) DO NOTHING
+	`
+	res, err := r.db.ExecContext(ctx, query, user.UserID, user.SourceEventID, user.RegisteredAt)
+	if err != nil {
+		return false, fmt.Errorf("insert known user: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("insert known user: %w", err)
+	}
+	return affected == 1, nil
+}
+
+// FindMissing compares as text so ids that are not valid UUIDs are reported
+// as unknown instead of failing the query.
+func (r *PostgresKnownUserRepository) FindMissing(ctx context.Context, userIDs []string) ([]string, error) {
+	const query = `
+		SELECT id
+		FROM unnest($1::text[]) AS id
+		WHERE NOT EXISTS (SELECT 1 FROM known_users k WHERE k.user_id::text = lower(id))
+	`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("find missing users: %w", err)
+	}
+	defer rows.Close()
+
+	var missing []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("find missing users: %w", err)
+		}
+		missing = append(missing, id)
+	}
+	return missing, rows.Err()
+}
+
+// PostgresUserEventSource reads the users-service outbox table directly.
+// It is only used to backfill known_users and never writes to that database.
+type PostgresUserEventSource struct {
+	db *sql.DB
+}
+
+func NewUserEventSource(db *sql.DB) *PostgresUserEventSource {
+	return &PostgresUserEventSource{db: db}
+}
+
+func (s *PostgresUserEventSource) ListUserCreated(ctx context.Context, afterTime time.Time, afterEventID string, limit int) ([]*entity.KnownUser, error) {
+	const query = `
+		SELECT id, payload::jsonb ->> 'userId', created_at
+		FROM outbox
+		WHERE type = 'UserCreated'
+		  AND (created_at, id::text) > ($1, $2)
+		ORDER BY created_at, id::text
+		LIMIT $3
+	`
+	rows, err := s.db.QueryContext(ctx, query, afterTime, afterEventID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list user created events: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*entity.KnownUser
+	for rows.Next() {
+		var u entity.KnownUser
+		var userID sql.NullString
+		if err := rows.Scan(&u.SourceEventID, &userID, &u.RegisteredAt); err != nil {
+			return nil, fmt.Errorf("list user created events: %w", err)
+		}
+		u.UserID = userID.String
+		users = append(users, &u)
+	}
+	return users, rows.Err()
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+type PostgresLedgerRepository struct {
+	db *sql.DB
+}
+
+func NewLedgerRepository(db *sql.DB) *PostgresLedgerRepository {
+	return &PostgresLedgerRepository{db: db}
+}
+
+func (r *PostgresLedgerRepository) FindImbalances(ctx context.Context) ([]entity.LedgerImbalance, error) {
+	const query = `
+		SELECT t.id, t.transaction_status, COALESCE(l.currency, t.currency), COALESCE(SUM(l.amount), 0), COUNT(l.id)
+		FROM transactions t
+		LEFT JOIN ledger_entries l ON l.transaction_id = t.id
+		GROUP BY t.id, t.transaction_status, COALESCE(l.currency, t.currency)
+		HAVING COALESCE(SUM(l.amount), 0) <> 0
+		    OR (t.transaction_status = 'COMPLETED') <> (COUNT(l.id) > 0)
+		ORDER BY t.id, 3
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("find ledger imbalances: %w", err)
+	}
+	defer rows.Close()
+
+	var imbalances []entity.LedgerImbalance
+	for rows.Next() {
+		var i entity.LedgerImbalance
+		if err := rows.Scan(&i.TransactionID, &i.Status, &i.Currency, &i.Sum, &i.Entries); err != nil {
+			return nil, fmt.Errorf("find ledger imbalances: %w", err)
+		}
+		imbalances = append(imbalances, i)
+	}
+	return imbalances, rows.Err()
+}
+
+// RebuildBalances holds an EXCLUSIVE lock on balances while it recomputes
+// them: reads keep working, but settlements wait, so none of them can apply
+// postings the rebuild did not see.
+func (r *PostgresLedgerRepository) RebuildBalances(ctx context.Context) (int64, error) {
+	dbTx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	if _, err := dbTx.ExecContext(ctx, `LOCK TABLE balances IN EXCLUSIVE MODE`); err != nil {
+		return 0, fmt.Errorf("lock balances: %w", err)
+	}
+
+	const upsert = `
+		INSERT INTO balances (user_id, currency, balance, updated_at)
+		SELECT user_id, currency, SUM(amount), NOW()
+		FROM ledger_entries
+		GROUP BY user_id, currency
+		ON CONFLICT (user_id, currency) DO UPDATE
+		SET balance = EXCLUDED.balance, updated_at = NOW()
+		WHERE balances.balance <> EXCLUDED.balance
+	`
+	res, err := dbTx.ExecContext(ctx, upsert)
+	if err != nil {
+		return 0, fmt.Errorf("rebuild balances: %w", err)
+	}
+	upserted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rebuild balances: %w", err)
+	}
+
+	const reset = `
+		UPDATE balances b
+		SET balance = 0, updated_at = NOW()
+		WHERE b.balance <> 0
+		  AND NOT EXISTS (
+		      SELECT 1 FROM ledger_entries l WHERE l.user_id = b.user_id AND l.currency = b.currency
+		  )
+	`
+	res, err = dbTx.ExecContext(ctx, reset)
+	if err != nil {
+		return 0, fmt.Errorf("reset orphan balances: %w", err)
+	}
+	orphans, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("reset orphan balances: %w", err)
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return 0, err
+	}
+	return upserted + orphans, nil
+}
+
+func (r *PostgresLedgerRepository) FindBalanceDrift(ctx context.Context) ([]entity.BalanceDrift, error) {
+	const query = `
+		SELECT COALESCE(b.user_id, l.user_id), COALESCE(b.currency, l.currency),
+		       COALESCE(b.balance, 0), COALESCE(l.total, 0)
+		FROM balances b
+		FULL OUTER JOIN (
+			SELECT user_id, currency, SUM(amount) AS total
+			FROM ledger_entries
+			GROUP BY user_id, currency
+		) l ON l.user_id = b.user_id AND l.currency = b.currency
+		WHERE COALESCE(b.balance, 0) <> COALESCE(l.total, 0)
+		ORDER BY 1, 2
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("find balance drift: %w", err)
+	}
+	defer rows.Close()
+
+	var drifts []entity.BalanceDrift
+	for rows.Next() {
+		var d entity.BalanceDrift
+		if err := rows.Scan(&d.UserID, &d.Currency, &d.Projected, &d.Computed); err != nil {
+			return nil, fmt.Errorf("find balance drift: %w", err)
+		}
+		drifts = append(drifts, d)
+	}
+	return drifts, rows.Err()
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+
+	"github.com/lib/pq"
+)
+
+// senderLimit is the limits of one sender account and what it has sent
+// within their windows.
+type senderLimit struct {
+	limits entity.Limits
+	used   entity.LimitUsage
+}
+
+// senderLimits holds the senderLimit of every sender account a creation DB
+// transaction has locked. The locks keep the usage current until commit, so
+// concurrent requests of a sender cannot both fit under a limit.
+type senderLimits map[accountKey]*senderLimit
+
+// loadLimits reads the limits of each sender account, a user's override
+// falling back to the currency's defaults column by column, with its usage in
+// the windows ending at at. The accounts must already be locked.
+func loadLimits(ctx context.Context, dbTx *sql.Tx, at time.Time, senders []accountKey) (senderLimits, error) {
+	const query = `
+		SELECT k.user_id, k.currency,
+		       COALESCE(o.max_amount, d.max_amount),
+		       COALESCE(o.daily_outgoing, d.daily_outgoing),
+		       COALESCE(o.monthly_outgoing, d.monthly_outgoing),
+		       COALESCE(o.hourly_transfers, d.hourly_transfers),
+		       u.daily, u.monthly, u.hourly
+		FROM unnest($1::uuid[], $2::text[]) AS k(user_id, currency)
+		LEFT JOIN transaction_limits d ON d.user_id IS NULL AND d.currency = k.currency
+		LEFT JOIN transaction_limits o ON o.user_id = k.user_id AND o.currency = k.currency
+		CROSS JOIN LATERAL (
+			SELECT COALESCE(SUM(t.amount) FILTER (WHERE t.created_at >= $4), 0) AS daily,
+			       COALESCE(SUM(t.amount) FILTER (WHERE t.created_at >= $5), 0) AS monthly,
+			       COUNT(*) FILTER (WHERE t.created_at >= $3) AS hourly
+			FROM transactions t
+			WHERE t.from_user_id = k.user_id
+			  AND t.currency = k.currency
+			  AND t.created_at >= $6
+			  AND t.reversal_of IS NULL
+			  AND t.transaction_status NOT IN ('FAILED', 'VOIDED', 'EXPIRED', 'CANCELLED', 'REJECTED')
+		) u
+	`
+	userIDs := make([]string, 0, len(senders))
+	currencies := make([]string, 0, len(senders))
+	seen := make(map[accountKey]bool, len(senders))
+	for _, k := range senders {
+		if !seen[k] {
+			seen[k] = true
+			userIDs = append(userIDs, k.UserID)
+			currencies = append(currencies, k.Currency)
+		}
+	}
+	hour, day, month := entity.LimitWindows(at)
+	rows, err := dbTx.QueryContext(ctx, query,
+		pq.Array(userIDs), pq.Array(currencies), hour, day, month, earliest(hour, month),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("load limits: %w", err)
+	}
+	defer rows.Close()
+
+	limits := make(senderLimits, len(userIDs))
+	for rows.Next() {
+		var (
+			k accountKey
+			l senderLimit
+		)
+		if err := rows.Scan(&k.UserID, &k.Currency,
+			&l.limits.MaxAmount, &l.limits.DailyOutgoing, &l.limits.MonthlyOutgoing, &l.limits.HourlyTransfers,
+			&l.used.Daily, &l.used.Monthly, &l.used.HourlyTransfers,
+		); err != nil {
+			return nil, fmt.Errorf("load limits: %w", err)
+		}
+		limits[k] = &l
+	}
+	return limits, rows.Err()
+}
+
+// take checks tx against its sender's limits and, if it fits, counts it
+// into the sender's usage.
+func (s senderLimits) take(tx *entity.Transaction) error {
+	l, ok := s[accountKey{tx.FromUserID, tx.Currency}]
+	if !ok {
+		return fmt.Errorf("limits of sender %s not loaded", tx.FromUserID)
+	}
+	if err := l.limits.Check(tx.Amount, l.used); err != nil {
+		return err
+	}
+	l.used.Add(tx.Amount)
+	return nil
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
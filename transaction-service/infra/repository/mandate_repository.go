@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+const mandateColumns = `id, from_user_id, to_user_id, amount, currency, description, schedule,
+	start_at, end_at, next_run_at, occurrence, status, version, created_at, updated_at`
+
+type PostgresMandateRepository struct {
+	db *sql.DB
+}
+
+func NewMandateRepository(db *sql.DB) *PostgresMandateRepository {
+	return &PostgresMandateRepository{db: db}
+}
+
+func (r *PostgresMandateRepository) Create(ctx context.Context, m *entity.Mandate, outbox *entity.Outbox) error {
+	dbTx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	const query = `
+		INSERT INTO mandates (` + mandateColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+	if _, err := dbTx.ExecContext(ctx, query,
+		m.ID, m.FromUserID, m.ToUserID, m.Amount, m.Currency, m.Description, m.Schedule,
+		m.StartAt, m.EndAt, m.NextRunAt, m.Occurrence, string(m.Status), m.Version, m.CreatedAt, m.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("insert mandate: %w", err)
+	}
+	if err := insertOutbox(ctx, dbTx, outbox); err != nil {
+		return err
+	}
+
+	return dbTx.Commit()
+}
+
+func (r *PostgresMandateRepository) FindByID(ctx context.Context, id string) (*entity.Mandate, error) {
+	const query = `SELECT ` + mandateColumns + ` FROM mandates WHERE id = $1`
+	m, err := scanMandate(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find mandate: %w", err)
+	}
+	return m, nil
+}
+
+func (r *PostgresMandateRepository) ListByUser(ctx context.Context, fromUserID string) ([]*entity.Mandate, error) {
+	const query = `
+		SELECT ` + mandateColumns + `
+		FROM mandates
+		WHERE from_user_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+	return r.query(ctx, "list mandates", query, fromUserID)
+}
+
+func (r *PostgresMandateRepository) Update(ctx context.Context, m *entity.Mandate, outbox *entity.Outbox) error {
+	const query = `
+		UPDATE mandates
+		SET amount = $3, description = $4, end_at = $5, status = $6,
+		    version = version + 1, updated_at = $7
+		WHERE id = $1 AND version = $2
+	`
+	return r.write(ctx, outbox, query,
+		m.ID, m.Version, m.Amount, m.Description, m.EndAt, string(m.Status), m.UpdatedAt,
+	)
+}
+
+// Advance ends the mandate against the stored end_at rather than the one m
+// was read with, so an end date amended during the run still applies.
+func (r *PostgresMandateRepository) Advance(ctx context.Context, m *entity.Mandate, outbox *entity.Outbox) error {
+	const query = `
+		UPDATE mandates
+		SET occurrence = $2, next_run_at = $3,
+		    status = CASE WHEN end_at IS NOT NULL AND $3 > end_at THEN 'ENDED' ELSE status END,
+		    version = version + 1, updated_at = $4
+		WHERE id = $1 AND occurrence = $2 - 1 AND status = 'ACTIVE'
+	`
+	return r.write(ctx, outbox, query, m.ID, m.Occurrence, m.NextRunAt, m.UpdatedAt)
+}
+
+func (r *PostgresMandateRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*entity.Mandate, error) {
+	const query = `
+		SELECT ` + mandateColumns + `
+		FROM mandates
+		WHERE status = 'ACTIVE' AND next_run_at <= $1
+		ORDER BY next_run_at
+		LIMIT $2
+	`
+	return r.query(ctx, "find due mandates", query, now, limit)
+}
+
+// write runs a conditional update of one mandate together with its outbox
+// event. No matching row means someone else changed the mandate first.
+func (r *PostgresMandateRepository) write(ctx context.Context, outbox *entity.Outbox, query string, args ...any) error {
+	dbTx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	res, err := dbTx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("update mandate: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update mandate: %w", err)
+	}
+	if affected == 0 {
+		return entity.ErrMandateChanged
+	}
+	if err := insertOutbox(ctx, dbTx, outbox); err != nil {
+		return err
+	}
+
+	return dbTx.Commit()
+}
+
+func (r *PostgresMandateRepository) query(ctx context.Context, op, query string, args ...any) ([]*entity.Mandate, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var mandates []*entity.Mandate
+	for rows.Next() {
+		m, err := scanMandate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		mandates = append(mandates, m)
+	}
+	return mandates, rows.Err()
+}
+
+// scanMandate reads the columns of mandateColumns, in order.
+func scanMandate(row rowScanner) (*entity.Mandate, error) {
+	var m entity.Mandate
+	if err := row.Scan(
+		&m.ID, &m.FromUserID, &m.ToUserID, &m.Amount, &m.Currency, &m.Description, &m.Schedule,
+		&m.StartAt, &m.EndAt, &m.NextRunAt, &m.Occurrence, &m.Status, &m.Version, &m.CreatedAt, &m.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
 
 	"github.com/lib/pq"
 )
@@ -28,7 +29,7 @@ func (r *PostgresOutboxRepository) FetchPending(ctx context.Context, limit int)
 	defer func() { _ = tx.Rollback() }()
 
 	rows, err := tx.QueryContext(ctx, `
-		SELECT id, type, payload
+		SELECT id, type, COALESCE(aggregate_id, ''), payload
 		FROM outbox
 		WHERE status = 'PENDING'
 		ORDER BY created_at
@@ -44,7 +45,7 @@ func (r *PostgresOutboxRepository) FetchPending(ctx context.Context, limit int)
 
 	for rows.Next() {
 		var e entity.Outbox
-		if err := rows.Scan(&e.ID, &e.Type, &e.Payload); err != nil {
+		if err := rows.Scan(&e.ID, &e.Type, &e.AggregateID, &e.Payload); err != nil {
 			rows.Close()
 			return nil, err
 		}
@@ -96,3 +97,39 @@ func (r *PostgresOutboxRepository) MarkForRetry(ctx context.Context, id string)
 	`, id, maxRetries)
 	return err
 }
+
+// FetchProcessed pages through already published events matching q in
+// (created_at, id) order, starting strictly after the cursor in q.
+func (r *PostgresOutboxRepository) FetchProcessed(ctx context.Context, q ports.ReplayQuery, limit int) ([]*entity.Outbox, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, type, COALESCE(aggregate_id, ''), payload, created_at, processed_at
+		FROM outbox
+		WHERE status = 'PROCESSED'
+		  AND ($1 = '' OR type = $1)
+		  AND ($2 = '' OR aggregate_id = $2)
+		  AND ($3::timestamp IS NULL OR created_at >= $3)
+		  AND ($4::timestamp IS NULL OR created_at < $4)
+		  AND (created_at, id::text) > ($5, $6)
+		ORDER BY created_at, id::text
+		LIMIT $7
+	`, q.Type, q.AggregateID, nullTime(q.From), nullTime(q.To), q.AfterCreatedAt, q.AfterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*entity.Outbox
+	for rows.Next() {
+		var e entity.Outbox
+		if err := rows.Scan(&e.ID, &e.Type, &e.AggregateID, &e.Payload, &e.CreatedAt, &e.ProcessedAt); err != nil {
+			return nil, err
+		}
+		e.Status = entity.OutboxStatusProcessed
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
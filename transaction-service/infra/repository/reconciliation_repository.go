@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+
+	"github.com/lib/pq"
+)
+
+type PostgresReconciliationRepository struct {
+	db *sql.DB
+}
+
+func NewReconciliationRepository(db *sql.DB) *PostgresReconciliationRepository {
+	return &PostgresReconciliationRepository{db: db}
+}
+
+// mandateEvents are the event types whose aggregate is a mandate; every
+// other event belongs to a transaction.
+var mandateEvents = []string{
+	entity.EventMandateCreated,
+	entity.EventMandateUpdated,
+	entity.EventMandateCancelled,
+	entity.EventMandateExecuted,
+	entity.EventMandateFailed,
+}
+
+// FindMissingEvents looks each transaction up in idx_outbox_aggregate_id.
+// The transaction and its first event are committed together, so a single
+// statement never sees one without the other while a write is in flight.
+func (r *PostgresReconciliationRepository) FindMissingEvents(ctx context.Context, limit int) (entity.DiscrepancySample, error) {
+	const query = `
+		SELECT t.id, '', '', t.transaction_status, t.created_at, COUNT(*) OVER ()
+		FROM transactions t
+		WHERE NOT EXISTS (
+			SELECT 1 FROM outbox o
+			WHERE o.aggregate_id = t.id::text
+			   OR (o.aggregate_id = t.reversal_of::text
+			       AND o.type = $1
+			       AND o.payload::jsonb ->> 'transactionId' = t.id::text)
+		)
+		ORDER BY t.created_at, t.id
+		LIMIT $2
+	`
+	return r.sample(ctx, entity.DiscrepancyMissingEvent, query, entity.EventTransactionReversed, limit)
+}
+
+// FindOrphanEvents treats an event without an aggregate_id as an orphan.
+func (r *PostgresReconciliationRepository) FindOrphanEvents(ctx context.Context, limit int) (entity.DiscrepancySample, error) {
+	const query = `
+		SELECT COALESCE(o.aggregate_id, ''), o.id, o.type, o.status, o.created_at, COUNT(*) OVER ()
+		FROM outbox o
+		LEFT JOIN transactions t ON t.id::text = o.aggregate_id AND o.type <> ALL($1)
+		LEFT JOIN mandates m ON m.id::text = o.aggregate_id AND o.type = ANY($1)
+		WHERE t.id IS NULL AND m.id IS NULL
+		ORDER BY o.created_at, o.id
+		LIMIT $2
+	`
+	return r.sample(ctx, entity.DiscrepancyOrphanEvent, query, pq.Array(mandateEvents), limit)
+}
+
+// FindStuckEvents measures how long an event sat from its creation: the
+// outbox does not record when it entered PROCESSING or FAILED.
+func (r *PostgresReconciliationRepository) FindStuckEvents(ctx context.Context, status entity.OutboxStatus, before time.Time, limit int) (entity.DiscrepancySample, error) {
+	const query = `
+		SELECT COALESCE(aggregate_id, ''), id, type, status, created_at, COUNT(*) OVER ()
+		FROM outbox
+		WHERE status = $1 AND created_at < $2
+		ORDER BY created_at, id
+		LIMIT $3
+	`
+	kind := map[entity.OutboxStatus]string{
+		entity.OutboxStatusPending:    entity.DiscrepancyStuckPending,
+		entity.OutboxStatusProcessing: entity.DiscrepancyStuckProcessing,
+		entity.OutboxStatusFailed:     entity.DiscrepancyStuckFailed,
+	}[status]
+	if kind == "" {
+		return entity.DiscrepancySample{}, fmt.Errorf("no stuck check for outbox status %s", status)
+	}
+	rows, err := r.db.QueryContext(ctx, query, string(status), before, limit)
+	if err != nil {
+		return entity.DiscrepancySample{}, fmt.Errorf("find %s events: %w", kind, err)
+	}
+	return scanDiscrepancies(rows, kind)
+}
+
+func (r *PostgresReconciliationRepository) sample(ctx context.Context, kind, query string, arg any, limit int) (entity.DiscrepancySample, error) {
+	rows, err := r.db.QueryContext(ctx, query, arg, limit)
+	if err != nil {
+		return entity.DiscrepancySample{}, fmt.Errorf("find %s: %w", kind, err)
+	}
+	return scanDiscrepancies(rows, kind)
+}
+
+// scanDiscrepancies reads rows of (aggregate_id, event_id, event_type,
+// status, created_at, total count).
+func scanDiscrepancies(rows *sql.Rows, kind string) (entity.DiscrepancySample, error) {
+	defer rows.Close()
+
+	var s entity.DiscrepancySample
+	for rows.Next() {
+		d := entity.Discrepancy{Kind: kind}
+		if err := rows.Scan(&d.AggregateID, &d.EventID, &d.EventType, &d.Status, &d.Since, &s.Count); err != nil {
+			return entity.DiscrepancySample{}, fmt.Errorf("scan %s: %w", kind, err)
+		}
+		s.Items = append(s.Items, d)
+	}
+	return s, rows.Err()
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type PostgresRiskHistoryRepository struct {
+	db *sql.DB
+}
+
+func NewRiskHistoryRepository(db *sql.DB) *PostgresRiskHistoryRepository {
+	return &PostgresRiskHistoryRepository{db: db}
+}
+
+func (r *PostgresRiskHistoryRepository) CountSent(ctx context.Context, fromUserID string, since time.Time) (int64, error) {
+	const query = `
+		SELECT COUNT(*)
+		FROM transactions
+		WHERE from_user_id = $1 AND created_at >= $2
+		  AND reversal_of IS NULL AND transaction_status <> 'REJECTED'
+	`
+	var n int64
+	if err := r.db.QueryRowContext(ctx, query, fromUserID, since).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count sent transactions: %w", err)
+	}
+	return n, nil
+}
+
+func (r *PostgresRiskHistoryRepository) HasPaid(ctx context.Context, fromUserID, toUserID string) (bool, error) {
+	const query = `
+		SELECT EXISTS (
+			SELECT 1 FROM transactions
+			WHERE from_user_id = $1 AND to_user_id = $2 AND transaction_status = 'COMPLETED'
+		)
+	`
+	var paid bool
+	if err := r.db.QueryRowContext(ctx, query, fromUserID, toUserID).Scan(&paid); err != nil {
+		return false, fmt.Errorf("find earlier payment: %w", err)
+	}
+	return paid, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+)
+
+// headlineOptions make ts_headline return up to two fragments of the
+// description around the matches, delimited by the entity match markers.
+var headlineOptions = fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxWords=35, MinWords=15, MaxFragments=2`,
+	entity.MatchStart, entity.MatchStop)
+
+// Search matches through the GIN index on description_tsv and ranks with
+// ts_rank_cd, which rewards matched words that sit close together.
+// Normalization 1 divides the rank by the log of the description length, so
+// a short description of the words outranks a long one mentioning them in
+// passing. Snippets are built only for the page, since ts_headline reparses
+// the description.
+func (r *PostgresTransactionRepository) Search(ctx context.Context, q ports.SearchQuery, limit int) ([]entity.SearchHit, error) {
+	const columns = `id, amount, currency, description, from_user_id, to_user_id, transaction_status, created_at, processed_at,
+		reversal_of, authorized_amount, expires_at, to_currency, to_amount, conversion_rate,
+		idempotency_key, request_hash, execute_at, risk_decision, risk_reasons, metadata`
+	const query = `
+		SELECT ` + columns + `, rank, ts_headline('english', description, query, $7)
+		FROM (
+			SELECT * FROM (
+				SELECT ` + columns + `, query, ts_rank_cd(description_tsv, query, 1)::real AS rank
+				FROM transactions, websearch_to_tsquery('english', $1) AS query
+				WHERE description_tsv @@ query
+				  AND ($2::uuid IS NULL OR from_user_id = $2 OR to_user_id = $2)
+			) matched
+			WHERE ($3::real IS NULL OR (rank, created_at, id) < ($3, $4::timestamp, $5::uuid))
+			ORDER BY rank DESC, created_at DESC, id DESC
+			LIMIT $6
+		) page
+		ORDER BY rank DESC, created_at DESC, id DESC
+	`
+	var afterRank sql.NullFloat64
+	if q.AfterID != "" {
+		afterRank = sql.NullFloat64{Float64: float64(q.AfterRank), Valid: true}
+	}
+	rows, err := r.db.QueryContext(ctx, query,
+		q.Text, sql.NullString{String: q.UserID, Valid: q.UserID != ""},
+		afterRank, nullTime(q.AfterCreatedAt), sql.NullString{String: q.AfterID, Valid: q.AfterID != ""},
+		limit, headlineOptions,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []entity.SearchHit
+	for rows.Next() {
+		var hit entity.SearchHit
+		tx, err := scanTransaction(withTrailing(rows, &hit.Rank, &hit.Snippet))
+		if err != nil {
+			return nil, fmt.Errorf("search transactions: %w", err)
+		}
+		hit.Transaction = tx
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+// trailingScanner scans the transaction columns into the destinations
+// scanTransaction passes and the columns after them into extra.
+type trailingScanner struct {
+	row   rowScanner
+	extra []any
+}
+
+func withTrailing(row rowScanner, extra ...any) trailingScanner {
+	return trailingScanner{row: row, extra: extra}
+}
+
+func (s trailingScanner) Scan(dest ...any) error {
+	return s.row.Scan(append(dest, s.extra...)...)
+}
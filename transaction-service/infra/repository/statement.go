@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+)
+
+// statementFetchSize is the number of postings read per FETCH.
+const statementFetchSize = 500
+
+// StreamStatement reads the opening balance and the postings in one
+// REPEATABLE READ transaction, so a transfer completing mid-read can neither
+// show up twice nor be missed between the two, and walks the postings
+// through a cursor a page at a time.
+func (r *PostgresTransactionRepository) StreamStatement(ctx context.Context, q ports.StatementQuery, opening func(balance int64) error, posting func(entity.StatementLine) error) error {
+	dbTx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	const balanceQuery = `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM ledger_entries
+		WHERE user_id = $1 AND currency = $2 AND created_at < $3
+	`
+	var balance int64
+	if err := dbTx.QueryRowContext(ctx, balanceQuery, q.UserID, q.Currency, q.From).Scan(&balance); err != nil {
+		return fmt.Errorf("read opening balance: %w", err)
+	}
+	if err := opening(balance); err != nil {
+		return err
+	}
+
+	const declare = `
+		DECLARE statement_cursor NO SCROLL CURSOR FOR
+		SELECT l.transaction_id, l.created_at, t.description,
+		       CASE WHEN t.from_user_id = l.user_id THEN t.to_user_id ELSE t.from_user_id END,
+		       l.amount
+		FROM ledger_entries l
+		JOIN transactions t ON t.id = l.transaction_id
+		WHERE l.user_id = $1 AND l.currency = $2 AND l.created_at >= $3 AND l.created_at < $4
+		ORDER BY l.created_at, l.id
+	`
+	if _, err := dbTx.ExecContext(ctx, declare, q.UserID, q.Currency, q.From, q.To); err != nil {
+		return fmt.Errorf("open statement cursor: %w", err)
+	}
+	fetch := fmt.Sprintf(`FETCH %d FROM statement_cursor`, statementFetchSize)
+	for {
+		n, err := fetchStatementLines(ctx, dbTx, fetch, posting)
+		if err != nil {
+			return err
+		}
+		if n < statementFetchSize {
+			return nil
+		}
+	}
+}
+
+// fetchStatementLines runs one FETCH, passing each row to posting, and
+// reports how many rows it returned.
+func fetchStatementLines(ctx context.Context, dbTx *sql.Tx, fetch string, posting func(entity.StatementLine) error) (int, error) {
+	rows, err := dbTx.QueryContext(ctx, fetch)
+	if err != nil {
+		return 0, fmt.Errorf("fetch statement lines: %w", err)
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		var line entity.StatementLine
+		if err := rows.Scan(&line.TransactionID, &line.PostedAt, &line.Description, &line.CounterpartyID, &line.Amount); err != nil {
+			return n, fmt.Errorf("fetch statement lines: %w", err)
+		}
+		n++
+		if err := posting(line); err != nil {
+			return n, err
+		}
+	}
+	return n, rows.Err()
+}
@@ -3,9 +3,16 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+
+	"github.com/lib/pq"
 )
 
 type PostgresTransactionRepository struct {
@@ -16,88 +23,1119 @@ func NewTransactionRepository(db *sql.DB) *PostgresTransactionRepository {
 	return &PostgresTransactionRepository{db: db}
 }
 
-func (r *PostgresTransactionRepository) Create(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error {
+func (r *PostgresTransactionRepository) Create(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
 	dbTx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
 	if err != nil {
 		return err
 	}
 	defer func() { _ = dbTx.Rollback() }()
 
-	const insertTx = `
-		INSERT INTO transactions (id, amount, description, from_user_id, to_user_id, transaction_status, created_at, idempotency_key)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`
-	if _, err := dbTx.ExecContext(ctx, insertTx,
-		tx.ID, tx.Amount, tx.Description, tx.FromUserID, tx.ToUserID, string(tx.Status), tx.CreatedAt, tx.IdempotencyKey,
-	); err != nil {
-		return fmt.Errorf("insert transaction: %w", err)
+	from, _ := accountsOf(tx)
+	balances, err := lockAccounts(ctx, dbTx, from)
+	if err != nil {
+		return err
+	}
+	// Holding the sender's account lock serialises this sender's requests,
+	// so a duplicate that raced ours past FindByIdempotencyKey has committed
+	// by now. Checking before the funds keeps it from reading as an overdraft.
+	if tx.IdempotencyKey != nil {
+		if err := ensureKeyUnused(ctx, dbTx, tx.FromUserID, *tx.IdempotencyKey); err != nil {
+			return err
+		}
+	}
+	// The same lock keeps the sender's usage current until commit. A
+	// transfer the risk rules rejected is recorded whatever the limits say.
+	if tx.Status != entity.StatusRejected {
+		limits, err := loadLimits(ctx, dbTx, tx.CreatedAt, []accountKey{from})
+		if err != nil {
+			return err
+		}
+		if err := limits.take(tx); err != nil {
+			return err
+		}
+	}
+	switch tx.Status {
+	case entity.StatusScheduled, entity.StatusHeld, entity.StatusRejected:
+		// Funds are checked and moved when the transfer is released or
+		// approved, if ever.
+	default:
+		// Only the sender is debited here. The receiver is paid when the
+		// transfer settles COMPLETED or, for a hold, when it is captured.
+		if balances[from] < tx.Amount {
+			return entity.ErrInsufficientFunds
+		}
+		if err := adjustAccount(ctx, dbTx, from, -tx.Amount); err != nil {
+			return err
+		}
 	}
 
-	const insertOutbox = `
-		INSERT INTO outbox (id, type, payload, status, created_at)
-		VALUES ($1, $2, $3, $4, $5)
-	`
-	if _, err := dbTx.ExecContext(ctx, insertOutbox,
-		outbox.ID, outbox.Type, outbox.Payload, string(outbox.Status), outbox.CreatedAt,
-	); err != nil {
-		return fmt.Errorf("insert outbox: %w", err)
+	if err := insertTransaction(ctx, dbTx, tx); err != nil {
+		if isUniqueViolation(err, idempotencyKeyIndex) {
+			return entity.ErrDuplicateIdempotencyKey
+		}
+		return err
+	}
+	if err := insertStatusChange(ctx, dbTx, change); err != nil {
+		return err
+	}
+
+	if err := insertOutbox(ctx, dbTx, outbox); err != nil {
+		return err
 	}
 
 	return dbTx.Commit()
 }
 
+// CreateBatch checks each write against the balances read once under the
+// account locks, keeping them current in memory, then applies the net
+// movement per account and COPYs the rows in, so a batch costs a round trip
+// per account rather than several per transfer.
+func (r *PostgresTransactionRepository) CreateBatch(ctx context.Context, writes []ports.TransactionWrite, atomic bool) ([]error, error) {
+	dbTx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	senders := make([]accountKey, 0, len(writes))
+	var refs []ports.IdempotencyRef
+	var refIndex []int
+	for i, w := range writes {
+		from, _ := accountsOf(w.Tx)
+		senders = append(senders, from)
+		if w.Tx.IdempotencyKey != nil {
+			refs = append(refs, ports.IdempotencyRef{FromUserID: w.Tx.FromUserID, Key: *w.Tx.IdempotencyKey})
+			refIndex = append(refIndex, i)
+		}
+	}
+	balances, err := lockAccounts(ctx, dbTx, senders...)
+	if err != nil {
+		return nil, err
+	}
+	// As in Create, the senders' locks make keys committed concurrently
+	// visible here.
+	used, err := usedIdempotencyKeys(ctx, dbTx, refs)
+	if err != nil {
+		return nil, err
+	}
+	keyTaken := make(map[int]bool, len(used))
+	for _, n := range used {
+		keyTaken[refIndex[n]] = true
+	}
+	limits, err := loadLimits(ctx, dbTx, writes[0].Tx.CreatedAt, senders)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, len(writes))
+	deltas := make(map[accountKey]int64)
+	accepted := make([]ports.TransactionWrite, 0, len(writes))
+	failed := false
+	for i, w := range writes {
+		tx := w.Tx
+		from, _ := accountsOf(tx)
+		if keyTaken[i] {
+			errs[i], failed = entity.ErrDuplicateIdempotencyKey, true
+			continue
+		}
+		// A HELD transfer moves no funds until it is approved, a REJECTED
+		// one never does. As in Create, a rejection is recorded whatever
+		// the limits say.
+		noFunds := tx.Status == entity.StatusHeld || tx.Status == entity.StatusRejected
+		if !noFunds && balances[from] < tx.Amount {
+			errs[i], failed = entity.ErrInsufficientFunds, true
+			continue
+		}
+		if tx.Status != entity.StatusRejected {
+			if err := limits.take(tx); err != nil {
+				errs[i], failed = err, true
+				continue
+			}
+		}
+		if noFunds {
+			accepted = append(accepted, w)
+			continue
+		}
+		// As in Create, only the sender is debited.
+		balances[from] -= tx.Amount
+		deltas[from] -= tx.Amount
+		accepted = append(accepted, w)
+	}
+	if len(accepted) == 0 || (atomic && failed) {
+		return errs, nil
+	}
+
+	changed := make([]accountKey, 0, len(deltas))
+	for k := range deltas {
+		changed = append(changed, k)
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i].less(changed[j]) })
+	for _, k := range changed {
+		if err := adjustAccount(ctx, dbTx, k, deltas[k]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := copyTransactions(ctx, dbTx, accepted); err != nil {
+		return nil, err
+	}
+	if err := copyStatusChanges(ctx, dbTx, accepted); err != nil {
+		return nil, err
+	}
+	if err := copyOutbox(ctx, dbTx, accepted); err != nil {
+		return nil, err
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
+
 func (r *PostgresTransactionRepository) FindByID(ctx context.Context, id string) (*entity.Transaction, error) {
 	const query = `
-		SELECT id, amount, description, from_user_id, to_user_id, transaction_status, created_at
+		SELECT id, amount, currency, description, from_user_id, to_user_id, transaction_status, created_at, processed_at,
+		       reversal_of, authorized_amount, expires_at, to_currency, to_amount, conversion_rate,
+		       idempotency_key, request_hash, execute_at, risk_decision, risk_reasons, metadata
 		FROM transactions
 		WHERE id = $1
 	`
-	var tx entity.Transaction
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&tx.ID, &tx.Amount, &tx.Description,
-		&tx.FromUserID, &tx.ToUserID, &tx.Status, &tx.CreatedAt,
-	)
+	tx, err := scanTransaction(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("find transaction: %w", err)
 	}
-	return &tx, nil
+	return tx, nil
 }
 
-func (r *PostgresTransactionRepository) FindByIdempotencyKey(ctx context.Context, key string) (*entity.Transaction, error) {
+func (r *PostgresTransactionRepository) FindByIdempotencyKey(ctx context.Context, fromUserID, key string) (*entity.Transaction, error) {
 	const query = `
-		SELECT id, amount, description, from_user_id, to_user_id, transaction_status, created_at
+		SELECT id, amount, currency, description, from_user_id, to_user_id, transaction_status, created_at, processed_at,
+		       reversal_of, authorized_amount, expires_at, to_currency, to_amount, conversion_rate,
+		       idempotency_key, request_hash, execute_at, risk_decision, risk_reasons, metadata
 		FROM transactions
-		WHERE idempotency_key = $1
+		WHERE from_user_id = $1 AND idempotency_key = $2
 	`
-	var tx entity.Transaction
-	err := r.db.QueryRowContext(ctx, query, key).Scan(
-		&tx.ID, &tx.Amount, &tx.Description,
-		&tx.FromUserID, &tx.ToUserID, &tx.Status, &tx.CreatedAt,
-	)
+	tx, err := scanTransaction(r.db.QueryRowContext(ctx, query, fromUserID, key))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("find transaction by idempotency key: %w", err)
 	}
-	return &tx, nil
+	return tx, nil
 }
 
-func (r *PostgresTransactionRepository) GetBalance(ctx context.Context, userID string) (int64, error) {
+// FindByIdempotencyKeys matches as UUIDs, so sender ids differing only in
+// case from the stored ones still match.
+func (r *PostgresTransactionRepository) FindByIdempotencyKeys(ctx context.Context, refs []ports.IdempotencyRef) ([]*entity.Transaction, error) {
 	const query = `
-		SELECT
-			COALESCE(SUM(CASE WHEN to_user_id   = $1 THEN amount ELSE 0 END), 0) -
-			COALESCE(SUM(CASE WHEN from_user_id = $1 THEN amount ELSE 0 END), 0) AS balance
-		FROM transactions
-		WHERE transaction_status = 'COMPLETED'
-		  AND (from_user_id = $1 OR to_user_id = $1)
+		SELECT t.id, t.amount, t.currency, t.description, t.from_user_id, t.to_user_id, t.transaction_status, t.created_at, t.processed_at,
+		       t.reversal_of, t.authorized_amount, t.expires_at, t.to_currency, t.to_amount, t.conversion_rate,
+		       t.idempotency_key, t.request_hash, t.execute_at, t.risk_decision, t.risk_reasons, t.metadata
+		FROM transactions t
+		JOIN unnest($1::uuid[], $2::text[]) AS k(from_user_id, idempotency_key)
+		  ON t.from_user_id = k.from_user_id AND t.idempotency_key = k.idempotency_key
+	`
+	userIDs, keys := splitRefs(refs)
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(userIDs), pq.Array(keys))
+	if err != nil {
+		return nil, fmt.Errorf("find transactions by idempotency keys: %w", err)
+	}
+	defer rows.Close()
+
+	var txs []*entity.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("find transactions by idempotency keys: %w", err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, rows.Err()
+}
+
+func (r *PostgresTransactionRepository) GetBalance(ctx context.Context, userID, currency string) (int64, error) {
+	const query = `
+		SELECT balance FROM balances WHERE user_id = $1 AND currency = $2
 	`
 	var balance int64
-	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&balance); err != nil {
+	err := r.db.QueryRowContext(ctx, query, userID, currency).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
 		return 0, fmt.Errorf("get balance: %w", err)
 	}
 	return balance, nil
 }
+
+func (r *PostgresTransactionRepository) GetBalanceAsOf(ctx context.Context, userID, currency string, asOf time.Time) (int64, error) {
+	const query = `
+		SELECT COALESCE(SUM(l.amount), 0)
+		FROM ledger_entries l
+		JOIN transactions t ON t.id = l.transaction_id
+		WHERE l.user_id = $1 AND l.currency = $2 AND t.processed_at <= $3
+	`
+	var balance int64
+	if err := r.db.QueryRowContext(ctx, query, userID, currency, asOf).Scan(&balance); err != nil {
+		return 0, fmt.Errorf("get balance as of %s: %w", asOf.Format(time.RFC3339Nano), err)
+	}
+	return balance, nil
+}
+
+func (r *PostgresTransactionRepository) GetAvailableBalance(ctx context.Context, userID, currency string) (int64, error) {
+	const query = `
+		SELECT balance FROM accounts WHERE user_id = $1 AND currency = $2
+	`
+	var balance int64
+	err := r.db.QueryRowContext(ctx, query, userID, currency).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get available balance: %w", err)
+	}
+	return balance, nil
+}
+
+func (r *PostgresTransactionRepository) Settle(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	dbTx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	const updateTx = `
+		UPDATE transactions
+		SET transaction_status = $2, processed_at = $3
+		WHERE id = $1 AND transaction_status = 'PENDING'
+	`
+	if err := execTransition(ctx, dbTx, updateTx, tx.ID, string(tx.Status), tx.ProcessedAt); err != nil {
+		return err
+	}
+	if err := insertStatusChange(ctx, dbTx, change); err != nil {
+		return err
+	}
+
+	// The sender was debited when the transaction was created. Completing
+	// it pays the receiver and books the postings; failing it only gives the
+	// sender back what was taken, since nothing reached the receiver.
+	from, to := accountsOf(tx)
+	if tx.Status == entity.StatusCompleted {
+		if _, err := lockAccounts(ctx, dbTx, to); err != nil {
+			return err
+		}
+		credit, _ := tx.Credit()
+		if err := adjustAccount(ctx, dbTx, to, credit); err != nil {
+			return err
+		}
+		postings := entity.NewPostings(tx)
+		if err := insertPostings(ctx, dbTx, postings); err != nil {
+			return err
+		}
+		if err := applyPostings(ctx, dbTx, postings); err != nil {
+			return err
+		}
+	}
+	if tx.Status == entity.StatusFailed {
+		if _, err := lockAccounts(ctx, dbTx, from); err != nil {
+			return err
+		}
+		if err := adjustAccount(ctx, dbTx, from, tx.Amount); err != nil {
+			return err
+		}
+	}
+
+	if err := insertOutbox(ctx, dbTx, outbox); err != nil {
+		return err
+	}
+
+	return dbTx.Commit()
+}
+
+// CaptureHold pays the receiver the captured amount, gives the sender back
+// whatever was authorized but not captured and books the postings.
+func (r *PostgresTransactionRepository) CaptureHold(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	dbTx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	const updateTx = `
+		UPDATE transactions
+		SET transaction_status = $2, amount = $3, processed_at = $4
+		WHERE id = $1 AND transaction_status = 'AUTHORIZED'
+	`
+	if err := execTransition(ctx, dbTx, updateTx, tx.ID, string(tx.Status), tx.Amount, tx.ProcessedAt); err != nil {
+		return err
+	}
+	if err := insertStatusChange(ctx, dbTx, change); err != nil {
+		return err
+	}
+
+	from, to := accountsOf(tx)
+	if _, err := lockAccounts(ctx, dbTx, from, to); err != nil {
+		return err
+	}
+	if err := adjustAccount(ctx, dbTx, to, tx.Amount); err != nil {
+		return err
+	}
+	if err := adjustAccount(ctx, dbTx, from, *tx.AuthorizedAmount-tx.Amount); err != nil {
+		return err
+	}
+
+	postings := entity.NewPostings(tx)
+	if err := insertPostings(ctx, dbTx, postings); err != nil {
+		return err
+	}
+	if err := applyPostings(ctx, dbTx, postings); err != nil {
+		return err
+	}
+	if err := insertOutbox(ctx, dbTx, outbox); err != nil {
+		return err
+	}
+
+	return dbTx.Commit()
+}
+
+// ReleaseHold persists a voided or expired hold and gives the authorized
+// amount back to the sender.
+func (r *PostgresTransactionRepository) ReleaseHold(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	dbTx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	const updateTx = `
+		UPDATE transactions
+		SET transaction_status = $2, processed_at = $3
+		WHERE id = $1 AND transaction_status = 'AUTHORIZED'
+	`
+	if err := execTransition(ctx, dbTx, updateTx, tx.ID, string(tx.Status), tx.ProcessedAt); err != nil {
+		return err
+	}
+	if err := insertStatusChange(ctx, dbTx, change); err != nil {
+		return err
+	}
+
+	from, _ := accountsOf(tx)
+	if _, err := lockAccounts(ctx, dbTx, from); err != nil {
+		return err
+	}
+	if err := adjustAccount(ctx, dbTx, from, *tx.AuthorizedAmount); err != nil {
+		return err
+	}
+	if err := insertOutbox(ctx, dbTx, outbox); err != nil {
+		return err
+	}
+
+	return dbTx.Commit()
+}
+
+func (r *PostgresTransactionRepository) FindExpiredHolds(ctx context.Context, now time.Time, limit int) ([]*entity.Transaction, error) {
+	const query = `
+		SELECT id, amount, currency, description, from_user_id, to_user_id, transaction_status, created_at, processed_at,
+		       reversal_of, authorized_amount, expires_at, to_currency, to_amount, conversion_rate,
+		       idempotency_key, request_hash, execute_at, risk_decision, risk_reasons, metadata
+		FROM transactions
+		WHERE transaction_status = 'AUTHORIZED' AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("find expired holds: %w", err)
+	}
+	defer rows.Close()
+
+	var txs []*entity.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("find expired holds: %w", err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, rows.Err()
+}
+
+func (r *PostgresTransactionRepository) FindDueScheduled(ctx context.Context, now time.Time, limit int) ([]*entity.Transaction, error) {
+	const query = `
+		SELECT id, amount, currency, description, from_user_id, to_user_id, transaction_status, created_at, processed_at,
+		       reversal_of, authorized_amount, expires_at, to_currency, to_amount, conversion_rate,
+		       idempotency_key, request_hash, execute_at, risk_decision, risk_reasons, metadata
+		FROM transactions
+		WHERE transaction_status = 'SCHEDULED' AND execute_at <= $1
+		ORDER BY execute_at
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("find due scheduled transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var txs []*entity.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("find due scheduled transactions: %w", err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, rows.Err()
+}
+
+// ResolveScheduled claims the row first, so a release racing a cancellation
+// loses cleanly, and only then locks the account a release pays from.
+func (r *PostgresTransactionRepository) ResolveScheduled(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	return r.resolve(ctx, entity.StatusScheduled, tx, change, outbox)
+}
+
+func (r *PostgresTransactionRepository) ResolveHeld(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	return r.resolve(ctx, entity.StatusHeld, tx, change, outbox)
+}
+
+// resolve moves a transaction that was created without moving funds out of
+// from. Into PENDING or AUTHORIZED it debits the sender as Create does; any
+// other status moves none.
+func (r *PostgresTransactionRepository) resolve(ctx context.Context, from entity.TransactionStatus, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	dbTx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	const updateTx = `
+		UPDATE transactions
+		SET transaction_status = $2, processed_at = $3
+		WHERE id = $1 AND transaction_status = $4
+	`
+	if err := execTransition(ctx, dbTx, updateTx, tx.ID, string(tx.Status), tx.ProcessedAt, string(from)); err != nil {
+		return err
+	}
+	if err := insertStatusChange(ctx, dbTx, change); err != nil {
+		return err
+	}
+
+	if tx.Status == entity.StatusPending || tx.Status == entity.StatusAuthorized {
+		sender, _ := accountsOf(tx)
+		balances, err := lockAccounts(ctx, dbTx, sender)
+		if err != nil {
+			return err
+		}
+		if balances[sender] < tx.Amount {
+			return entity.ErrInsufficientFunds
+		}
+		if err := adjustAccount(ctx, dbTx, sender, -tx.Amount); err != nil {
+			return err
+		}
+	}
+
+	if err := insertOutbox(ctx, dbTx, outbox); err != nil {
+		return err
+	}
+
+	return dbTx.Commit()
+}
+
+// ReleaseIdempotencyKeys clears the key and request hash of up to limit
+// transactions created before the cutoff, so their senders may reuse the keys.
+func (r *PostgresTransactionRepository) ReleaseIdempotencyKeys(ctx context.Context, before time.Time, limit int) (int64, error) {
+	const query = `
+		UPDATE transactions
+		SET idempotency_key = NULL, request_hash = NULL
+		WHERE id IN (
+			SELECT id FROM transactions
+			WHERE idempotency_key IS NOT NULL AND created_at < $1
+			LIMIT $2
+		)
+	`
+	res, err := r.db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("release idempotency keys: %w", err)
+	}
+	released, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("release idempotency keys: %w", err)
+	}
+	return released, nil
+}
+
+// idempotencyKeyIndex is the unique index on (from_user_id, idempotency_key).
+const idempotencyKeyIndex = "uidx_transactions_from_user_idempotency_key"
+
+func ensureKeyUnused(ctx context.Context, dbTx *sql.Tx, fromUserID, key string) error {
+	const query = `
+		SELECT EXISTS (SELECT 1 FROM transactions WHERE from_user_id = $1 AND idempotency_key = $2)
+	`
+	var used bool
+	if err := dbTx.QueryRowContext(ctx, query, fromUserID, key).Scan(&used); err != nil {
+		return fmt.Errorf("check idempotency key: %w", err)
+	}
+	if used {
+		return entity.ErrDuplicateIdempotencyKey
+	}
+	return nil
+}
+
+// usedIdempotencyKeys returns the positions in refs of the keys their
+// senders already used.
+func usedIdempotencyKeys(ctx context.Context, dbTx *sql.Tx, refs []ports.IdempotencyRef) ([]int, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	const query = `
+		SELECT k.n - 1
+		FROM unnest($1::uuid[], $2::text[]) WITH ORDINALITY AS k(from_user_id, idempotency_key, n)
+		WHERE EXISTS (
+			SELECT 1 FROM transactions t
+			WHERE t.from_user_id = k.from_user_id AND t.idempotency_key = k.idempotency_key
+		)
+	`
+	userIDs, keys := splitRefs(refs)
+	rows, err := dbTx.QueryContext(ctx, query, pq.Array(userIDs), pq.Array(keys))
+	if err != nil {
+		return nil, fmt.Errorf("check idempotency keys: %w", err)
+	}
+	defer rows.Close()
+
+	var used []int
+	for rows.Next() {
+		var n int
+		if err := rows.Scan(&n); err != nil {
+			return nil, fmt.Errorf("check idempotency keys: %w", err)
+		}
+		used = append(used, n)
+	}
+	return used, rows.Err()
+}
+
+func splitRefs(refs []ports.IdempotencyRef) (userIDs, keys []string) {
+	userIDs = make([]string, len(refs))
+	keys = make([]string, len(refs))
+	for i, ref := range refs {
+		userIDs[i], keys[i] = ref.FromUserID, ref.Key
+	}
+	return userIDs, keys
+}
+
+// isUniqueViolation reports whether err is Postgres unique_violation (23505)
+// on constraint.
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
+
+// execTransition runs a conditional status UPDATE and reports
+// entity.ErrInvalidStatusTransition when it matched no row.
+func execTransition(ctx context.Context, dbTx *sql.Tx, query string, args ...any) error {
+	res, err := dbTx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("update transaction status: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update transaction status: %w", err)
+	}
+	if affected == 0 {
+		return entity.ErrInvalidStatusTransition
+	}
+	return nil
+}
+
+// Reverse locks the original row so concurrent reversals of the same
+// transaction are serialised, checks the cumulative amount, then moves the
+// funds and writes the reversal, its postings and its outbox event at once.
+func (r *PostgresTransactionRepository) Reverse(ctx context.Context, reversal *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	dbTx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	const lockOriginal = `
+		SELECT amount, transaction_status, reversal_of IS NOT NULL
+		FROM transactions
+		WHERE id = $1
+		FOR UPDATE
+	`
+	var (
+		originalAmount int64
+		status         entity.TransactionStatus
+		isReversal     bool
+	)
+	if err := dbTx.QueryRowContext(ctx, lockOriginal, *reversal.ReversalOf).Scan(&originalAmount, &status, &isReversal); err != nil {
+		return fmt.Errorf("lock original transaction: %w", err)
+	}
+	if status != entity.StatusCompleted || isReversal {
+		return entity.ErrNotReversible
+	}
+
+	const reversed = `
+		SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE reversal_of = $1
+	`
+	var alreadyReversed int64
+	if err := dbTx.QueryRowContext(ctx, reversed, *reversal.ReversalOf).Scan(&alreadyReversed); err != nil {
+		return fmt.Errorf("sum reversals: %w", err)
+	}
+	if alreadyReversed+reversal.Amount > originalAmount {
+		return entity.ErrReversalExceedsOriginal
+	}
+
+	from, to := accountsOf(reversal)
+	balances, err := lockAccounts(ctx, dbTx, from, to)
+	if err != nil {
+		return err
+	}
+	if balances[from] < reversal.Amount {
+		return entity.ErrInsufficientFunds
+	}
+	if err := moveFunds(ctx, dbTx, from, reversal.Amount, to, reversal.Amount); err != nil {
+		return err
+	}
+
+	if err := insertTransaction(ctx, dbTx, reversal); err != nil {
+		return err
+	}
+	if err := insertStatusChange(ctx, dbTx, change); err != nil {
+		return err
+	}
+	postings := entity.NewPostings(reversal)
+	if err := insertPostings(ctx, dbTx, postings); err != nil {
+		return err
+	}
+	if err := applyPostings(ctx, dbTx, postings); err != nil {
+		return err
+	}
+	if err := insertOutbox(ctx, dbTx, outbox); err != nil {
+		return err
+	}
+
+	return dbTx.Commit()
+}
+
+// Fund credits the receiver and books the deposit's postings in the same DB
+// transaction, so the account, the ledger and the balances projection all
+// see the money arrive at once. FundingAccount has no accounts row to lock.
+func (r *PostgresTransactionRepository) Fund(ctx context.Context, funding *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	dbTx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	_, to := accountsOf(funding)
+	if _, err := lockAccounts(ctx, dbTx, to); err != nil {
+		return err
+	}
+	if err := adjustAccount(ctx, dbTx, to, funding.Amount); err != nil {
+		return err
+	}
+
+	if err := insertTransaction(ctx, dbTx, funding); err != nil {
+		return err
+	}
+	if err := insertStatusChange(ctx, dbTx, change); err != nil {
+		return err
+	}
+	postings := entity.NewPostings(funding)
+	if err := insertPostings(ctx, dbTx, postings); err != nil {
+		return err
+	}
+	if err := applyPostings(ctx, dbTx, postings); err != nil {
+		return err
+	}
+	if err := insertOutbox(ctx, dbTx, outbox); err != nil {
+		return err
+	}
+
+	return dbTx.Commit()
+}
+
+func (r *PostgresTransactionRepository) FindStatusHistory(ctx context.Context, transactionID string) ([]*entity.StatusChange, error) {
+	const query = `
+		SELECT id, transaction_id, COALESCE(from_status, ''), to_status, actor, reason, changed_at
+		FROM transaction_status_history
+		WHERE transaction_id = $1
+		ORDER BY changed_at, seq
+	`
+	rows, err := r.db.QueryContext(ctx, query, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("find status history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*entity.StatusChange
+	for rows.Next() {
+		var c entity.StatusChange
+		if err := rows.Scan(&c.ID, &c.TransactionID, &c.FromStatus, &c.ToStatus, &c.Actor, &c.Reason, &c.ChangedAt); err != nil {
+			return nil, fmt.Errorf("find status history: %w", err)
+		}
+		changes = append(changes, &c)
+	}
+	return changes, rows.Err()
+}
+
+// ListByUser reads each direction through its (user, created_at, id) index
+// and merges them, so a page never sorts more than 2*limit rows. A metadata
+// filter is a containment test the planner can answer from the GIN index on
+// metadata instead, when few transactions carry the pair.
+func (r *PostgresTransactionRepository) ListByUser(ctx context.Context, q ports.TransactionQuery, limit int) ([]*entity.Transaction, error) {
+	const filters = `
+		  AND ($3 = '' OR transaction_status = $3)
+		  AND ($4::bigint IS NULL OR amount >= $4)
+		  AND ($5::bigint IS NULL OR amount <= $5)
+		  AND ($6::timestamp IS NULL OR created_at >= $6)
+		  AND ($7::timestamp IS NULL OR created_at < $7)
+		  AND ($8::timestamp IS NULL OR (created_at, id) < ($8, $9::uuid))
+		  AND ($11::jsonb IS NULL OR metadata @> $11::jsonb)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $10`
+	const columns = `id, amount, currency, description, from_user_id, to_user_id, transaction_status, created_at, processed_at,
+		reversal_of, authorized_amount, expires_at, to_currency, to_amount, conversion_rate,
+		idempotency_key, request_hash, execute_at, risk_decision, risk_reasons, metadata`
+	query := `
+		SELECT ` + columns + ` FROM (
+			(SELECT ` + columns + ` FROM transactions
+			 WHERE from_user_id = $1 AND $2 <> 'received'` + filters + `)
+			UNION ALL
+			(SELECT ` + columns + ` FROM transactions
+			 WHERE to_user_id = $1 AND $2 <> 'sent'` + filters + `)
+		) t
+		ORDER BY created_at DESC, id DESC
+		LIMIT $10
+	`
+	rows, err := r.db.QueryContext(ctx, query,
+		q.UserID, string(q.Direction), string(q.Status),
+		nullInt64(q.MinAmount), nullInt64(q.MaxAmount),
+		nullTime(q.From), nullTime(q.To),
+		nullTime(q.BeforeCreatedAt), sql.NullString{String: q.BeforeID, Valid: q.BeforeID != ""},
+		limit, metadataColumn(q.Metadata),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var txs []*entity.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list transactions: %w", err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, rows.Err()
+}
+
+// rowScanner is the Scan method shared by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTransaction reads the columns selected by every transaction query, in
+// order: id, amount, currency, description, from_user_id, to_user_id,
+// transaction_status, created_at, processed_at, reversal_of,
+// authorized_amount, expires_at, to_currency, to_amount, conversion_rate,
+// idempotency_key, request_hash, execute_at, risk_decision, risk_reasons,
+// metadata.
+func scanTransaction(row rowScanner) (*entity.Transaction, error) {
+	var (
+		tx           entity.Transaction
+		toCurrency   sql.NullString
+		toAmount     sql.NullInt64
+		rate         sql.NullString
+		riskDecision sql.NullString
+		riskReasons  pq.StringArray
+		metadata     []byte
+	)
+	if err := row.Scan(
+		&tx.ID, &tx.Amount, &tx.Currency, &tx.Description,
+		&tx.FromUserID, &tx.ToUserID, &tx.Status, &tx.CreatedAt, &tx.ProcessedAt, &tx.ReversalOf,
+		&tx.AuthorizedAmount, &tx.ExpiresAt, &toCurrency, &toAmount, &rate,
+		&tx.IdempotencyKey, &tx.RequestHash, &tx.ExecuteAt, &riskDecision, &riskReasons, &metadata,
+	); err != nil {
+		return nil, err
+	}
+	if metadata != nil {
+		if err := json.Unmarshal(metadata, &tx.Metadata); err != nil {
+			return nil, fmt.Errorf("decode metadata: %w", err)
+		}
+	}
+	if toCurrency.Valid {
+		tx.Conversion = &entity.Conversion{Currency: toCurrency.String, Rate: rate.String, Amount: toAmount.Int64}
+	}
+	if riskDecision.Valid {
+		tx.Risk = &entity.RiskAssessment{Decision: entity.RiskDecision(riskDecision.String), Reasons: riskReasons}
+	}
+	return &tx, nil
+}
+
+func nullInt64(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
+
+func insertTransaction(ctx context.Context, dbTx *sql.Tx, tx *entity.Transaction) error {
+	const query = `
+		INSERT INTO transactions (
+			id, amount, currency, description, from_user_id, to_user_id, transaction_status,
+			created_at, processed_at, idempotency_key, reversal_of, authorized_amount, expires_at,
+			to_currency, to_amount, conversion_rate, request_hash, execute_at, risk_decision, risk_reasons, metadata
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+	`
+	toCurrency, toAmount, rate := conversionColumns(tx)
+	riskDecision, riskReasons := riskColumns(tx)
+	if _, err := dbTx.ExecContext(ctx, query,
+		tx.ID, tx.Amount, tx.Currency, tx.Description, tx.FromUserID, tx.ToUserID, string(tx.Status),
+		tx.CreatedAt, tx.ProcessedAt, tx.IdempotencyKey, tx.ReversalOf, tx.AuthorizedAmount, tx.ExpiresAt,
+		toCurrency, toAmount, rate, tx.RequestHash, tx.ExecuteAt, riskDecision, riskReasons, metadataColumn(tx.Metadata),
+	); err != nil {
+		return fmt.Errorf("insert transaction: %w", err)
+	}
+	return nil
+}
+
+// conversionColumns returns to_currency, to_amount and conversion_rate,
+// which are NULL unless tx converts between currencies.
+func conversionColumns(tx *entity.Transaction) (toCurrency sql.NullString, toAmount sql.NullInt64, rate sql.NullString) {
+	if c := tx.Conversion; c != nil {
+		toCurrency = sql.NullString{String: c.Currency, Valid: true}
+		toAmount = sql.NullInt64{Int64: c.Amount, Valid: true}
+		rate = sql.NullString{String: c.Rate, Valid: true}
+	}
+	return toCurrency, toAmount, rate
+}
+
+// riskColumns returns the risk decision and reasons of tx, both NULL when
+// it was never assessed.
+func riskColumns(tx *entity.Transaction) (decision sql.NullString, reasons any) {
+	if tx.Risk == nil {
+		return sql.NullString{}, nil
+	}
+	return sql.NullString{String: string(tx.Risk.Decision), Valid: true}, pq.Array(tx.Risk.Reasons)
+}
+
+// metadataColumn encodes metadata for its JSONB column, NULL when there is
+// none. It is passed as text, which pq would otherwise send as bytea.
+func metadataColumn(metadata map[string]string) sql.NullString {
+	if len(metadata) == 0 {
+		return sql.NullString{}
+	}
+	b, _ := json.Marshal(metadata)
+	return sql.NullString{String: string(b), Valid: true}
+}
+
+// copyTransactions loads the transactions of writes with COPY, writing the
+// same columns as insertTransaction.
+func copyTransactions(ctx context.Context, dbTx *sql.Tx, writes []ports.TransactionWrite) error {
+	return copyRows(ctx, dbTx, "transactions", []string{
+		"id", "amount", "currency", "description", "from_user_id", "to_user_id", "transaction_status",
+		"created_at", "processed_at", "idempotency_key", "reversal_of", "authorized_amount", "expires_at",
+		"to_currency", "to_amount", "conversion_rate", "request_hash", "execute_at", "risk_decision", "risk_reasons",
+		"metadata",
+	}, len(writes), func(i int) []any {
+		tx := writes[i].Tx
+		toCurrency, toAmount, rate := conversionColumns(tx)
+		riskDecision, riskReasons := riskColumns(tx)
+		return []any{
+			tx.ID, tx.Amount, tx.Currency, tx.Description, tx.FromUserID, tx.ToUserID, string(tx.Status),
+			tx.CreatedAt, tx.ProcessedAt, tx.IdempotencyKey, tx.ReversalOf, tx.AuthorizedAmount, tx.ExpiresAt,
+			toCurrency, toAmount, rate, tx.RequestHash, tx.ExecuteAt, riskDecision, riskReasons,
+			metadataColumn(tx.Metadata),
+		}
+	})
+}
+
+func copyStatusChanges(ctx context.Context, dbTx *sql.Tx, writes []ports.TransactionWrite) error {
+	return copyRows(ctx, dbTx, "transaction_status_history", []string{
+		"id", "transaction_id", "from_status", "to_status", "actor", "reason", "changed_at",
+	}, len(writes), func(i int) []any {
+		c := writes[i].Change
+		from := sql.NullString{String: string(c.FromStatus), Valid: c.FromStatus != ""}
+		return []any{c.ID, c.TransactionID, from, string(c.ToStatus), c.Actor, c.Reason, c.ChangedAt}
+	})
+}
+
+func copyOutbox(ctx context.Context, dbTx *sql.Tx, writes []ports.TransactionWrite) error {
+	return copyRows(ctx, dbTx, "outbox", []string{
+		"id", "type", "aggregate_id", "payload", "status", "created_at",
+	}, len(writes), func(i int) []any {
+		o := writes[i].Outbox
+		return []any{o.ID, o.Type, o.AggregateID, o.Payload, string(o.Status), o.CreatedAt}
+	})
+}
+
+// copyRows streams n rows into table with COPY FROM STDIN.
+func copyRows(ctx context.Context, dbTx *sql.Tx, table string, columns []string, n int, row func(i int) []any) error {
+	stmt, err := dbTx.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return fmt.Errorf("copy into %s: %w", table, err)
+	}
+	for i := 0; i < n; i++ {
+		if _, err := stmt.ExecContext(ctx, row(i)...); err != nil {
+			_ = stmt.Close()
+			return fmt.Errorf("copy into %s: %w", table, err)
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		_ = stmt.Close()
+		return fmt.Errorf("copy into %s: %w", table, err)
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("copy into %s: %w", table, err)
+	}
+	return nil
+}
+
+func insertStatusChange(ctx context.Context, dbTx *sql.Tx, change *entity.StatusChange) error {
+	const query = `
+		INSERT INTO transaction_status_history (id, transaction_id, from_status, to_status, actor, reason, changed_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7)
+	`
+	if _, err := dbTx.ExecContext(ctx, query,
+		change.ID, change.TransactionID, string(change.FromStatus), string(change.ToStatus),
+		change.Actor, change.Reason, change.ChangedAt,
+	); err != nil {
+		return fmt.Errorf("insert status change: %w", err)
+	}
+	return nil
+}
+
+func insertOutbox(ctx context.Context, dbTx *sql.Tx, outbox *entity.Outbox) error {
+	const query = `
+		INSERT INTO outbox (id, type, aggregate_id, payload, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := dbTx.ExecContext(ctx, query,
+		outbox.ID, outbox.Type, outbox.AggregateID, outbox.Payload, string(outbox.Status), outbox.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("insert outbox: %w", err)
+	}
+	return nil
+}
+
+// insertPostings refuses unbalanced postings before they reach the deferred
+// ledger_entries_check_balanced trigger, which only fires at commit.
+func insertPostings(ctx context.Context, dbTx *sql.Tx, entries []*entity.LedgerEntry) error {
+	if err := entity.CheckBalanced(entries); err != nil {
+		return err
+	}
+	const query = `
+		INSERT INTO ledger_entries (id, transaction_id, user_id, currency, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	for _, e := range entries {
+		if _, err := dbTx.ExecContext(ctx, query, e.ID, e.TransactionID, e.UserID, e.Currency, e.Amount, e.CreatedAt); err != nil {
+			return fmt.Errorf("insert ledger entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// applyPostings adds postings to the balances projection. Rows are touched in
+// (user ID, currency) order, like lockAccounts, so concurrent settlements
+// cannot deadlock.
+func applyPostings(ctx context.Context, dbTx *sql.Tx, entries []*entity.LedgerEntry) error {
+	sorted := append([]*entity.LedgerEntry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return accountKey{sorted[i].UserID, sorted[i].Currency}.less(accountKey{sorted[j].UserID, sorted[j].Currency})
+	})
+
+	const query = `
+		INSERT INTO balances (user_id, currency, balance, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id, currency) DO UPDATE
+		SET balance = balances.balance + EXCLUDED.balance, updated_at = NOW()
+	`
+	for _, e := range sorted {
+		if _, err := dbTx.ExecContext(ctx, query, e.UserID, e.Currency, e.Amount); err != nil {
+			return fmt.Errorf("apply posting to balance: %w", err)
+		}
+	}
+	return nil
+}
+
+// accountKey identifies an accounts row: one per user per currency.
+type accountKey struct {
+	UserID   string
+	Currency string
+}
+
+func (k accountKey) less(o accountKey) bool {
+	if k.UserID != o.UserID {
+		return k.UserID < o.UserID
+	}
+	return k.Currency < o.Currency
+}
+
+// accountsOf returns the account tx debits and the one it credits.
+func accountsOf(tx *entity.Transaction) (from, to accountKey) {
+	_, creditCurrency := tx.Credit()
+	return accountKey{tx.FromUserID, tx.Currency}, accountKey{tx.ToUserID, creditCurrency}
+}
+
+// lockAccounts creates any missing account rows and locks them with
+// SELECT ... FOR UPDATE, always in (user ID, currency) order so two transfers
+// in opposite directions cannot deadlock. It returns the locked balances.
+func lockAccounts(ctx context.Context, dbTx *sql.Tx, keys ...accountKey) (map[accountKey]int64, error) {
+	sorted := append([]accountKey(nil), keys...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].less(sorted[j]) })
+
+	const upsert = `
+		INSERT INTO accounts (user_id, currency) VALUES ($1, $2)
+		ON CONFLICT (user_id, currency) DO NOTHING
+	`
+	const lock = `
+		SELECT balance FROM accounts WHERE user_id = $1 AND currency = $2 FOR UPDATE
+	`
+	balances := make(map[accountKey]int64, len(sorted))
+	for _, k := range sorted {
+		if _, ok := balances[k]; ok {
+			continue
+		}
+		if _, err := dbTx.ExecContext(ctx, upsert, k.UserID, k.Currency); err != nil {
+			return nil, fmt.Errorf("create account: %w", err)
+		}
+		var balance int64
+		if err := dbTx.QueryRowContext(ctx, lock, k.UserID, k.Currency).Scan(&balance); err != nil {
+			return nil, fmt.Errorf("lock account: %w", err)
+		}
+		balances[k] = balance
+	}
+	return balances, nil
+}
+
+// moveFunds debits from and credits to, which differ on a transfer between
+// currencies; both rows must already be locked.
+func moveFunds(ctx context.Context, dbTx *sql.Tx, from accountKey, debit int64, to accountKey, credit int64) error {
+	if err := adjustAccount(ctx, dbTx, from, -debit); err != nil {
+		return err
+	}
+	return adjustAccount(ctx, dbTx, to, credit)
+}
+
+// adjustAccount adds delta to a locked account row.
+func adjustAccount(ctx context.Context, dbTx *sql.Tx, account accountKey, delta int64) error {
+	if delta == 0 {
+		return nil
+	}
+	const query = `
+		UPDATE accounts
+		SET balance = balance + $3, updated_at = NOW()
+		WHERE user_id = $1 AND currency = $2
+	`
+	if _, err := dbTx.ExecContext(ctx, query, account.UserID, account.Currency, delta); err != nil {
+		return fmt.Errorf("adjust account: %w", err)
+	}
+	return nil
+}
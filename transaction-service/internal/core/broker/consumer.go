@@ -0,0 +1,142 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Message is the broker-agnostic view of a delivery handed to a MessageHandler.
+type Message struct {
+	ID        string
+	Type      string
+	Body      []byte
+	Timestamp time.Time
+}
+
+// MessageHandler processes one message. Returning nil acknowledges it; errors
+// wrapped with Permanent send the message straight to the DLQ, any other
+// error schedules a delayed retry until the RetryPolicy is exhausted.
+type MessageHandler func(ctx context.Context, msg Message) error
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as non-retryable so the consumer dead-letters the
+// message instead of retrying it.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// attemptsHeader counts how many times a message has been handed to a handler.
+const attemptsHeader = "x-attempts"
+
+type RabbitMQConsumer struct {
+	channel  *amqp.Channel
+	queue    string
+	prefetch int
+	retry    RetryPolicy
+}
+
+// NewRabbitMQConsumer consumes queue, which must have been declared by
+// EventTopology so that RetryQueue(queue) and the DLQ exist.
+func NewRabbitMQConsumer(ch *amqp.Channel, queue string, prefetch int, retry RetryPolicy) *RabbitMQConsumer {
+	return &RabbitMQConsumer{
+		channel:  ch,
+		queue:    queue,
+		prefetch: prefetch,
+		retry:    retry,
+	}
+}
+
+// Consume delivers messages to handler until ctx is cancelled or the channel
+// is closed by the broker.
+func (c *RabbitMQConsumer) Consume(ctx context.Context, handler MessageHandler) error {
+	if err := c.channel.Qos(c.prefetch, 0, false); err != nil {
+		return fmt.Errorf("set qos: %w", err)
+	}
+
+	deliveries, err := c.channel.ConsumeWithContext(ctx, c.queue, "", false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("consume %s: %w", c.queue, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-deliveries:
+			if !ok {
+				return fmt.Errorf("consume %s: delivery channel closed", c.queue)
+			}
+			c.dispatch(ctx, handler, d)
+		}
+	}
+}
+
+func (c *RabbitMQConsumer) dispatch(ctx context.Context, handler MessageHandler, d amqp.Delivery) {
+	eventType, _ := d.Headers["event_type"].(string)
+
+	err := handler(ctx, Message{
+		ID:        d.MessageId,
+		Type:      eventType,
+		Body:      d.Body,
+		Timestamp: d.Timestamp,
+	})
+	if err == nil {
+		_ = d.Ack(false)
+		return
+	}
+
+	attempts := attemptsOf(d) + 1
+	var perm *permanentError
+	if errors.As(err, &perm) || attempts >= c.retry.MaxAttempts {
+		// Rejecting without requeue dead-letters the message to the DLQ.
+		_ = d.Nack(false, false)
+		return
+	}
+
+	if perr := c.scheduleRetry(ctx, d, attempts); perr != nil {
+		_ = d.Nack(false, true)
+		return
+	}
+	_ = d.Ack(false)
+}
+
+// scheduleRetry parks a copy of d in the retry queue; its TTL routes it back
+// to the main queue through the default exchange.
+func (c *RabbitMQConsumer) scheduleRetry(ctx context.Context, d amqp.Delivery, attempts int) error {
+	headers := amqp.Table{}
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+	headers[attemptsHeader] = int32(attempts)
+
+	return c.channel.PublishWithContext(ctx, "", RetryQueue(c.queue), false, false, amqp.Publishing{
+		ContentType:  d.ContentType,
+		Body:         d.Body,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    d.MessageId,
+		Timestamp:    d.Timestamp,
+		Headers:      headers,
+	})
+}
+
+func attemptsOf(d amqp.Delivery) int {
+	switch v := d.Headers[attemptsHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
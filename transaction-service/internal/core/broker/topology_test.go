@@ -0,0 +1,94 @@
+package broker_test
+
+import (
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/broker"
+	"transaction-service/internal/core/domain/entity"
+)
+
+func TestRoutingKey(t *testing.T) {
+	cases := map[string]string{
+		entity.EventTransactionCreated:   "transaction.created",
+		entity.EventTransactionCompleted: "transaction.completed",
+		entity.EventUserCreated:          "user.created",
+	}
+	for eventType, want := range cases {
+		if got := broker.RoutingKey(eventType); got != want {
+			t.Fatalf("RoutingKey(%q): expected %q, got %q", eventType, want, got)
+		}
+	}
+}
+
+func TestEventTopology_DeclaresRetryAndDeadLetterQueues(t *testing.T) {
+	topo := broker.EventTopology("tx.events", broker.RetryPolicy{Delay: 5 * time.Second, MaxAttempts: 3},
+		broker.Subscription{Consumer: "settlement", EventType: entity.EventTransactionCreated},
+	)
+
+	queues := map[string]map[string]any{}
+	for _, q := range topo.Queues {
+		queues[q.Name] = q.Args
+	}
+
+	main, ok := queues["settlement.transaction.created"]
+	if !ok {
+		t.Fatal("expected main queue settlement.transaction.created")
+	}
+	if main["x-dead-letter-exchange"] != "tx.events.dlx" || main["x-dead-letter-routing-key"] != "settlement.transaction.created" {
+		t.Fatalf("expected main queue to dead-letter to the DLX under its own name, got %v", main)
+	}
+
+	retry, ok := queues["settlement.transaction.created.retry"]
+	if !ok {
+		t.Fatal("expected retry queue settlement.transaction.created.retry")
+	}
+	if retry["x-message-ttl"] != int64(5000) {
+		t.Fatalf("expected retry TTL 5000ms, got %v", retry["x-message-ttl"])
+	}
+	if retry["x-dead-letter-routing-key"] != "settlement.transaction.created" {
+		t.Fatalf("expected retry queue to route back to the main queue, got %v", retry["x-dead-letter-routing-key"])
+	}
+
+	if _, ok := queues["settlement.transaction.created.dlq"]; !ok {
+		t.Fatal("expected dead-letter queue settlement.transaction.created.dlq")
+	}
+	if len(topo.Exchanges) != 2 {
+		t.Fatalf("expected main exchange and DLX, got %d exchanges", len(topo.Exchanges))
+	}
+}
+
+func TestEventTopology_GivesEachConsumerItsOwnQueue(t *testing.T) {
+	topo := broker.EventTopology("tx.events", broker.RetryPolicy{Delay: time.Second, MaxAttempts: 3},
+		broker.Subscription{Consumer: "settlement", EventType: entity.EventTransactionCreated},
+		broker.Subscription{Consumer: "audit", EventType: entity.EventTransactionCreated},
+	)
+
+	bindings := map[broker.BindingSpec]bool{}
+	for _, b := range topo.Bindings {
+		bindings[b] = true
+	}
+
+	for _, want := range []broker.BindingSpec{
+		{Queue: "settlement.transaction.created", Exchange: "tx.events", RoutingKey: "transaction.created"},
+		{Queue: "audit.transaction.created", Exchange: "tx.events", RoutingKey: "transaction.created"},
+		{Queue: "settlement.transaction.created.dlq", Exchange: "tx.events.dlx", RoutingKey: "settlement.transaction.created"},
+		{Queue: "audit.transaction.created.dlq", Exchange: "tx.events.dlx", RoutingKey: "audit.transaction.created"},
+	} {
+		if !bindings[want] {
+			t.Fatalf("expected binding %+v, got %+v", want, topo.Bindings)
+		}
+	}
+	if len(topo.Bindings) != 4 || len(topo.Queues) != 6 {
+		t.Fatalf("expected a main, retry and dead-letter queue per consumer, got %d queues and %d bindings", len(topo.Queues), len(topo.Bindings))
+	}
+}
+
+func TestSubscriptions_MatchTheUsersService(t *testing.T) {
+	if got := broker.KnownUsersSubscription.Queue(); got != "userprojector.user.created" {
+		t.Fatalf("expected the queue users-service declares, got %q", got)
+	}
+	if got := broker.SettlementSubscription.Queue(); got != "settlement.transaction.created" {
+		t.Fatalf("expected settlement.transaction.created, got %q", got)
+	}
+}
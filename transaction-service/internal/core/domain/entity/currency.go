@@ -0,0 +1,110 @@
+package entity
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+)
+
+// DefaultCurrency is used when a request does not name a currency. Amounts
+// stored before currencies existed were backfilled with it.
+const DefaultCurrency = "USD"
+
+// FXClearingAccount is the ledger account that takes the sender's currency
+// and pays out the receiver's on a transfer between currencies, so the
+// postings of each currency still sum to zero. It never has an accounts row.
+const FXClearingAccount = "00000000-0000-0000-0000-00000000f0c0"
+
+var (
+	ErrUnsupportedCurrency      = errors.New("unsupported currency")
+	ErrConversionRateRequired   = errors.New("conversion_rate is required to transfer between currencies")
+	ErrUnexpectedConversionRate = errors.New("conversion_rate requires a to_currency different from currency")
+	ErrInvalidConversionRate    = errors.New("conversion_rate must be a positive decimal with at most 12 fractional digits")
+	ErrConvertedAmountInvalid   = errors.New("converted amount must be between one minor unit and the int64 range")
+)
+
+// minorUnits holds the ISO 4217 exponent of each supported currency: amounts
+// are integers in 10^-exponent of the major unit (cents for USD, yen for JPY).
+var minorUnits = map[string]int{
+	"AUD": 2,
+	"BHD": 3,
+	"BRL": 2,
+	"CAD": 2,
+	"CHF": 2,
+	"CLP": 0,
+	"CNY": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"HKD": 2,
+	"INR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"MXN": 2,
+	"NOK": 2,
+	"NZD": 2,
+	"SEK": 2,
+	"SGD": 2,
+	"USD": 2,
+	"ZAR": 2,
+}
+
+var rateFormat = regexp.MustCompile(`^[0-9]+(\.[0-9]{1,12})?$`)
+
+// MinorUnits returns the ISO 4217 exponent of currency.
+func MinorUnits(currency string) (int, error) {
+	exp, ok := minorUnits[currency]
+	if !ok {
+		return 0, ErrUnsupportedCurrency
+	}
+	return exp, nil
+}
+
+// Conversion records how a transfer between currencies was priced: the
+// receiver is credited Amount in Currency, which is the sender's amount at
+// Rate units of Currency per unit of the sender's currency.
+type Conversion struct {
+	Currency string `json:"currency"`
+	Rate     string `json:"rate"`
+	Amount   int64  `json:"amount"`
+}
+
+// Convert prices amount minor units of from in to at rate, rounding half up
+// to the nearest minor unit of to.
+func Convert(amount int64, from, to, rate string) (int64, error) {
+	fromExp, err := MinorUnits(from)
+	if err != nil {
+		return 0, err
+	}
+	toExp, err := MinorUnits(to)
+	if err != nil {
+		return 0, err
+	}
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || !rateFormat.MatchString(rate) || r.Sign() <= 0 {
+		return 0, ErrInvalidConversionRate
+	}
+
+	v := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), r)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(toExp-fromExp))), nil))
+	if toExp >= fromExp {
+		v.Mul(v, scale)
+	} else {
+		v.Quo(v, scale)
+	}
+
+	// Half up: floor(v + 1/2), v being positive.
+	v.Add(v, big.NewRat(1, 2))
+	rounded := new(big.Int).Quo(v.Num(), v.Denom())
+	if rounded.Sign() <= 0 || !rounded.IsInt64() {
+		return 0, ErrConvertedAmountInvalid
+	}
+	return rounded.Int64(), nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,127 @@
+package entity_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+func TestConvert_ScalesBetweenMinorUnits(t *testing.T) {
+	cases := []struct {
+		name     string
+		amount   int64
+		from, to string
+		rate     string
+		want     int64
+	}{
+		{"same exponent", 1000, "USD", "EUR", "0.9215", 922},
+		{"to zero decimals", 1000, "USD", "JPY", "151.237", 1512},
+		{"from zero decimals", 1512, "JPY", "USD", "0.006612", 1000},
+		{"to three decimals", 1000, "USD", "KWD", "0.3071", 3071},
+		{"rounds half up", 1, "USD", "EUR", "0.5", 1},
+		{"rounds to nothing", 1, "USD", "EUR", "0.4999", 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := entity.Convert(tc.amount, tc.from, tc.to, tc.rate)
+			if tc.want == 0 {
+				// Less than half a minor unit cannot be credited.
+				if !errors.Is(err, entity.ErrConvertedAmountInvalid) {
+					t.Fatalf("expected ErrConvertedAmountInvalid, got %d, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestConvert_RejectsInvalidInput(t *testing.T) {
+	cases := []struct {
+		name     string
+		from, to string
+		rate     string
+		want     error
+	}{
+		{"unknown source currency", "XXX", "EUR", "1", entity.ErrUnsupportedCurrency},
+		{"unknown target currency", "USD", "usd", "1", entity.ErrUnsupportedCurrency},
+		{"zero rate", "USD", "EUR", "0", entity.ErrInvalidConversionRate},
+		{"negative rate", "USD", "EUR", "-1", entity.ErrInvalidConversionRate},
+		{"fraction", "USD", "EUR", "1/3", entity.ErrInvalidConversionRate},
+		{"exponent", "USD", "EUR", "1e2", entity.ErrInvalidConversionRate},
+		{"too precise", "USD", "EUR", "0.1234567890123", entity.ErrInvalidConversionRate},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := entity.Convert(100, tc.from, tc.to, tc.rate); !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+
+	if _, err := entity.Convert(math.MaxInt64, "JPY", "KWD", "1000"); !errors.Is(err, entity.ErrConvertedAmountInvalid) {
+		t.Fatalf("expected overflow to be rejected, got %v", err)
+	}
+}
+
+func TestNewTransaction_RejectsUnsupportedCurrency(t *testing.T) {
+	if _, err := entity.NewTransaction("user-1", "user-2", 100, "XYZ", ""); !errors.Is(err, entity.ErrUnsupportedCurrency) {
+		t.Fatalf("expected ErrUnsupportedCurrency, got %v", err)
+	}
+}
+
+func TestConvertTo(t *testing.T) {
+	t.Run("records the conversion", func(t *testing.T) {
+		tx, _ := entity.NewTransaction("user-1", "user-2", 1000, "USD", "")
+		if err := tx.ConvertTo("EUR", "0.9215"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if amount, currency := tx.Credit(); amount != 922 || currency != "EUR" {
+			t.Fatalf("expected a credit of 922 EUR, got %d %s", amount, currency)
+		}
+		if tx.Conversion.Rate != "0.9215" {
+			t.Fatalf("expected the rate to be recorded, got %q", tx.Conversion.Rate)
+		}
+	})
+
+	t.Run("same currency is a plain transfer", func(t *testing.T) {
+		tx, _ := entity.NewTransaction("user-1", "user-2", 1000, "USD", "")
+		if err := tx.ConvertTo("USD", ""); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if amount, currency := tx.Credit(); tx.Conversion != nil || amount != 1000 || currency != "USD" {
+			t.Fatalf("expected a plain credit of 1000 USD, got %d %s", amount, currency)
+		}
+	})
+
+	cases := []struct {
+		name     string
+		currency string
+		rate     string
+		want     error
+	}{
+		{"missing rate", "EUR", "", entity.ErrConversionRateRequired},
+		{"rate without currency", "", "1.1", entity.ErrUnexpectedConversionRate},
+		{"rate for the same currency", "USD", "1.1", entity.ErrUnexpectedConversionRate},
+		{"unknown currency", "ABC", "1.1", entity.ErrUnsupportedCurrency},
+		{"invalid rate", "EUR", "abc", entity.ErrInvalidConversionRate},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx, _ := entity.NewTransaction("user-1", "user-2", 1000, "USD", "")
+			if err := tx.ConvertTo(tc.currency, tc.rate); !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+			if tx.Conversion != nil {
+				t.Fatal("expected no conversion to be recorded")
+			}
+		})
+	}
+}
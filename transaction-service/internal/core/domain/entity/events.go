@@ -0,0 +1,37 @@
+package entity
+
+// Outbox event types emitted by the transaction service.
+const (
+	EventTransactionCreated   = "TransactionCreated"
+	EventTransactionCompleted = "TransactionCompleted"
+	EventTransactionFailed    = "TransactionFailed"
+	EventTransactionReversed  = "TransactionReversed"
+
+	EventTransactionAuthorized = "TransactionAuthorized"
+	EventTransactionCaptured   = "TransactionCaptured"
+	EventTransactionVoided     = "TransactionVoided"
+	EventTransactionExpired    = "TransactionExpired"
+
+	// An operator's deposit into a user's account; see NewFunding.
+	EventAccountFunded = "AccountFunded"
+
+	// A scheduled transfer emits TransactionCreated when it is released.
+	EventTransactionScheduled = "TransactionScheduled"
+	EventTransactionCancelled = "TransactionCancelled"
+
+	// A held transfer emits the event of the status it is approved into.
+	EventTransactionHeld     = "TransactionHeld"
+	EventTransactionRejected = "TransactionRejected"
+
+	// Recurring transfer mandates. Each run emits MandateExecuted with the
+	// transaction it created, or MandateFailed when none could be.
+	EventMandateCreated   = "MandateCreated"
+	EventMandateUpdated   = "MandateUpdated"
+	EventMandateCancelled = "MandateCancelled"
+	EventMandateExecuted  = "MandateExecuted"
+	EventMandateFailed    = "MandateFailed"
+)
+
+// EventUserCreated is published by users-service and consumed by the known
+// users projector.
+const EventUserCreated = "UserCreated"
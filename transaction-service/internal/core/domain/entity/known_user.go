@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// KnownUser is the local projection of a user registered in users-service,
+// built from its UserCreated events.
+type KnownUser struct {
+	UserID        string
+	SourceEventID string
+	RegisteredAt  time.Time
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrUnbalancedPostings = errors.New("ledger postings do not sum to zero")
+
+// LedgerEntry is one posting of a settled transaction. Debits are negative
+// and credits positive, so the postings of a transaction sum to zero.
+type LedgerEntry struct {
+	ID            string
+	TransactionID string
+	UserID        string
+	Currency      string
+	Amount        int64
+	CreatedAt     time.Time
+}
+
+// LedgerImbalance describes a transaction that breaks the ledger invariants:
+// its postings in Currency do not sum to zero, or its status and postings
+// disagree.
+type LedgerImbalance struct {
+	TransactionID string            `json:"transaction_id"`
+	Status        TransactionStatus `json:"status"`
+	Currency      string            `json:"currency"`
+	Sum           int64             `json:"sum"`
+	Entries       int               `json:"entries"`
+}
+
+// BalanceDrift is a user whose projected balance in Currency differs from
+// the sum of their postings in it.
+type BalanceDrift struct {
+	UserID    string `json:"user_id"`
+	Currency  string `json:"currency"`
+	Projected int64  `json:"projected"`
+	Computed  int64  `json:"computed"`
+}
+
+// NewPostings returns the debit of the sender and the credit of the receiver
+// for t, stamped with its settlement time. A transfer between currencies
+// goes through FXClearingAccount, which is credited in the sender's currency
+// and debited in the receiver's.
+func NewPostings(t *Transaction) []*LedgerEntry {
+	at := t.CreatedAt
+	if t.ProcessedAt != nil {
+		at = *t.ProcessedAt
+	}
+	posting := func(userID, currency string, amount int64) *LedgerEntry {
+		return &LedgerEntry{
+			ID: uuid.NewString(), TransactionID: t.ID, UserID: userID,
+			Currency: currency, Amount: amount, CreatedAt: at,
+		}
+	}
+
+	credit, creditCurrency := t.Credit()
+	if t.Conversion == nil {
+		return []*LedgerEntry{
+			posting(t.FromUserID, t.Currency, -t.Amount),
+			posting(t.ToUserID, t.Currency, t.Amount),
+		}
+	}
+	return []*LedgerEntry{
+		posting(t.FromUserID, t.Currency, -t.Amount),
+		posting(FXClearingAccount, t.Currency, t.Amount),
+		posting(FXClearingAccount, creditCurrency, -credit),
+		posting(t.ToUserID, creditCurrency, credit),
+	}
+}
+
+// CheckBalanced returns ErrUnbalancedPostings unless entries sum to zero in
+// every currency.
+func CheckBalanced(entries []*LedgerEntry) error {
+	sums := make(map[string]int64)
+	for _, e := range entries {
+		sums[e.Currency] += e.Amount
+	}
+	for _, sum := range sums {
+		if sum != 0 {
+			return ErrUnbalancedPostings
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+func TestNewPostings_DebitsSenderAndCreditsReceiver(t *testing.T) {
+	tx, _ := entity.NewTransaction("user-1", "user-2", 500, "USD", "")
+	settledAt := time.Now().UTC()
+	_ = tx.Complete(settledAt)
+
+	postings := entity.NewPostings(tx)
+
+	if len(postings) != 2 {
+		t.Fatalf("expected 2 postings, got %d", len(postings))
+	}
+	debit, credit := postings[0], postings[1]
+	if debit.UserID != "user-1" || debit.Amount != -500 {
+		t.Fatalf("expected debit of -500 for user-1, got %d for %s", debit.Amount, debit.UserID)
+	}
+	if credit.UserID != "user-2" || credit.Amount != 500 {
+		t.Fatalf("expected credit of 500 for user-2, got %d for %s", credit.Amount, credit.UserID)
+	}
+	for _, p := range postings {
+		if p.TransactionID != tx.ID {
+			t.Fatalf("expected transaction id %s, got %s", tx.ID, p.TransactionID)
+		}
+		if !p.CreatedAt.Equal(settledAt) {
+			t.Fatalf("expected postings stamped with settlement time, got %v", p.CreatedAt)
+		}
+	}
+	if postings[0].ID == postings[1].ID {
+		t.Fatal("expected distinct posting IDs")
+	}
+	if err := entity.CheckBalanced(postings); err != nil {
+		t.Fatalf("expected balanced postings, got %v", err)
+	}
+}
+
+func TestNewPostings_BetweenCurrenciesGoesThroughClearing(t *testing.T) {
+	tx, _ := entity.NewTransaction("user-1", "user-2", 1000, "USD", "")
+	_ = tx.ConvertTo("EUR", "0.9215")
+	_ = tx.Complete(time.Now().UTC())
+
+	postings := entity.NewPostings(tx)
+
+	want := []struct {
+		user     string
+		currency string
+		amount   int64
+	}{
+		{"user-1", "USD", -1000},
+		{entity.FXClearingAccount, "USD", 1000},
+		{entity.FXClearingAccount, "EUR", -922},
+		{"user-2", "EUR", 922},
+	}
+	if len(postings) != len(want) {
+		t.Fatalf("expected %d postings, got %d", len(want), len(postings))
+	}
+	for i, w := range want {
+		p := postings[i]
+		if p.UserID != w.user || p.Currency != w.currency || p.Amount != w.amount {
+			t.Fatalf("posting %d: expected %d %s for %s, got %d %s for %s",
+				i, w.amount, w.currency, w.user, p.Amount, p.Currency, p.UserID)
+		}
+	}
+	if err := entity.CheckBalanced(postings); err != nil {
+		t.Fatalf("expected balanced postings, got %v", err)
+	}
+}
+
+func TestCheckBalanced_RejectsSumAcrossCurrencies(t *testing.T) {
+	postings := []*entity.LedgerEntry{
+		{UserID: "user-1", Currency: "USD", Amount: -500},
+		{UserID: "user-2", Currency: "EUR", Amount: 500},
+	}
+
+	if err := entity.CheckBalanced(postings); !errors.Is(err, entity.ErrUnbalancedPostings) {
+		t.Fatalf("expected ErrUnbalancedPostings, got %v", err)
+	}
+}
+
+func TestCheckBalanced_RejectsNonZeroSum(t *testing.T) {
+	postings := []*entity.LedgerEntry{
+		{UserID: "user-1", Amount: -500},
+		{UserID: "user-2", Amount: 400},
+	}
+
+	if err := entity.CheckBalanced(postings); !errors.Is(err, entity.ErrUnbalancedPostings) {
+		t.Fatalf("expected ErrUnbalancedPostings, got %v", err)
+	}
+}
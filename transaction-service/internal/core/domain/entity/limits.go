@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Limit names, as stored in transaction_limits and reported when exceeded.
+const (
+	LimitMaxAmount       = "max_amount"
+	LimitDailyOutgoing   = "daily_outgoing"
+	LimitMonthlyOutgoing = "monthly_outgoing"
+	LimitHourlyTransfers = "hourly_transfers"
+)
+
+// Limits caps what a sender may send in one currency. Nil fields are not
+// limited. Daily and monthly totals run over the UTC calendar day and month,
+// the transfer count over the last hour.
+type Limits struct {
+	MaxAmount       *int64
+	DailyOutgoing   *int64
+	MonthlyOutgoing *int64
+	HourlyTransfers *int64
+}
+
+// LimitUsage is what a sender has already sent in a currency within each
+// window of Limits.
+type LimitUsage struct {
+	Daily           int64
+	Monthly         int64
+	HourlyTransfers int64
+}
+
+// LimitExceededError names the limit a transfer would exceed.
+type LimitExceededError struct {
+	Limit string
+	Max   int64
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("transfer exceeds the %s limit of %d", e.Limit, e.Max)
+}
+
+// LimitWindows returns the start of the hourly, daily and monthly windows
+// that end at at.
+func LimitWindows(at time.Time) (hour, day, month time.Time) {
+	at = at.UTC()
+	y, m, d := at.Date()
+	return at.Add(-time.Hour), time.Date(y, m, d, 0, 0, 0, 0, time.UTC), time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Check reports the first limit a transfer of amount would exceed on top of
+// used, as a *LimitExceededError.
+func (l Limits) Check(amount int64, used LimitUsage) error {
+	switch {
+	case l.MaxAmount != nil && amount > *l.MaxAmount:
+		return &LimitExceededError{Limit: LimitMaxAmount, Max: *l.MaxAmount}
+	case l.DailyOutgoing != nil && used.Daily+amount > *l.DailyOutgoing:
+		return &LimitExceededError{Limit: LimitDailyOutgoing, Max: *l.DailyOutgoing}
+	case l.MonthlyOutgoing != nil && used.Monthly+amount > *l.MonthlyOutgoing:
+		return &LimitExceededError{Limit: LimitMonthlyOutgoing, Max: *l.MonthlyOutgoing}
+	case l.HourlyTransfers != nil && used.HourlyTransfers+1 > *l.HourlyTransfers:
+		return &LimitExceededError{Limit: LimitHourlyTransfers, Max: *l.HourlyTransfers}
+	}
+	return nil
+}
+
+// Add counts a transfer of amount into u.
+func (u *LimitUsage) Add(amount int64) {
+	u.Daily += amount
+	u.Monthly += amount
+	u.HourlyTransfers++
+}
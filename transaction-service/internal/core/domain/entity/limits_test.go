@@ -0,0 +1,84 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+func limit(v int64) *int64 { return &v }
+
+func TestLimits_Check(t *testing.T) {
+	limits := entity.Limits{
+		MaxAmount:       limit(1000),
+		DailyOutgoing:   limit(2000),
+		MonthlyOutgoing: limit(5000),
+		HourlyTransfers: limit(3),
+	}
+	cases := []struct {
+		name   string
+		amount int64
+		used   entity.LimitUsage
+		want   string
+	}{
+		{"within every limit", 1000, entity.LimitUsage{Daily: 1000, Monthly: 4000, HourlyTransfers: 2}, ""},
+		{"single amount", 1001, entity.LimitUsage{}, entity.LimitMaxAmount},
+		{"daily total", 500, entity.LimitUsage{Daily: 1600, Monthly: 1600}, entity.LimitDailyOutgoing},
+		{"monthly total", 500, entity.LimitUsage{Daily: 0, Monthly: 4600}, entity.LimitMonthlyOutgoing},
+		{"hourly count", 1, entity.LimitUsage{Daily: 3, Monthly: 3, HourlyTransfers: 3}, entity.LimitHourlyTransfers},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := limits.Check(c.amount, c.used)
+			if c.want == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got: %v", err)
+				}
+				return
+			}
+			var exceeded *entity.LimitExceededError
+			if !errors.As(err, &exceeded) || exceeded.Limit != c.want {
+				t.Fatalf("expected the %s limit, got: %v", c.want, err)
+			}
+		})
+	}
+}
+
+func TestLimits_UnsetLimitsNeverApply(t *testing.T) {
+	if err := (entity.Limits{}).Check(1<<40, entity.LimitUsage{Daily: 1 << 40, HourlyTransfers: 1 << 20}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestLimitUsage_Add(t *testing.T) {
+	used := entity.LimitUsage{Daily: 100, Monthly: 300, HourlyTransfers: 1}
+
+	used.Add(50)
+
+	if used != (entity.LimitUsage{Daily: 150, Monthly: 350, HourlyTransfers: 2}) {
+		t.Fatalf("unexpected usage: %+v", used)
+	}
+}
+
+func TestLimitWindows(t *testing.T) {
+	at := time.Date(2026, time.March, 1, 0, 30, 0, 0, time.UTC)
+
+	hour, day, month := entity.LimitWindows(at)
+
+	if !hour.Equal(time.Date(2026, time.February, 28, 23, 30, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected hourly window start: %v", hour)
+	}
+	if !day.Equal(time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)) || !month.Equal(day) {
+		t.Fatalf("unexpected daily/monthly window start: %v / %v", day, month)
+	}
+}
+
+func TestLimitExceededError_NamesTheLimit(t *testing.T) {
+	err := &entity.LimitExceededError{Limit: entity.LimitDailyOutgoing, Max: 2000}
+
+	if err.Error() != "transfer exceeds the daily_outgoing limit of 2000" {
+		t.Fatalf("unexpected message: %q", err.Error())
+	}
+}
@@ -0,0 +1,207 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MandateStatus string
+
+const (
+	MandateActive    MandateStatus = "ACTIVE"
+	MandateCancelled MandateStatus = "CANCELLED"
+	// MandateEnded is reached when the next occurrence would fall after
+	// EndAt.
+	MandateEnded MandateStatus = "ENDED"
+)
+
+// MinScheduleInterval is the shortest "@every" interval a mandate accepts.
+const MinScheduleInterval = time.Hour
+
+var (
+	ErrInvalidSchedule     = errors.New(`schedule must be @daily, @weekly, @monthly, @yearly or "@every <duration>"`)
+	ErrEndBeforeStart      = errors.New("end_at must not be before start_at")
+	ErrMandateNotActive    = errors.New("mandate is not active")
+	ErrMandateChanged      = errors.New("mandate was changed concurrently")
+	ErrScheduleTooFrequent = fmt.Errorf("@every interval must be at least %s", MinScheduleInterval)
+)
+
+// Schedule is when a mandate recurs. It is parsed from a cron-style
+// descriptor and measured from the mandate's start, so a monthly mandate
+// started on the 31st runs on the last day of shorter months without
+// drifting.
+type Schedule struct {
+	months int
+	days   int
+	every  time.Duration
+}
+
+func ParseSchedule(spec string) (Schedule, error) {
+	switch spec {
+	case "@daily":
+		return Schedule{days: 1}, nil
+	case "@weekly":
+		return Schedule{days: 7}, nil
+	case "@monthly":
+		return Schedule{months: 1}, nil
+	case "@yearly":
+		return Schedule{months: 12}, nil
+	}
+
+	rest, ok := strings.CutPrefix(spec, "@every ")
+	if !ok {
+		return Schedule{}, ErrInvalidSchedule
+	}
+	every, err := time.ParseDuration(strings.TrimSpace(rest))
+	if err != nil {
+		return Schedule{}, ErrInvalidSchedule
+	}
+	if every < MinScheduleInterval {
+		return Schedule{}, ErrScheduleTooFrequent
+	}
+	return Schedule{every: every}, nil
+}
+
+// Occurrence returns when the n-th run, counting from 0, of a schedule
+// starting at start is due.
+func (s Schedule) Occurrence(start time.Time, n int64) time.Time {
+	switch {
+	case s.months > 0:
+		return addMonths(start, s.months*int(n))
+	case s.days > 0:
+		return start.AddDate(0, 0, s.days*int(n))
+	default:
+		return start.Add(s.every * time.Duration(n))
+	}
+}
+
+// addMonths adds months to t, clamping the day to the end of the target
+// month instead of overflowing into the next one.
+func addMonths(t time.Time, months int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(d, last)-1)
+}
+
+// Mandate is a standing instruction to transfer Amount from FromUserID to
+// ToUserID on every occurrence of Schedule from StartAt until EndAt.
+// Occurrence counts the runs done so far and NextRunAt is when the next one
+// is due. Version changes on every write.
+type Mandate struct {
+	ID          string
+	FromUserID  string
+	ToUserID    string
+	Amount      int64
+	Currency    string
+	Description string
+	Schedule    string
+	StartAt     time.Time
+	EndAt       *time.Time
+	NextRunAt   time.Time
+	Occurrence  int64
+	Status      MandateStatus
+	Version     int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func NewMandate(fromUserID, toUserID string, amount int64, currency, description, schedule string, startAt time.Time, endAt *time.Time) (*Mandate, error) {
+	if err := validateTransfer(fromUserID, toUserID, amount, currency); err != nil {
+		return nil, err
+	}
+	if _, err := ParseSchedule(schedule); err != nil {
+		return nil, err
+	}
+	if endAt != nil && endAt.Before(startAt) {
+		return nil, ErrEndBeforeStart
+	}
+
+	now := time.Now().UTC()
+	return &Mandate{
+		ID:          uuid.NewString(),
+		FromUserID:  fromUserID,
+		ToUserID:    toUserID,
+		Amount:      amount,
+		Currency:    currency,
+		Description: description,
+		Schedule:    schedule,
+		StartAt:     startAt,
+		EndAt:       endAt,
+		NextRunAt:   startAt,
+		Status:      MandateActive,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// OccurrenceKey is the idempotency key of the mandate's next run. It is the
+// same every time that run is attempted, so a retry never pays twice.
+func (m *Mandate) OccurrenceKey() string {
+	return fmt.Sprintf("mandate-%s-%d", m.ID, m.Occurrence)
+}
+
+// Advance moves an ACTIVE mandate past its due run. It ends once the next
+// occurrence would fall after EndAt.
+func (m *Mandate) Advance(at time.Time) error {
+	if m.Status != MandateActive {
+		return ErrMandateNotActive
+	}
+	schedule, err := ParseSchedule(m.Schedule)
+	if err != nil {
+		return err
+	}
+	m.Occurrence++
+	m.NextRunAt = schedule.Occurrence(m.StartAt, m.Occurrence)
+	m.endIfPast()
+	m.UpdatedAt = at
+	return nil
+}
+
+// Amend changes the terms of an ACTIVE mandate for its future runs. Nil
+// arguments are left as they are. Moving EndAt before the next run ends the
+// mandate.
+func (m *Mandate) Amend(amount *int64, description *string, endAt *time.Time, at time.Time) error {
+	if m.Status != MandateActive {
+		return ErrMandateNotActive
+	}
+	if amount != nil && *amount <= 0 {
+		return ErrAmountMustBePositive
+	}
+	if endAt != nil && endAt.Before(m.StartAt) {
+		return ErrEndBeforeStart
+	}
+
+	if amount != nil {
+		m.Amount = *amount
+	}
+	if description != nil {
+		m.Description = *description
+	}
+	if endAt != nil {
+		m.EndAt = endAt
+		m.endIfPast()
+	}
+	m.UpdatedAt = at
+	return nil
+}
+
+// Cancel stops an ACTIVE mandate before its next run.
+func (m *Mandate) Cancel(at time.Time) error {
+	if m.Status != MandateActive {
+		return ErrMandateNotActive
+	}
+	m.Status = MandateCancelled
+	m.UpdatedAt = at
+	return nil
+}
+
+func (m *Mandate) endIfPast() {
+	if m.EndAt != nil && m.NextRunAt.After(*m.EndAt) {
+		m.Status = MandateEnded
+	}
+}
@@ -0,0 +1,172 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+func newMandate(t *testing.T, schedule string, startAt time.Time, endAt *time.Time) *entity.Mandate {
+	t.Helper()
+	m, err := entity.NewMandate("user-1", "user-2", 500, "USD", "rent", schedule, startAt, endAt)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return m
+}
+
+func TestParseSchedule(t *testing.T) {
+	start := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		spec string
+		n    int64
+		want time.Time
+	}{
+		{"@daily", 3, time.Date(2026, 2, 3, 9, 0, 0, 0, time.UTC)},
+		{"@weekly", 2, time.Date(2026, 2, 14, 9, 0, 0, 0, time.UTC)},
+		{"@monthly", 1, time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC)},
+		{"@monthly", 2, time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC)},
+		{"@yearly", 1, time.Date(2027, 1, 31, 9, 0, 0, 0, time.UTC)},
+		{"@every 36h", 2, time.Date(2026, 2, 3, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		s, err := entity.ParseSchedule(tc.spec)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got: %v", tc.spec, err)
+		}
+		if got := s.Occurrence(start, tc.n); !got.Equal(tc.want) {
+			t.Fatalf("%s occurrence %d: expected %v, got %v", tc.spec, tc.n, tc.want, got)
+		}
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for spec, want := range map[string]error{
+		"":             entity.ErrInvalidSchedule,
+		"0 9 * * *":    entity.ErrInvalidSchedule,
+		"@every often": entity.ErrInvalidSchedule,
+		"@every 30m":   entity.ErrScheduleTooFrequent,
+	} {
+		if _, err := entity.ParseSchedule(spec); err != want {
+			t.Fatalf("%q: expected %v, got %v", spec, want, err)
+		}
+	}
+}
+
+func TestNewMandate(t *testing.T) {
+	start := time.Now().UTC().Add(time.Hour)
+	m := newMandate(t, "@monthly", start, nil)
+
+	if m.ID == "" || m.Status != entity.MandateActive || !m.NextRunAt.Equal(start) || m.Occurrence != 0 {
+		t.Fatalf("expected an ACTIVE mandate due at its start, got %+v", m)
+	}
+
+	before := start.Add(-time.Minute)
+	cases := map[string]func() error{
+		"transfer": func() error {
+			_, err := entity.NewMandate("user-1", "user-1", 500, "USD", "", "@daily", start, nil)
+			return err
+		},
+		"schedule": func() error {
+			_, err := entity.NewMandate("user-1", "user-2", 500, "USD", "", "hourly", start, nil)
+			return err
+		},
+		"end": func() error {
+			_, err := entity.NewMandate("user-1", "user-2", 500, "USD", "", "@daily", start, &before)
+			return err
+		},
+	}
+	for name, create := range cases {
+		if err := create(); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMandate_OccurrenceKeyIsStablePerRun(t *testing.T) {
+	m := newMandate(t, "@daily", time.Now().UTC(), nil)
+	first := m.OccurrenceKey()
+
+	if m.OccurrenceKey() != first {
+		t.Fatal("expected the same key for the same run")
+	}
+	if err := m.Advance(time.Now().UTC()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if m.OccurrenceKey() == first {
+		t.Fatal("expected a new key for the next run")
+	}
+}
+
+func TestMandate_AdvanceEndsAfterEndAt(t *testing.T) {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+	m := newMandate(t, "@daily", start, &end)
+
+	if err := m.Advance(start); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if m.Status != entity.MandateActive || m.Occurrence != 1 || !m.NextRunAt.Equal(end) {
+		t.Fatalf("expected an ACTIVE mandate due on its end date, got %+v", m)
+	}
+
+	if err := m.Advance(end); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if m.Status != entity.MandateEnded {
+		t.Fatalf("expected ENDED, got %s", m.Status)
+	}
+	if err := m.Advance(end); err != entity.ErrMandateNotActive {
+		t.Fatalf("expected ErrMandateNotActive, got %v", err)
+	}
+}
+
+func TestMandate_Amend(t *testing.T) {
+	start := time.Now().UTC().Add(time.Hour)
+	m := newMandate(t, "@weekly", start, nil)
+	amount, description := int64(750), "new rent"
+
+	if err := m.Amend(&amount, &description, nil, start); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if m.Amount != 750 || m.Description != "new rent" || m.EndAt != nil || m.Status != entity.MandateActive {
+		t.Fatalf("unexpected mandate after amend: %+v", m)
+	}
+
+	zero := int64(0)
+	if err := m.Amend(&zero, nil, nil, start); err != entity.ErrAmountMustBePositive {
+		t.Fatalf("expected ErrAmountMustBePositive, got %v", err)
+	}
+	beforeStart := start.Add(-time.Hour)
+	if err := m.Amend(nil, nil, &beforeStart, start); err != entity.ErrEndBeforeStart {
+		t.Fatalf("expected ErrEndBeforeStart, got %v", err)
+	}
+
+	// An end date before the next run ends the mandate straight away.
+	_ = m.Advance(start)
+	if err := m.Amend(nil, nil, &start, start); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if m.Status != entity.MandateEnded {
+		t.Fatalf("expected ENDED, got %s", m.Status)
+	}
+	if err := m.Amend(&amount, nil, nil, start); err != entity.ErrMandateNotActive {
+		t.Fatalf("expected ErrMandateNotActive, got %v", err)
+	}
+}
+
+func TestMandate_Cancel(t *testing.T) {
+	m := newMandate(t, "@daily", time.Now().UTC(), nil)
+	at := time.Now().UTC()
+
+	if err := m.Cancel(at); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if m.Status != entity.MandateCancelled || !m.UpdatedAt.Equal(at) {
+		t.Fatalf("expected CANCELLED at %v, got %+v", at, m)
+	}
+	if err := m.Cancel(at); err != entity.ErrMandateNotActive {
+		t.Fatalf("expected ErrMandateNotActive, got %v", err)
+	}
+}
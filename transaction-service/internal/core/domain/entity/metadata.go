@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Limits on the metadata a client can attach to a transaction, so it stays
+// a handful of references rather than a document store.
+const (
+	MaxMetadataKeys        = 20
+	MaxMetadataKeyLength   = 40
+	MaxMetadataValueLength = 500
+)
+
+var ErrMetadataKeyRequired = errors.New("metadata keys must not be empty")
+
+// ValidateMetadata checks metadata against the key count and the key and
+// value lengths, counted in characters.
+func ValidateMetadata(metadata map[string]string) error {
+	if len(metadata) > MaxMetadataKeys {
+		return fmt.Errorf("metadata must have at most %d keys", MaxMetadataKeys)
+	}
+	for k, v := range metadata {
+		if k == "" {
+			return ErrMetadataKeyRequired
+		}
+		if utf8.RuneCountInString(k) > MaxMetadataKeyLength {
+			return fmt.Errorf("metadata key %q exceeds %d characters", k, MaxMetadataKeyLength)
+		}
+		if utf8.RuneCountInString(v) > MaxMetadataValueLength {
+			return fmt.Errorf("metadata value of %q exceeds %d characters", k, MaxMetadataValueLength)
+		}
+	}
+	return nil
+}
+
+// Annotate attaches the client's metadata to a new transaction. Empty
+// metadata leaves none.
+func (t *Transaction) Annotate(metadata map[string]string) error {
+	if err := ValidateMetadata(metadata); err != nil {
+		return err
+	}
+	if len(metadata) == 0 {
+		t.Metadata = nil
+		return nil
+	}
+	t.Metadata = make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		t.Metadata[k] = v
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package entity_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+func TestTransaction_Annotate(t *testing.T) {
+	tx, _ := entity.NewTransaction("user-1", "user-2", 1000, "USD", "")
+	metadata := map[string]string{"order_id": "ord-42", "invoice": strings.Repeat("é", entity.MaxMetadataValueLength)}
+
+	if err := tx.Annotate(metadata); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	metadata["order_id"] = "changed"
+	if tx.Metadata["order_id"] != "ord-42" || len(tx.Metadata) != 2 {
+		t.Fatalf("expected a copy of the metadata, got %v", tx.Metadata)
+	}
+
+	if err := tx.Annotate(map[string]string{}); err != nil || tx.Metadata != nil {
+		t.Fatalf("expected empty metadata to leave none, got %v (%v)", tx.Metadata, err)
+	}
+}
+
+func TestValidateMetadata_Limits(t *testing.T) {
+	tooMany := make(map[string]string)
+	for i := 0; i <= entity.MaxMetadataKeys; i++ {
+		tooMany[fmt.Sprintf("key-%d", i)] = "v"
+	}
+
+	cases := map[string]map[string]string{
+		"too many keys": tooMany,
+		"empty key":     {"": "v"},
+		"long key":      {strings.Repeat("k", entity.MaxMetadataKeyLength+1): "v"},
+		"long value":    {"note": strings.Repeat("v", entity.MaxMetadataValueLength+1)},
+	}
+	for name, metadata := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := entity.ValidateMetadata(metadata); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+	if err := entity.ValidateMetadata(map[string]string{"": "v"}); !errors.Is(err, entity.ErrMetadataKeyRequired) {
+		t.Fatalf("expected ErrMetadataKeyRequired, got %v", err)
+	}
+}
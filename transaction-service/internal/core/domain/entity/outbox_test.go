@@ -7,7 +7,7 @@ import (
 )
 
 func TestNewOutbox_DefaultFields(t *testing.T) {
-	outbox := entity.NewOutbox("TransactionCreated", `{"id":"tx-1"}`)
+	outbox := entity.NewOutbox("TransactionCreated", "tx-1", `{"id":"tx-1"}`)
 
 	if outbox.ID == "" {
 		t.Fatal("expected non-empty ID")
@@ -15,6 +15,9 @@ func TestNewOutbox_DefaultFields(t *testing.T) {
 	if outbox.Type != "TransactionCreated" {
 		t.Fatalf("expected type 'TransactionCreated', got '%s'", outbox.Type)
 	}
+	if outbox.AggregateID != "tx-1" {
+		t.Fatalf("expected aggregate ID 'tx-1', got '%s'", outbox.AggregateID)
+	}
 	if outbox.Payload != `{"id":"tx-1"}` {
 		t.Fatalf("unexpected payload: %s", outbox.Payload)
 	}
@@ -30,8 +33,8 @@ func TestNewOutbox_DefaultFields(t *testing.T) {
 }
 
 func TestNewOutbox_UniqueIDs(t *testing.T) {
-	o1 := entity.NewOutbox("EventA", "{}")
-	o2 := entity.NewOutbox("EventA", "{}")
+	o1 := entity.NewOutbox("EventA", "tx-1", "{}")
+	o2 := entity.NewOutbox("EventA", "tx-1", "{}")
 
 	if o1.ID == o2.ID {
 		t.Fatal("expected unique IDs for each outbox event")
@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+// Kinds of discrepancy the outbox reconciliation reports.
+const (
+	// DiscrepancyMissingEvent is a transaction without any outbox event.
+	DiscrepancyMissingEvent = "missing_event"
+	// DiscrepancyOrphanEvent is an event whose aggregate does not exist.
+	DiscrepancyOrphanEvent = "orphan_event"
+	// DiscrepancyStuckPending, DiscrepancyStuckProcessing and
+	// DiscrepancyStuckFailed are events left in that status past its
+	// threshold.
+	DiscrepancyStuckPending    = "stuck_pending"
+	DiscrepancyStuckProcessing = "stuck_processing"
+	DiscrepancyStuckFailed     = "stuck_failed"
+)
+
+// Discrepancy is one row that breaks the outbox invariants. Event fields are
+// empty on a missing event; Since is when the row was created.
+type Discrepancy struct {
+	Kind        string    `json:"kind"`
+	AggregateID string    `json:"aggregate_id,omitempty"`
+	EventID     string    `json:"event_id,omitempty"`
+	EventType   string    `json:"event_type,omitempty"`
+	Status      string    `json:"status"`
+	Since       time.Time `json:"since"`
+}
+
+// DiscrepancySample is the total number of discrepancies of one kind and
+// the oldest of them.
+type DiscrepancySample struct {
+	Count int64
+	Items []Discrepancy
+}
+
+// ReconciliationReport is the outcome of one reconciliation run: a count
+// per kind, including zeros, and a bounded sample of the discrepancies.
+type ReconciliationReport struct {
+	CheckedAt     time.Time        `json:"checked_at"`
+	Counts        map[string]int64 `json:"counts"`
+	Discrepancies []Discrepancy    `json:"discrepancies"`
+}
+
+// Total is the number of discrepancies of every kind.
+func (r *ReconciliationReport) Total() int64 {
+	var total int64
+	for _, n := range r.Counts {
+		total += n
+	}
+	return total
+}
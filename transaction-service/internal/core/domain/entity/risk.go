@@ -0,0 +1,73 @@
+package entity
+
+import "time"
+
+type RiskDecision string
+
+const (
+	RiskAllow RiskDecision = "ALLOW"
+	// RiskReview holds the transfer for a reviewer to approve or reject.
+	RiskReview RiskDecision = "REVIEW"
+	RiskDeny   RiskDecision = "DENY"
+)
+
+// RiskAssessment is the decision a RiskEvaluator reached on a transfer
+// before it was persisted, with the reasons of the rules that fired.
+type RiskAssessment struct {
+	Decision RiskDecision
+	Reasons  []string
+}
+
+// Stricter reports whether d decides more strictly than o: DENY over REVIEW
+// over ALLOW.
+func (d RiskDecision) Stricter(o RiskDecision) bool {
+	return riskSeverity[d] > riskSeverity[o]
+}
+
+var riskSeverity = map[RiskDecision]int{RiskAllow: 0, RiskReview: 1, RiskDeny: 2}
+
+// Assess records the risk decision on a new transaction. REVIEW makes it
+// HELD and DENY makes it REJECTED; neither moves funds.
+func (t *Transaction) Assess(assessment RiskAssessment) error {
+	if t.ProcessedAt != nil || (t.Status != StatusPending && t.Status != StatusAuthorized && t.Status != StatusScheduled) {
+		return ErrInvalidStatusTransition
+	}
+	t.Risk = &assessment
+	switch assessment.Decision {
+	case RiskReview:
+		t.Status = StatusHeld
+	case RiskDeny:
+		t.Status = StatusRejected
+		now := t.CreatedAt
+		t.ProcessedAt = &now
+	}
+	return nil
+}
+
+// Approve releases a HELD transaction into the status it would have been
+// created with: SCHEDULED when it has an ExecuteAt, AUTHORIZED for a hold
+// and PENDING otherwise.
+func (t *Transaction) Approve() error {
+	if t.Status != StatusHeld {
+		return ErrInvalidStatusTransition
+	}
+	switch {
+	case t.ExecuteAt != nil:
+		t.Status = StatusScheduled
+	case t.AuthorizedAmount != nil:
+		t.Status = StatusAuthorized
+	default:
+		t.Status = StatusPending
+	}
+	return nil
+}
+
+// Reject turns a HELD transaction down.
+func (t *Transaction) Reject(at time.Time) error {
+	if t.Status != StatusHeld {
+		return ErrInvalidStatusTransition
+	}
+	t.Status = StatusRejected
+	t.ProcessedAt = &at
+	return nil
+}
@@ -0,0 +1,107 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+func TestRiskDecision_Stricter(t *testing.T) {
+	if !entity.RiskDeny.Stricter(entity.RiskReview) || !entity.RiskReview.Stricter(entity.RiskAllow) {
+		t.Fatal("expected DENY over REVIEW over ALLOW")
+	}
+	if entity.RiskReview.Stricter(entity.RiskReview) || entity.RiskAllow.Stricter(entity.RiskDeny) {
+		t.Fatal("expected a decision not to be stricter than itself or a stricter one")
+	}
+}
+
+func TestTransaction_Assess(t *testing.T) {
+	cases := []struct {
+		decision entity.RiskDecision
+		want     entity.TransactionStatus
+	}{
+		{entity.RiskAllow, entity.StatusPending},
+		{entity.RiskReview, entity.StatusHeld},
+		{entity.RiskDeny, entity.StatusRejected},
+	}
+	for _, c := range cases {
+		t.Run(string(c.decision), func(t *testing.T) {
+			tx, _ := entity.NewTransaction("user-1", "user-2", 1000, "USD", "")
+			assessment := entity.RiskAssessment{Decision: c.decision, Reasons: []string{"rule"}}
+
+			if err := tx.Assess(assessment); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if tx.Status != c.want {
+				t.Fatalf("expected status %s, got %s", c.want, tx.Status)
+			}
+			if tx.Risk == nil || tx.Risk.Decision != c.decision {
+				t.Fatalf("expected the assessment to be recorded, got %+v", tx.Risk)
+			}
+			if (tx.ProcessedAt != nil) != (c.want == entity.StatusRejected) {
+				t.Fatalf("expected only a REJECTED transfer to be processed, got %v", tx.ProcessedAt)
+			}
+		})
+	}
+}
+
+func TestTransaction_AssessRejectsProcessedTransactions(t *testing.T) {
+	tx, _ := entity.NewTransaction("user-1", "user-2", 1000, "USD", "")
+	_ = tx.Complete(time.Now())
+
+	if err := tx.Assess(entity.RiskAssessment{Decision: entity.RiskReview}); !errors.Is(err, entity.ErrInvalidStatusTransition) {
+		t.Fatalf("expected ErrInvalidStatusTransition, got: %v", err)
+	}
+}
+
+func TestTransaction_Approve(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	executeAt := time.Now().Add(2 * time.Hour)
+	hold, _ := entity.NewAuthorization("user-1", "user-2", 1000, "USD", "", expiresAt)
+	scheduled, _ := entity.NewTransaction("user-1", "user-2", 1000, "USD", "")
+	_ = scheduled.Schedule(executeAt)
+	transfer, _ := entity.NewTransaction("user-1", "user-2", 1000, "USD", "")
+
+	cases := []struct {
+		name string
+		tx   *entity.Transaction
+		want entity.TransactionStatus
+	}{
+		{"transfer", transfer, entity.StatusPending},
+		{"hold", hold, entity.StatusAuthorized},
+		{"scheduled", scheduled, entity.StatusScheduled},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_ = c.tx.Assess(entity.RiskAssessment{Decision: entity.RiskReview})
+
+			if err := c.tx.Approve(); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if c.tx.Status != c.want {
+				t.Fatalf("expected status %s, got %s", c.want, c.tx.Status)
+			}
+			if err := c.tx.Approve(); !errors.Is(err, entity.ErrInvalidStatusTransition) {
+				t.Fatalf("expected a second approval to fail, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestTransaction_Reject(t *testing.T) {
+	tx, _ := entity.NewTransaction("user-1", "user-2", 1000, "USD", "")
+	if err := tx.Reject(time.Now()); !errors.Is(err, entity.ErrInvalidStatusTransition) {
+		t.Fatalf("expected a PENDING transfer not to be rejectable, got: %v", err)
+	}
+	_ = tx.Assess(entity.RiskAssessment{Decision: entity.RiskReview})
+	at := time.Now().UTC()
+
+	if err := tx.Reject(at); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if tx.Status != entity.StatusRejected || tx.ProcessedAt == nil || !tx.ProcessedAt.Equal(at) {
+		t.Fatalf("expected a REJECTED transfer processed at %v, got %+v", at, tx)
+	}
+}
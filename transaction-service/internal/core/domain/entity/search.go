@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"html"
+	"strings"
+)
+
+// MatchStart and MatchStop delimit the matched words in SearchHit.Snippet.
+// They are control characters, which no description is expected to carry.
+const (
+	MatchStart = "\x02"
+	MatchStop  = "\x03"
+)
+
+// SearchHit is a transaction matched by a description search, with its
+// relevance and the fragment of its description around the matches.
+type SearchHit struct {
+	Transaction *Transaction
+	Rank        float32
+	Snippet     string
+}
+
+// HighlightHTML returns the snippet HTML-escaped with every match wrapped in
+// <mark>, so a client can render it without trusting the description.
+func (h SearchHit) HighlightHTML() string {
+	var b strings.Builder
+	rest := h.Snippet
+	for {
+		before, after, found := strings.Cut(rest, MatchStart)
+		b.WriteString(html.EscapeString(strings.ReplaceAll(before, MatchStop, "")))
+		if !found {
+			return b.String()
+		}
+		match, tail, _ := strings.Cut(after, MatchStop)
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(match))
+		b.WriteString("</mark>")
+		rest = tail
+	}
+}
@@ -0,0 +1,27 @@
+package entity_test
+
+import (
+	"testing"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+func TestSearchHit_HighlightHTML(t *testing.T) {
+	cases := map[string]struct {
+		snippet string
+		want    string
+	}{
+		"no match":       {"rent for <b>May</b>", "rent for &lt;b&gt;May&lt;/b&gt;"},
+		"matches":        {"\x02refund\x03 of \x02invoice\x03 & fees", "<mark>refund</mark> of <mark>invoice</mark> &amp; fees"},
+		"escaped match":  {"pay \x02<script>\x03", "pay <mark>&lt;script&gt;</mark>"},
+		"unclosed match": {"\x02refund", "<mark>refund</mark>"},
+		"stray stop":     {"refund\x03 due", "refund due"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := (entity.SearchHit{Snippet: tc.snippet}).HighlightHTML(); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatementLine is one ledger posting on a user's account statement. Amount
+// is signed, credits positive and debits negative, and Balance is the
+// account's ledger balance once it was posted. CounterpartyID is the other
+// user of the transaction.
+type StatementLine struct {
+	TransactionID  string
+	PostedAt       time.Time
+	Description    string
+	CounterpartyID string
+	Amount         int64
+	Balance        int64
+}
+
+// FormatAmount renders amount minor units as a signed decimal in the major
+// unit of a currency with exponent exp, e.g. -1234 with exponent 2 as
+// "-12.34".
+func FormatAmount(amount int64, exp int) string {
+	digits := strconv.FormatUint(absAmount(amount), 10)
+	if exp > 0 {
+		if len(digits) <= exp {
+			digits = strings.Repeat("0", exp-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+	}
+	if amount < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// absAmount is |amount| as unsigned, so math.MinInt64 does not overflow.
+func absAmount(amount int64) uint64 {
+	if amount < 0 {
+		return uint64(-(amount + 1)) + 1
+	}
+	return uint64(amount)
+}
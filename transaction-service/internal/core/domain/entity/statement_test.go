@@ -0,0 +1,29 @@
+package entity_test
+
+import (
+	"math"
+	"testing"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+func TestFormatAmount(t *testing.T) {
+	cases := []struct {
+		amount int64
+		exp    int
+		want   string
+	}{
+		{1234, 2, "12.34"},
+		{-1234, 2, "-12.34"},
+		{5, 2, "0.05"},
+		{-5, 3, "-0.005"},
+		{0, 2, "0.00"},
+		{1500, 0, "1500"},
+		{math.MinInt64, 2, "-92233720368547758.08"},
+	}
+	for _, c := range cases {
+		if got := entity.FormatAmount(c.amount, c.exp); got != c.want {
+			t.Errorf("FormatAmount(%d, %d) = %q, want %q", c.amount, c.exp, got, c.want)
+		}
+	}
+}
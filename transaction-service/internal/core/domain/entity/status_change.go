@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actors that change a transaction's status.
+const (
+	ActorAPI        = "api"
+	ActorSettlement = "settlement"
+	ActorScheduler  = "scheduler"
+	ActorAdmin      = "admin"
+)
+
+// StatusChange is one entry of a transaction's status history. FromStatus is
+// empty for the status the transaction was created with.
+type StatusChange struct {
+	ID            string
+	TransactionID string
+	FromStatus    TransactionStatus
+	ToStatus      TransactionStatus
+	Actor         string
+	Reason        string
+	ChangedAt     time.Time
+}
+
+// NewStatusChange records tx moving from status from to its current status,
+// at ProcessedAt once the transaction was processed and at CreatedAt before.
+func NewStatusChange(tx *Transaction, from TransactionStatus, actor, reason string) *StatusChange {
+	changedAt := tx.CreatedAt
+	if tx.ProcessedAt != nil {
+		changedAt = *tx.ProcessedAt
+	}
+	return &StatusChange{
+		ID:            uuid.NewString(),
+		TransactionID: tx.ID,
+		FromStatus:    from,
+		ToStatus:      tx.Status,
+		Actor:         actor,
+		Reason:        reason,
+		ChangedAt:     changedAt,
+	}
+}
@@ -0,0 +1,42 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+func TestNewStatusChange_CreationUsesCreatedAt(t *testing.T) {
+	tx, _ := entity.NewTransaction("user-1", "user-2", 500, "USD", "")
+
+	change := entity.NewStatusChange(tx, "", entity.ActorAPI, "")
+
+	if change.ID == "" || change.TransactionID != tx.ID {
+		t.Fatalf("unexpected identifiers: %+v", change)
+	}
+	if change.FromStatus != "" || change.ToStatus != entity.StatusPending {
+		t.Fatalf("expected '' -> PENDING, got %q -> %q", change.FromStatus, change.ToStatus)
+	}
+	if !change.ChangedAt.Equal(tx.CreatedAt) {
+		t.Fatalf("expected the creation time, got %v", change.ChangedAt)
+	}
+}
+
+func TestNewStatusChange_TransitionUsesProcessedAt(t *testing.T) {
+	tx, _ := entity.NewTransaction("user-1", "user-2", 500, "USD", "")
+	settledAt := tx.CreatedAt.Add(time.Second)
+	_ = tx.Fail(settledAt)
+
+	change := entity.NewStatusChange(tx, entity.StatusPending, entity.ActorSettlement, "insufficient funds")
+
+	if change.FromStatus != entity.StatusPending || change.ToStatus != entity.StatusFailed {
+		t.Fatalf("expected PENDING -> FAILED, got %q -> %q", change.FromStatus, change.ToStatus)
+	}
+	if change.Actor != entity.ActorSettlement || change.Reason != "insufficient funds" {
+		t.Fatalf("unexpected actor or reason: %+v", change)
+	}
+	if !change.ChangedAt.Equal(settledAt) {
+		t.Fatalf("expected the settlement time, got %v", change.ChangedAt)
+	}
+}
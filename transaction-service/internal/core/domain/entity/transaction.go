@@ -14,6 +14,23 @@ const (
 	StatusProcessing TransactionStatus = "PROCESSING"
 	StatusCompleted  TransactionStatus = "COMPLETED"
 	StatusFailed     TransactionStatus = "FAILED"
+
+	// Hold lifecycle: AUTHORIZED reserves the sender's funds until it is
+	// captured (COMPLETED), voided or expired.
+	StatusAuthorized TransactionStatus = "AUTHORIZED"
+	StatusVoided     TransactionStatus = "VOIDED"
+	StatusExpired    TransactionStatus = "EXPIRED"
+
+	// Scheduled lifecycle: a SCHEDULED transfer moves no funds until it is
+	// released as PENDING at its ExecuteAt, or cancelled before that.
+	StatusScheduled TransactionStatus = "SCHEDULED"
+	StatusCancelled TransactionStatus = "CANCELLED"
+
+	// Risk lifecycle: a HELD transfer moves no funds until a reviewer
+	// approves it into the status it was created for, or rejects it.
+	// REJECTED is also where transfers the risk rules deny end up.
+	StatusHeld     TransactionStatus = "HELD"
+	StatusRejected TransactionStatus = "REJECTED"
 )
 
 var (
@@ -21,11 +38,27 @@ var (
 	ErrToUserIDRequired     = errors.New("to_user_id is required")
 	ErrSameUser             = errors.New("from and to user cannot be the same")
 	ErrAmountMustBePositive = errors.New("amount must be greater than zero")
+
+	ErrInvalidStatusTransition = errors.New("invalid transaction status transition")
+	ErrInsufficientFunds       = errors.New("insufficient funds")
+
+	ErrNotReversible           = errors.New("only completed transactions that are not reversals can be reversed")
+	ErrReversalExceedsOriginal = errors.New("reversals would exceed the original amount")
+
+	ErrCaptureExceedsHold = errors.New("capture amount exceeds the authorized amount")
+	ErrHoldExpired        = errors.New("hold has expired")
+
+	ErrConvertedNotReversible = errors.New("transfers between currencies cannot be reversed")
+
+	ErrDuplicateIdempotencyKey = errors.New("idempotency key already used by the sender")
+
+	ErrNotDue = errors.New("scheduled transaction is not due yet")
 )
 
 type Transaction struct {
 	ID             string
 	Amount         int64
+	Currency       string
 	Description    string
 	FromUserID     string
 	ToUserID       string
@@ -33,29 +66,273 @@ type Transaction struct {
 	CreatedAt      time.Time
 	ProcessedAt    *time.Time
 	IdempotencyKey *string
+	// RequestHash fingerprints the request that created the transaction
+	// with IdempotencyKey, so a reused key can be told apart from a retry.
+	RequestHash *string
+	ReversalOf  *string
+	// AuthorizedAmount and ExpiresAt are only set on holds. Amount becomes
+	// the captured amount on capture; AuthorizedAmount keeps what was held.
+	AuthorizedAmount *int64
+	ExpiresAt        *time.Time
+	// Conversion is only set on transfers between currencies; Amount and
+	// Currency are then what the sender pays.
+	Conversion *Conversion
+	// ExecuteAt is only set on scheduled transfers: when they are released.
+	ExecuteAt *time.Time
+	// Risk is the decision of the risk rules, on transactions created since
+	// they were introduced.
+	Risk *RiskAssessment
+	// Metadata holds the client's own references, such as an order ID,
+	// within the limits ValidateMetadata enforces.
+	Metadata map[string]string
 }
 
-func NewTransaction(fromUserID, toUserID string, amount int64, description string) (*Transaction, error) {
-	if fromUserID == "" {
-		return nil, ErrFromUserIDRequired
+func NewTransaction(fromUserID, toUserID string, amount int64, currency, description string) (*Transaction, error) {
+	if err := validateTransfer(fromUserID, toUserID, amount, currency); err != nil {
+		return nil, err
 	}
-	if toUserID == "" {
-		return nil, ErrToUserIDRequired
+
+	return &Transaction{
+		ID:          uuid.NewString(),
+		Amount:      amount,
+		Currency:    currency,
+		Description: description,
+		FromUserID:  fromUserID,
+		ToUserID:    toUserID,
+		Status:      StatusPending,
+		CreatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// NewAuthorization builds a hold of amount that expires at expiresAt unless
+// it is captured or voided first.
+func NewAuthorization(fromUserID, toUserID string, amount int64, currency, description string, expiresAt time.Time) (*Transaction, error) {
+	tx, err := NewTransaction(fromUserID, toUserID, amount, currency, description)
+	if err != nil {
+		return nil, err
 	}
-	if fromUserID == toUserID {
-		return nil, ErrSameUser
+	tx.Status = StatusAuthorized
+	tx.AuthorizedAmount = &amount
+	tx.ExpiresAt = &expiresAt
+	return tx, nil
+}
+
+// ConvertTo makes t a transfer that credits the receiver in currency at rate.
+// A currency equal to t.Currency, or none, leaves t a plain transfer and
+// then accepts no rate.
+func (t *Transaction) ConvertTo(currency, rate string) error {
+	if currency == "" || currency == t.Currency {
+		if rate != "" {
+			return ErrUnexpectedConversionRate
+		}
+		return nil
+	}
+	if _, err := MinorUnits(currency); err != nil {
+		return err
+	}
+	if rate == "" {
+		return ErrConversionRateRequired
+	}
+	amount, err := Convert(t.Amount, t.Currency, currency, rate)
+	if err != nil {
+		return err
+	}
+	t.Conversion = &Conversion{Currency: currency, Rate: rate, Amount: amount}
+	return nil
+}
+
+// Schedule defers a new PENDING transfer until executeAt.
+func (t *Transaction) Schedule(executeAt time.Time) error {
+	if t.Status != StatusPending || t.ProcessedAt != nil {
+		return ErrInvalidStatusTransition
+	}
+	t.Status = StatusScheduled
+	t.ExecuteAt = &executeAt
+	return nil
+}
+
+// Release makes a due SCHEDULED transfer PENDING, as if it had just been
+// created; settlement then processes it like any other.
+func (t *Transaction) Release(at time.Time) error {
+	if t.Status != StatusScheduled || t.ExecuteAt == nil {
+		return ErrInvalidStatusTransition
+	}
+	if at.Before(*t.ExecuteAt) {
+		return ErrNotDue
+	}
+	t.Status = StatusPending
+	return nil
+}
+
+// FailScheduled moves a due SCHEDULED transfer whose sender cannot pay it
+// to FAILED.
+func (t *Transaction) FailScheduled(at time.Time) error {
+	if t.Status != StatusScheduled || t.ExecuteAt == nil || at.Before(*t.ExecuteAt) {
+		return ErrInvalidStatusTransition
+	}
+	t.Status = StatusFailed
+	t.ProcessedAt = &at
+	return nil
+}
+
+// Cancel drops a SCHEDULED transfer at the sender's request. It remains
+// possible past ExecuteAt until the scheduler has released the transfer.
+func (t *Transaction) Cancel(at time.Time) error {
+	if t.Status != StatusScheduled {
+		return ErrInvalidStatusTransition
+	}
+	t.Status = StatusCancelled
+	t.ProcessedAt = &at
+	return nil
+}
+
+// Credit returns the amount and currency the receiver gets: the converted
+// ones for a transfer between currencies, Amount and Currency otherwise.
+func (t *Transaction) Credit() (int64, string) {
+	if t.Conversion != nil {
+		return t.Conversion.Amount, t.Conversion.Currency
+	}
+	return t.Amount, t.Currency
+}
+
+// Capture completes an AUTHORIZED hold for amount, which may be less than
+// what was authorized; the remainder is released.
+func (t *Transaction) Capture(amount int64, at time.Time) error {
+	if t.Status != StatusAuthorized || t.AuthorizedAmount == nil {
+		return ErrInvalidStatusTransition
+	}
+	if t.ExpiresAt != nil && !at.Before(*t.ExpiresAt) {
+		return ErrHoldExpired
+	}
+	if amount <= 0 {
+		return ErrAmountMustBePositive
+	}
+	if amount > *t.AuthorizedAmount {
+		return ErrCaptureExceedsHold
+	}
+	t.Amount = amount
+	t.Status = StatusCompleted
+	t.ProcessedAt = &at
+	return nil
+}
+
+// Void releases an AUTHORIZED hold at the holder's request.
+func (t *Transaction) Void(at time.Time) error {
+	return t.release(StatusVoided, at)
+}
+
+// Expire releases an AUTHORIZED hold whose ExpiresAt has passed.
+func (t *Transaction) Expire(at time.Time) error {
+	if t.ExpiresAt == nil || at.Before(*t.ExpiresAt) {
+		return ErrInvalidStatusTransition
+	}
+	return t.release(StatusExpired, at)
+}
+
+func (t *Transaction) release(status TransactionStatus, at time.Time) error {
+	if t.Status != StatusAuthorized {
+		return ErrInvalidStatusTransition
+	}
+	t.Status = status
+	t.ProcessedAt = &at
+	return nil
+}
+
+// NewReversal builds the compensating transaction for amount of original:
+// money flows back from the receiver to the sender and the reversal is
+// COMPLETED at creation. It does not know about earlier partial reversals;
+// the repository enforces the cumulative limit.
+func NewReversal(original *Transaction, amount int64, description string) (*Transaction, error) {
+	if original.Status != StatusCompleted || original.ReversalOf != nil {
+		return nil, ErrNotReversible
+	}
+	if original.Conversion != nil {
+		return nil, ErrConvertedNotReversible
 	}
 	if amount <= 0 {
 		return nil, ErrAmountMustBePositive
 	}
+	if amount > original.Amount {
+		return nil, ErrReversalExceedsOriginal
+	}
 
+	now := time.Now().UTC()
+	originalID := original.ID
 	return &Transaction{
 		ID:          uuid.NewString(),
 		Amount:      amount,
+		Currency:    original.Currency,
 		Description: description,
-		FromUserID:  fromUserID,
+		FromUserID:  original.ToUserID,
+		ToUserID:    original.FromUserID,
+		Status:      StatusCompleted,
+		CreatedAt:   now,
+		ProcessedAt: &now,
+		ReversalOf:  &originalID,
+	}, nil
+}
+
+// FundingAccount is the ledger account deposits are paid from, so the
+// postings of a deposit sum to zero like any other. Its balance is minus
+// everything ever paid in; like FXClearingAccount it never has an accounts
+// row.
+const FundingAccount = "00000000-0000-0000-0000-00000000f00d"
+
+// NewFunding builds a deposit of amount into toUserID's account, paid from
+// FundingAccount and COMPLETED at creation like a reversal.
+func NewFunding(toUserID string, amount int64, currency, description string) (*Transaction, error) {
+	if err := validateTransfer(FundingAccount, toUserID, amount, currency); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	return &Transaction{
+		ID:          uuid.NewString(),
+		Amount:      amount,
+		Currency:    currency,
+		Description: description,
+		FromUserID:  FundingAccount,
 		ToUserID:    toUserID,
-		Status:      StatusPending,
-		CreatedAt:   time.Now().UTC(),
+		Status:      StatusCompleted,
+		CreatedAt:   now,
+		ProcessedAt: &now,
 	}, nil
 }
+
+// Complete moves a PENDING transaction to COMPLETED.
+func (t *Transaction) Complete(at time.Time) error {
+	return t.settle(StatusCompleted, at)
+}
+
+// Fail moves a PENDING transaction to FAILED.
+func (t *Transaction) Fail(at time.Time) error {
+	return t.settle(StatusFailed, at)
+}
+
+func (t *Transaction) settle(status TransactionStatus, at time.Time) error {
+	if t.Status != StatusPending {
+		return ErrInvalidStatusTransition
+	}
+	t.Status = status
+	t.ProcessedAt = &at
+	return nil
+}
+
+func validateTransfer(fromUserID, toUserID string, amount int64, currency string) error {
+	if fromUserID == "" {
+		return ErrFromUserIDRequired
+	}
+	if toUserID == "" {
+		return ErrToUserIDRequired
+	}
+	if fromUserID == toUserID {
+		return ErrSameUser
+	}
+	if amount <= 0 {
+		return ErrAmountMustBePositive
+	}
+	if _, err := MinorUnits(currency); err != nil {
+		return err
+	}
+	return nil
+}
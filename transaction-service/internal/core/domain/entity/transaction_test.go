@@ -2,12 +2,13 @@ package entity_test
 
 import (
 	"testing"
+	"time"
 
 	"transaction-service/internal/core/domain/entity"
 )
 
 func TestNewTransaction_Success(t *testing.T) {
-	tx, err := entity.NewTransaction("user-1", "user-2", 500, "payment")
+	tx, err := entity.NewTransaction("user-1", "user-2", 500, "USD", "payment")
 
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
@@ -33,8 +34,8 @@ func TestNewTransaction_Success(t *testing.T) {
 }
 
 func TestNewTransaction_UniqueIDs(t *testing.T) {
-	tx1, _ := entity.NewTransaction("user-1", "user-2", 100, "")
-	tx2, _ := entity.NewTransaction("user-1", "user-2", 100, "")
+	tx1, _ := entity.NewTransaction("user-1", "user-2", 100, "USD", "")
+	tx2, _ := entity.NewTransaction("user-1", "user-2", 100, "USD", "")
 
 	if tx1.ID == tx2.ID {
 		t.Fatal("expected unique IDs for each transaction")
@@ -42,7 +43,7 @@ func TestNewTransaction_UniqueIDs(t *testing.T) {
 }
 
 func TestNewTransaction_MissingFromUserID(t *testing.T) {
-	_, err := entity.NewTransaction("", "user-2", 100, "")
+	_, err := entity.NewTransaction("", "user-2", 100, "USD", "")
 
 	if err != entity.ErrFromUserIDRequired {
 		t.Fatalf("expected ErrFromUserIDRequired, got: %v", err)
@@ -50,7 +51,7 @@ func TestNewTransaction_MissingFromUserID(t *testing.T) {
 }
 
 func TestNewTransaction_MissingToUserID(t *testing.T) {
-	_, err := entity.NewTransaction("user-1", "", 100, "")
+	_, err := entity.NewTransaction("user-1", "", 100, "USD", "")
 
 	if err != entity.ErrToUserIDRequired {
 		t.Fatalf("expected ErrToUserIDRequired, got: %v", err)
@@ -58,7 +59,7 @@ func TestNewTransaction_MissingToUserID(t *testing.T) {
 }
 
 func TestNewTransaction_SameUser(t *testing.T) {
-	_, err := entity.NewTransaction("user-1", "user-1", 100, "")
+	_, err := entity.NewTransaction("user-1", "user-1", 100, "USD", "")
 
 	if err != entity.ErrSameUser {
 		t.Fatalf("expected ErrSameUser, got: %v", err)
@@ -68,9 +69,322 @@ func TestNewTransaction_SameUser(t *testing.T) {
 func TestNewTransaction_InvalidAmount(t *testing.T) {
 	cases := []int64{0, -1, -100}
 	for _, amount := range cases {
-		_, err := entity.NewTransaction("user-1", "user-2", amount, "")
+		_, err := entity.NewTransaction("user-1", "user-2", amount, "USD", "")
 		if err != entity.ErrAmountMustBePositive {
 			t.Fatalf("amount %d: expected ErrAmountMustBePositive, got: %v", amount, err)
 		}
 	}
 }
+
+func TestTransaction_Complete(t *testing.T) {
+	tx, _ := entity.NewTransaction("user-1", "user-2", 100, "USD", "")
+	at := time.Now().UTC()
+
+	if err := tx.Complete(at); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if tx.Status != entity.StatusCompleted {
+		t.Fatalf("expected status COMPLETED, got '%s'", tx.Status)
+	}
+	if tx.ProcessedAt == nil || !tx.ProcessedAt.Equal(at) {
+		t.Fatal("expected ProcessedAt to be set")
+	}
+}
+
+func TestTransaction_Fail(t *testing.T) {
+	tx, _ := entity.NewTransaction("user-1", "user-2", 100, "USD", "")
+
+	if err := tx.Fail(time.Now().UTC()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if tx.Status != entity.StatusFailed {
+		t.Fatalf("expected status FAILED, got '%s'", tx.Status)
+	}
+	if tx.ProcessedAt == nil {
+		t.Fatal("expected ProcessedAt to be set")
+	}
+}
+
+func TestTransaction_SettleTwice(t *testing.T) {
+	tx, _ := entity.NewTransaction("user-1", "user-2", 100, "USD", "")
+	_ = tx.Complete(time.Now().UTC())
+
+	if err := tx.Fail(time.Now().UTC()); err != entity.ErrInvalidStatusTransition {
+		t.Fatalf("expected ErrInvalidStatusTransition, got: %v", err)
+	}
+	if tx.Status != entity.StatusCompleted {
+		t.Fatalf("expected status to stay COMPLETED, got '%s'", tx.Status)
+	}
+}
+
+func completedTransaction(t *testing.T) *entity.Transaction {
+	t.Helper()
+	tx, err := entity.NewTransaction("user-1", "user-2", 500, "USD", "payment")
+	if err != nil {
+		t.Fatalf("new transaction: %v", err)
+	}
+	_ = tx.Complete(time.Now().UTC())
+	return tx
+}
+
+func TestNewReversal_SwapsParticipantsAndCompletes(t *testing.T) {
+	original := completedTransaction(t)
+
+	rev, err := entity.NewReversal(original, 200, "refund")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if rev.FromUserID != "user-2" || rev.ToUserID != "user-1" {
+		t.Fatalf("expected money to flow back from user-2 to user-1, got %s -> %s", rev.FromUserID, rev.ToUserID)
+	}
+	if rev.Amount != 200 || rev.Currency != "USD" || rev.Status != entity.StatusCompleted || rev.ProcessedAt == nil {
+		t.Fatalf("unexpected reversal: %+v", rev)
+	}
+	if rev.ReversalOf == nil || *rev.ReversalOf != original.ID {
+		t.Fatalf("expected reversal_of %s, got %v", original.ID, rev.ReversalOf)
+	}
+	if rev.ID == original.ID {
+		t.Fatal("expected the reversal to get its own ID")
+	}
+}
+
+func TestNewReversal_Rejections(t *testing.T) {
+	pending, _ := entity.NewTransaction("user-1", "user-2", 500, "USD", "")
+	reversal, _ := entity.NewReversal(completedTransaction(t), 100, "")
+	converted := completedTransaction(t)
+	converted.Conversion = &entity.Conversion{Currency: "EUR", Rate: "0.9", Amount: 450}
+
+	cases := []struct {
+		name     string
+		original *entity.Transaction
+		amount   int64
+		want     error
+	}{
+		{"not completed", pending, 100, entity.ErrNotReversible},
+		{"reversal of a reversal", reversal, 50, entity.ErrNotReversible},
+		{"between currencies", converted, 100, entity.ErrConvertedNotReversible},
+		{"zero amount", completedTransaction(t), 0, entity.ErrAmountMustBePositive},
+		{"above original", completedTransaction(t), 501, entity.ErrReversalExceedsOriginal},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := entity.NewReversal(tc.original, tc.amount, ""); err != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestNewFunding_PaysFromTheFundingAccount(t *testing.T) {
+	funding, err := entity.NewFunding("user-1", 1000, "USD", "opening deposit")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if funding.FromUserID != entity.FundingAccount || funding.ToUserID != "user-1" {
+		t.Fatalf("expected a deposit from the funding account, got %s -> %s", funding.FromUserID, funding.ToUserID)
+	}
+	if funding.Status != entity.StatusCompleted || funding.ProcessedAt == nil || funding.Amount != 1000 {
+		t.Fatalf("unexpected funding: %+v", funding)
+	}
+	if err := entity.CheckBalanced(entity.NewPostings(funding)); err != nil {
+		t.Fatalf("expected balanced postings, got %v", err)
+	}
+}
+
+func TestNewFunding_Rejections(t *testing.T) {
+	cases := map[string]struct {
+		toUserID string
+		amount   int64
+		currency string
+		want     error
+	}{
+		"no receiver":      {"", 100, "USD", entity.ErrToUserIDRequired},
+		"funding account":  {entity.FundingAccount, 100, "USD", entity.ErrSameUser},
+		"zero amount":      {"user-1", 0, "USD", entity.ErrAmountMustBePositive},
+		"unknown currency": {"user-1", 100, "XXX", entity.ErrUnsupportedCurrency},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := entity.NewFunding(tc.toUserID, tc.amount, tc.currency, ""); err != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func newHold(t *testing.T, expiresAt time.Time) *entity.Transaction {
+	t.Helper()
+	hold, err := entity.NewAuthorization("user-1", "user-2", 500, "USD", "order", expiresAt)
+	if err != nil {
+		t.Fatalf("new authorization: %v", err)
+	}
+	return hold
+}
+
+func TestNewAuthorization_HoldsAmountUntilExpiry(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).UTC()
+
+	hold := newHold(t, expiresAt)
+
+	if hold.Status != entity.StatusAuthorized {
+		t.Fatalf("expected AUTHORIZED, got %s", hold.Status)
+	}
+	if *hold.AuthorizedAmount != 500 || !hold.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("unexpected hold: authorized %d, expires %v", *hold.AuthorizedAmount, hold.ExpiresAt)
+	}
+}
+
+func TestNewAuthorization_ValidatesTransfer(t *testing.T) {
+	if _, err := entity.NewAuthorization("user-1", "user-1", 500, "USD", "", time.Now()); err != entity.ErrSameUser {
+		t.Fatalf("expected ErrSameUser, got %v", err)
+	}
+}
+
+func TestTransaction_CapturePartial(t *testing.T) {
+	hold := newHold(t, time.Now().Add(time.Hour))
+	at := time.Now().UTC()
+
+	if err := hold.Capture(300, at); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if hold.Status != entity.StatusCompleted || hold.Amount != 300 || *hold.AuthorizedAmount != 500 {
+		t.Fatalf("unexpected capture result: status %s, amount %d, authorized %d", hold.Status, hold.Amount, *hold.AuthorizedAmount)
+	}
+	if hold.ProcessedAt == nil || !hold.ProcessedAt.Equal(at) {
+		t.Fatalf("expected ProcessedAt %v, got %v", at, hold.ProcessedAt)
+	}
+}
+
+func TestTransaction_CaptureRejections(t *testing.T) {
+	now := time.Now().UTC()
+	pending, _ := entity.NewTransaction("user-1", "user-2", 500, "USD", "")
+
+	cases := []struct {
+		name   string
+		tx     *entity.Transaction
+		amount int64
+		want   error
+	}{
+		{"not a hold", pending, 100, entity.ErrInvalidStatusTransition},
+		{"expired", newHold(t, now.Add(-time.Second)), 100, entity.ErrHoldExpired},
+		{"zero amount", newHold(t, now.Add(time.Hour)), 0, entity.ErrAmountMustBePositive},
+		{"above hold", newHold(t, now.Add(time.Hour)), 501, entity.ErrCaptureExceedsHold},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.tx.Capture(tc.amount, now); err != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestTransaction_Void(t *testing.T) {
+	hold := newHold(t, time.Now().Add(time.Hour))
+
+	if err := hold.Void(time.Now().UTC()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if hold.Status != entity.StatusVoided {
+		t.Fatalf("expected VOIDED, got %s", hold.Status)
+	}
+	if err := hold.Void(time.Now().UTC()); err != entity.ErrInvalidStatusTransition {
+		t.Fatalf("expected a second void to fail, got %v", err)
+	}
+}
+
+func TestTransaction_Expire(t *testing.T) {
+	now := time.Now().UTC()
+
+	live := newHold(t, now.Add(time.Hour))
+	if err := live.Expire(now); err != entity.ErrInvalidStatusTransition {
+		t.Fatalf("expected a live hold not to expire, got %v", err)
+	}
+
+	stale := newHold(t, now.Add(-time.Minute))
+	if err := stale.Expire(now); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if stale.Status != entity.StatusExpired {
+		t.Fatalf("expected EXPIRED, got %s", stale.Status)
+	}
+}
+
+func newScheduled(t *testing.T, executeAt time.Time) *entity.Transaction {
+	t.Helper()
+	tx, err := entity.NewTransaction("user-1", "user-2", 500, "USD", "rent")
+	if err != nil {
+		t.Fatalf("new transaction: %v", err)
+	}
+	if err := tx.Schedule(executeAt); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	return tx
+}
+
+func TestTransaction_Schedule(t *testing.T) {
+	executeAt := time.Now().UTC().Add(time.Hour)
+	tx := newScheduled(t, executeAt)
+
+	if tx.Status != entity.StatusScheduled || !tx.ExecuteAt.Equal(executeAt) {
+		t.Fatalf("expected SCHEDULED at %v, got %s at %v", executeAt, tx.Status, tx.ExecuteAt)
+	}
+	if err := tx.Schedule(executeAt); err != entity.ErrInvalidStatusTransition {
+		t.Fatalf("expected rescheduling to fail, got %v", err)
+	}
+}
+
+func TestTransaction_Release(t *testing.T) {
+	now := time.Now().UTC()
+
+	early := newScheduled(t, now.Add(time.Hour))
+	if err := early.Release(now); err != entity.ErrNotDue {
+		t.Fatalf("expected ErrNotDue, got %v", err)
+	}
+
+	due := newScheduled(t, now)
+	if err := due.Release(now); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if due.Status != entity.StatusPending || due.ProcessedAt != nil {
+		t.Fatalf("expected an unprocessed PENDING transfer, got %s processed at %v", due.Status, due.ProcessedAt)
+	}
+	if err := due.Release(now); err != entity.ErrInvalidStatusTransition {
+		t.Fatalf("expected a second release to fail, got %v", err)
+	}
+}
+
+func TestTransaction_FailScheduled(t *testing.T) {
+	now := time.Now().UTC()
+
+	if err := newScheduled(t, now.Add(time.Hour)).FailScheduled(now); err != entity.ErrInvalidStatusTransition {
+		t.Fatalf("expected a transfer that is not due not to fail, got %v", err)
+	}
+
+	due := newScheduled(t, now.Add(-time.Minute))
+	if err := due.FailScheduled(now); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if due.Status != entity.StatusFailed || due.ProcessedAt == nil {
+		t.Fatalf("expected a processed FAILED transfer, got %s", due.Status)
+	}
+}
+
+func TestTransaction_Cancel(t *testing.T) {
+	now := time.Now().UTC()
+	tx := newScheduled(t, now.Add(-time.Minute))
+
+	if err := tx.Cancel(now); err != nil {
+		t.Fatalf("expected a due transfer not yet released to cancel, got %v", err)
+	}
+	if tx.Status != entity.StatusCancelled || tx.ProcessedAt == nil {
+		t.Fatalf("expected a processed CANCELLED transfer, got %s", tx.Status)
+	}
+
+	pending, _ := entity.NewTransaction("user-1", "user-2", 500, "USD", "")
+	if err := pending.Cancel(now); err != entity.ErrInvalidStatusTransition {
+		t.Fatalf("expected a PENDING transfer not to cancel, got %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+type KnownUserRepository interface {
+	// Save records the user if it is not known yet and reports whether a new
+	// row was written, so replayed events are no-ops.
+	Save(ctx context.Context, user *entity.KnownUser) (bool, error)
+	// FindMissing returns the subset of userIDs that are not known.
+	FindMissing(ctx context.Context, userIDs []string) ([]string, error)
+}
+
+// UserEventSource reads UserCreated history from users-service, oldest first.
+// Results start strictly after the (afterTime, afterEventID) cursor.
+type UserEventSource interface {
+	ListUserCreated(ctx context.Context, afterTime time.Time, afterEventID string, limit int) ([]*entity.KnownUser, error)
+}
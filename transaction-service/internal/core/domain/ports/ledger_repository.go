@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+type LedgerRepository interface {
+	// FindImbalances scans the books for transactions whose postings do not
+	// sum to zero, COMPLETED transactions without postings and postings of
+	// transactions that are not COMPLETED.
+	FindImbalances(ctx context.Context) ([]entity.LedgerImbalance, error)
+	// RebuildBalances recomputes the balances projection from the postings
+	// and returns how many rows it corrected.
+	RebuildBalances(ctx context.Context) (int64, error)
+	// FindBalanceDrift compares the balances projection with a full scan of
+	// the postings and returns every user on which they disagree.
+	FindBalanceDrift(ctx context.Context) ([]entity.BalanceDrift, error)
+}
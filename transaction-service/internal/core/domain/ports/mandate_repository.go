@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+// MandateRepository stores recurring transfer mandates. Every write inserts
+// its outbox event in the same DB transaction.
+type MandateRepository interface {
+	Create(ctx context.Context, m *entity.Mandate, outbox *entity.Outbox) error
+	FindByID(ctx context.Context, id string) (*entity.Mandate, error)
+	// ListByUser returns the mandates fromUserID pays, newest first.
+	ListByUser(ctx context.Context, fromUserID string) ([]*entity.Mandate, error)
+	// Update saves the terms and status of m if it is still at m.Version,
+	// and returns entity.ErrMandateChanged otherwise.
+	Update(ctx context.Context, m *entity.Mandate, outbox *entity.Outbox) error
+	// Advance records the run before m.Occurrence if the mandate is still
+	// ACTIVE and has not been advanced past it, and returns
+	// entity.ErrMandateChanged otherwise. Amendments made meanwhile are kept.
+	Advance(ctx context.Context, m *entity.Mandate, outbox *entity.Outbox) error
+	// FindDue returns up to limit ACTIVE mandates whose next run is due at
+	// now, earliest first.
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*entity.Mandate, error)
+}
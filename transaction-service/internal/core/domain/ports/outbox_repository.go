@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"transaction-service/internal/core/domain/entity"
 )
@@ -11,4 +12,17 @@ type OutboxRepository interface {
 	MarkProcessed(ctx context.Context, id string) error
 	MarkFailed(ctx context.Context, id string) error
 	MarkForRetry(ctx context.Context, id string) error
+	FetchProcessed(ctx context.Context, q ReplayQuery, limit int) ([]*entity.Outbox, error)
+}
+
+// ReplayQuery selects PROCESSED events for replay. Empty fields and zero
+// times are not filtered on; AfterCreatedAt/AfterID is the paging cursor.
+type ReplayQuery struct {
+	Type        string
+	AggregateID string
+	From        time.Time
+	To          time.Time
+
+	AfterCreatedAt time.Time
+	AfterID        string
 }
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+// ReconciliationRepository finds the rows that break the outbox pattern.
+// Each method returns the total count and at most limit rows, oldest first.
+type ReconciliationRepository interface {
+	// FindMissingEvents returns transactions without any outbox event. A
+	// reversal counts as covered by the TransactionReversed event written on
+	// its original.
+	FindMissingEvents(ctx context.Context, limit int) (entity.DiscrepancySample, error)
+	// FindOrphanEvents returns outbox events whose transaction or mandate
+	// does not exist.
+	FindOrphanEvents(ctx context.Context, limit int) (entity.DiscrepancySample, error)
+	// FindStuckEvents returns events in status created before the cutoff.
+	FindStuckEvents(ctx context.Context, status entity.OutboxStatus, before time.Time, limit int) (entity.DiscrepancySample, error)
+}
@@ -0,0 +1,32 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+// RiskEvaluator decides whether a new transaction may go ahead before it is
+// persisted: ALLOW, REVIEW to hold it for a reviewer, or DENY.
+type RiskEvaluator interface {
+	// Evaluate assesses tx. pending are the sender's transfers accepted
+	// earlier in the same request and not committed yet; they count toward
+	// the sender's history like committed ones.
+	Evaluate(ctx context.Context, tx *entity.Transaction, pending []*entity.Transaction) (entity.RiskAssessment, error)
+	// Screen re-checks the parties of an accepted tx when it settles,
+	// against rules that may have changed since, and returns why it may no
+	// longer go ahead; none when it may.
+	Screen(tx *entity.Transaction) []string
+}
+
+// RiskHistory is what the built-in risk rules know of a sender's past
+// transfers.
+type RiskHistory interface {
+	// CountSent returns how many transactions fromUserID created at or after
+	// since, rejected ones excluded.
+	CountSent(ctx context.Context, fromUserID string, since time.Time) (int64, error)
+	// HasPaid reports whether fromUserID ever completed a transfer to
+	// toUserID.
+	HasPaid(ctx context.Context, fromUserID, toUserID string) (bool, error)
+}
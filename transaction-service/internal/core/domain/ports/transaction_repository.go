@@ -2,13 +2,172 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"transaction-service/internal/core/domain/entity"
 )
 
+// Every method that changes a transaction's status takes the matching
+// entity.StatusChange and appends it to the status history in the same DB
+// transaction as the change itself.
 type TransactionRepository interface {
-	Create(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error
+	// Create locks the sender's account, debits it and inserts the
+	// transaction and its outbox event in one DB transaction; the receiver is
+	// only paid when the transfer completes. It returns
+	// entity.ErrInsufficientFunds when the sender cannot afford the transfer,
+	// a *entity.LimitExceededError when it would exceed one of the sender's
+	// limits, and entity.ErrDuplicateIdempotencyKey when a concurrent request
+	// of the same sender committed a transaction with the same idempotency key
+	// first. A SCHEDULED or HELD transaction moves no funds until
+	// ResolveScheduled or ResolveHeld moves it on, but counts against the
+	// limits when it is created; a REJECTED one is recorded as it is.
+	Create(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error
+	// CreateBatch persists writes as Create would, in one DB transaction that
+	// locks every sender's account once. It returns one error per write, nil
+	// for those it created: entity.ErrInsufficientFunds, a
+	// *entity.LimitExceededError, or entity.ErrDuplicateIdempotencyKey when
+	// the sender already used the key.
+	// With atomic set nothing is committed unless every write succeeds.
+	CreateBatch(ctx context.Context, writes []TransactionWrite, atomic bool) ([]error, error)
 	FindByID(ctx context.Context, id string) (*entity.Transaction, error)
-	FindByIdempotencyKey(ctx context.Context, key string) (*entity.Transaction, error)
-	GetBalance(ctx context.Context, userID string) (int64, error)
+	// FindByIdempotencyKey returns the transaction fromUserID created with
+	// key, or nil. Keys are scoped per sender.
+	FindByIdempotencyKey(ctx context.Context, fromUserID, key string) (*entity.Transaction, error)
+	// FindByIdempotencyKeys looks up many keys at once and returns the
+	// transactions found, in no particular order.
+	FindByIdempotencyKeys(ctx context.Context, refs []IdempotencyRef) ([]*entity.Transaction, error)
+	// ReleaseIdempotencyKeys clears the idempotency key of up to limit
+	// transactions created before the cutoff and reports how many it cleared.
+	ReleaseIdempotencyKeys(ctx context.Context, before time.Time, limit int) (int64, error)
+	// GetBalance reads the user's ledger balance in currency from the
+	// balances projection.
+	GetBalance(ctx context.Context, userID, currency string) (int64, error)
+	// GetBalanceAsOf sums the user's ledger postings in currency of the
+	// transactions processed at or before asOf, so a transfer created before
+	// the cutoff but settled after it is not counted.
+	GetBalanceAsOf(ctx context.Context, userID, currency string, asOf time.Time) (int64, error)
+	// GetAvailableBalance reads the user's spendable balance in currency from
+	// accounts, which holds and outgoing transfers reduce as soon as they are
+	// created.
+	GetAvailableBalance(ctx context.Context, userID, currency string) (int64, error)
+	// Settle persists the final status of a PENDING transaction together with
+	// its outbox event. COMPLETED, it pays the receiver, writes the ledger
+	// postings and applies them to the balances projection; FAILED, it
+	// refunds the sender, the only account Create touched. It returns entity.ErrInvalidStatusTransition when the row is
+	// no longer PENDING.
+	Settle(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error
+	// CaptureHold persists a hold captured by entity.Transaction.Capture,
+	// paying the receiver and releasing the uncaptured remainder. It returns
+	// entity.ErrInvalidStatusTransition when the hold is no longer AUTHORIZED.
+	CaptureHold(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error
+	// ReleaseHold persists a voided or expired hold and gives the authorized
+	// amount back. It returns entity.ErrInvalidStatusTransition when the hold
+	// is no longer AUTHORIZED.
+	ReleaseHold(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error
+	// FindExpiredHolds returns up to limit AUTHORIZED holds whose expiry is
+	// at or before now, oldest expiry first.
+	FindExpiredHolds(ctx context.Context, now time.Time, limit int) ([]*entity.Transaction, error)
+	// FindDueScheduled returns up to limit SCHEDULED transactions whose
+	// execution time is at or before now, earliest first.
+	FindDueScheduled(ctx context.Context, now time.Time, limit int) ([]*entity.Transaction, error)
+	// ResolveScheduled persists a SCHEDULED transaction leaving that status.
+	// Released to PENDING it debits the sender as Create does and returns
+	// entity.ErrInsufficientFunds, writing nothing, when the sender cannot
+	// pay; FAILED or CANCELLED it moves none. It returns
+	// entity.ErrInvalidStatusTransition when the row is no longer SCHEDULED.
+	ResolveScheduled(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error
+	// ResolveHeld persists a HELD transaction leaving that status. Approved
+	// to PENDING or AUTHORIZED it debits the sender as Create does, and it
+	// returns entity.ErrInsufficientFunds, writing nothing, when the sender
+	// cannot pay; SCHEDULED or REJECTED it moves none. It returns
+	// entity.ErrInvalidStatusTransition when the row is no longer HELD.
+	ResolveHeld(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error
+	// Reverse persists a COMPLETED reversal built by entity.NewReversal with
+	// its funds movement, postings and outbox event. It returns
+	// entity.ErrReversalExceedsOriginal when earlier reversals leave less than
+	// its amount, and entity.ErrInsufficientFunds when the original receiver
+	// cannot pay it back.
+	Reverse(ctx context.Context, reversal *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error
+	// Fund persists a COMPLETED deposit built by entity.NewFunding, crediting
+	// the receiver's account and writing its postings, balances and outbox
+	// event in one DB transaction.
+	Fund(ctx context.Context, funding *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error
+	// FindStatusHistory returns the status changes of a transaction, oldest
+	// first.
+	FindStatusHistory(ctx context.Context, transactionID string) ([]*entity.StatusChange, error)
+	// ListByUser returns up to limit transactions matching q, newest first.
+	ListByUser(ctx context.Context, q TransactionQuery, limit int) ([]*entity.Transaction, error)
+	// Search returns up to limit transactions whose description matches
+	// q.Text, most relevant first and newest first among equals, each with a
+	// snippet whose matches are delimited by entity.MatchStart and
+	// entity.MatchStop.
+	Search(ctx context.Context, q SearchQuery, limit int) ([]entity.SearchHit, error)
+	// StreamStatement reads, from one snapshot, the ledger balance of q's
+	// account before q.From and then its postings within the period, oldest
+	// first, through a server-side cursor so a long history is never held in
+	// memory. It calls opening once and then posting for each entry, with
+	// Balance left zero; an error from either ends the read and is returned.
+	StreamStatement(ctx context.Context, q StatementQuery, opening func(balance int64) error, posting func(entity.StatementLine) error) error
+}
+
+// TransactionWrite is a new transaction with the status change and outbox
+// event persisted alongside it.
+type TransactionWrite struct {
+	Tx     *entity.Transaction
+	Change *entity.StatusChange
+	Outbox *entity.Outbox
+}
+
+// IdempotencyRef is an idempotency key scoped to the sender that used it.
+type IdempotencyRef struct {
+	FromUserID string
+	Key        string
+}
+
+type Direction string
+
+const (
+	DirectionAny      Direction = ""
+	DirectionSent     Direction = "sent"
+	DirectionReceived Direction = "received"
+)
+
+// TransactionQuery selects the transactions of UserID. Empty fields, nil
+// amounts and zero times are not filtered on; From is inclusive and To
+// exclusive. Metadata matches transactions whose metadata contains all of
+// its pairs. BeforeCreatedAt/BeforeID is the paging cursor.
+type TransactionQuery struct {
+	UserID    string
+	Direction Direction
+	Status    entity.TransactionStatus
+	MinAmount *int64
+	MaxAmount *int64
+	From      time.Time
+	To        time.Time
+	Metadata  map[string]string
+
+	BeforeCreatedAt time.Time
+	BeforeID        string
+}
+
+// SearchQuery selects the transactions whose description matches Text, in
+// web search syntax: words, "quoted phrases", or and -excluded words. A
+// non-empty UserID keeps those it sent or received. AfterRank, AfterCreatedAt
+// and AfterID are the paging cursor, unset when AfterID is empty.
+type SearchQuery struct {
+	Text   string
+	UserID string
+
+	AfterRank      float32
+	AfterCreatedAt time.Time
+	AfterID        string
+}
+
+// StatementQuery selects the postings of UserID's account in Currency made
+// at or after From and before To.
+type StatementQuery struct {
+	UserID   string
+	Currency string
+	From     time.Time
+	To       time.Time
 }
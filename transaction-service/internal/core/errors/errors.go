@@ -63,6 +63,17 @@ func Conflict(opts ...UserFriendlyExceptionOption) *Exception {
 	return UserFriendlyException(defaultOpts...)
 }
 
+func UnprocessableEntity(opts ...UserFriendlyExceptionOption) *Exception {
+	defaultOpts := []UserFriendlyExceptionOption{
+		WithCode(422),
+		WithMessage("unprocessable entity"),
+	}
+
+	defaultOpts = append(defaultOpts, opts...)
+
+	return UserFriendlyException(defaultOpts...)
+}
+
 func Unauthorized(opts ...UserFriendlyExceptionOption) *Exception {
 	defaultOpts := []UserFriendlyExceptionOption{
 		WithCode(401),
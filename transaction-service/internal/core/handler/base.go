@@ -3,6 +3,8 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+
+	apperrors "transaction-service/internal/core/errors"
 )
 
 type (
@@ -45,3 +47,21 @@ func (b *Base) RespondWithSuccess(w http.ResponseWriter, code int, message strin
 		Data:    data,
 	})
 }
+
+// wrap adapts a handler that returns an error, answering an
+// *apperrors.Exception with its own status and anything else with 500.
+func (b *Base) wrap(fn func(http.ResponseWriter, *http.Request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := fn(w, r)
+		if err == nil {
+			return
+		}
+
+		if exc, ok := err.(*apperrors.Exception); ok {
+			b.RespondWithError(w, r, exc.Code, exc.Message, exc.Err)
+			return
+		}
+
+		b.RespondWithError(w, r, http.StatusInternalServerError, "internal server error", err.Error())
+	}
+}
@@ -3,5 +3,9 @@ package handler
 import "transaction-service/internal/core/usecase"
 
 func NewHandlerFactory(f *usecase.Factory) *Handler {
-	return NewHandler(f.Create, f.Status, f.Balance)
+	return NewHandler(f.Create, f.Batch, f.Status, f.Balance, f.List, f.Search, f.Reverse, f.Capture, f.Void, f.History, f.Cancel, f.Statement)
+}
+
+func NewMandateHandlerFactory(f *usecase.Factory) *MandateHandler {
+	return NewMandateHandler(f.CreateMandate, f.GetMandate, f.ListMandates, f.UpdateMandate, f.CancelMandate)
 }
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"transaction-service/internal/core/usecase"
+)
+
+type (
+	// MandateHandler serves the recurring transfer mandate endpoints.
+	MandateHandler struct {
+		create *usecase.CreateMandateUseCase
+		get    *usecase.GetMandateUseCase
+		list   *usecase.ListMandatesUseCase
+		update *usecase.UpdateMandateUseCase
+		cancel *usecase.CancelMandateUseCase
+		Base
+	}
+
+	// MandateReq is the request body for creating a mandate. Schedule is
+	// @daily, @weekly, @monthly, @yearly or "@every <duration>" of at least
+	// an hour, measured from start_at (default: now). The mandate ends after
+	// the last run on or before end_at.
+	MandateReq struct {
+		FromUserID  string     `json:"from_user_id"  example:"user-abc"`
+		ToUserID    string     `json:"to_user_id"    example:"user-xyz"`
+		Amount      int64      `json:"amount"        example:"120000"`
+		Currency    string     `json:"currency"      example:"USD"`
+		Description string     `json:"description"   example:"rent"`
+		Schedule    string     `json:"schedule"      example:"@monthly"`
+		StartAt     *time.Time `json:"start_at"      example:"2026-11-01T09:00:00Z"`
+		EndAt       *time.Time `json:"end_at"        example:"2027-10-31T23:59:59Z"`
+	}
+
+	// UpdateMandateReq changes the terms of a mandate's future runs. Omitted
+	// fields are left as they are.
+	UpdateMandateReq struct {
+		Amount      *int64     `json:"amount"       example:"125000"`
+		Description *string    `json:"description"  example:"rent"`
+		EndAt       *time.Time `json:"end_at"       example:"2027-10-31T23:59:59Z"`
+	}
+)
+
+func NewMandateHandler(
+	create *usecase.CreateMandateUseCase,
+	get *usecase.GetMandateUseCase,
+	list *usecase.ListMandatesUseCase,
+	update *usecase.UpdateMandateUseCase,
+	cancel *usecase.CancelMandateUseCase,
+) *MandateHandler {
+	return &MandateHandler{
+		create: create,
+		get:    get,
+		list:   list,
+		update: update,
+		cancel: cancel,
+	}
+}
+
+func (h *MandateHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/v1/mandates", h.wrap(h.handleCreate))
+	mux.HandleFunc("GET /api/v1/mandates/{id}", h.wrap(h.handleGet))
+	mux.HandleFunc("PATCH /api/v1/mandates/{id}", h.wrap(h.handleUpdate))
+	mux.HandleFunc("DELETE /api/v1/mandates/{id}", h.wrap(h.handleCancel))
+	mux.HandleFunc("GET /api/v1/users/{userId}/mandates", h.wrap(h.handleListByUser))
+}
+
+// handleCreate godoc
+// @Summary      Create mandate
+// @Description  Creates a recurring transfer mandate. The scheduler creates one transaction per occurrence until the mandate is cancelled or ends.
+// @Tags         mandates
+// @Accept       json
+// @Produce      json
+// @Param        request  body      MandateReq  true  "Mandate payload"
+// @Success      201      {object}  Response
+// @Failure      400      {object}  ErrResponse
+// @Failure      422      {object}  ErrResponse
+// @Failure      500      {object}  ErrResponse
+// @Router       /api/v1/mandates [post]
+func (h *MandateHandler) handleCreate(w http.ResponseWriter, r *http.Request) error {
+	var req MandateReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.RespondWithError(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return nil
+	}
+
+	var startAt time.Time
+	if req.StartAt != nil {
+		startAt = *req.StartAt
+	}
+
+	out, err := h.create.Execute(r.Context(), usecase.CreateMandateInput{
+		FromUserID:  req.FromUserID,
+		ToUserID:    req.ToUserID,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Description: req.Description,
+		Schedule:    req.Schedule,
+		StartAt:     startAt,
+		EndAt:       req.EndAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	h.RespondWithSuccess(w, http.StatusCreated, "mandate created", out)
+	return nil
+}
+
+// handleGet godoc
+// @Summary      Get mandate
+// @Description  Returns a mandate with its schedule, next run and the number of runs so far.
+// @Tags         mandates
+// @Produce      json
+// @Param        id   path      string  true  "Mandate ID"
+// @Success      200  {object}  Response
+// @Failure      404  {object}  ErrResponse
+// @Failure      500  {object}  ErrResponse
+// @Router       /api/v1/mandates/{id} [get]
+func (h *MandateHandler) handleGet(w http.ResponseWriter, r *http.Request) error {
+	out, err := h.get.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+
+	h.RespondWithSuccess(w, http.StatusOK, "ok", out)
+	return nil
+}
+
+// handleUpdate godoc
+// @Summary      Update mandate
+// @Description  Changes the amount, description or end date of an ACTIVE mandate for its future runs.
+// @Tags         mandates
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string            true  "Mandate ID"
+// @Param        request  body      UpdateMandateReq  true  "Fields to change"
+// @Success      200      {object}  Response
+// @Failure      400      {object}  ErrResponse
+// @Failure      404      {object}  ErrResponse
+// @Failure      409      {object}  ErrResponse
+// @Failure      500      {object}  ErrResponse
+// @Router       /api/v1/mandates/{id} [patch]
+func (h *MandateHandler) handleUpdate(w http.ResponseWriter, r *http.Request) error {
+	var req UpdateMandateReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.RespondWithError(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return nil
+	}
+
+	out, err := h.update.Execute(r.Context(), usecase.UpdateMandateInput{
+		ID:          r.PathValue("id"),
+		Amount:      req.Amount,
+		Description: req.Description,
+		EndAt:       req.EndAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	h.RespondWithSuccess(w, http.StatusOK, "mandate updated", out)
+	return nil
+}
+
+// handleCancel godoc
+// @Summary      Cancel mandate
+// @Description  Cancels an ACTIVE mandate. Transactions of past runs are not affected.
+// @Tags         mandates
+// @Produce      json
+// @Param        id   path      string  true  "Mandate ID"
+// @Success      200  {object}  Response
+// @Failure      404  {object}  ErrResponse
+// @Failure      409  {object}  ErrResponse
+// @Failure      500  {object}  ErrResponse
+// @Router       /api/v1/mandates/{id} [delete]
+func (h *MandateHandler) handleCancel(w http.ResponseWriter, r *http.Request) error {
+	out, err := h.cancel.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+
+	h.RespondWithSuccess(w, http.StatusOK, "mandate cancelled", out)
+	return nil
+}
+
+// handleListByUser godoc
+// @Summary      List user mandates
+// @Description  Returns every mandate the user pays, newest first.
+// @Tags         mandates
+// @Produce      json
+// @Param        userId  path      string  true  "User ID"
+// @Success      200     {object}  Response
+// @Failure      500     {object}  ErrResponse
+// @Router       /api/v1/users/{userId}/mandates [get]
+func (h *MandateHandler) handleListByUser(w http.ResponseWriter, r *http.Request) error {
+	out, err := h.list.Execute(r.Context(), r.PathValue("userId"))
+	if err != nil {
+		return err
+	}
+
+	h.RespondWithSuccess(w, http.StatusOK, "ok", map[string]any{"items": out})
+	return nil
+}
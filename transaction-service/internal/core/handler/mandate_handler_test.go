@@ -0,0 +1,201 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/handler"
+	"transaction-service/internal/core/usecase"
+)
+
+// stubMandateRepository implements ports.MandateRepository for handler tests.
+type stubMandateRepository struct {
+	findByIDFn   func(ctx context.Context, id string) (*entity.Mandate, error)
+	listByUserFn func(ctx context.Context, fromUserID string) ([]*entity.Mandate, error)
+	updateFn     func(ctx context.Context, m *entity.Mandate) error
+}
+
+func (s *stubMandateRepository) Create(_ context.Context, _ *entity.Mandate, _ *entity.Outbox) error {
+	return nil
+}
+
+func (s *stubMandateRepository) FindByID(_ context.Context, id string) (*entity.Mandate, error) {
+	if s.findByIDFn != nil {
+		return s.findByIDFn(context.Background(), id)
+	}
+	return nil, nil
+}
+
+func (s *stubMandateRepository) ListByUser(_ context.Context, fromUserID string) ([]*entity.Mandate, error) {
+	if s.listByUserFn != nil {
+		return s.listByUserFn(context.Background(), fromUserID)
+	}
+	return nil, nil
+}
+
+func (s *stubMandateRepository) Update(_ context.Context, m *entity.Mandate, _ *entity.Outbox) error {
+	if s.updateFn != nil {
+		return s.updateFn(context.Background(), m)
+	}
+	return nil
+}
+
+func (s *stubMandateRepository) Advance(_ context.Context, _ *entity.Mandate, _ *entity.Outbox) error {
+	return nil
+}
+
+func (s *stubMandateRepository) FindDue(_ context.Context, _ time.Time, _ int) ([]*entity.Mandate, error) {
+	return nil, nil
+}
+
+func newTestMandateHandler(repo *stubMandateRepository) *handler.MandateHandler {
+	f := usecase.NewFactory(&stubTransactionRepository{}, repo, &stubKnownUserRepository{}, stubRiskEvaluator{}, testLogger())
+	return handler.NewMandateHandlerFactory(f)
+}
+
+func serveMandate(h *handler.MandateHandler, method, target string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func activeMandate(id string) *entity.Mandate {
+	start := time.Now().UTC().Add(time.Hour)
+	return &entity.Mandate{
+		ID:         id,
+		FromUserID: "user-1",
+		ToUserID:   "user-2",
+		Amount:     1000,
+		Currency:   "USD",
+		Schedule:   "@monthly",
+		StartAt:    start,
+		NextRunAt:  start,
+		Status:     entity.MandateActive,
+	}
+}
+
+func TestHandleCreateMandate_Returns201(t *testing.T) {
+	h := newTestMandateHandler(&stubMandateRepository{})
+
+	body, _ := json.Marshal(map[string]any{
+		"from_user_id": "user-1",
+		"to_user_id":   "user-2",
+		"amount":       1000,
+		"schedule":     "@weekly",
+	})
+	rec := serveMandate(h, http.MethodPost, "/api/v1/mandates", body)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rec.Code)
+	}
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Data["schedule"] != "@weekly" || resp.Data["status"] != string(entity.MandateActive) {
+		t.Fatalf("unexpected response data: %v", resp.Data)
+	}
+}
+
+func TestHandleCreateMandate_InvalidSchedule_Returns400(t *testing.T) {
+	h := newTestMandateHandler(&stubMandateRepository{})
+
+	body, _ := json.Marshal(map[string]any{
+		"from_user_id": "user-1",
+		"to_user_id":   "user-2",
+		"amount":       1000,
+		"schedule":     "@every 1m",
+	})
+	rec := serveMandate(h, http.MethodPost, "/api/v1/mandates", body)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestHandleGetMandate_NotFound_Returns404(t *testing.T) {
+	h := newTestMandateHandler(&stubMandateRepository{})
+
+	rec := serveMandate(h, http.MethodGet, "/api/v1/mandates/m-1", nil)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestHandleListMandates_Returns200(t *testing.T) {
+	repo := &stubMandateRepository{
+		listByUserFn: func(_ context.Context, fromUserID string) ([]*entity.Mandate, error) {
+			if fromUserID != "user-1" {
+				t.Fatalf("expected user-1, got %s", fromUserID)
+			}
+			return []*entity.Mandate{activeMandate("m-1")}, nil
+		},
+	}
+	h := newTestMandateHandler(repo)
+
+	rec := serveMandate(h, http.MethodGet, "/api/v1/users/user-1/mandates", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Data struct {
+			Items []map[string]any `json:"items"`
+		} `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if len(resp.Data.Items) != 1 || resp.Data.Items[0]["id"] != "m-1" {
+		t.Fatalf("unexpected response data: %+v", resp.Data)
+	}
+}
+
+func TestHandleUpdateMandate_Returns200(t *testing.T) {
+	repo := &stubMandateRepository{
+		findByIDFn: func(_ context.Context, id string) (*entity.Mandate, error) {
+			return activeMandate(id), nil
+		},
+	}
+	h := newTestMandateHandler(repo)
+
+	body, _ := json.Marshal(map[string]any{"amount": 2500})
+	rec := serveMandate(h, http.MethodPatch, "/api/v1/mandates/m-1", body)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Data["amount"] != float64(2500) {
+		t.Fatalf("unexpected response data: %v", resp.Data)
+	}
+}
+
+func TestHandleCancelMandate_AlreadyCancelled_Returns409(t *testing.T) {
+	repo := &stubMandateRepository{
+		findByIDFn: func(_ context.Context, id string) (*entity.Mandate, error) {
+			m := activeMandate(id)
+			m.Status = entity.MandateCancelled
+			return m, nil
+		},
+	}
+	h := newTestMandateHandler(repo)
+
+	rec := serveMandate(h, http.MethodDelete, "/api/v1/mandates/m-1", nil)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+}
@@ -2,53 +2,151 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
-	apperrors "transaction-service/internal/core/errors"
 	"transaction-service/internal/core/usecase"
 )
 
 type (
 	// Handler serves the transaction HTTP endpoints.
 	Handler struct {
-		create  *usecase.CreateTransactionUseCase
-		status  *usecase.GetTransactionStatusUseCase
-		balance *usecase.GetBalanceUseCase
+		create    *usecase.CreateTransactionUseCase
+		batch     *usecase.CreateBatchUseCase
+		status    *usecase.GetTransactionStatusUseCase
+		balance   *usecase.GetBalanceUseCase
+		list      *usecase.ListTransactionsUseCase
+		search    *usecase.SearchTransactionsUseCase
+		reverse   *usecase.ReverseTransactionUseCase
+		capture   *usecase.CaptureHoldUseCase
+		void      *usecase.VoidHoldUseCase
+		history   *usecase.GetTransactionHistoryUseCase
+		cancel    *usecase.CancelScheduledUseCase
+		statement *usecase.ExportStatementUseCase
 		Base
 	}
 
-	// CreateReq is the request body for creating a transaction.
+	// CreateReq is the request body for creating a transaction. Amount is in
+	// minor units of currency (default USD); a to_currency other than
+	// currency credits the receiver at conversion_rate units of to_currency
+	// per unit of currency. With authorize_only set it places a hold that
+	// must be captured or voided within hold_ttl_seconds (default 7 days).
+	// An execute_at in the future schedules the transfer for that time.
+	// Metadata is up to 20 string pairs of the client's own references,
+	// keys up to 40 and values up to 500 characters.
 	CreateReq struct {
-		FromUserID  string `json:"from_user_id"  example:"user-abc"`
-		ToUserID    string `json:"to_user_id"    example:"user-xyz"`
-		Amount      int64  `json:"amount"        example:"1000"`
-		Description string `json:"description"   example:"payment for services"`
+		FromUserID     string            `json:"from_user_id"      example:"user-abc"`
+		ToUserID       string            `json:"to_user_id"        example:"user-xyz"`
+		Amount         int64             `json:"amount"            example:"1000"`
+		Currency       string            `json:"currency"          example:"USD"`
+		ToCurrency     string            `json:"to_currency"       example:"EUR"`
+		ConversionRate string            `json:"conversion_rate"   example:"0.9215"`
+		Description    string            `json:"description"       example:"payment for services"`
+		AuthorizeOnly  bool              `json:"authorize_only"    example:"false"`
+		HoldTTLSeconds int64             `json:"hold_ttl_seconds"  example:"3600"`
+		ExecuteAt      *time.Time        `json:"execute_at"        example:"2026-11-01T09:00:00Z"`
+		Metadata       map[string]string `json:"metadata"          example:"order_id:ord-42"`
+	}
+
+	// BatchReq is the request body for submitting many transfers at once.
+	// Mode is atomic (the default), where any failing item leaves the whole
+	// batch uncommitted, or best_effort, where every item that can be
+	// created is.
+	BatchReq struct {
+		Mode  string         `json:"mode"   example:"atomic"`
+		Items []BatchItemReq `json:"items"`
+	}
+
+	// BatchItemReq is one transfer of a batch. Its fields mean what they do
+	// in CreateReq; idempotency_key takes the place of the Idempotency-Key
+	// header and is scoped to the sender like it.
+	BatchItemReq struct {
+		FromUserID     string            `json:"from_user_id"     example:"user-abc"`
+		ToUserID       string            `json:"to_user_id"       example:"user-xyz"`
+		Amount         int64             `json:"amount"           example:"1000"`
+		Currency       string            `json:"currency"         example:"USD"`
+		ToCurrency     string            `json:"to_currency"      example:"EUR"`
+		ConversionRate string            `json:"conversion_rate"  example:"0.9215"`
+		Description    string            `json:"description"      example:"salary"`
+		IdempotencyKey string            `json:"idempotency_key"  example:"payroll-2026-10-emp-42"`
+		Metadata       map[string]string `json:"metadata"         example:"employee_id:emp-42"`
+	}
+
+	// ReverseReq is the optional request body for reversing a transaction.
+	// A zero amount reverses the full original amount.
+	ReverseReq struct {
+		Amount      int64  `json:"amount"       example:"500"`
+		Description string `json:"description"  example:"refund"`
+	}
+
+	// CaptureReq is the optional request body for capturing a hold. A zero
+	// amount captures the full authorized amount.
+	CaptureReq struct {
+		Amount int64 `json:"amount"  example:"800"`
 	}
 )
 
 func NewHandler(
 	create *usecase.CreateTransactionUseCase,
+	batch *usecase.CreateBatchUseCase,
 	status *usecase.GetTransactionStatusUseCase,
 	balance *usecase.GetBalanceUseCase,
+	list *usecase.ListTransactionsUseCase,
+	search *usecase.SearchTransactionsUseCase,
+	reverse *usecase.ReverseTransactionUseCase,
+	capture *usecase.CaptureHoldUseCase,
+	void *usecase.VoidHoldUseCase,
+	history *usecase.GetTransactionHistoryUseCase,
+	cancel *usecase.CancelScheduledUseCase,
+	statement *usecase.ExportStatementUseCase,
 ) *Handler {
-	return &Handler{create: create, status: status, balance: balance}
+	return &Handler{
+		create:    create,
+		batch:     batch,
+		status:    status,
+		balance:   balance,
+		list:      list,
+		search:    search,
+		reverse:   reverse,
+		capture:   capture,
+		void:      void,
+		history:   history,
+		cancel:    cancel,
+		statement: statement,
+	}
 }
 
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /api/v1/transactions", h.wrap(h.handleCreate))
+	mux.HandleFunc("POST /api/v1/transactions:batch", h.wrap(h.handleCreateBatch))
+	mux.HandleFunc("GET /api/v1/transactions:search", h.wrap(h.handleSearch))
 	mux.HandleFunc("GET /api/v1/transactions/{id}", h.wrap(h.handleGetStatus))
+	mux.HandleFunc("GET /api/v1/transactions/{id}/history", h.wrap(h.handleGetHistory))
+	mux.HandleFunc("POST /api/v1/transactions/{id}/reversal", h.wrap(h.handleReverse))
+	mux.HandleFunc("POST /api/v1/transactions/{id}/capture", h.wrap(h.handleCapture))
+	mux.HandleFunc("POST /api/v1/transactions/{id}/void", h.wrap(h.handleVoid))
+	mux.HandleFunc("POST /api/v1/transactions/{id}/cancel", h.wrap(h.handleCancel))
 	mux.HandleFunc("GET /api/v1/balance/{userId}", h.wrap(h.handleGetBalance))
+	mux.HandleFunc("GET /api/v1/users/{userId}/transactions", h.wrap(h.handleListByUser))
+	mux.HandleFunc("GET /api/v1/users/{userId}/statement", h.wrap(h.handleStatement))
 }
 
 // handleCreate godoc
 // @Summary      Create transaction
-// @Description  Creates a new transaction and saves an outbox event atomically.
+// @Description  Creates a new transaction and saves an outbox event atomically. With authorize_only it places a hold on the sender's funds instead.
 // @Tags         transactions
 // @Accept       json
 // @Produce      json
 // @Param        request  body      CreateReq   true  "Transaction payload"
 // @Success      201      {object}  Response
 // @Failure      400      {object}  ErrResponse
+// @Failure      422      {object}  ErrResponse
 // @Failure      500      {object}  ErrResponse
 // @Router       /api/v1/transactions [post]
 func (h *Handler) handleCreate(w http.ResponseWriter, r *http.Request) error {
@@ -58,12 +156,24 @@ func (h *Handler) handleCreate(w http.ResponseWriter, r *http.Request) error {
 		return nil
 	}
 
+	var executeAt time.Time
+	if req.ExecuteAt != nil {
+		executeAt = *req.ExecuteAt
+	}
+
 	out, err := h.create.Execute(r.Context(), usecase.CreateInput{
 		FromUserID:     req.FromUserID,
 		ToUserID:       req.ToUserID,
 		Amount:         req.Amount,
+		Currency:       req.Currency,
+		ToCurrency:     req.ToCurrency,
+		ConversionRate: req.ConversionRate,
 		Description:    req.Description,
 		IdempotencyKey: r.Header.Get("Idempotency-Key"),
+		AuthorizeOnly:  req.AuthorizeOnly,
+		HoldTTL:        time.Duration(req.HoldTTLSeconds) * time.Second,
+		ExecuteAt:      executeAt,
+		Metadata:       req.Metadata,
 	})
 	if err != nil {
 		return err
@@ -76,16 +186,73 @@ func (h *Handler) handleCreate(w http.ResponseWriter, r *http.Request) error {
 		message = "transaction already exists"
 	}
 
-	h.RespondWithSuccess(w, statusCode, message, map[string]string{
-		"id":     out.ID,
-		"status": out.Status,
+	data := map[string]any{
+		"id":       out.ID,
+		"status":   out.Status,
+		"currency": out.Currency,
+	}
+	if out.Conversion != nil {
+		data["conversion"] = out.Conversion
+	}
+	if out.ExpiresAt != nil {
+		data["expires_at"] = out.ExpiresAt
+	}
+	if out.ExecuteAt != nil {
+		data["execute_at"] = out.ExecuteAt
+	}
+
+	h.RespondWithSuccess(w, statusCode, message, data)
+	return nil
+}
+
+// handleCreateBatch godoc
+// @Summary      Create transactions in bulk
+// @Description  Validates and creates up to 5000 transfers, reporting an outcome per item: created, existing (replayed by idempotency key), failed or aborted (valid, but not committed because another item of an atomic batch failed).
+// @Tags         transactions
+// @Accept       json
+// @Produce      json
+// @Param        request  body      BatchReq    true  "Batch of transfers"
+// @Success      200      {object}  Response
+// @Failure      400      {object}  ErrResponse
+// @Failure      500      {object}  ErrResponse
+// @Router       /api/v1/transactions:batch [post]
+func (h *Handler) handleCreateBatch(w http.ResponseWriter, r *http.Request) error {
+	var req BatchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.RespondWithError(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return nil
+	}
+
+	items := make([]usecase.BatchItem, len(req.Items))
+	for i, it := range req.Items {
+		items[i] = usecase.BatchItem{
+			FromUserID:     it.FromUserID,
+			ToUserID:       it.ToUserID,
+			Amount:         it.Amount,
+			Currency:       it.Currency,
+			ToCurrency:     it.ToCurrency,
+			ConversionRate: it.ConversionRate,
+			Description:    it.Description,
+			IdempotencyKey: it.IdempotencyKey,
+			Metadata:       it.Metadata,
+		}
+	}
+
+	out, err := h.batch.Execute(r.Context(), usecase.CreateBatchInput{
+		Mode:  usecase.BatchMode(req.Mode),
+		Items: items,
 	})
+	if err != nil {
+		return err
+	}
+
+	h.RespondWithSuccess(w, http.StatusOK, "batch processed", out)
 	return nil
 }
 
 // handleGetStatus godoc
-// @Summary      Get transaction status
-// @Description  Returns the current status of a transaction by ID.
+// @Summary      Get transaction
+// @Description  Returns a transaction by ID with its current status, amounts, parties and timestamps.
 // @Tags         transactions
 // @Produce      json
 // @Param        id   path      string  true  "Transaction ID"
@@ -110,15 +277,173 @@ func (h *Handler) handleGetStatus(w http.ResponseWriter, r *http.Request) error
 	return nil
 }
 
+// handleGetHistory godoc
+// @Summary      Get transaction status history
+// @Description  Returns every status change of a transaction, oldest first, with the actor that made it and why.
+// @Tags         transactions
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Success      200  {object}  Response
+// @Failure      400  {object}  ErrResponse
+// @Failure      404  {object}  ErrResponse
+// @Failure      500  {object}  ErrResponse
+// @Router       /api/v1/transactions/{id}/history [get]
+func (h *Handler) handleGetHistory(w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		h.RespondWithError(w, r, http.StatusBadRequest, "missing parameter", "transaction id is required")
+		return nil
+	}
+
+	out, err := h.history.Execute(r.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	h.RespondWithSuccess(w, http.StatusOK, "ok", out)
+	return nil
+}
+
+// handleReverse godoc
+// @Summary      Reverse transaction
+// @Description  Creates a compensating transaction for a COMPLETED one. Partial reversals may not exceed the original amount in total.
+// @Tags         transactions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string      true   "Transaction ID"
+// @Param        request  body      ReverseReq  false  "Amount to reverse (default: full amount)"
+// @Success      201      {object}  Response
+// @Failure      400      {object}  ErrResponse
+// @Failure      404      {object}  ErrResponse
+// @Failure      409      {object}  ErrResponse
+// @Failure      422      {object}  ErrResponse
+// @Failure      500      {object}  ErrResponse
+// @Router       /api/v1/transactions/{id}/reversal [post]
+func (h *Handler) handleReverse(w http.ResponseWriter, r *http.Request) error {
+	var req ReverseReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.RespondWithError(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return nil
+	}
+
+	out, err := h.reverse.Execute(r.Context(), usecase.ReverseInput{
+		TransactionID: r.PathValue("id"),
+		Amount:        req.Amount,
+		Description:   req.Description,
+	})
+	if err != nil {
+		return err
+	}
+
+	h.RespondWithSuccess(w, http.StatusCreated, "transaction reversed", map[string]any{
+		"id":          out.ID,
+		"reversal_of": out.ReversalOf,
+		"amount":      out.Amount,
+		"status":      out.Status,
+	})
+	return nil
+}
+
+// handleCapture godoc
+// @Summary      Capture hold
+// @Description  Captures an AUTHORIZED hold fully or partially, paying the receiver and releasing the rest to the sender.
+// @Tags         transactions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string      true   "Transaction ID"
+// @Param        request  body      CaptureReq  false  "Amount to capture (default: full authorized amount)"
+// @Success      200      {object}  Response
+// @Failure      400      {object}  ErrResponse
+// @Failure      404      {object}  ErrResponse
+// @Failure      409      {object}  ErrResponse
+// @Failure      422      {object}  ErrResponse
+// @Failure      500      {object}  ErrResponse
+// @Router       /api/v1/transactions/{id}/capture [post]
+func (h *Handler) handleCapture(w http.ResponseWriter, r *http.Request) error {
+	var req CaptureReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.RespondWithError(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return nil
+	}
+
+	out, err := h.capture.Execute(r.Context(), usecase.CaptureInput{
+		TransactionID: r.PathValue("id"),
+		Amount:        req.Amount,
+	})
+	if err != nil {
+		return err
+	}
+
+	h.RespondWithSuccess(w, http.StatusOK, "hold captured", map[string]any{
+		"id":       out.ID,
+		"status":   out.Status,
+		"captured": out.Captured,
+		"released": out.Released,
+	})
+	return nil
+}
+
+// handleVoid godoc
+// @Summary      Void hold
+// @Description  Releases an AUTHORIZED hold back to the sender.
+// @Tags         transactions
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Success      200  {object}  Response
+// @Failure      400  {object}  ErrResponse
+// @Failure      404  {object}  ErrResponse
+// @Failure      409  {object}  ErrResponse
+// @Failure      500  {object}  ErrResponse
+// @Router       /api/v1/transactions/{id}/void [post]
+func (h *Handler) handleVoid(w http.ResponseWriter, r *http.Request) error {
+	out, err := h.void.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+
+	h.RespondWithSuccess(w, http.StatusOK, "hold voided", map[string]any{
+		"id":       out.ID,
+		"status":   out.Status,
+		"released": out.Released,
+	})
+	return nil
+}
+
+// handleCancel godoc
+// @Summary      Cancel scheduled transaction
+// @Description  Cancels a SCHEDULED transaction before the scheduler releases it. No funds have moved yet.
+// @Tags         transactions
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Success      200  {object}  Response
+// @Failure      404  {object}  ErrResponse
+// @Failure      409  {object}  ErrResponse
+// @Failure      500  {object}  ErrResponse
+// @Router       /api/v1/transactions/{id}/cancel [post]
+func (h *Handler) handleCancel(w http.ResponseWriter, r *http.Request) error {
+	out, err := h.cancel.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+
+	h.RespondWithSuccess(w, http.StatusOK, "transaction cancelled", map[string]any{
+		"id":     out.ID,
+		"status": out.Status,
+	})
+	return nil
+}
+
 // handleGetBalance godoc
 // @Summary      Get user balance
-// @Description  Returns the net balance for a given user ID.
+// @Description  Returns the ledger balance and the available balance, net of open holds, for a given user ID in one currency. With as_of it returns the ledger balance of the transactions processed by then, and the cutoff used.
 // @Tags         balance
 // @Produce      json
-// @Param        userId  path      string  true  "User ID"
-// @Success      200     {object}  Response
-// @Failure      400     {object}  ErrResponse
-// @Failure      500     {object}  ErrResponse
+// @Param        userId    path      string  true   "User ID"
+// @Param        currency  query     string  false  "ISO 4217 currency code (default USD)"
+// @Param        as_of     query     string  false  "Point in time, inclusive (RFC3339, default now)"
+// @Success      200       {object}  Response
+// @Failure      400       {object}  ErrResponse
+// @Failure      500       {object}  ErrResponse
 // @Router       /api/v1/balance/{userId} [get]
 func (h *Handler) handleGetBalance(w http.ResponseWriter, r *http.Request) error {
 	userID := r.PathValue("userId")
@@ -127,7 +452,82 @@ func (h *Handler) handleGetBalance(w http.ResponseWriter, r *http.Request) error
 		return nil
 	}
 
-	out, err := h.balance.Execute(r.Context(), usecase.BalanceInput{UserID: userID})
+	asOf, err := optionalTime(r.URL.Query().Get("as_of"))
+	if err != nil {
+		h.RespondWithError(w, r, http.StatusBadRequest, "invalid parameter", "as_of must be an RFC3339 timestamp")
+		return nil
+	}
+
+	out, err := h.balance.Execute(r.Context(), usecase.BalanceInput{
+		UserID:   userID,
+		Currency: r.URL.Query().Get("currency"),
+		AsOf:     asOf,
+	})
+	if err != nil {
+		return err
+	}
+
+	h.RespondWithSuccess(w, http.StatusOK, "ok", out)
+	return nil
+}
+
+// handleListByUser godoc
+// @Summary      List user transactions
+// @Description  Returns the transactions a user sent or received, newest first, using keyset pagination.
+// @Tags         transactions
+// @Produce      json
+// @Param        userId      path      string  true   "User ID"
+// @Param        direction   query     string  false  "sent or received"
+// @Param        status      query     string  false  "Transaction status"
+// @Param        min_amount  query     int     false  "Minimum amount (inclusive)"
+// @Param        max_amount  query     int     false  "Maximum amount (inclusive)"
+// @Param        from        query     string  false  "Created at or after (RFC3339)"
+// @Param        to          query     string  false  "Created before (RFC3339)"
+// @Param        cursor      query     string  false  "next_cursor of the previous page"
+// @Param        limit       query     int     false  "Page size (1-100, default 20)"
+// @Param        metadata    query     string  false  "metadata[key]=value, repeatable with other keys; matches transactions carrying every pair"
+// @Success      200         {object}  Response
+// @Failure      400         {object}  ErrResponse
+// @Failure      500         {object}  ErrResponse
+// @Router       /api/v1/users/{userId}/transactions [get]
+func (h *Handler) handleListByUser(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	input := usecase.ListInput{
+		UserID:    r.PathValue("userId"),
+		Direction: query.Get("direction"),
+		Status:    query.Get("status"),
+		Cursor:    query.Get("cursor"),
+	}
+
+	var err error
+	if input.MinAmount, err = optionalInt64(query.Get("min_amount")); err != nil {
+		h.RespondWithError(w, r, http.StatusBadRequest, "invalid parameter", "min_amount must be an integer")
+		return nil
+	}
+	if input.MaxAmount, err = optionalInt64(query.Get("max_amount")); err != nil {
+		h.RespondWithError(w, r, http.StatusBadRequest, "invalid parameter", "max_amount must be an integer")
+		return nil
+	}
+	if input.From, err = optionalTime(query.Get("from")); err != nil {
+		h.RespondWithError(w, r, http.StatusBadRequest, "invalid parameter", "from must be an RFC3339 timestamp")
+		return nil
+	}
+	if input.To, err = optionalTime(query.Get("to")); err != nil {
+		h.RespondWithError(w, r, http.StatusBadRequest, "invalid parameter", "to must be an RFC3339 timestamp")
+		return nil
+	}
+	if v := query.Get("limit"); v != "" {
+		if input.Limit, err = strconv.Atoi(v); err != nil {
+			h.RespondWithError(w, r, http.StatusBadRequest, "invalid parameter", "limit must be an integer")
+			return nil
+		}
+	}
+	if input.Metadata, err = metadataFilter(query); err != nil {
+		h.RespondWithError(w, r, http.StatusBadRequest, "invalid parameter", err.Error())
+		return nil
+	}
+
+	out, err := h.list.Execute(r.Context(), input)
 	if err != nil {
 		return err
 	}
@@ -136,18 +536,142 @@ func (h *Handler) handleGetBalance(w http.ResponseWriter, r *http.Request) error
 	return nil
 }
 
-func (h *Handler) wrap(fn func(http.ResponseWriter, *http.Request) error) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		err := fn(w, r)
-		if err == nil {
-			return
+// handleSearch godoc
+// @Summary      Search transactions
+// @Description  Full-text search over transaction descriptions, most relevant first, with a snippet of each description whose matches are wrapped in <mark>. q accepts words, "quoted phrases", or and -excluded words.
+// @Tags         transactions
+// @Produce      json
+// @Param        q        query     string  true   "Search text (at most 200 characters)"
+// @Param        user_id  query     string  false  "Only transactions this user sent or received"
+// @Param        cursor   query     string  false  "next_cursor of the previous page"
+// @Param        limit    query     int     false  "Page size (1-100, default 20)"
+// @Success      200      {object}  Response
+// @Failure      400      {object}  ErrResponse
+// @Failure      500      {object}  ErrResponse
+// @Router       /api/v1/transactions:search [get]
+func (h *Handler) handleSearch(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	input := usecase.SearchInput{
+		Query:  query.Get("q"),
+		UserID: query.Get("user_id"),
+		Cursor: query.Get("cursor"),
+	}
+	if v := query.Get("limit"); v != "" {
+		var err error
+		if input.Limit, err = strconv.Atoi(v); err != nil {
+			h.RespondWithError(w, r, http.StatusBadRequest, "invalid parameter", "limit must be an integer")
+			return nil
+		}
+	}
+
+	out, err := h.search.Execute(r.Context(), input)
+	if err != nil {
+		return err
+	}
+
+	h.RespondWithSuccess(w, http.StatusOK, "ok", out)
+	return nil
+}
+
+// handleStatement godoc
+// @Summary      Export account statement
+// @Description  Streams the postings of a user's account in one currency over a period, with the opening and closing balances, as CSV with a running balance per line or as OFX.
+// @Tags         transactions
+// @Produce      text/csv
+// @Produce      application/x-ofx
+// @Param        userId    path      string  true   "User ID"
+// @Param        from      query     string  true   "Period start, inclusive (RFC3339)"
+// @Param        to        query     string  false  "Period end, exclusive (RFC3339, default now)"
+// @Param        currency  query     string  false  "ISO 4217 currency code (default USD)"
+// @Param        format    query     string  false  "csv (default) or ofx"
+// @Success      200       {file}    file
+// @Failure      400       {object}  ErrResponse
+// @Failure      500       {object}  ErrResponse
+// @Router       /api/v1/users/{userId}/statement [get]
+func (h *Handler) handleStatement(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	input := usecase.StatementInput{
+		UserID:   r.PathValue("userId"),
+		Currency: query.Get("currency"),
+		Format:   query.Get("format"),
+	}
+
+	var err error
+	if input.From, err = optionalTime(query.Get("from")); err != nil {
+		h.RespondWithError(w, r, http.StatusBadRequest, "invalid parameter", "from must be an RFC3339 timestamp")
+		return nil
+	}
+	if input.To, err = optionalTime(query.Get("to")); err != nil {
+		h.RespondWithError(w, r, http.StatusBadRequest, "invalid parameter", "to must be an RFC3339 timestamp")
+		return nil
+	}
+
+	// A long history takes longer to stream than the server's write timeout.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	sink := &statementResponse{ResponseWriter: w}
+	if err := h.statement.Execute(r.Context(), input, sink); err != nil {
+		if sink.started {
+			// The 200 is already out; aborting the connection keeps a
+			// truncated statement from passing for a complete one.
+			panic(http.ErrAbortHandler)
 		}
+		return err
+	}
+	return nil
+}
+
+// statementResponse sends the statement headers when the use case starts
+// writing, so an error before that is still answered as JSON.
+type statementResponse struct {
+	http.ResponseWriter
+	started bool
+}
 
-		if exc, ok := err.(*apperrors.Exception); ok {
-			h.RespondWithError(w, r, exc.Code, exc.Message, exc.Err)
-			return
+func (s *statementResponse) Start(contentType, filename string) {
+	s.started = true
+	s.Header().Set("Content-Type", contentType)
+	s.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	s.WriteHeader(http.StatusOK)
+}
+
+// metadataFilter collects the metadata[key]=value query parameters.
+func metadataFilter(query url.Values) (map[string]string, error) {
+	var filter map[string]string
+	for param, values := range query {
+		key, ok := strings.CutPrefix(param, "metadata[")
+		if !ok {
+			continue
+		}
+		key, ok = strings.CutSuffix(key, "]")
+		if !ok {
+			return nil, fmt.Errorf("%s must be written metadata[key]", param)
 		}
+		if len(values) > 1 {
+			return nil, fmt.Errorf("%s must be given once", param)
+		}
+		if filter == nil {
+			filter = make(map[string]string)
+		}
+		filter[key] = values[0]
+	}
+	return filter, nil
+}
+
+func optionalInt64(v string) (*int64, error) {
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
 
-		h.RespondWithError(w, r, http.StatusInternalServerError, "internal server error", err.Error())
+func optionalTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
 	}
+	return time.Parse(time.RFC3339, v)
 }
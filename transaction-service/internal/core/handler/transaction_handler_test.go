@@ -8,9 +8,12 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
 	apperrors "transaction-service/internal/core/errors"
 	"transaction-service/internal/core/handler"
 	"transaction-service/internal/core/usecase"
@@ -23,18 +26,39 @@ func testLogger() *slog.Logger {
 // stubTransactionRepository implements ports.TransactionRepository for handler tests.
 type stubTransactionRepository struct {
 	createFn               func(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error
+	createBatchFn          func(ctx context.Context, writes []ports.TransactionWrite, atomic bool) ([]error, error)
 	findByIDFn             func(ctx context.Context, id string) (*entity.Transaction, error)
-	findByIdempotencyKeyFn func(ctx context.Context, key string) (*entity.Transaction, error)
-	getBalanceFn           func(ctx context.Context, userID string) (int64, error)
+	findByIdempotencyKeyFn func(ctx context.Context, fromUserID, key string) (*entity.Transaction, error)
+	getBalanceFn           func(ctx context.Context, userID, currency string) (int64, error)
+	getBalanceAsOfFn       func(ctx context.Context, userID, currency string, asOf time.Time) (int64, error)
+	settleFn               func(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error
+	listByUserFn           func(ctx context.Context, q ports.TransactionQuery, limit int) ([]*entity.Transaction, error)
+	searchFn               func(ctx context.Context, q ports.SearchQuery, limit int) ([]entity.SearchHit, error)
+	reverseFn              func(ctx context.Context, reversal *entity.Transaction, outbox *entity.Outbox) error
+	getAvailableBalanceFn  func(ctx context.Context, userID, currency string) (int64, error)
+	captureHoldFn          func(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error
+	releaseHoldFn          func(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error
+	findExpiredHoldsFn     func(ctx context.Context, now time.Time, limit int) ([]*entity.Transaction, error)
+	findStatusHistoryFn    func(ctx context.Context, transactionID string) ([]*entity.StatusChange, error)
+	resolveScheduledFn     func(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error
+	resolveHeldFn          func(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error
+	streamStatementFn      func(ctx context.Context, q ports.StatementQuery, opening func(int64) error, posting func(entity.StatementLine) error) error
 }
 
-func (s *stubTransactionRepository) Create(_ context.Context, tx *entity.Transaction, outbox *entity.Outbox) error {
+func (s *stubTransactionRepository) Create(_ context.Context, tx *entity.Transaction, _ *entity.StatusChange, outbox *entity.Outbox) error {
 	if s.createFn != nil {
 		return s.createFn(context.Background(), tx, outbox)
 	}
 	return nil
 }
 
+func (s *stubTransactionRepository) CreateBatch(_ context.Context, writes []ports.TransactionWrite, atomic bool) ([]error, error) {
+	if s.createBatchFn != nil {
+		return s.createBatchFn(context.Background(), writes, atomic)
+	}
+	return make([]error, len(writes)), nil
+}
+
 func (s *stubTransactionRepository) FindByID(_ context.Context, id string) (*entity.Transaction, error) {
 	if s.findByIDFn != nil {
 		return s.findByIDFn(context.Background(), id)
@@ -42,22 +66,155 @@ func (s *stubTransactionRepository) FindByID(_ context.Context, id string) (*ent
 	return nil, nil
 }
 
-func (s *stubTransactionRepository) FindByIdempotencyKey(_ context.Context, key string) (*entity.Transaction, error) {
+func (s *stubTransactionRepository) FindByIdempotencyKey(_ context.Context, fromUserID, key string) (*entity.Transaction, error) {
 	if s.findByIdempotencyKeyFn != nil {
-		return s.findByIdempotencyKeyFn(context.Background(), key)
+		return s.findByIdempotencyKeyFn(context.Background(), fromUserID, key)
 	}
 	return nil, nil
 }
 
-func (s *stubTransactionRepository) GetBalance(_ context.Context, userID string) (int64, error) {
+func (s *stubTransactionRepository) FindByIdempotencyKeys(_ context.Context, _ []ports.IdempotencyRef) ([]*entity.Transaction, error) {
+	return nil, nil
+}
+
+func (s *stubTransactionRepository) GetBalance(_ context.Context, userID, currency string) (int64, error) {
 	if s.getBalanceFn != nil {
-		return s.getBalanceFn(context.Background(), userID)
+		return s.getBalanceFn(context.Background(), userID, currency)
+	}
+	return 0, nil
+}
+
+func (s *stubTransactionRepository) GetBalanceAsOf(_ context.Context, userID, currency string, asOf time.Time) (int64, error) {
+	if s.getBalanceAsOfFn != nil {
+		return s.getBalanceAsOfFn(context.Background(), userID, currency, asOf)
+	}
+	return 0, nil
+}
+
+func (s *stubTransactionRepository) Settle(_ context.Context, tx *entity.Transaction, _ *entity.StatusChange, outbox *entity.Outbox) error {
+	if s.settleFn != nil {
+		return s.settleFn(context.Background(), tx, outbox)
+	}
+	return nil
+}
+
+func (s *stubTransactionRepository) ListByUser(_ context.Context, q ports.TransactionQuery, limit int) ([]*entity.Transaction, error) {
+	if s.listByUserFn != nil {
+		return s.listByUserFn(context.Background(), q, limit)
+	}
+	return nil, nil
+}
+
+func (s *stubTransactionRepository) Search(_ context.Context, q ports.SearchQuery, limit int) ([]entity.SearchHit, error) {
+	if s.searchFn != nil {
+		return s.searchFn(context.Background(), q, limit)
+	}
+	return nil, nil
+}
+
+func (s *stubTransactionRepository) Reverse(_ context.Context, reversal *entity.Transaction, _ *entity.StatusChange, outbox *entity.Outbox) error {
+	if s.reverseFn != nil {
+		return s.reverseFn(context.Background(), reversal, outbox)
+	}
+	return nil
+}
+
+func (s *stubTransactionRepository) Fund(_ context.Context, _ *entity.Transaction, _ *entity.StatusChange, _ *entity.Outbox) error {
+	return nil
+}
+
+func (s *stubTransactionRepository) GetAvailableBalance(_ context.Context, userID, currency string) (int64, error) {
+	if s.getAvailableBalanceFn != nil {
+		return s.getAvailableBalanceFn(context.Background(), userID, currency)
+	}
+	return 0, nil
+}
+
+func (s *stubTransactionRepository) CaptureHold(_ context.Context, tx *entity.Transaction, _ *entity.StatusChange, outbox *entity.Outbox) error {
+	if s.captureHoldFn != nil {
+		return s.captureHoldFn(context.Background(), tx, outbox)
+	}
+	return nil
+}
+
+func (s *stubTransactionRepository) ReleaseHold(_ context.Context, tx *entity.Transaction, _ *entity.StatusChange, outbox *entity.Outbox) error {
+	if s.releaseHoldFn != nil {
+		return s.releaseHoldFn(context.Background(), tx, outbox)
+	}
+	return nil
+}
+
+func (s *stubTransactionRepository) FindExpiredHolds(_ context.Context, now time.Time, limit int) ([]*entity.Transaction, error) {
+	if s.findExpiredHoldsFn != nil {
+		return s.findExpiredHoldsFn(context.Background(), now, limit)
+	}
+	return nil, nil
+}
+
+func (s *stubTransactionRepository) FindStatusHistory(_ context.Context, transactionID string) ([]*entity.StatusChange, error) {
+	if s.findStatusHistoryFn != nil {
+		return s.findStatusHistoryFn(context.Background(), transactionID)
 	}
+	return nil, nil
+}
+
+func (s *stubTransactionRepository) ReleaseIdempotencyKeys(_ context.Context, _ time.Time, _ int) (int64, error) {
 	return 0, nil
 }
 
+func (s *stubTransactionRepository) FindDueScheduled(_ context.Context, _ time.Time, _ int) ([]*entity.Transaction, error) {
+	return nil, nil
+}
+
+func (s *stubTransactionRepository) ResolveScheduled(_ context.Context, tx *entity.Transaction, _ *entity.StatusChange, outbox *entity.Outbox) error {
+	if s.resolveScheduledFn != nil {
+		return s.resolveScheduledFn(context.Background(), tx, outbox)
+	}
+	return nil
+}
+
+func (s *stubTransactionRepository) ResolveHeld(_ context.Context, tx *entity.Transaction, _ *entity.StatusChange, outbox *entity.Outbox) error {
+	if s.resolveHeldFn != nil {
+		return s.resolveHeldFn(context.Background(), tx, outbox)
+	}
+	return nil
+}
+
+func (s *stubTransactionRepository) StreamStatement(_ context.Context, q ports.StatementQuery, opening func(int64) error, posting func(entity.StatementLine) error) error {
+	if s.streamStatementFn != nil {
+		return s.streamStatementFn(context.Background(), q, opening, posting)
+	}
+	return opening(0)
+}
+
+// stubRiskEvaluator allows every transfer.
+type stubRiskEvaluator struct{}
+
+func (stubRiskEvaluator) Evaluate(_ context.Context, _ *entity.Transaction, _ []*entity.Transaction) (entity.RiskAssessment, error) {
+	return entity.RiskAssessment{Decision: entity.RiskAllow}, nil
+}
+
+func (stubRiskEvaluator) Screen(_ *entity.Transaction) []string { return nil }
+
+// stubKnownUserRepository treats every user as known unless missing is set.
+type stubKnownUserRepository struct {
+	missing []string
+}
+
+func (s *stubKnownUserRepository) Save(_ context.Context, _ *entity.KnownUser) (bool, error) {
+	return true, nil
+}
+
+func (s *stubKnownUserRepository) FindMissing(_ context.Context, _ []string) ([]string, error) {
+	return s.missing, nil
+}
+
 func newTestHandler(repo *stubTransactionRepository) *handler.Handler {
-	f := usecase.NewFactory(repo, testLogger())
+	return newTestHandlerWithUsers(repo, &stubKnownUserRepository{})
+}
+
+func newTestHandlerWithUsers(repo *stubTransactionRepository, users *stubKnownUserRepository) *handler.Handler {
+	f := usecase.NewFactory(repo, &stubMandateRepository{}, users, stubRiskEvaluator{}, testLogger())
 	return handler.NewHandlerFactory(f)
 }
 
@@ -81,10 +238,36 @@ func TestHandleCreate_Returns201(t *testing.T) {
 	}
 }
 
+func TestHandleCreate_ForwardsMetadata(t *testing.T) {
+	var created *entity.Transaction
+	repo := &stubTransactionRepository{
+		createFn: func(_ context.Context, tx *entity.Transaction, _ *entity.Outbox) error {
+			created = tx
+			return nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	body := `{"from_user_id": "user-1", "to_user_id": "user-2", "amount": 1000, "metadata": {"order_id": "ord-42"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rec.Code)
+	}
+	if created == nil || created.Metadata["order_id"] != "ord-42" {
+		t.Fatalf("expected the metadata on the transaction, got %+v", created)
+	}
+}
+
 func TestHandleCreate_IdempotentReturns200(t *testing.T) {
 	existing := &entity.Transaction{ID: "tx-existing", Status: entity.StatusPending}
 	repo := &stubTransactionRepository{
-		findByIdempotencyKeyFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+		findByIdempotencyKeyFn: func(_ context.Context, _, _ string) (*entity.Transaction, error) {
 			return existing, nil
 		},
 	}
@@ -143,6 +326,51 @@ func TestHandleCreate_ValidationError_Returns400(t *testing.T) {
 	}
 }
 
+func TestHandleCreate_UnknownUser_Returns422(t *testing.T) {
+	h := newTestHandlerWithUsers(&stubTransactionRepository{}, &stubKnownUserRepository{missing: []string{"user-2"}})
+
+	body, _ := json.Marshal(map[string]any{
+		"from_user_id": "user-1",
+		"to_user_id":   "user-2",
+		"amount":       100,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", rec.Code)
+	}
+}
+
+func TestHandleCreate_InsufficientFunds_Returns422(t *testing.T) {
+	repo := &stubTransactionRepository{
+		createFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+			return entity.ErrInsufficientFunds
+		},
+	}
+	h := newTestHandler(repo)
+
+	body, _ := json.Marshal(map[string]any{
+		"from_user_id": "user-1",
+		"to_user_id":   "user-2",
+		"amount":       100,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", rec.Code)
+	}
+}
+
 func TestHandleGetStatus_Returns200(t *testing.T) {
 	repo := &stubTransactionRepository{
 		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
@@ -205,7 +433,7 @@ func TestHandleGetStatus_RepoError_Returns500(t *testing.T) {
 
 func TestHandleGetBalance_Returns200(t *testing.T) {
 	repo := &stubTransactionRepository{
-		getBalanceFn: func(_ context.Context, _ string) (int64, error) {
+		getBalanceFn: func(_ context.Context, _, _ string) (int64, error) {
 			return 2500, nil
 		},
 	}
@@ -222,3 +450,808 @@ func TestHandleGetBalance_Returns200(t *testing.T) {
 		t.Fatalf("expected 200, got %d", rec.Code)
 	}
 }
+
+// listUserID is a user ID the listing and the statement accept; it must be
+// a UUID.
+const listUserID = "7f9c2c1e-4a57-4c4b-9d1a-0d3c5e0b8a11"
+
+func TestHandleListByUser_Returns200(t *testing.T) {
+	var captured ports.TransactionQuery
+	repo := &stubTransactionRepository{
+		listByUserFn: func(_ context.Context, q ports.TransactionQuery, _ int) ([]*entity.Transaction, error) {
+			captured = q
+			return []*entity.Transaction{{ID: "tx-1", Status: entity.StatusCompleted}}, nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/v1/users/"+listUserID+"/transactions?direction=sent&min_amount=5&from=2026-01-01T00:00:00Z&limit=10&metadata[order_id]=ord-42", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if captured.UserID != listUserID || captured.Direction != ports.DirectionSent || *captured.MinAmount != 5 || captured.From.IsZero() {
+		t.Fatalf("query parameters not forwarded: %+v", captured)
+	}
+	if len(captured.Metadata) != 1 || captured.Metadata["order_id"] != "ord-42" {
+		t.Fatalf("expected the metadata filter forwarded, got %v", captured.Metadata)
+	}
+}
+
+func TestHandleListByUser_InvalidParameters_Returns400(t *testing.T) {
+	for _, query := range []string{
+		"min_amount=ten",
+		"max_amount=1.5",
+		"from=yesterday",
+		"to=2026-13-01",
+		"limit=all",
+		"direction=sideways",
+		"metadata[order_id=ord-42",
+		"metadata[order_id]=ord-42&metadata[order_id]=ord-43",
+		"metadata[]=ord-42",
+	} {
+		t.Run(query, func(t *testing.T) {
+			h := newTestHandler(&stubTransactionRepository{})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+listUserID+"/transactions?"+query, nil)
+			rec := httptest.NewRecorder()
+
+			mux := http.NewServeMux()
+			h.RegisterRoutes(mux)
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestHandleSearch_Returns200(t *testing.T) {
+	var captured ports.SearchQuery
+	var capturedLimit int
+	repo := &stubTransactionRepository{
+		searchFn: func(_ context.Context, q ports.SearchQuery, limit int) ([]entity.SearchHit, error) {
+			captured, capturedLimit = q, limit
+			tx := &entity.Transaction{ID: "tx-1", Description: "refund of order 42", Status: entity.StatusCompleted}
+			return []entity.SearchHit{{Transaction: tx, Rank: 0.1, Snippet: "\x02refund\x03 of order 42"}}, nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions:search?q=refund+-fee&user_id="+listUserID+"&limit=5", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if captured.Text != "refund -fee" || captured.UserID != listUserID || capturedLimit != 6 {
+		t.Fatalf("query parameters not forwarded: %+v (limit %d)", captured, capturedLimit)
+	}
+	var body struct {
+		Data usecase.SearchOutput `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(body.Data.Items) != 1 || body.Data.Items[0].ID != "tx-1" || body.Data.Items[0].Snippet != "<mark>refund</mark> of order 42" {
+		t.Fatalf("unexpected items: %+v", body.Data.Items)
+	}
+}
+
+func TestHandleSearch_InvalidParameters_Returns400(t *testing.T) {
+	for _, query := range []string{"", "q=refund&limit=all", "q=refund&cursor=nope", "q=refund&user_id=user-1"} {
+		t.Run(query, func(t *testing.T) {
+			h := newTestHandler(&stubTransactionRepository{})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions:search?"+query, nil)
+			rec := httptest.NewRecorder()
+
+			mux := http.NewServeMux()
+			h.RegisterRoutes(mux)
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestHandleStatement_StreamsCSV(t *testing.T) {
+	var captured ports.StatementQuery
+	repo := &stubTransactionRepository{
+		streamStatementFn: func(_ context.Context, q ports.StatementQuery, opening func(int64) error, posting func(entity.StatementLine) error) error {
+			captured = q
+			if err := opening(1000); err != nil {
+				return err
+			}
+			return posting(entity.StatementLine{TransactionID: "tx-1", PostedAt: q.From, CounterpartyID: "user-2", Amount: -250})
+		},
+	}
+	h := newTestHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/v1/users/"+listUserID+"/statement?from=2026-09-01T00:00:00Z&to=2026-10-01T00:00:00Z&currency=EUR", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Fatalf("expected a CSV content type, got %q", ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); cd != `attachment; filename="statement-EUR-20260901-20261001.csv"` {
+		t.Fatalf("unexpected content disposition %q", cd)
+	}
+	if captured.UserID != listUserID || captured.Currency != "EUR" || captured.To.IsZero() {
+		t.Fatalf("query parameters not forwarded: %+v", captured)
+	}
+	if !strings.Contains(rec.Body.String(), "tx-1,,user-2,-2.50,EUR,7.50") {
+		t.Fatalf("expected the posting with its running balance, got %s", rec.Body.String())
+	}
+}
+
+func TestHandleStatement_InvalidParameters_Returns400(t *testing.T) {
+	for _, path := range []string{
+		listUserID + "/statement?",
+		listUserID + "/statement?from=yesterday",
+		listUserID + "/statement?from=2026-09-01T00:00:00Z&to=2026-13-01",
+		listUserID + "/statement?from=2026-09-01T00:00:00Z&format=pdf",
+		"user-1/statement?from=2026-09-01T00:00:00Z",
+	} {
+		t.Run(path, func(t *testing.T) {
+			h := newTestHandler(&stubTransactionRepository{})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+path, nil)
+			rec := httptest.NewRecorder()
+
+			mux := http.NewServeMux()
+			h.RegisterRoutes(mux)
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", rec.Code)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Fatalf("expected a JSON error, got %q", ct)
+			}
+		})
+	}
+}
+
+func TestHandleReverse_Returns201(t *testing.T) {
+	at := time.Now().UTC()
+	repo := &stubTransactionRepository{
+		findByIDFn: func(_ context.Context, id string) (*entity.Transaction, error) {
+			return &entity.Transaction{
+				ID: id, Amount: 500, FromUserID: "user-1", ToUserID: "user-2",
+				Status: entity.StatusCompleted, CreatedAt: at, ProcessedAt: &at,
+			}, nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	body, _ := json.Marshal(map[string]any{"amount": 200})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions/tx-1/reversal", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rec.Code)
+	}
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Data["reversal_of"] != "tx-1" || resp.Data["amount"] != float64(200) {
+		t.Fatalf("unexpected response data: %v", resp.Data)
+	}
+}
+
+func TestHandleReverse_EmptyBodyReversesFullAmount(t *testing.T) {
+	var reversed int64
+	repo := &stubTransactionRepository{
+		findByIDFn: func(_ context.Context, id string) (*entity.Transaction, error) {
+			return &entity.Transaction{ID: id, Amount: 500, FromUserID: "user-1", ToUserID: "user-2", Status: entity.StatusCompleted}, nil
+		},
+		reverseFn: func(_ context.Context, reversal *entity.Transaction, _ *entity.Outbox) error {
+			reversed = reversal.Amount
+			return nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions/tx-1/reversal", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rec.Code)
+	}
+	if reversed != 500 {
+		t.Fatalf("expected the full 500 to be reversed, got %d", reversed)
+	}
+}
+
+func TestHandleReverse_InvalidBody_Returns400(t *testing.T) {
+	h := newTestHandler(&stubTransactionRepository{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions/tx-1/reversal", bytes.NewReader([]byte("not-json")))
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestHandleReverse_AlreadyReversed_Returns409(t *testing.T) {
+	repo := &stubTransactionRepository{
+		findByIDFn: func(_ context.Context, id string) (*entity.Transaction, error) {
+			return &entity.Transaction{ID: id, Amount: 500, FromUserID: "user-1", ToUserID: "user-2", Status: entity.StatusCompleted}, nil
+		},
+		reverseFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+			return entity.ErrReversalExceedsOriginal
+		},
+	}
+	h := newTestHandler(repo)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions/tx-1/reversal", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+}
+
+func openHold(id string) *entity.Transaction {
+	amount := int64(1000)
+	expiresAt := time.Now().UTC().Add(time.Hour)
+	return &entity.Transaction{
+		ID: id, Amount: amount, FromUserID: "user-1", ToUserID: "user-2",
+		Status: entity.StatusAuthorized, AuthorizedAmount: &amount, ExpiresAt: &expiresAt,
+	}
+}
+
+func TestHandleCreate_AuthorizeOnlyReturnsExpiry(t *testing.T) {
+	h := newTestHandler(&stubTransactionRepository{})
+
+	body, _ := json.Marshal(map[string]any{
+		"from_user_id":     "user-1",
+		"to_user_id":       "user-2",
+		"amount":           1000,
+		"authorize_only":   true,
+		"hold_ttl_seconds": 3600,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rec.Code)
+	}
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Data["status"] != string(entity.StatusAuthorized) || resp.Data["expires_at"] == nil {
+		t.Fatalf("unexpected response data: %v", resp.Data)
+	}
+}
+
+func TestHandleCapture_Returns200(t *testing.T) {
+	repo := &stubTransactionRepository{
+		findByIDFn: func(_ context.Context, id string) (*entity.Transaction, error) {
+			return openHold(id), nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	body, _ := json.Marshal(map[string]any{"amount": 800})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions/tx-1/capture", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Data["captured"] != float64(800) || resp.Data["released"] != float64(200) {
+		t.Fatalf("unexpected response data: %v", resp.Data)
+	}
+}
+
+func TestHandleCapture_InvalidBody_Returns400(t *testing.T) {
+	h := newTestHandler(&stubTransactionRepository{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions/tx-1/capture", bytes.NewReader([]byte("not-json")))
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestHandleCapture_AlreadySettled_Returns409(t *testing.T) {
+	repo := &stubTransactionRepository{
+		findByIDFn: func(_ context.Context, id string) (*entity.Transaction, error) {
+			return openHold(id), nil
+		},
+		captureHoldFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+			return entity.ErrInvalidStatusTransition
+		},
+	}
+	h := newTestHandler(repo)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions/tx-1/capture", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+}
+
+func TestHandleVoid_Returns200(t *testing.T) {
+	repo := &stubTransactionRepository{
+		findByIDFn: func(_ context.Context, id string) (*entity.Transaction, error) {
+			return openHold(id), nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions/tx-1/void", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Data["status"] != string(entity.StatusVoided) || resp.Data["released"] != float64(1000) {
+		t.Fatalf("unexpected response data: %v", resp.Data)
+	}
+}
+
+func TestHandleVoid_NotFound_Returns404(t *testing.T) {
+	h := newTestHandler(&stubTransactionRepository{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions/tx-1/void", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestHandleCreate_BetweenCurrenciesReturnsConversion(t *testing.T) {
+	h := newTestHandler(&stubTransactionRepository{})
+
+	body, _ := json.Marshal(map[string]any{
+		"from_user_id":    "user-1",
+		"to_user_id":      "user-2",
+		"amount":          1000,
+		"currency":        "USD",
+		"to_currency":     "EUR",
+		"conversion_rate": "0.9215",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rec.Code)
+	}
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	conversion, _ := resp.Data["conversion"].(map[string]any)
+	if resp.Data["currency"] != "USD" || conversion["currency"] != "EUR" || conversion["amount"] != float64(922) {
+		t.Fatalf("unexpected response data: %v", resp.Data)
+	}
+}
+
+func TestHandleGetBalance_ReadsCurrencyQuery(t *testing.T) {
+	var requested string
+	repo := &stubTransactionRepository{
+		getBalanceFn: func(_ context.Context, _, currency string) (int64, error) {
+			requested = currency
+			return 0, nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/balance/user-1?currency=EUR", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if requested != "EUR" {
+		t.Fatalf("expected the EUR balance to be read, got %q", requested)
+	}
+}
+
+func TestHandleGetBalance_AsOfReturnsCutoff(t *testing.T) {
+	repo := &stubTransactionRepository{
+		getBalanceAsOfFn: func(_ context.Context, _, _ string, _ time.Time) (int64, error) {
+			return 1200, nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/balance/user-1?as_of=2026-09-30T23:59:59.1234567%2B02:00", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Data["AsOf"] != "2026-09-30T21:59:59.123456Z" || body.Data["Balance"] != float64(1200) {
+		t.Fatalf("expected the balance at the microsecond cutoff, got %v", body.Data)
+	}
+	if _, ok := body.Data["Available"]; ok {
+		t.Fatalf("expected no available balance for a past cutoff, got %v", body.Data)
+	}
+}
+
+func TestHandleGetBalance_InvalidAsOf_Returns400(t *testing.T) {
+	h := newTestHandler(&stubTransactionRepository{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/balance/user-1?as_of=yesterday", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestHandleGetStatus_ReturnsFullTransaction(t *testing.T) {
+	processedAt := time.Date(2026, 1, 1, 12, 0, 1, 0, time.UTC)
+	repo := &stubTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return &entity.Transaction{
+				ID:          "tx-1",
+				Amount:      1000,
+				Currency:    "USD",
+				FromUserID:  "user-1",
+				ToUserID:    "user-2",
+				Status:      entity.StatusCompleted,
+				CreatedAt:   processedAt.Add(-time.Second),
+				ProcessedAt: &processedAt,
+			}, nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions/tx-1", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Data["id"] != "tx-1" || resp.Data["status"] != "COMPLETED" || resp.Data["amount"] != float64(1000) ||
+		resp.Data["from_user_id"] != "user-1" || resp.Data["to_user_id"] != "user-2" ||
+		resp.Data["processed_at"] != "2026-01-01T12:00:01Z" {
+		t.Fatalf("unexpected response data: %v", resp.Data)
+	}
+}
+
+func TestHandleGetHistory_Returns200(t *testing.T) {
+	repo := &stubTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return &entity.Transaction{ID: "tx-1", Status: entity.StatusCompleted}, nil
+		},
+		findStatusHistoryFn: func(_ context.Context, id string) ([]*entity.StatusChange, error) {
+			return []*entity.StatusChange{
+				{TransactionID: id, ToStatus: entity.StatusPending, Actor: entity.ActorAPI},
+				{TransactionID: id, FromStatus: entity.StatusPending, ToStatus: entity.StatusCompleted, Actor: entity.ActorSettlement},
+			}, nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions/tx-1/history", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Data usecase.HistoryOutput `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Data.TransactionID != "tx-1" || len(resp.Data.Changes) != 2 || resp.Data.Changes[1].Actor != entity.ActorSettlement {
+		t.Fatalf("unexpected response data: %+v", resp.Data)
+	}
+}
+
+func TestHandleGetHistory_NotFound_Returns404(t *testing.T) {
+	h := newTestHandler(&stubTransactionRepository{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions/tx-unknown/history", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestHandleCreate_IdempotencyKeyReusedWithOtherPayload_Returns422(t *testing.T) {
+	otherRequest := strings.Repeat("0", 64)
+	existing := &entity.Transaction{ID: "tx-existing", Status: entity.StatusPending, RequestHash: &otherRequest}
+	repo := &stubTransactionRepository{
+		findByIdempotencyKeyFn: func(_ context.Context, _, _ string) (*entity.Transaction, error) {
+			return existing, nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	body, _ := json.Marshal(map[string]any{
+		"from_user_id": "user-1",
+		"to_user_id":   "user-2",
+		"amount":       1000,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions", bytes.NewReader(body))
+	req.Header.Set("Idempotency-Key", "key-abc")
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for a reused key, got %d", rec.Code)
+	}
+}
+
+func TestHandleCreateBatch_ReturnsPerItemResults(t *testing.T) {
+	var atomicWrite bool
+	repo := &stubTransactionRepository{
+		createBatchFn: func(_ context.Context, writes []ports.TransactionWrite, atomic bool) ([]error, error) {
+			atomicWrite = atomic
+			errs := make([]error, len(writes))
+			errs[1] = entity.ErrInsufficientFunds
+			return errs, nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	body, _ := json.Marshal(map[string]any{
+		"mode": "best_effort",
+		"items": []map[string]any{
+			{"from_user_id": "user-1", "to_user_id": "user-2", "amount": 1000, "idempotency_key": "pay-1"},
+			{"from_user_id": "user-1", "to_user_id": "user-3", "amount": 5000, "idempotency_key": "pay-2"},
+			{"from_user_id": "user-1", "to_user_id": "user-1", "amount": 100},
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions:batch", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if atomicWrite {
+		t.Fatal("expected a best-effort write")
+	}
+	var resp struct {
+		Data usecase.CreateBatchOutput `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	out := resp.Data
+	if out.Mode != usecase.BatchBestEffort || out.Created != 1 || out.Failed != 2 || len(out.Items) != 3 {
+		t.Fatalf("unexpected summary: %+v", out)
+	}
+	if out.Items[0].Outcome != usecase.OutcomeCreated || out.Items[0].ID == "" {
+		t.Fatalf("expected item 0 created, got %+v", out.Items[0])
+	}
+	if e := out.Items[1].Error; e == nil || e.Status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected item 1 to fail with 422, got %+v", out.Items[1])
+	}
+	if e := out.Items[2].Error; e == nil || e.Status != http.StatusBadRequest {
+		t.Fatalf("expected item 2 to fail with 400, got %+v", out.Items[2])
+	}
+}
+
+func TestHandleCreateBatch_InvalidBatch_Returns400(t *testing.T) {
+	for name, body := range map[string]string{
+		"invalid body": `{"items": `,
+		"no items":     `{"mode": "atomic", "items": []}`,
+		"unknown mode": `{"mode": "sometimes", "items": [{"from_user_id": "user-1", "to_user_id": "user-2", "amount": 1}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			h := newTestHandler(&stubTransactionRepository{})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions:batch", strings.NewReader(body))
+			rec := httptest.NewRecorder()
+
+			mux := http.NewServeMux()
+			h.RegisterRoutes(mux)
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestHandleCreate_ExecuteAtSchedulesTransfer(t *testing.T) {
+	h := newTestHandler(&stubTransactionRepository{})
+
+	executeAt := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+	body, _ := json.Marshal(map[string]any{
+		"from_user_id": "user-1",
+		"to_user_id":   "user-2",
+		"amount":       1000,
+		"execute_at":   executeAt.Format(time.RFC3339),
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Data["status"] != string(entity.StatusScheduled) || resp.Data["execute_at"] != executeAt.Format(time.RFC3339) {
+		t.Fatalf("unexpected response data: %v", resp.Data)
+	}
+}
+
+func TestHandleCancel_Returns200(t *testing.T) {
+	executeAt := time.Now().UTC().Add(time.Hour)
+	repo := &stubTransactionRepository{
+		findByIDFn: func(_ context.Context, id string) (*entity.Transaction, error) {
+			return &entity.Transaction{
+				ID:         id,
+				Amount:     1000,
+				Currency:   "USD",
+				FromUserID: "user-1",
+				ToUserID:   "user-2",
+				Status:     entity.StatusScheduled,
+				ExecuteAt:  &executeAt,
+			}, nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions/tx-1/cancel", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Data["id"] != "tx-1" || resp.Data["status"] != string(entity.StatusCancelled) {
+		t.Fatalf("unexpected response data: %v", resp.Data)
+	}
+}
+
+func TestHandleCancel_AlreadyReleased_Returns409(t *testing.T) {
+	repo := &stubTransactionRepository{
+		findByIDFn: func(_ context.Context, id string) (*entity.Transaction, error) {
+			return &entity.Transaction{ID: id, Amount: 1000, Status: entity.StatusPending}, nil
+		},
+	}
+	h := newTestHandler(repo)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions/tx-1/cancel", nil)
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,98 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+)
+
+// Engine is the built-in ports.RiskEvaluator. It reads the sender's history
+// without locking it, so the velocity rule is best effort under concurrent
+// requests; the transaction limits are the hard caps.
+type Engine struct {
+	rules   Rules
+	blocked map[string]bool
+	history ports.RiskHistory
+}
+
+func NewEngine(rules Rules, history ports.RiskHistory) *Engine {
+	blocked := make(map[string]bool, len(rules.Blocklist))
+	for _, id := range rules.Blocklist {
+		blocked[strings.ToLower(id)] = true
+	}
+	return &Engine{rules: rules, blocked: blocked, history: history}
+}
+
+func (e *Engine) Evaluate(ctx context.Context, tx *entity.Transaction, pending []*entity.Transaction) (entity.RiskAssessment, error) {
+	a := entity.RiskAssessment{Decision: entity.RiskAllow}
+	flag := func(d entity.RiskDecision, reason string) {
+		if d.Stricter(a.Decision) {
+			a.Decision = d
+		}
+		a.Reasons = append(a.Reasons, reason)
+	}
+
+	for _, reason := range e.Screen(tx) {
+		flag(entity.RiskDeny, reason)
+	}
+	if a.Decision == entity.RiskDeny {
+		// Nothing can make it stricter; spare the lookups.
+		return a, nil
+	}
+
+	for _, rule := range e.rules.Velocity {
+		window := time.Duration(rule.Window)
+		since := tx.CreatedAt.Add(-window)
+		sent, err := e.history.CountSent(ctx, tx.FromUserID, since)
+		if err != nil {
+			return entity.RiskAssessment{}, err
+		}
+		sent += countPending(pending, tx.FromUserID, since)
+		if sent+1 > rule.MaxTransfers {
+			flag(rule.Decision, fmt.Sprintf("more than %d transfers within %s", rule.MaxTransfers, window))
+		}
+	}
+
+	if rule := e.rules.NewRecipient; rule != nil {
+		if min, ok := rule.MinAmount[tx.Currency]; ok && tx.Amount >= min {
+			paid, err := e.history.HasPaid(ctx, tx.FromUserID, tx.ToUserID)
+			if err != nil {
+				return entity.RiskAssessment{}, err
+			}
+			if !paid {
+				flag(rule.Decision, fmt.Sprintf("first payment to the recipient is at least %d %s", min, tx.Currency))
+			}
+		}
+	}
+
+	return a, nil
+}
+
+// Screen applies the blocklist alone. The other rules judge the transfer
+// as it was requested and would not judge it differently later.
+func (e *Engine) Screen(tx *entity.Transaction) []string {
+	var reasons []string
+	if e.blocked[strings.ToLower(tx.FromUserID)] {
+		reasons = append(reasons, "sender is blocklisted")
+	}
+	if e.blocked[strings.ToLower(tx.ToUserID)] {
+		reasons = append(reasons, "recipient is blocklisted")
+	}
+	return reasons
+}
+
+// countPending counts the transfers of pending that CountSent would count
+// once committed: fromUserID's, created at or after since, not rejected.
+func countPending(pending []*entity.Transaction, fromUserID string, since time.Time) int64 {
+	var n int64
+	for _, tx := range pending {
+		if strings.EqualFold(tx.FromUserID, fromUserID) && !tx.CreatedAt.Before(since) && tx.Status != entity.StatusRejected {
+			n++
+		}
+	}
+	return n
+}
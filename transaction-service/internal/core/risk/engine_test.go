@@ -0,0 +1,199 @@
+package risk_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/risk"
+)
+
+type fakeHistory struct {
+	sent    int64
+	paid    bool
+	err     error
+	since   []time.Time
+	hasPaid int
+}
+
+func (f *fakeHistory) CountSent(_ context.Context, _ string, since time.Time) (int64, error) {
+	f.since = append(f.since, since)
+	return f.sent, f.err
+}
+
+func (f *fakeHistory) HasPaid(_ context.Context, _, _ string) (bool, error) {
+	f.hasPaid++
+	return f.paid, f.err
+}
+
+func transfer(amount int64) *entity.Transaction {
+	tx, _ := entity.NewTransaction("user-1", "user-2", amount, "USD", "")
+	return tx
+}
+
+func TestEngine_NoRulesAllows(t *testing.T) {
+	history := &fakeHistory{}
+
+	a, err := risk.NewEngine(risk.Rules{}, history).Evaluate(context.Background(), transfer(1000), nil)
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if a.Decision != entity.RiskAllow || len(a.Reasons) != 0 {
+		t.Fatalf("expected ALLOW without reasons, got %+v", a)
+	}
+	if len(history.since) != 0 || history.hasPaid != 0 {
+		t.Fatal("expected no history lookups")
+	}
+}
+
+func TestEngine_BlocklistDeniesWithoutLookups(t *testing.T) {
+	history := &fakeHistory{}
+	rules := risk.Rules{
+		Blocklist: []string{"USER-2"},
+		Velocity:  []risk.VelocityRule{{Window: risk.Duration(time.Hour), MaxTransfers: 1, Decision: entity.RiskReview}},
+	}
+
+	a, err := risk.NewEngine(rules, history).Evaluate(context.Background(), transfer(1000), nil)
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if a.Decision != entity.RiskDeny || !reflect.DeepEqual(a.Reasons, []string{"recipient is blocklisted"}) {
+		t.Fatalf("expected DENY for the recipient, got %+v", a)
+	}
+	if len(history.since) != 0 {
+		t.Fatal("expected no history lookups once denied")
+	}
+}
+
+func TestEngine_ScreenAppliesOnlyTheBlocklist(t *testing.T) {
+	history := &fakeHistory{}
+	rules := risk.Rules{
+		Blocklist:    []string{"user-1", "USER-2"},
+		Velocity:     []risk.VelocityRule{{Window: risk.Duration(time.Hour), MaxTransfers: 0, Decision: entity.RiskDeny}},
+		NewRecipient: &risk.NewRecipientRule{MinAmount: map[string]int64{"USD": 1}, Decision: entity.RiskDeny},
+	}
+
+	reasons := risk.NewEngine(rules, history).Screen(transfer(1000))
+
+	if !reflect.DeepEqual(reasons, []string{"sender is blocklisted", "recipient is blocklisted"}) {
+		t.Fatalf("expected both parties flagged, got %v", reasons)
+	}
+	if len(history.since) != 0 || history.hasPaid != 0 {
+		t.Fatal("expected no history lookups")
+	}
+	if reasons := risk.NewEngine(risk.Rules{Blocklist: []string{"user-3"}}, history).Screen(transfer(1000)); len(reasons) != 0 {
+		t.Fatalf("expected a clear screen, got %v", reasons)
+	}
+}
+
+func TestEngine_Velocity(t *testing.T) {
+	rules := risk.Rules{Velocity: []risk.VelocityRule{
+		{Window: risk.Duration(time.Minute), MaxTransfers: 3, Decision: entity.RiskDeny},
+		{Window: risk.Duration(time.Hour), MaxTransfers: 2, Decision: entity.RiskReview},
+	}}
+	tx := transfer(1000)
+
+	t.Run("under every limit", func(t *testing.T) {
+		history := &fakeHistory{sent: 1}
+		a, _ := risk.NewEngine(rules, history).Evaluate(context.Background(), tx, nil)
+		if a.Decision != entity.RiskAllow {
+			t.Fatalf("expected ALLOW, got %+v", a)
+		}
+		if !history.since[0].Equal(tx.CreatedAt.Add(-time.Minute)) || !history.since[1].Equal(tx.CreatedAt.Add(-time.Hour)) {
+			t.Fatalf("expected windows ending at the transfer, got %v", history.since)
+		}
+	})
+
+	t.Run("strictest firing rule wins", func(t *testing.T) {
+		a, _ := risk.NewEngine(rules, &fakeHistory{sent: 3}).Evaluate(context.Background(), tx, nil)
+		if a.Decision != entity.RiskDeny || len(a.Reasons) != 2 {
+			t.Fatalf("expected DENY with both reasons, got %+v", a)
+		}
+	})
+
+	t.Run("pending transfers count", func(t *testing.T) {
+		other := transfer(1000)
+		other.FromUserID = "user-3"
+		rejected := transfer(1000)
+		rejected.Status = entity.StatusRejected
+		old := transfer(1000)
+		old.CreatedAt = tx.CreatedAt.Add(-2 * time.Minute)
+		pending := []*entity.Transaction{transfer(1000), other, rejected, old}
+
+		// One committed, the first pending one and the old one make three
+		// within the hour, but only two within the minute.
+		a, _ := risk.NewEngine(rules, &fakeHistory{sent: 1}).Evaluate(context.Background(), tx, pending)
+		if a.Decision != entity.RiskReview || !reflect.DeepEqual(a.Reasons, []string{"more than 2 transfers within 1h0m0s"}) {
+			t.Fatalf("expected REVIEW for the hourly rule, got %+v", a)
+		}
+	})
+
+	t.Run("review only", func(t *testing.T) {
+		a, _ := risk.NewEngine(rules, &fakeHistory{sent: 2}).Evaluate(context.Background(), tx, nil)
+		if a.Decision != entity.RiskReview || !reflect.DeepEqual(a.Reasons, []string{"more than 2 transfers within 1h0m0s"}) {
+			t.Fatalf("expected REVIEW for the hourly rule, got %+v", a)
+		}
+	})
+}
+
+func TestEngine_NewRecipient(t *testing.T) {
+	rules := risk.Rules{NewRecipient: &risk.NewRecipientRule{
+		MinAmount: map[string]int64{"USD": 5000},
+		Decision:  entity.RiskReview,
+	}}
+	cases := []struct {
+		name    string
+		tx      *entity.Transaction
+		paid    bool
+		want    entity.RiskDecision
+		lookups int
+	}{
+		{"below the threshold", transfer(4999), false, entity.RiskAllow, 0},
+		{"large to a new recipient", transfer(5000), false, entity.RiskReview, 1},
+		{"large to a known recipient", transfer(5000), true, entity.RiskAllow, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			history := &fakeHistory{paid: c.paid}
+			a, err := risk.NewEngine(rules, history).Evaluate(context.Background(), c.tx, nil)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if a.Decision != c.want || history.hasPaid != c.lookups {
+				t.Fatalf("expected %s after %d lookups, got %+v after %d", c.want, c.lookups, a, history.hasPaid)
+			}
+		})
+	}
+
+	t.Run("currency without threshold", func(t *testing.T) {
+		tx, _ := entity.NewTransaction("user-1", "user-2", 1_000_000, "EUR", "")
+		a, _ := risk.NewEngine(rules, &fakeHistory{}).Evaluate(context.Background(), tx, nil)
+		if a.Decision != entity.RiskAllow {
+			t.Fatalf("expected ALLOW, got %+v", a)
+		}
+	})
+}
+
+func TestEngine_HistoryErrors(t *testing.T) {
+	boom := errors.New("db down")
+	engines := map[string]*risk.Engine{
+		"velocity": risk.NewEngine(risk.Rules{
+			Velocity: []risk.VelocityRule{{Window: risk.Duration(time.Hour), MaxTransfers: 1, Decision: entity.RiskReview}},
+		}, &fakeHistory{err: boom}),
+		"new recipient": risk.NewEngine(risk.Rules{
+			NewRecipient: &risk.NewRecipientRule{MinAmount: map[string]int64{"USD": 1}, Decision: entity.RiskReview},
+		}, &fakeHistory{err: boom}),
+	}
+	for name, engine := range engines {
+		t.Run(name, func(t *testing.T) {
+			if _, err := engine.Evaluate(context.Background(), transfer(1000), nil); !errors.Is(err, boom) {
+				t.Fatalf("expected the history error, got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package risk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+type (
+	// Rules configures the Engine. Each rule that fires adds its reason to
+	// the assessment; the strictest decision among them wins.
+	Rules struct {
+		// Blocklist denies every transfer from or to these user IDs.
+		Blocklist []string `json:"blocklist"`
+		// Velocity flags a sender creating more than MaxTransfers within
+		// Window, this one included.
+		Velocity []VelocityRule `json:"velocity"`
+		// NewRecipient flags a transfer of at least MinAmount, in minor units
+		// of its currency, to a receiver the sender never completed a
+		// payment to. Currencies without a MinAmount are not checked.
+		NewRecipient *NewRecipientRule `json:"new_recipient"`
+	}
+
+	VelocityRule struct {
+		Window       Duration            `json:"window"`
+		MaxTransfers int64               `json:"max_transfers"`
+		Decision     entity.RiskDecision `json:"decision"`
+	}
+
+	NewRecipientRule struct {
+		MinAmount map[string]int64    `json:"min_amount"`
+		Decision  entity.RiskDecision `json:"decision"`
+	}
+
+	// Duration reads a Go duration string such as "10m" from JSON.
+	Duration time.Duration
+)
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// LoadRules reads the rules from the JSON file at path. An empty path means
+// no rules, so every transfer is allowed. A rule without a decision asks
+// for REVIEW.
+func LoadRules(path string) (Rules, error) {
+	if path == "" {
+		return Rules{}, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Rules{}, fmt.Errorf("read risk rules: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.DisallowUnknownFields()
+	var rules Rules
+	if err := dec.Decode(&rules); err != nil {
+		return Rules{}, fmt.Errorf("parse risk rules %s: %w", path, err)
+	}
+	if err := rules.normalize(); err != nil {
+		return Rules{}, fmt.Errorf("invalid risk rules %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+func (r *Rules) normalize() error {
+	for i := range r.Velocity {
+		v := &r.Velocity[i]
+		if v.Window <= 0 || v.MaxTransfers <= 0 {
+			return errors.New("velocity rules need a positive window and max_transfers")
+		}
+		if err := defaultDecision(&v.Decision); err != nil {
+			return err
+		}
+	}
+	if r.NewRecipient != nil {
+		for currency, amount := range r.NewRecipient.MinAmount {
+			if amount <= 0 {
+				return fmt.Errorf("new_recipient min_amount for %s must be positive", currency)
+			}
+		}
+		if err := defaultDecision(&r.NewRecipient.Decision); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func defaultDecision(d *entity.RiskDecision) error {
+	switch *d {
+	case "":
+		*d = entity.RiskReview
+	case entity.RiskReview, entity.RiskDeny:
+	default:
+		return fmt.Errorf("decision must be %s or %s, got %q", entity.RiskReview, entity.RiskDeny, *d)
+	}
+	return nil
+}
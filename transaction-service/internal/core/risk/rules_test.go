@@ -0,0 +1,93 @@
+package risk_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/risk"
+)
+
+func writeRules(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write rules: %v", err)
+	}
+	return path
+}
+
+func TestLoadRules_EmptyPathHasNoRules(t *testing.T) {
+	rules, err := risk.LoadRules("")
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(rules.Blocklist) != 0 || len(rules.Velocity) != 0 || rules.NewRecipient != nil {
+		t.Fatalf("expected no rules, got %+v", rules)
+	}
+}
+
+func TestLoadRules_ParsesAndDefaultsDecisions(t *testing.T) {
+	path := writeRules(t, `{
+		"blocklist": ["user-9"],
+		"velocity": [{"window": "10m", "max_transfers": 5}],
+		"new_recipient": {"min_amount": {"USD": 100000}, "decision": "DENY"}
+	}`)
+
+	rules, err := risk.LoadRules(path)
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(rules.Blocklist) != 1 || rules.Blocklist[0] != "user-9" {
+		t.Fatalf("unexpected blocklist: %v", rules.Blocklist)
+	}
+	v := rules.Velocity[0]
+	if time.Duration(v.Window) != 10*time.Minute || v.MaxTransfers != 5 || v.Decision != entity.RiskReview {
+		t.Fatalf("unexpected velocity rule: %+v", v)
+	}
+	if rules.NewRecipient.MinAmount["USD"] != 100000 || rules.NewRecipient.Decision != entity.RiskDeny {
+		t.Fatalf("unexpected new recipient rule: %+v", rules.NewRecipient)
+	}
+}
+
+func TestLoadRules_ExampleFileIsValid(t *testing.T) {
+	if _, err := risk.LoadRules("../../../config/risk-rules.example.json"); err != nil {
+		t.Fatalf("expected the example rules to load, got: %v", err)
+	}
+}
+
+func TestLoadRules_Errors(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"malformed", `{`, "parse"},
+		{"unknown field", `{"allowlist": []}`, "parse"},
+		{"bad window", `{"velocity": [{"window": "soon", "max_transfers": 1}]}`, "parse"},
+		{"no window", `{"velocity": [{"max_transfers": 1}]}`, "positive window"},
+		{"no max", `{"velocity": [{"window": "1h"}]}`, "positive window"},
+		{"allow decision", `{"velocity": [{"window": "1h", "max_transfers": 1, "decision": "ALLOW"}]}`, "decision must be"},
+		{"zero amount", `{"new_recipient": {"min_amount": {"USD": 0}}}`, "must be positive"},
+		{"bad decision", `{"new_recipient": {"min_amount": {"USD": 1}, "decision": "BLOCK"}}`, "decision must be"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := risk.LoadRules(writeRules(t, c.content))
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("expected an error containing %q, got: %v", c.want, err)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := risk.LoadRules(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
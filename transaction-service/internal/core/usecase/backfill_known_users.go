@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+const defaultBackfillBatchSize = 500
+
+type (
+	BackfillInput struct {
+		BatchSize int
+	}
+
+	BackfillOutput struct {
+		Scanned  int
+		Inserted int
+		Skipped  int
+	}
+
+	BackfillKnownUsersUseCase struct {
+		source ports.UserEventSource
+		sync   *SyncKnownUserUseCase
+		logger *slog.Logger
+	}
+)
+
+func NewBackfillKnownUsersUseCase(source ports.UserEventSource, users ports.KnownUserRepository, logger *slog.Logger) *BackfillKnownUsersUseCase {
+	return &BackfillKnownUsersUseCase{
+		source: source,
+		sync:   NewSyncKnownUserUseCase(users, logger),
+		logger: logger,
+	}
+}
+
+// Execute walks the whole UserCreated history page by page and projects every
+// event. It is safe to run repeatedly and alongside the live consumer.
+func (uc *BackfillKnownUsersUseCase) Execute(ctx context.Context, input BackfillInput) (*BackfillOutput, error) {
+	batchSize := input.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBackfillBatchSize
+	}
+
+	uc.logger.InfoContext(ctx, "backfill known users started", slog.Int("batch_size", batchSize))
+
+	out := &BackfillOutput{}
+	var afterTime time.Time
+	var afterID string
+
+	for {
+		page, err := uc.source.ListUserCreated(ctx, afterTime, afterID, batchSize)
+		if err != nil {
+			uc.logger.ErrorContext(ctx, "list user created events failed", slog.String("error", err.Error()))
+			return nil, apperrors.Unexpected(apperrors.WithError(err))
+		}
+
+		for _, user := range page {
+			out.Scanned++
+			res, err := uc.sync.Execute(ctx, SyncKnownUserInput{
+				UserID:     user.UserID,
+				EventID:    user.SourceEventID,
+				OccurredAt: user.RegisteredAt,
+			})
+			if err != nil {
+				var exc *apperrors.Exception
+				if errors.As(err, &exc) && exc.Code < http.StatusInternalServerError {
+					out.Skipped++
+					continue
+				}
+				return nil, err
+			}
+			if res.Created {
+				out.Inserted++
+			}
+		}
+
+		if len(page) < batchSize {
+			break
+		}
+		last := page[len(page)-1]
+		afterTime, afterID = last.RegisteredAt, last.SourceEventID
+	}
+
+	uc.logger.InfoContext(ctx, "backfill known users finished",
+		slog.Int("scanned", out.Scanned),
+		slog.Int("inserted", out.Inserted),
+		slog.Int("skipped", out.Skipped),
+	)
+
+	return out, nil
+}
@@ -0,0 +1,82 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+func TestRebuildBalancesUseCase_ReportsCorrectedRows(t *testing.T) {
+	ledger := &mockLedgerRepository{
+		rebuildBalancesFn: func(_ context.Context) (int64, error) { return 3, nil },
+	}
+	uc := usecase.NewRebuildBalancesUseCase(ledger, testLogger())
+
+	out, err := uc.Execute(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Corrected != 3 {
+		t.Fatalf("expected 3 corrected rows, got %d", out.Corrected)
+	}
+}
+
+func TestRebuildBalancesUseCase_RepositoryError(t *testing.T) {
+	ledger := &mockLedgerRepository{
+		rebuildBalancesFn: func(_ context.Context) (int64, error) { return 0, errors.New("db error") },
+	}
+	uc := usecase.NewRebuildBalancesUseCase(ledger, testLogger())
+
+	_, err := uc.Execute(context.Background())
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
+
+func TestCheckBalancesUseCase_Consistent(t *testing.T) {
+	uc := usecase.NewCheckBalancesUseCase(&mockLedgerRepository{}, testLogger())
+
+	out, err := uc.Execute(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(out.Drifts) != 0 {
+		t.Fatalf("expected no drift, got %d", len(out.Drifts))
+	}
+}
+
+func TestCheckBalancesUseCase_ReportsDrift(t *testing.T) {
+	ledger := &mockLedgerRepository{
+		findBalanceDriftFn: func(_ context.Context) ([]entity.BalanceDrift, error) {
+			return []entity.BalanceDrift{{UserID: "user-1", Projected: 100, Computed: 250}}, nil
+		},
+	}
+	uc := usecase.NewCheckBalancesUseCase(ledger, testLogger())
+
+	out, err := uc.Execute(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(out.Drifts) != 1 || out.Drifts[0].Computed != 250 {
+		t.Fatalf("unexpected drift report: %+v", out.Drifts)
+	}
+}
+
+func TestCheckBalancesUseCase_RepositoryError(t *testing.T) {
+	ledger := &mockLedgerRepository{
+		findBalanceDriftFn: func(_ context.Context) ([]entity.BalanceDrift, error) {
+			return nil, errors.New("db error")
+		},
+	}
+	uc := usecase.NewCheckBalancesUseCase(ledger, testLogger())
+
+	_, err := uc.Execute(context.Background())
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
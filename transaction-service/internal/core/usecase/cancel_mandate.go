@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+)
+
+type CancelMandateUseCase struct {
+	mandates ports.MandateRepository
+	logger   *slog.Logger
+}
+
+func NewCancelMandateUseCase(mandates ports.MandateRepository, logger *slog.Logger) *CancelMandateUseCase {
+	return &CancelMandateUseCase{mandates: mandates, logger: logger}
+}
+
+// Execute cancels an ACTIVE mandate and emits MandateCancelled. Transfers of
+// past runs are left alone.
+func (uc *CancelMandateUseCase) Execute(ctx context.Context, id string) (*MandateItem, error) {
+	uc.logger.InfoContext(ctx, "cancel mandate request", slog.String("mandate_id", id))
+
+	m, err := findMandate(ctx, uc.mandates, uc.logger, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.Cancel(time.Now().UTC()); err != nil {
+		uc.logger.WarnContext(ctx, "cancel mandate rejected",
+			slog.String("mandate_id", m.ID),
+			slog.String("status", string(m.Status)),
+		)
+		return nil, mandateRejection(err)
+	}
+
+	if err := saveMandate(ctx, uc.mandates, uc.logger, m, entity.EventMandateCancelled); err != nil {
+		return nil, err
+	}
+
+	uc.logger.InfoContext(ctx, "mandate cancelled", slog.String("mandate_id", m.ID))
+
+	item := newMandateItem(m)
+	return &item, nil
+}
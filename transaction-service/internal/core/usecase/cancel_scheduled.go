@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	CancelOutput struct {
+		ID     string
+		Status string
+	}
+
+	CancelScheduledUseCase struct {
+		repo   ports.TransactionRepository
+		logger *slog.Logger
+	}
+)
+
+var errNotScheduled = errors.New("only scheduled transactions that have not been released can be cancelled")
+
+func NewCancelScheduledUseCase(repo ports.TransactionRepository, logger *slog.Logger) *CancelScheduledUseCase {
+	return &CancelScheduledUseCase{repo: repo, logger: logger}
+}
+
+// Execute cancels a SCHEDULED transfer and emits TransactionCancelled. Once
+// the scheduler has released it the transfer can no longer be cancelled; it
+// can be reversed after it completes instead.
+func (uc *CancelScheduledUseCase) Execute(ctx context.Context, id string) (*CancelOutput, error) {
+	uc.logger.InfoContext(ctx, "cancel scheduled transaction request", slog.String("transaction_id", id))
+
+	if id == "" {
+		uc.logger.WarnContext(ctx, "cancel validation failed", slog.String("reason", "transaction_id is required"))
+		return nil, apperrors.BadRequest(apperrors.WithMessage("transaction_id is required"))
+	}
+
+	tx, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "find transaction failed",
+			slog.String("transaction_id", id),
+			slog.String("error", err.Error()),
+		)
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if tx == nil {
+		uc.logger.WarnContext(ctx, "transaction not found", slog.String("transaction_id", id))
+		return nil, apperrors.NotFound(apperrors.WithMessage("transaction not found"))
+	}
+
+	now := time.Now().UTC()
+	if err := tx.Cancel(now); err != nil {
+		uc.logger.WarnContext(ctx, "cancel rejected",
+			slog.String("transaction_id", tx.ID),
+			slog.String("status", string(tx.Status)),
+		)
+		return nil, apperrors.Conflict(apperrors.WithMessage(errNotScheduled.Error()))
+	}
+
+	outbox, err := newScheduledOutbox(tx, entity.EventTransactionCancelled, "")
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "marshal outbox payload failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if err := resolveScheduled(ctx, uc.repo, tx, outbox, entity.ActorAPI, "", now); err != nil {
+		if errors.Is(err, entity.ErrInvalidStatusTransition) {
+			uc.logger.WarnContext(ctx, "scheduled transaction released concurrently", slog.String("transaction_id", tx.ID))
+			return nil, apperrors.Conflict(apperrors.WithMessage(errNotScheduled.Error()))
+		}
+		uc.logger.ErrorContext(ctx, "persist cancellation failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	uc.logger.InfoContext(ctx, "scheduled transaction cancelled", slog.String("transaction_id", tx.ID))
+
+	return &CancelOutput{ID: tx.ID, Status: string(tx.Status)}, nil
+}
+
+// resolveScheduled writes a transfer leaving SCHEDULED at at with its outbox
+// event and the status change made by actor. The change is dated explicitly
+// because a release leaves ProcessedAt to settlement.
+func resolveScheduled(ctx context.Context, repo ports.TransactionRepository, tx *entity.Transaction, outbox *entity.Outbox, actor, reason string, at time.Time) error {
+	change := entity.NewStatusChange(tx, entity.StatusScheduled, actor, reason)
+	change.ChangedAt = at
+	return repo.ResolveScheduled(ctx, tx, change, outbox)
+}
+
+// newScheduledOutbox builds the event of a scheduled transfer that ends
+// without being released.
+func newScheduledOutbox(tx *entity.Transaction, eventType, reason string) (*entity.Outbox, error) {
+	payload, err := json.Marshal(map[string]any{
+		"transactionId": tx.ID,
+		"fromUserId":    tx.FromUserID,
+		"toUserId":      tx.ToUserID,
+		"amount":        tx.Amount,
+		"currency":      tx.Currency,
+		"conversion":    tx.Conversion,
+		"status":        tx.Status,
+		"executeAt":     tx.ExecuteAt,
+		"processedAt":   tx.ProcessedAt,
+		"reason":        reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entity.NewOutbox(eventType, tx.ID, string(payload)), nil
+}
@@ -0,0 +1,111 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+func scheduledFixture(id string, executeAt time.Time) *entity.Transaction {
+	return &entity.Transaction{
+		ID:         id,
+		Amount:     1000,
+		Currency:   "USD",
+		FromUserID: "user-1",
+		ToUserID:   "user-2",
+		Status:     entity.StatusScheduled,
+		ExecuteAt:  &executeAt,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+func TestCancelScheduledUseCase_CancelsScheduledTransfer(t *testing.T) {
+	var (
+		persisted *entity.Transaction
+		outbox    *entity.Outbox
+	)
+	repo := &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return scheduledFixture("tx-1", time.Now().UTC().Add(time.Hour)), nil
+		},
+		resolveScheduledFn: func(_ context.Context, tx *entity.Transaction, o *entity.Outbox) error {
+			persisted, outbox = tx, o
+			return nil
+		},
+	}
+	uc := usecase.NewCancelScheduledUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), "tx-1")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.ID != "tx-1" || out.Status != string(entity.StatusCancelled) {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if persisted.Status != entity.StatusCancelled || persisted.ProcessedAt == nil {
+		t.Fatalf("expected a processed CANCELLED transfer, got %+v", persisted)
+	}
+	if outbox.Type != entity.EventTransactionCancelled {
+		t.Fatalf("expected event %s, got %s", entity.EventTransactionCancelled, outbox.Type)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(outbox.Payload), &payload); err != nil {
+		t.Fatalf("expected a JSON payload, got %v", err)
+	}
+	if payload["status"] != string(entity.StatusCancelled) || payload["executeAt"] == nil {
+		t.Fatalf("expected the cancelled status and execution time in the payload, got %v", payload)
+	}
+	c := assertStatusChange(t, repo, "tx-1", entity.StatusScheduled, entity.StatusCancelled, entity.ActorAPI)
+	if !c.ChangedAt.Equal(*persisted.ProcessedAt) {
+		t.Fatalf("expected the change dated at the cancellation, got %v", c.ChangedAt)
+	}
+}
+
+func TestCancelScheduledUseCase_Errors(t *testing.T) {
+	released := scheduledFixture("tx-1", time.Now().UTC().Add(-time.Minute))
+	released.Status = entity.StatusPending
+	scheduled := func(context.Context, string) (*entity.Transaction, error) {
+		return scheduledFixture("tx-1", time.Now().UTC().Add(time.Hour)), nil
+	}
+
+	cases := []struct {
+		name       string
+		id         string
+		find       func(context.Context, string) (*entity.Transaction, error)
+		resolveErr error
+		code       int
+	}{
+		{"missing id", "", nil, nil, http.StatusBadRequest},
+		{"not found", "tx-1", nil, nil, http.StatusNotFound},
+		{"find error", "tx-1", func(context.Context, string) (*entity.Transaction, error) {
+			return nil, errors.New("db error")
+		}, nil, http.StatusInternalServerError},
+		{"already released", "tx-1", func(context.Context, string) (*entity.Transaction, error) {
+			return released, nil
+		}, nil, http.StatusConflict},
+		{"released concurrently", "tx-1", scheduled, entity.ErrInvalidStatusTransition, http.StatusConflict},
+		{"resolve error", "tx-1", scheduled, errors.New("db error"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTransactionRepository{
+				findByIDFn: tc.find,
+				resolveScheduledFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+					return tc.resolveErr
+				},
+			}
+			uc := usecase.NewCancelScheduledUseCase(repo, testLogger())
+
+			_, err := uc.Execute(context.Background(), tc.id)
+
+			_ = assertException(t, err, tc.code)
+		})
+	}
+}
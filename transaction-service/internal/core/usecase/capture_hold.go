@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	CaptureInput struct {
+		TransactionID string
+		// Amount to capture; zero captures the full authorized amount.
+		Amount int64
+	}
+
+	CaptureOutput struct {
+		ID       string
+		Status   string
+		Captured int64
+		Released int64
+	}
+
+	CaptureHoldUseCase struct {
+		repo   ports.TransactionRepository
+		logger *slog.Logger
+	}
+)
+
+func NewCaptureHoldUseCase(repo ports.TransactionRepository, logger *slog.Logger) *CaptureHoldUseCase {
+	return &CaptureHoldUseCase{repo: repo, logger: logger}
+}
+
+// Execute captures an AUTHORIZED hold fully or partially, releasing the rest
+// to the sender, and emits TransactionCaptured.
+func (uc *CaptureHoldUseCase) Execute(ctx context.Context, input CaptureInput) (*CaptureOutput, error) {
+	uc.logger.InfoContext(ctx, "capture hold request", slog.String("transaction_id", input.TransactionID))
+
+	if input.Amount < 0 {
+		uc.logger.WarnContext(ctx, "capture validation failed", slog.String("reason", entity.ErrAmountMustBePositive.Error()))
+		return nil, apperrors.BadRequest(apperrors.WithMessage(entity.ErrAmountMustBePositive.Error()))
+	}
+
+	hold, err := findHold(ctx, uc.repo, uc.logger, input.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := input.Amount
+	if amount == 0 && hold.AuthorizedAmount != nil {
+		amount = *hold.AuthorizedAmount
+	}
+	if err := hold.Capture(amount, time.Now().UTC()); err != nil {
+		uc.logger.WarnContext(ctx, "capture rejected",
+			slog.String("transaction_id", hold.ID),
+			slog.String("reason", err.Error()),
+		)
+		return nil, holdRejection(err)
+	}
+	released := *hold.AuthorizedAmount - hold.Amount
+
+	payload, err := json.Marshal(map[string]any{
+		"transactionId":    hold.ID,
+		"fromUserId":       hold.FromUserID,
+		"toUserId":         hold.ToUserID,
+		"currency":         hold.Currency,
+		"authorizedAmount": hold.AuthorizedAmount,
+		"capturedAmount":   hold.Amount,
+		"releasedAmount":   released,
+		"status":           hold.Status,
+		"processedAt":      hold.ProcessedAt,
+	})
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "marshal outbox payload failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	change := entity.NewStatusChange(hold, entity.StatusAuthorized, entity.ActorAPI,
+		fmt.Sprintf("captured %d of %d", hold.Amount, *hold.AuthorizedAmount))
+	if err := uc.repo.CaptureHold(ctx, hold, change, entity.NewOutbox(entity.EventTransactionCaptured, hold.ID, string(payload))); err != nil {
+		if errors.Is(err, entity.ErrInvalidStatusTransition) {
+			uc.logger.WarnContext(ctx, "hold settled concurrently", slog.String("transaction_id", hold.ID))
+			return nil, holdRejection(err)
+		}
+		uc.logger.ErrorContext(ctx, "persist capture failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	uc.logger.InfoContext(ctx, "hold captured",
+		slog.String("transaction_id", hold.ID),
+		slog.Int64("captured", hold.Amount),
+		slog.Int64("released", released),
+	)
+
+	return &CaptureOutput{
+		ID:       hold.ID,
+		Status:   string(hold.Status),
+		Captured: hold.Amount,
+		Released: released,
+	}, nil
+}
+
+// findHold loads the transaction behind a capture or void request.
+func findHold(ctx context.Context, repo ports.TransactionRepository, logger *slog.Logger, id string) (*entity.Transaction, error) {
+	if id == "" {
+		logger.WarnContext(ctx, "hold validation failed", slog.String("reason", "transaction_id is required"))
+		return nil, apperrors.BadRequest(apperrors.WithMessage("transaction_id is required"))
+	}
+
+	tx, err := repo.FindByID(ctx, id)
+	if err != nil {
+		logger.ErrorContext(ctx, "find transaction failed",
+			slog.String("transaction_id", id),
+			slog.String("error", err.Error()),
+		)
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if tx == nil {
+		logger.WarnContext(ctx, "transaction not found", slog.String("transaction_id", id))
+		return nil, apperrors.NotFound(apperrors.WithMessage("transaction not found"))
+	}
+	return tx, nil
+}
+
+// holdRejection maps the errors of a hold transition to an Exception. A hold
+// that is no longer AUTHORIZED is a conflict with whoever settled it first.
+func holdRejection(err error) *apperrors.Exception {
+	switch {
+	case errors.Is(err, entity.ErrInvalidStatusTransition):
+		return apperrors.Conflict(apperrors.WithMessage("transaction is not an open hold"))
+	case errors.Is(err, entity.ErrHoldExpired):
+		return apperrors.UnprocessableEntity(apperrors.WithMessage(entity.ErrHoldExpired.Error()))
+	default:
+		return apperrors.UnprocessableEntity(apperrors.WithMessage(err.Error()))
+	}
+}
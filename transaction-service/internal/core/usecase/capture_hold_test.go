@@ -0,0 +1,178 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+func holdFixture() *entity.Transaction {
+	amount := int64(1000)
+	expiresAt := time.Now().UTC().Add(time.Hour)
+	return &entity.Transaction{
+		ID:               "tx-hold",
+		Amount:           amount,
+		Currency:         "USD",
+		FromUserID:       "user-1",
+		ToUserID:         "user-2",
+		Status:           entity.StatusAuthorized,
+		AuthorizedAmount: &amount,
+		ExpiresAt:        &expiresAt,
+		CreatedAt:        time.Now().UTC(),
+	}
+}
+
+func holdRepository(hold *entity.Transaction) *mockTransactionRepository {
+	return &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return hold, nil
+		},
+	}
+}
+
+func TestCaptureHoldUseCase_FullCapture(t *testing.T) {
+	var outbox *entity.Outbox
+	repo := holdRepository(holdFixture())
+	repo.captureHoldFn = func(_ context.Context, _ *entity.Transaction, o *entity.Outbox) error {
+		outbox = o
+		return nil
+	}
+	uc := usecase.NewCaptureHoldUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.CaptureInput{TransactionID: "tx-hold"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Status != string(entity.StatusCompleted) || out.Captured != 1000 || out.Released != 0 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if outbox.Type != entity.EventTransactionCaptured || outbox.AggregateID != "tx-hold" {
+		t.Fatalf("unexpected outbox event %s for %s", outbox.Type, outbox.AggregateID)
+	}
+}
+
+func TestCaptureHoldUseCase_PartialCaptureReleasesRest(t *testing.T) {
+	var outbox *entity.Outbox
+	repo := holdRepository(holdFixture())
+	repo.captureHoldFn = func(_ context.Context, _ *entity.Transaction, o *entity.Outbox) error {
+		outbox = o
+		return nil
+	}
+	uc := usecase.NewCaptureHoldUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.CaptureInput{TransactionID: "tx-hold", Amount: 800})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Captured != 800 || out.Released != 200 {
+		t.Fatalf("expected 800 captured and 200 released, got %+v", out)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(outbox.Payload), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload["capturedAmount"] != float64(800) || payload["releasedAmount"] != float64(200) {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+	change := assertStatusChange(t, repo, "tx-hold", entity.StatusAuthorized, entity.StatusCompleted, entity.ActorAPI)
+	if change.Reason != "captured 800 of 1000" {
+		t.Fatalf("unexpected reason %q", change.Reason)
+	}
+}
+
+func TestCaptureHoldUseCase_InputValidation(t *testing.T) {
+	uc := usecase.NewCaptureHoldUseCase(&mockTransactionRepository{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.CaptureInput{})
+	_ = assertException(t, err, http.StatusBadRequest)
+
+	_, err = uc.Execute(context.Background(), usecase.CaptureInput{TransactionID: "tx-hold", Amount: -1})
+	_ = assertException(t, err, http.StatusBadRequest)
+}
+
+func TestCaptureHoldUseCase_NotFound(t *testing.T) {
+	uc := usecase.NewCaptureHoldUseCase(&mockTransactionRepository{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.CaptureInput{TransactionID: "missing"})
+
+	_ = assertException(t, err, http.StatusNotFound)
+}
+
+func TestCaptureHoldUseCase_FindError(t *testing.T) {
+	repo := &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return nil, errors.New("db error")
+		},
+	}
+	uc := usecase.NewCaptureHoldUseCase(repo, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.CaptureInput{TransactionID: "tx-hold"})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
+
+func TestCaptureHoldUseCase_RejectedByEntity(t *testing.T) {
+	expired := holdFixture()
+	past := time.Now().UTC().Add(-time.Minute)
+	expired.ExpiresAt = &past
+
+	voided := holdFixture()
+	voided.Status = entity.StatusVoided
+
+	cases := []struct {
+		name   string
+		hold   *entity.Transaction
+		amount int64
+		code   int
+	}{
+		{"not an open hold", voided, 0, http.StatusConflict},
+		{"expired", expired, 0, http.StatusUnprocessableEntity},
+		{"exceeds hold", holdFixture(), 1001, http.StatusUnprocessableEntity},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := holdRepository(tc.hold)
+			repo.captureHoldFn = func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+				t.Fatal("repository must not be called")
+				return nil
+			}
+			uc := usecase.NewCaptureHoldUseCase(repo, testLogger())
+
+			_, err := uc.Execute(context.Background(), usecase.CaptureInput{TransactionID: "tx-hold", Amount: tc.amount})
+
+			_ = assertException(t, err, tc.code)
+		})
+	}
+}
+
+func TestCaptureHoldUseCase_RejectedByRepository(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"settled concurrently", entity.ErrInvalidStatusTransition, http.StatusConflict},
+		{"db error", errors.New("db error"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := holdRepository(holdFixture())
+			repo.captureHoldFn = func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+				return tc.err
+			}
+			uc := usecase.NewCaptureHoldUseCase(repo, testLogger())
+
+			_, err := uc.Execute(context.Background(), usecase.CaptureInput{TransactionID: "tx-hold"})
+
+			_ = assertException(t, err, tc.code)
+		})
+	}
+}
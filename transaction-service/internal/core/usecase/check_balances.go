@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	BalanceCheckOutput struct {
+		Drifts []entity.BalanceDrift
+	}
+
+	CheckBalancesUseCase struct {
+		ledger ports.LedgerRepository
+		logger *slog.Logger
+	}
+)
+
+func NewCheckBalancesUseCase(ledger ports.LedgerRepository, logger *slog.Logger) *CheckBalancesUseCase {
+	return &CheckBalancesUseCase{ledger: ledger, logger: logger}
+}
+
+// Execute compares the balances projection with a full scan of the postings
+// and logs every user on which they disagree.
+func (uc *CheckBalancesUseCase) Execute(ctx context.Context) (*BalanceCheckOutput, error) {
+	uc.logger.InfoContext(ctx, "balance check started")
+
+	drifts, err := uc.ledger.FindBalanceDrift(ctx)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "find balance drift failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	for _, d := range drifts {
+		uc.logger.ErrorContext(ctx, "balance drift",
+			slog.String("user_id", d.UserID),
+			slog.Int64("projected", d.Projected),
+			slog.Int64("computed", d.Computed),
+		)
+	}
+
+	uc.logger.InfoContext(ctx, "balance check finished", slog.Int("drifts", len(drifts)))
+
+	return &BalanceCheckOutput{Drifts: drifts}, nil
+}
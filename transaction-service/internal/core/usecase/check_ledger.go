@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	LedgerCheckOutput struct {
+		Imbalances []entity.LedgerImbalance
+	}
+
+	CheckLedgerUseCase struct {
+		ledger ports.LedgerRepository
+		logger *slog.Logger
+	}
+)
+
+func NewCheckLedgerUseCase(ledger ports.LedgerRepository, logger *slog.Logger) *CheckLedgerUseCase {
+	return &CheckLedgerUseCase{ledger: ledger, logger: logger}
+}
+
+// Execute verifies the double-entry invariants and logs every transaction
+// that breaks them. Callers decide whether a non-empty report is fatal.
+func (uc *CheckLedgerUseCase) Execute(ctx context.Context) (*LedgerCheckOutput, error) {
+	uc.logger.InfoContext(ctx, "ledger check started")
+
+	imbalances, err := uc.ledger.FindImbalances(ctx)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "find ledger imbalances failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	for _, i := range imbalances {
+		uc.logger.ErrorContext(ctx, "ledger imbalance",
+			slog.String("transaction_id", i.TransactionID),
+			slog.String("status", string(i.Status)),
+			slog.Int64("sum", i.Sum),
+			slog.Int("entries", i.Entries),
+		)
+	}
+
+	uc.logger.InfoContext(ctx, "ledger check finished", slog.Int("imbalances", len(imbalances)))
+
+	return &LedgerCheckOutput{Imbalances: imbalances}, nil
+}
@@ -0,0 +1,88 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+type mockLedgerRepository struct {
+	findImbalancesFn   func(ctx context.Context) ([]entity.LedgerImbalance, error)
+	rebuildBalancesFn  func(ctx context.Context) (int64, error)
+	findBalanceDriftFn func(ctx context.Context) ([]entity.BalanceDrift, error)
+}
+
+func (m *mockLedgerRepository) FindImbalances(ctx context.Context) ([]entity.LedgerImbalance, error) {
+	if m.findImbalancesFn != nil {
+		return m.findImbalancesFn(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockLedgerRepository) RebuildBalances(ctx context.Context) (int64, error) {
+	if m.rebuildBalancesFn != nil {
+		return m.rebuildBalancesFn(ctx)
+	}
+	return 0, nil
+}
+
+func (m *mockLedgerRepository) FindBalanceDrift(ctx context.Context) ([]entity.BalanceDrift, error) {
+	if m.findBalanceDriftFn != nil {
+		return m.findBalanceDriftFn(ctx)
+	}
+	return nil, nil
+}
+
+func TestCheckLedgerUseCase_Balanced(t *testing.T) {
+	uc := usecase.NewCheckLedgerUseCase(&mockLedgerRepository{}, testLogger())
+
+	out, err := uc.Execute(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(out.Imbalances) != 0 {
+		t.Fatalf("expected no imbalances, got %d", len(out.Imbalances))
+	}
+}
+
+func TestCheckLedgerUseCase_ReportsImbalances(t *testing.T) {
+	ledger := &mockLedgerRepository{
+		findImbalancesFn: func(_ context.Context) ([]entity.LedgerImbalance, error) {
+			return []entity.LedgerImbalance{
+				{TransactionID: "tx-1", Status: entity.StatusCompleted, Sum: 0, Entries: 0},
+				{TransactionID: "tx-2", Status: entity.StatusCompleted, Sum: 100, Entries: 1},
+			}, nil
+		},
+	}
+	uc := usecase.NewCheckLedgerUseCase(ledger, testLogger())
+
+	out, err := uc.Execute(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(out.Imbalances) != 2 {
+		t.Fatalf("expected 2 imbalances, got %d", len(out.Imbalances))
+	}
+	if out.Imbalances[1].TransactionID != "tx-2" || out.Imbalances[1].Sum != 100 {
+		t.Fatalf("unexpected imbalance: %+v", out.Imbalances[1])
+	}
+}
+
+func TestCheckLedgerUseCase_RepositoryError(t *testing.T) {
+	ledger := &mockLedgerRepository{
+		findImbalancesFn: func(_ context.Context) ([]entity.LedgerImbalance, error) {
+			return nil, errors.New("db error")
+		},
+	}
+	uc := usecase.NewCheckLedgerUseCase(ledger, testLogger())
+
+	_, err := uc.Execute(context.Background())
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
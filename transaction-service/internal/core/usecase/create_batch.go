@@ -0,0 +1,473 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	BatchMode string
+
+	// BatchItem is one transfer of a batch, with the fields of CreateInput
+	// that apply to transfers; holds cannot be batched.
+	BatchItem struct {
+		FromUserID     string
+		ToUserID       string
+		Amount         int64
+		Currency       string
+		ToCurrency     string
+		ConversionRate string
+		Description    string
+		IdempotencyKey string
+		Metadata       map[string]string
+	}
+
+	CreateBatchInput struct {
+		// Mode defaults to BatchAtomic.
+		Mode  BatchMode
+		Items []BatchItem
+	}
+
+	BatchItemError struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+	}
+
+	// BatchItemResult reports what happened to the item at Index. ID, Status
+	// and Currency are set when the outcome is created or existing.
+	BatchItemResult struct {
+		Index      int                `json:"index"`
+		Outcome    string             `json:"outcome"`
+		ID         string             `json:"id,omitempty"`
+		Status     string             `json:"status,omitempty"`
+		Currency   string             `json:"currency,omitempty"`
+		Conversion *entity.Conversion `json:"conversion,omitempty"`
+		Error      *BatchItemError    `json:"error,omitempty"`
+	}
+
+	CreateBatchOutput struct {
+		Mode     BatchMode         `json:"mode"`
+		Created  int               `json:"created"`
+		Existing int               `json:"existing"`
+		Failed   int               `json:"failed"`
+		Aborted  int               `json:"aborted"`
+		Items    []BatchItemResult `json:"items"`
+	}
+
+	CreateBatchUseCase struct {
+		repo   ports.TransactionRepository
+		users  ports.KnownUserRepository
+		risk   ports.RiskEvaluator
+		logger *slog.Logger
+	}
+
+	// batchEntry is a valid item on its way to the repository.
+	batchEntry struct {
+		index int
+		input CreateInput
+		hash  string
+		tx    *entity.Transaction
+	}
+)
+
+const (
+	// BatchAtomic commits every item or none of them.
+	BatchAtomic BatchMode = "atomic"
+	// BatchBestEffort commits the items that succeed, BatchChunkSize per DB
+	// transaction.
+	BatchBestEffort BatchMode = "best_effort"
+
+	MaxBatchItems  = 5000
+	BatchChunkSize = 500
+)
+
+// Outcomes of a batch item. Aborted items were valid but not committed
+// because another item of an atomic batch failed.
+const (
+	OutcomeCreated  = "created"
+	OutcomeExisting = "existing"
+	OutcomeFailed   = "failed"
+	OutcomeAborted  = "aborted"
+)
+
+func NewCreateBatchUseCase(repo ports.TransactionRepository, users ports.KnownUserRepository, risk ports.RiskEvaluator, logger *slog.Logger) *CreateBatchUseCase {
+	return &CreateBatchUseCase{repo: repo, users: users, risk: risk, logger: logger}
+}
+
+// Execute validates and creates every transfer of the batch, answering each
+// item as CreateTransactionUseCase would: items whose idempotency key the
+// sender already used for the same request are replayed. Items the risk
+// rules deny are recorded as REJECTED, with their event, even when an atomic
+// batch aborts, and fail as declined. Only problems with the batch as a
+// whole, and storage failures in atomic mode, are returned as errors;
+// everything else is reported per item.
+func (uc *CreateBatchUseCase) Execute(ctx context.Context, input CreateBatchInput) (*CreateBatchOutput, error) {
+	mode := input.Mode
+	if mode == "" {
+		mode = BatchAtomic
+	}
+	uc.logger.InfoContext(ctx, "create batch request",
+		slog.String("mode", string(mode)),
+		slog.Int("items", len(input.Items)),
+	)
+
+	if mode != BatchAtomic && mode != BatchBestEffort {
+		return nil, apperrors.BadRequest(apperrors.WithMessage(
+			fmt.Sprintf("mode must be %s or %s", BatchAtomic, BatchBestEffort),
+		))
+	}
+	if len(input.Items) == 0 || len(input.Items) > MaxBatchItems {
+		return nil, apperrors.BadRequest(apperrors.WithMessage(
+			fmt.Sprintf("a batch must have between 1 and %d items", MaxBatchItems),
+		))
+	}
+
+	results := make([]BatchItemResult, len(input.Items))
+	for i := range results {
+		results[i].Index = i
+	}
+
+	entries := uc.validate(ctx, input.Items, results)
+	entries, err := uc.rejectUnknownUsers(ctx, entries, results)
+	if err != nil {
+		return nil, err
+	}
+	entries, err = uc.replayExisting(ctx, entries, results)
+	if err != nil {
+		return nil, err
+	}
+	entries, rejected, err := uc.assessRisk(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+	// Like a single request, a denied transfer is recorded whatever becomes
+	// of the others; it moves no funds, so it cannot fail for want of them.
+	if err := uc.persist(ctx, BatchBestEffort, rejected, results); err != nil {
+		return nil, err
+	}
+
+	if mode == BatchAtomic && anyFailed(results) {
+		for _, e := range entries {
+			results[e.index].Outcome = OutcomeAborted
+		}
+	} else if err := uc.persist(ctx, mode, entries, results); err != nil {
+		return nil, err
+	}
+
+	out := &CreateBatchOutput{Mode: mode, Items: results}
+	for _, r := range results {
+		switch r.Outcome {
+		case OutcomeCreated:
+			out.Created++
+		case OutcomeExisting:
+			out.Existing++
+		case OutcomeFailed:
+			out.Failed++
+		case OutcomeAborted:
+			out.Aborted++
+		}
+	}
+	uc.logger.InfoContext(ctx, "batch processed",
+		slog.String("mode", string(mode)),
+		slog.Int("created", out.Created),
+		slog.Int("existing", out.Existing),
+		slog.Int("failed", out.Failed),
+		slog.Int("aborted", out.Aborted),
+	)
+	return out, nil
+}
+
+// validate builds the transfer of every item and rejects items that reuse
+// an idempotency key of an earlier item from the same sender.
+func (uc *CreateBatchUseCase) validate(ctx context.Context, items []BatchItem, results []BatchItemResult) []*batchEntry {
+	entries := make([]*batchEntry, 0, len(items))
+	keys := make(map[ports.IdempotencyRef]int)
+	for i, item := range items {
+		in := CreateInput{
+			FromUserID:     item.FromUserID,
+			ToUserID:       item.ToUserID,
+			Amount:         item.Amount,
+			Currency:       item.Currency,
+			ToCurrency:     item.ToCurrency,
+			ConversionRate: item.ConversionRate,
+			Description:    item.Description,
+			IdempotencyKey: item.IdempotencyKey,
+			Metadata:       item.Metadata,
+		}
+		tx, err := newTransfer(in)
+		if err != nil {
+			failItem(results, i, apperrors.BadRequest(apperrors.WithMessage(err.Error())))
+			continue
+		}
+		if in.IdempotencyKey != "" {
+			ref := ports.IdempotencyRef{FromUserID: strings.ToLower(in.FromUserID), Key: in.IdempotencyKey}
+			if first, ok := keys[ref]; ok {
+				failItem(results, i, apperrors.BadRequest(apperrors.WithMessage(
+					fmt.Sprintf("idempotency key already used by item %d of the batch", first),
+				)))
+				continue
+			}
+			keys[ref] = i
+		}
+		entries = append(entries, &batchEntry{index: i, input: in, hash: fingerprint(in), tx: tx})
+	}
+	if invalid := len(items) - len(entries); invalid > 0 {
+		uc.logger.WarnContext(ctx, "batch items failed validation", slog.Int("count", invalid))
+	}
+	return entries
+}
+
+// rejectUnknownUsers looks every participant up at once and fails the items
+// naming a user that is not known.
+func (uc *CreateBatchUseCase) rejectUnknownUsers(ctx context.Context, entries []*batchEntry, results []BatchItemResult) ([]*batchEntry, error) {
+	if len(entries) == 0 {
+		return entries, nil
+	}
+	seen := make(map[string]bool)
+	var ids []string
+	for _, e := range entries {
+		for _, id := range []string{e.tx.FromUserID, e.tx.ToUserID} {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	missing, err := uc.users.FindMissing(ctx, ids)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "find missing users failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if len(missing) == 0 {
+		return entries, nil
+	}
+	unknown := make(map[string]bool, len(missing))
+	for _, id := range missing {
+		unknown[id] = true
+	}
+
+	uc.logger.WarnContext(ctx, "batch items rejected — unknown participants", slog.Int("users", len(missing)))
+	kept := entries[:0]
+	for _, e := range entries {
+		var names []string
+		for _, id := range []string{e.tx.FromUserID, e.tx.ToUserID} {
+			if unknown[id] {
+				names = append(names, id)
+			}
+		}
+		if len(names) > 0 {
+			failItem(results, e.index, apperrors.UnprocessableEntity(
+				apperrors.WithMessage(fmt.Sprintf("unknown user: %s", strings.Join(names, ", "))),
+			))
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return kept, nil
+}
+
+// replayExisting looks every idempotency key up at once. Items the sender
+// already created are answered with the existing transaction, or failed when
+// the key was used for a different request.
+func (uc *CreateBatchUseCase) replayExisting(ctx context.Context, entries []*batchEntry, results []BatchItemResult) ([]*batchEntry, error) {
+	var refs []ports.IdempotencyRef
+	for _, e := range entries {
+		if e.input.IdempotencyKey != "" {
+			refs = append(refs, ports.IdempotencyRef{FromUserID: e.input.FromUserID, Key: e.input.IdempotencyKey})
+		}
+	}
+	if len(refs) == 0 {
+		return entries, nil
+	}
+	found, err := uc.repo.FindByIdempotencyKeys(ctx, refs)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "find by idempotency keys failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if len(found) == 0 {
+		return entries, nil
+	}
+	existing := make(map[ports.IdempotencyRef]*entity.Transaction, len(found))
+	for _, tx := range found {
+		if tx.IdempotencyKey != nil {
+			existing[ports.IdempotencyRef{FromUserID: strings.ToLower(tx.FromUserID), Key: *tx.IdempotencyKey}] = tx
+		}
+	}
+
+	kept := entries[:0]
+	for _, e := range entries {
+		tx, ok := existing[ports.IdempotencyRef{FromUserID: strings.ToLower(e.input.FromUserID), Key: e.input.IdempotencyKey}]
+		if e.input.IdempotencyKey == "" || !ok {
+			kept = append(kept, e)
+			continue
+		}
+		replayItem(results, e.index, tx, e.hash)
+	}
+	uc.logger.InfoContext(ctx, "idempotent batch items — returning existing transactions",
+		slog.Int("count", len(found)),
+	)
+	return kept, nil
+}
+
+// assessRisk records the risk decision on every new item and splits off the
+// ones the rules deny; items held for review are created HELD. Each item is
+// assessed with its sender's earlier items, so a batch counts toward the
+// velocity rules as the same transfers sent one by one would.
+func (uc *CreateBatchUseCase) assessRisk(ctx context.Context, entries []*batchEntry) (kept, rejected []*batchEntry, err error) {
+	pending := make(map[string][]*entity.Transaction)
+	kept = make([]*batchEntry, 0, len(entries))
+	for _, e := range entries {
+		sender := strings.ToLower(e.tx.FromUserID)
+		if err := assess(ctx, uc.risk, uc.logger, e.tx, pending[sender]); err != nil {
+			return nil, nil, err
+		}
+		if e.tx.Status == entity.StatusRejected {
+			rejected = append(rejected, e)
+			continue
+		}
+		pending[sender] = append(pending[sender], e.tx)
+		kept = append(kept, e)
+	}
+	return kept, rejected, nil
+}
+
+// persist hands the entries to the repository: all at once in atomic mode,
+// BatchChunkSize at a time otherwise, so a large best-effort batch does not
+// hold its account locks for the whole run and a failing chunk only fails
+// its own items.
+func (uc *CreateBatchUseCase) persist(ctx context.Context, mode BatchMode, entries []*batchEntry, results []BatchItemResult) error {
+	atomic := mode == BatchAtomic
+	size := BatchChunkSize
+	if atomic {
+		size = len(entries)
+	}
+
+	for start := 0; start < len(entries); start += size {
+		chunk := entries[start:min(start+size, len(entries))]
+		writes := make([]ports.TransactionWrite, len(chunk))
+		for i, e := range chunk {
+			outbox, err := newCreatedOutbox(e.tx, assessedEventType(e.tx, entity.EventTransactionCreated))
+			if err != nil {
+				uc.logger.ErrorContext(ctx, "marshal outbox payload failed", slog.String("error", err.Error()))
+				return apperrors.Unexpected(apperrors.WithError(err))
+			}
+			if e.input.IdempotencyKey != "" {
+				e.tx.IdempotencyKey = &e.input.IdempotencyKey
+				e.tx.RequestHash = &e.hash
+			}
+			writes[i] = ports.TransactionWrite{
+				Tx:     e.tx,
+				Change: entity.NewStatusChange(e.tx, "", entity.ActorAPI, riskReason(e.tx)),
+				Outbox: outbox,
+			}
+		}
+
+		errs, err := uc.repo.CreateBatch(ctx, writes, atomic)
+		if err != nil {
+			uc.logger.ErrorContext(ctx, "persist batch failed",
+				slog.Int("items", len(chunk)),
+				slog.String("error", err.Error()),
+			)
+			if atomic {
+				return apperrors.Unexpected(apperrors.WithError(err))
+			}
+			for _, e := range chunk {
+				failItem(results, e.index, apperrors.Unexpected(apperrors.WithMessage("transaction could not be persisted")))
+			}
+			continue
+		}
+
+		rolledBack := false
+		for i, e := range chunk {
+			if errs[i] == nil {
+				continue
+			}
+			rolledBack = atomic
+			switch {
+			case errors.Is(errs[i], entity.ErrDuplicateIdempotencyKey):
+				// A concurrent request of the sender won the key; answer as
+				// its retry.
+				uc.replayOne(ctx, e, results)
+			case errors.Is(errs[i], entity.ErrInsufficientFunds):
+				failItem(results, e.index, apperrors.UnprocessableEntity(apperrors.WithMessage(entity.ErrInsufficientFunds.Error())))
+			case errors.As(errs[i], new(*entity.LimitExceededError)):
+				failItem(results, e.index, apperrors.UnprocessableEntity(apperrors.WithMessage(errs[i].Error())))
+			default:
+				uc.logger.ErrorContext(ctx, "persist batch item failed", slog.String("error", errs[i].Error()))
+				failItem(results, e.index, apperrors.Unexpected(apperrors.WithMessage("transaction could not be persisted")))
+			}
+		}
+		for i, e := range chunk {
+			if errs[i] != nil {
+				continue
+			}
+			if rolledBack {
+				results[e.index].Outcome = OutcomeAborted
+				continue
+			}
+			if e.tx.Status == entity.StatusRejected {
+				failItem(results, e.index, apperrors.UnprocessableEntity(apperrors.WithMessage(errDeclined.Error())))
+				continue
+			}
+			results[e.index].Outcome = OutcomeCreated
+			results[e.index].ID = e.tx.ID
+			results[e.index].Status = string(e.tx.Status)
+			results[e.index].Currency = e.tx.Currency
+			results[e.index].Conversion = e.tx.Conversion
+		}
+	}
+	return nil
+}
+
+func (uc *CreateBatchUseCase) replayOne(ctx context.Context, e *batchEntry, results []BatchItemResult) {
+	existing, err := uc.repo.FindByIdempotencyKey(ctx, e.input.FromUserID, e.input.IdempotencyKey)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "find by idempotency key failed", slog.String("error", err.Error()))
+		failItem(results, e.index, apperrors.Unexpected(apperrors.WithMessage("transaction could not be persisted")))
+		return
+	}
+	if existing == nil {
+		failItem(results, e.index, apperrors.Unexpected(apperrors.WithMessage(entity.ErrDuplicateIdempotencyKey.Error())))
+		return
+	}
+	replayItem(results, e.index, existing, e.hash)
+}
+
+// replayItem answers item i with existing, the transaction its sender created
+// with the same idempotency key.
+func replayItem(results []BatchItemResult, i int, existing *entity.Transaction, requestHash string) {
+	if !sameRequest(existing, requestHash) {
+		failItem(results, i, apperrors.UnprocessableEntity(apperrors.WithMessage(errIdempotencyKeyReused.Error())))
+		return
+	}
+	if existing.Status == entity.StatusRejected {
+		failItem(results, i, apperrors.UnprocessableEntity(apperrors.WithMessage(errDeclined.Error())))
+		return
+	}
+	results[i].Outcome = OutcomeExisting
+	results[i].ID = existing.ID
+	results[i].Status = string(existing.Status)
+	results[i].Currency = existing.Currency
+	results[i].Conversion = existing.Conversion
+}
+
+func failItem(results []BatchItemResult, i int, exc *apperrors.Exception) {
+	results[i].Outcome = OutcomeFailed
+	results[i].Error = &BatchItemError{Status: exc.Code, Message: exc.Message}
+}
+
+func anyFailed(results []BatchItemResult) bool {
+	for _, r := range results {
+		if r.Outcome == OutcomeFailed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,642 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	"transaction-service/internal/core/usecase"
+)
+
+func batchItems(n int) []usecase.BatchItem {
+	items := make([]usecase.BatchItem, n)
+	for i := range items {
+		items[i] = usecase.BatchItem{FromUserID: "employer", ToUserID: "employee", Amount: 1000, Description: "salary"}
+	}
+	return items
+}
+
+func assertOutcomes(t *testing.T, out *usecase.CreateBatchOutput, want ...string) {
+	t.Helper()
+	if len(out.Items) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(out.Items))
+	}
+	for i, r := range out.Items {
+		if r.Index != i || r.Outcome != want[i] {
+			t.Fatalf("item %d: expected %s, got %+v", i, want[i], r)
+		}
+	}
+}
+
+func TestCreateBatchUseCase_AtomicCreatesEveryItem(t *testing.T) {
+	var calls int
+	var written []ports.TransactionWrite
+	repo := &mockTransactionRepository{
+		createBatchFn: func(_ context.Context, writes []ports.TransactionWrite, atomic bool) ([]error, error) {
+			calls++
+			if !atomic {
+				t.Fatal("expected an atomic write")
+			}
+			written = writes
+			return make([]error, len(writes)), nil
+		},
+	}
+	uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	items := batchItems(3)
+	items[1].IdempotencyKey = "payroll-1"
+	items[2].Currency, items[2].ToCurrency, items[2].ConversionRate = "USD", "EUR", "0.9215"
+	out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Items: items})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if calls != 1 || len(written) != 3 {
+		t.Fatalf("expected one write of 3 items, got %d calls and %d items", calls, len(written))
+	}
+	assertOutcomes(t, out, usecase.OutcomeCreated, usecase.OutcomeCreated, usecase.OutcomeCreated)
+	if out.Mode != usecase.BatchAtomic || out.Created != 3 {
+		t.Fatalf("unexpected summary: %+v", out)
+	}
+	for i, w := range written {
+		if out.Items[i].ID != w.Tx.ID || out.Items[i].Status != string(entity.StatusPending) {
+			t.Fatalf("item %d does not report its transaction: %+v", i, out.Items[i])
+		}
+		if w.Outbox.Type != entity.EventTransactionCreated || w.Outbox.AggregateID != w.Tx.ID {
+			t.Fatalf("item %d has an unexpected outbox event: %+v", i, w.Outbox)
+		}
+		if w.Change.FromStatus != "" || w.Change.ToStatus != entity.StatusPending || w.Change.Actor != entity.ActorAPI {
+			t.Fatalf("item %d has an unexpected status change: %+v", i, w.Change)
+		}
+	}
+	if written[0].Tx.IdempotencyKey != nil || written[1].Tx.IdempotencyKey == nil || written[1].Tx.RequestHash == nil {
+		t.Fatal("expected only the keyed item to carry its key and request hash")
+	}
+	if c := out.Items[2].Conversion; c == nil || c.Amount != 922 {
+		t.Fatalf("expected the conversion reported, got %+v", c)
+	}
+}
+
+func TestCreateBatchUseCase_CarriesItemMetadata(t *testing.T) {
+	var written []ports.TransactionWrite
+	repo := &mockTransactionRepository{
+		createBatchFn: func(_ context.Context, writes []ports.TransactionWrite, _ bool) ([]error, error) {
+			written = writes
+			return make([]error, len(writes)), nil
+		},
+	}
+	uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	items := batchItems(3)
+	items[0].Metadata = map[string]string{"employee_id": "emp-42"}
+	items[2].Metadata = map[string]string{"": "missing key"}
+	out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Mode: usecase.BatchBestEffort, Items: items})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	assertOutcomes(t, out, usecase.OutcomeCreated, usecase.OutcomeCreated, usecase.OutcomeFailed)
+	if len(written) != 2 || written[0].Tx.Metadata["employee_id"] != "emp-42" || written[1].Tx.Metadata != nil {
+		t.Fatalf("expected only the first item annotated, got %+v", written)
+	}
+	if e := out.Items[2].Error; e == nil || e.Status != http.StatusBadRequest || e.Message != entity.ErrMetadataKeyRequired.Error() {
+		t.Fatalf("unexpected item error: %+v", e)
+	}
+}
+
+func TestCreateBatchUseCase_RejectsBatch(t *testing.T) {
+	cases := []struct {
+		name  string
+		input usecase.CreateBatchInput
+	}{
+		{"unknown mode", usecase.CreateBatchInput{Mode: "eventually", Items: batchItems(1)}},
+		{"no items", usecase.CreateBatchInput{Mode: usecase.BatchBestEffort}},
+		{"too many items", usecase.CreateBatchInput{Items: batchItems(usecase.MaxBatchItems + 1)}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTransactionRepository{
+				createBatchFn: func(context.Context, []ports.TransactionWrite, bool) ([]error, error) {
+					t.Fatal("expected nothing persisted")
+					return nil, nil
+				},
+			}
+			uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+			_, err := uc.Execute(context.Background(), tc.input)
+
+			_ = assertException(t, err, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestCreateBatchUseCase_AtomicAbortsOnInvalidItem(t *testing.T) {
+	repo := &mockTransactionRepository{
+		createBatchFn: func(context.Context, []ports.TransactionWrite, bool) ([]error, error) {
+			t.Fatal("expected nothing persisted")
+			return nil, nil
+		},
+	}
+	uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	items := batchItems(3)
+	items[1].Amount = 0
+	out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Mode: usecase.BatchAtomic, Items: items})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	assertOutcomes(t, out, usecase.OutcomeAborted, usecase.OutcomeFailed, usecase.OutcomeAborted)
+	if e := out.Items[1].Error; e == nil || e.Status != http.StatusBadRequest || e.Message != entity.ErrAmountMustBePositive.Error() {
+		t.Fatalf("unexpected item error: %+v", e)
+	}
+	if out.Failed != 1 || out.Aborted != 2 || out.Created != 0 {
+		t.Fatalf("unexpected summary: %+v", out)
+	}
+}
+
+func TestCreateBatchUseCase_BestEffortSkipsFailedItems(t *testing.T) {
+	repo := &mockTransactionRepository{
+		createBatchFn: func(_ context.Context, writes []ports.TransactionWrite, atomic bool) ([]error, error) {
+			if atomic {
+				t.Fatal("expected a best-effort write")
+			}
+			if len(writes) != 2 {
+				t.Fatalf("expected the 2 valid items, got %d", len(writes))
+			}
+			return []error{nil, entity.ErrInsufficientFunds}, nil
+		},
+	}
+	users := &mockKnownUserRepository{
+		findMissingFn: func(context.Context, []string) ([]string, error) { return []string{"stranger"}, nil },
+	}
+	uc := usecase.NewCreateBatchUseCase(repo, users, &mockRiskEvaluator{}, testLogger())
+
+	items := batchItems(4)
+	items[1].ToUserID = "employer"
+	items[2].ToUserID = "stranger"
+	out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Mode: usecase.BatchBestEffort, Items: items})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	assertOutcomes(t, out, usecase.OutcomeCreated, usecase.OutcomeFailed, usecase.OutcomeFailed, usecase.OutcomeFailed)
+	wantStatus := []int{0, http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusUnprocessableEntity}
+	for i, want := range wantStatus[1:] {
+		if e := out.Items[i+1].Error; e == nil || e.Status != want {
+			t.Fatalf("item %d: expected status %d, got %+v", i+1, want, e)
+		}
+	}
+	if msg := out.Items[2].Error.Message; msg != "unknown user: stranger" {
+		t.Fatalf("unexpected unknown user message: %q", msg)
+	}
+	if out.Created != 1 || out.Failed != 3 {
+		t.Fatalf("unexpected summary: %+v", out)
+	}
+}
+
+func TestCreateBatchUseCase_BestEffortWritesInChunks(t *testing.T) {
+	var sizes []int
+	repo := &mockTransactionRepository{
+		createBatchFn: func(_ context.Context, writes []ports.TransactionWrite, _ bool) ([]error, error) {
+			sizes = append(sizes, len(writes))
+			if len(sizes) == 2 {
+				return nil, errors.New("connection reset")
+			}
+			return make([]error, len(writes)), nil
+		},
+	}
+	uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{
+		Mode:  usecase.BatchBestEffort,
+		Items: batchItems(usecase.BatchChunkSize + 1),
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(sizes) != 2 || sizes[0] != usecase.BatchChunkSize || sizes[1] != 1 {
+		t.Fatalf("expected chunks of %d and 1, got %v", usecase.BatchChunkSize, sizes)
+	}
+	if out.Created != usecase.BatchChunkSize || out.Failed != 1 {
+		t.Fatalf("expected the failed chunk's item failed, got %+v", out)
+	}
+	if e := out.Items[usecase.BatchChunkSize].Error; e == nil || e.Status != http.StatusInternalServerError {
+		t.Fatalf("unexpected item error: %+v", e)
+	}
+}
+
+func TestCreateBatchUseCase_AtomicRepositoryFailure(t *testing.T) {
+	t.Run("item rejected", func(t *testing.T) {
+		repo := &mockTransactionRepository{
+			createBatchFn: func(_ context.Context, writes []ports.TransactionWrite, _ bool) ([]error, error) {
+				errs := make([]error, len(writes))
+				errs[1] = entity.ErrInsufficientFunds
+				return errs, nil
+			},
+		}
+		uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+		out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Items: batchItems(3)})
+
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		assertOutcomes(t, out, usecase.OutcomeAborted, usecase.OutcomeFailed, usecase.OutcomeAborted)
+		if e := out.Items[1].Error; e.Status != http.StatusUnprocessableEntity || e.Message != entity.ErrInsufficientFunds.Error() {
+			t.Fatalf("unexpected item error: %+v", e)
+		}
+		if out.Items[0].ID != "" {
+			t.Fatal("expected aborted items to report no transaction")
+		}
+	})
+
+	t.Run("limit exceeded", func(t *testing.T) {
+		exceeded := &entity.LimitExceededError{Limit: entity.LimitHourlyTransfers, Max: 2}
+		repo := &mockTransactionRepository{
+			createBatchFn: func(_ context.Context, writes []ports.TransactionWrite, _ bool) ([]error, error) {
+				errs := make([]error, len(writes))
+				errs[2] = exceeded
+				return errs, nil
+			},
+		}
+		uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+		out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Items: batchItems(3)})
+
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		assertOutcomes(t, out, usecase.OutcomeAborted, usecase.OutcomeAborted, usecase.OutcomeFailed)
+		if e := out.Items[2].Error; e.Status != http.StatusUnprocessableEntity || e.Message != exceeded.Error() {
+			t.Fatalf("unexpected item error: %+v", e)
+		}
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		repo := &mockTransactionRepository{
+			createBatchFn: func(context.Context, []ports.TransactionWrite, bool) ([]error, error) {
+				return nil, errors.New("connection reset")
+			},
+		}
+		uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+		_, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Items: batchItems(2)})
+
+		_ = assertException(t, err, http.StatusInternalServerError)
+	})
+
+	t.Run("unexpected item error", func(t *testing.T) {
+		repo := &mockTransactionRepository{
+			createBatchFn: func(_ context.Context, writes []ports.TransactionWrite, _ bool) ([]error, error) {
+				return []error{errors.New("boom")}, nil
+			},
+		}
+		uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+		out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Items: batchItems(1)})
+
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if e := out.Items[0].Error; e == nil || e.Status != http.StatusInternalServerError {
+			t.Fatalf("unexpected item error: %+v", e)
+		}
+	})
+}
+
+func TestCreateBatchUseCase_IdempotencyKeys(t *testing.T) {
+	// A first run records the transactions a retry of the batch must find.
+	var first []ports.TransactionWrite
+	seed := &mockTransactionRepository{
+		createBatchFn: func(_ context.Context, writes []ports.TransactionWrite, _ bool) ([]error, error) {
+			first = writes
+			return make([]error, len(writes)), nil
+		},
+	}
+	items := batchItems(2)
+	items[0].IdempotencyKey, items[1].IdempotencyKey = "emp-1", "emp-2"
+	if _, err := usecase.NewCreateBatchUseCase(seed, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger()).
+		Execute(context.Background(), usecase.CreateBatchInput{Items: items}); err != nil {
+		t.Fatalf("seed batch: %v", err)
+	}
+
+	t.Run("retry replays the existing transactions", func(t *testing.T) {
+		repo := &mockTransactionRepository{
+			findByIdempotencyKeysFn: func(_ context.Context, refs []ports.IdempotencyRef) ([]*entity.Transaction, error) {
+				if len(refs) != 2 || refs[0] != (ports.IdempotencyRef{FromUserID: "employer", Key: "emp-1"}) {
+					t.Fatalf("unexpected lookup: %+v", refs)
+				}
+				// Stored sender ids may differ in case from the request's.
+				winner := *first[0].Tx
+				winner.FromUserID = strings.ToUpper(winner.FromUserID)
+				return []*entity.Transaction{&winner, first[1].Tx}, nil
+			},
+			createBatchFn: func(context.Context, []ports.TransactionWrite, bool) ([]error, error) {
+				t.Fatal("expected nothing persisted")
+				return nil, nil
+			},
+		}
+		uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+		out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Items: items})
+
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		assertOutcomes(t, out, usecase.OutcomeExisting, usecase.OutcomeExisting)
+		if out.Items[0].ID != first[0].Tx.ID || out.Existing != 2 {
+			t.Fatalf("expected the first run's transactions, got %+v", out)
+		}
+	})
+
+	t.Run("key reused for a different transfer", func(t *testing.T) {
+		repo := &mockTransactionRepository{
+			findByIdempotencyKeysFn: func(context.Context, []ports.IdempotencyRef) ([]*entity.Transaction, error) {
+				return []*entity.Transaction{first[0].Tx}, nil
+			},
+		}
+		uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+		changed := append([]usecase.BatchItem(nil), items...)
+		changed[0].Amount = 2000
+		out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Mode: usecase.BatchBestEffort, Items: changed})
+
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		assertOutcomes(t, out, usecase.OutcomeFailed, usecase.OutcomeCreated)
+		if e := out.Items[0].Error; e.Status != http.StatusUnprocessableEntity {
+			t.Fatalf("unexpected item error: %+v", e)
+		}
+	})
+
+	t.Run("key repeated within the batch", func(t *testing.T) {
+		uc := usecase.NewCreateBatchUseCase(&mockTransactionRepository{}, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+		repeated := batchItems(2)
+		repeated[0].IdempotencyKey, repeated[1].IdempotencyKey = "emp-1", "emp-1"
+		out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Mode: usecase.BatchBestEffort, Items: repeated})
+
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		assertOutcomes(t, out, usecase.OutcomeCreated, usecase.OutcomeFailed)
+		if e := out.Items[1].Error; e.Status != http.StatusBadRequest || !strings.Contains(e.Message, "item 0") {
+			t.Fatalf("unexpected item error: %+v", e)
+		}
+	})
+
+	t.Run("key taken concurrently", func(t *testing.T) {
+		other := strings.Repeat("f", 64)
+		repo := &mockTransactionRepository{
+			createBatchFn: func(_ context.Context, writes []ports.TransactionWrite, _ bool) ([]error, error) {
+				return []error{entity.ErrDuplicateIdempotencyKey, entity.ErrDuplicateIdempotencyKey}, nil
+			},
+			findByIdempotencyKeyFn: func(_ context.Context, _, key string) (*entity.Transaction, error) {
+				if key == "emp-1" {
+					return first[0].Tx, nil
+				}
+				return &entity.Transaction{ID: "tx-other", RequestHash: &other}, nil
+			},
+		}
+		uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+		out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Items: items})
+
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		assertOutcomes(t, out, usecase.OutcomeExisting, usecase.OutcomeFailed)
+	})
+
+	t.Run("winner lookup fails", func(t *testing.T) {
+		repo := &mockTransactionRepository{
+			createBatchFn: func(_ context.Context, writes []ports.TransactionWrite, _ bool) ([]error, error) {
+				return []error{entity.ErrDuplicateIdempotencyKey, entity.ErrDuplicateIdempotencyKey}, nil
+			},
+			findByIdempotencyKeyFn: func(_ context.Context, _, key string) (*entity.Transaction, error) {
+				if key == "emp-1" {
+					return nil, errors.New("connection reset")
+				}
+				return nil, nil
+			},
+		}
+		uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+		out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Items: items})
+
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		assertOutcomes(t, out, usecase.OutcomeFailed, usecase.OutcomeFailed)
+		for _, r := range out.Items {
+			if r.Error.Status != http.StatusInternalServerError {
+				t.Fatalf("unexpected item error: %+v", r.Error)
+			}
+		}
+	})
+
+	t.Run("lookup fails", func(t *testing.T) {
+		repo := &mockTransactionRepository{
+			findByIdempotencyKeysFn: func(context.Context, []ports.IdempotencyRef) ([]*entity.Transaction, error) {
+				return nil, errors.New("connection reset")
+			},
+		}
+		uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+		_, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Items: items})
+
+		_ = assertException(t, err, http.StatusInternalServerError)
+	})
+}
+
+func TestCreateBatchUseCase_KnownUsersLookupFails(t *testing.T) {
+	users := &mockKnownUserRepository{
+		findMissingFn: func(context.Context, []string) ([]string, error) { return nil, errors.New("connection reset") },
+	}
+	uc := usecase.NewCreateBatchUseCase(&mockTransactionRepository{}, users, &mockRiskEvaluator{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Items: batchItems(1)})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
+
+func TestCreateBatchUseCase_RiskDecisions(t *testing.T) {
+	// Items to user "held" are reviewed and to user "blocked" denied.
+	risk := &mockRiskEvaluator{
+		evaluateFn: func(_ context.Context, tx *entity.Transaction, _ []*entity.Transaction) (entity.RiskAssessment, error) {
+			switch tx.ToUserID {
+			case "held":
+				return entity.RiskAssessment{Decision: entity.RiskReview, Reasons: []string{"new recipient"}}, nil
+			case "blocked":
+				return entity.RiskAssessment{Decision: entity.RiskDeny, Reasons: []string{"blocklisted"}}, nil
+			}
+			return entity.RiskAssessment{Decision: entity.RiskAllow}, nil
+		},
+	}
+	items := batchItems(3)
+	items[1].ToUserID = "held"
+	items[2].ToUserID = "blocked"
+
+	t.Run("best effort", func(t *testing.T) {
+		var written []ports.TransactionWrite
+		repo := &mockTransactionRepository{
+			createBatchFn: func(_ context.Context, writes []ports.TransactionWrite, _ bool) ([]error, error) {
+				written = append(written, writes...)
+				return make([]error, len(writes)), nil
+			},
+		}
+		uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, risk, testLogger())
+
+		out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Mode: usecase.BatchBestEffort, Items: items})
+
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		assertOutcomes(t, out, usecase.OutcomeCreated, usecase.OutcomeCreated, usecase.OutcomeFailed)
+		if e := out.Items[2].Error; e.Status != http.StatusUnprocessableEntity || e.Message != "transaction declined" {
+			t.Fatalf("expected the denied item declined, got %+v", e)
+		}
+		if len(written) != 3 {
+			t.Fatalf("expected every item written, got %d writes", len(written))
+		}
+		rejected := written[0]
+		if rejected.Tx.Status != entity.StatusRejected || rejected.Outbox.Type != entity.EventTransactionRejected || rejected.Change.Reason != "blocklisted" {
+			t.Fatalf("expected a REJECTED write, got %+v %+v %+v", rejected.Tx, rejected.Outbox, rejected.Change)
+		}
+		held := written[2]
+		if held.Tx.Status != entity.StatusHeld || held.Outbox.Type != entity.EventTransactionHeld || held.Change.Reason != "new recipient" {
+			t.Fatalf("expected a HELD write, got %+v %+v %+v", held.Tx, held.Outbox, held.Change)
+		}
+		if out.Items[1].Status != string(entity.StatusHeld) {
+			t.Fatalf("expected the held item reported HELD, got %+v", out.Items[1])
+		}
+	})
+
+	t.Run("atomic", func(t *testing.T) {
+		var written []ports.TransactionWrite
+		repo := &mockTransactionRepository{
+			createBatchFn: func(_ context.Context, writes []ports.TransactionWrite, _ bool) ([]error, error) {
+				written = append(written, writes...)
+				return make([]error, len(writes)), nil
+			},
+		}
+		uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, risk, testLogger())
+
+		out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Items: items})
+
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		assertOutcomes(t, out, usecase.OutcomeAborted, usecase.OutcomeAborted, usecase.OutcomeFailed)
+		if len(written) != 1 || written[0].Tx.Status != entity.StatusRejected {
+			t.Fatalf("expected only the denied item recorded, got %d writes", len(written))
+		}
+	})
+
+	t.Run("recording the rejection fails", func(t *testing.T) {
+		repo := &mockTransactionRepository{
+			createBatchFn: func(context.Context, []ports.TransactionWrite, bool) ([]error, error) {
+				return nil, errors.New("db down")
+			},
+		}
+		uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, risk, testLogger())
+
+		out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Mode: usecase.BatchBestEffort, Items: items[2:]})
+
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if e := out.Items[0].Error; e == nil || e.Status != http.StatusInternalServerError {
+			t.Fatalf("expected the unrecorded rejection to fail as 500, got %+v", out.Items[0])
+		}
+	})
+
+	t.Run("evaluator error", func(t *testing.T) {
+		failing := &mockRiskEvaluator{
+			evaluateFn: func(context.Context, *entity.Transaction, []*entity.Transaction) (entity.RiskAssessment, error) {
+				return entity.RiskAssessment{}, errors.New("db down")
+			},
+		}
+		uc := usecase.NewCreateBatchUseCase(&mockTransactionRepository{}, &mockKnownUserRepository{}, failing, testLogger())
+
+		_, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Mode: usecase.BatchBestEffort, Items: items})
+
+		_ = assertException(t, err, http.StatusInternalServerError)
+	})
+}
+
+func TestCreateBatchUseCase_CountsEarlierItemsTowardVelocity(t *testing.T) {
+	// The evaluator denies a sender's fourth transfer, counting the earlier
+	// ones of the batch as the engine's velocity rule does.
+	var seen []int
+	risk := &mockRiskEvaluator{
+		evaluateFn: func(_ context.Context, tx *entity.Transaction, pending []*entity.Transaction) (entity.RiskAssessment, error) {
+			seen = append(seen, len(pending))
+			for _, p := range pending {
+				if p.FromUserID != tx.FromUserID {
+					t.Fatalf("expected only the sender's own items, got one from %s", p.FromUserID)
+				}
+			}
+			if len(pending)+1 > 3 {
+				return entity.RiskAssessment{Decision: entity.RiskDeny, Reasons: []string{"more than 3 transfers within 1m0s"}}, nil
+			}
+			return entity.RiskAssessment{Decision: entity.RiskAllow}, nil
+		},
+	}
+	items := batchItems(6)
+	items[2].FromUserID = "other-sender"
+	var written []ports.TransactionWrite
+	repo := &mockTransactionRepository{
+		createBatchFn: func(_ context.Context, writes []ports.TransactionWrite, _ bool) ([]error, error) {
+			written = append(written, writes...)
+			return make([]error, len(writes)), nil
+		},
+	}
+	uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, risk, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Mode: usecase.BatchBestEffort, Items: items})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !reflect.DeepEqual(seen, []int{0, 1, 0, 2, 3, 3}) {
+		t.Fatalf("expected each item assessed with the sender's earlier accepted items, got %v", seen)
+	}
+	assertOutcomes(t, out,
+		usecase.OutcomeCreated, usecase.OutcomeCreated, usecase.OutcomeCreated,
+		usecase.OutcomeCreated, usecase.OutcomeFailed, usecase.OutcomeFailed,
+	)
+	if len(written) != 6 || written[0].Tx.Status != entity.StatusRejected || written[1].Tx.Status != entity.StatusRejected {
+		t.Fatalf("expected the two transfers over the limit recorded REJECTED, got %d writes", len(written))
+	}
+}
+
+func TestCreateBatchUseCase_ReplaysRejectedAsDeclined(t *testing.T) {
+	items := batchItems(1)
+	items[0].IdempotencyKey = "payroll-1"
+	repo := &mockTransactionRepository{
+		findByIdempotencyKeysFn: func(context.Context, []ports.IdempotencyRef) ([]*entity.Transaction, error) {
+			key := "payroll-1"
+			return []*entity.Transaction{{ID: "tx-1", FromUserID: "employer", Status: entity.StatusRejected, IdempotencyKey: &key}}, nil
+		},
+	}
+	uc := usecase.NewCreateBatchUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.CreateBatchInput{Items: items})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	assertOutcomes(t, out, usecase.OutcomeFailed)
+	if out.Items[0].Error.Message != "transaction declined" {
+		t.Fatalf("expected the replay declined, got %+v", out.Items[0].Error)
+	}
+}
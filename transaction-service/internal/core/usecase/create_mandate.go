@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	CreateMandateInput struct {
+		FromUserID string
+		ToUserID   string
+		Amount     int64
+		// Currency defaults to entity.DefaultCurrency.
+		Currency    string
+		Description string
+		// Schedule is a cron-style descriptor parsed by entity.ParseSchedule.
+		Schedule string
+		// StartAt is the first run; zero starts on the next scheduler pass.
+		StartAt time.Time
+		EndAt   *time.Time
+	}
+
+	CreateMandateUseCase struct {
+		mandates ports.MandateRepository
+		users    ports.KnownUserRepository
+		logger   *slog.Logger
+	}
+)
+
+func NewCreateMandateUseCase(mandates ports.MandateRepository, users ports.KnownUserRepository, logger *slog.Logger) *CreateMandateUseCase {
+	return &CreateMandateUseCase{mandates: mandates, users: users, logger: logger}
+}
+
+// Execute stores an ACTIVE mandate and emits MandateCreated. Its transfers
+// are created by RunMandatesUseCase as they fall due.
+func (uc *CreateMandateUseCase) Execute(ctx context.Context, input CreateMandateInput) (*MandateItem, error) {
+	uc.logger.InfoContext(ctx, "create mandate request", slog.String("schedule", input.Schedule))
+
+	m, err := newMandate(input)
+	if err != nil {
+		uc.logger.WarnContext(ctx, "mandate validation failed", slog.String("reason", err.Error()))
+		return nil, apperrors.BadRequest(apperrors.WithMessage(err.Error()))
+	}
+
+	if err := requireKnownUsers(ctx, uc.users, uc.logger, m.FromUserID, m.ToUserID); err != nil {
+		return nil, err
+	}
+
+	outbox, err := newMandateOutbox(m, entity.EventMandateCreated, nil)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "marshal outbox payload failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if err := uc.mandates.Create(ctx, m, outbox); err != nil {
+		uc.logger.ErrorContext(ctx, "persist mandate failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	uc.logger.InfoContext(ctx, "mandate created",
+		slog.String("mandate_id", m.ID),
+		slog.Time("next_run_at", m.NextRunAt),
+	)
+
+	item := newMandateItem(m)
+	return &item, nil
+}
+
+// newMandate starts the mandate no earlier than now and no later than
+// MaxScheduleAhead, like a scheduled transfer.
+func newMandate(input CreateMandateInput) (*entity.Mandate, error) {
+	currency := input.Currency
+	if currency == "" {
+		currency = entity.DefaultCurrency
+	}
+
+	now := time.Now().UTC()
+	startAt := now
+	if !input.StartAt.IsZero() {
+		startAt = input.StartAt.UTC()
+		if startAt.Before(now) {
+			return nil, errors.New("start_at must not be in the past")
+		}
+		if startAt.After(now.Add(MaxScheduleAhead)) {
+			return nil, fmt.Errorf("start_at must be at most %d days ahead", MaxScheduleAhead/(24*time.Hour))
+		}
+	}
+
+	var endAt *time.Time
+	if input.EndAt != nil {
+		end := input.EndAt.UTC()
+		endAt = &end
+	}
+
+	return entity.NewMandate(input.FromUserID, input.ToUserID, input.Amount, currency, input.Description, input.Schedule, startAt, endAt)
+}
@@ -0,0 +1,207 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+// mockMandateRepository records the outbox event of every write.
+type mockMandateRepository struct {
+	createFn     func(ctx context.Context, m *entity.Mandate) error
+	findByIDFn   func(ctx context.Context, id string) (*entity.Mandate, error)
+	listByUserFn func(ctx context.Context, fromUserID string) ([]*entity.Mandate, error)
+	updateFn     func(ctx context.Context, m *entity.Mandate) error
+	advanceFn    func(ctx context.Context, m *entity.Mandate) error
+	findDueFn    func(ctx context.Context, now time.Time, limit int) ([]*entity.Mandate, error)
+
+	events []*entity.Outbox
+}
+
+func (r *mockMandateRepository) Create(ctx context.Context, m *entity.Mandate, outbox *entity.Outbox) error {
+	r.events = append(r.events, outbox)
+	if r.createFn != nil {
+		return r.createFn(ctx, m)
+	}
+	return nil
+}
+
+func (r *mockMandateRepository) FindByID(ctx context.Context, id string) (*entity.Mandate, error) {
+	if r.findByIDFn != nil {
+		return r.findByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (r *mockMandateRepository) ListByUser(ctx context.Context, fromUserID string) ([]*entity.Mandate, error) {
+	if r.listByUserFn != nil {
+		return r.listByUserFn(ctx, fromUserID)
+	}
+	return nil, nil
+}
+
+func (r *mockMandateRepository) Update(ctx context.Context, m *entity.Mandate, outbox *entity.Outbox) error {
+	r.events = append(r.events, outbox)
+	if r.updateFn != nil {
+		return r.updateFn(ctx, m)
+	}
+	return nil
+}
+
+func (r *mockMandateRepository) Advance(ctx context.Context, m *entity.Mandate, outbox *entity.Outbox) error {
+	r.events = append(r.events, outbox)
+	if r.advanceFn != nil {
+		return r.advanceFn(ctx, m)
+	}
+	return nil
+}
+
+func (r *mockMandateRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*entity.Mandate, error) {
+	if r.findDueFn != nil {
+		return r.findDueFn(ctx, now, limit)
+	}
+	return nil, nil
+}
+
+// lastEvent returns the type and decoded payload of the last event written.
+func (r *mockMandateRepository) lastEvent(t *testing.T) (string, map[string]any) {
+	t.Helper()
+	if len(r.events) == 0 {
+		t.Fatal("expected an outbox event")
+	}
+	e := r.events[len(r.events)-1]
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(e.Payload), &payload); err != nil {
+		t.Fatalf("expected a JSON payload, got %v", err)
+	}
+	return e.Type, payload
+}
+
+func mandateFixture() *entity.Mandate {
+	start := time.Now().UTC().Add(-time.Minute)
+	return &entity.Mandate{
+		ID:         "mandate-1",
+		FromUserID: "user-1",
+		ToUserID:   "user-2",
+		Amount:     1000,
+		Currency:   "USD",
+		Schedule:   "@monthly",
+		StartAt:    start,
+		NextRunAt:  start,
+		Status:     entity.MandateActive,
+		CreatedAt:  start,
+		UpdatedAt:  start,
+	}
+}
+
+func TestCreateMandateUseCase_Success(t *testing.T) {
+	var persisted *entity.Mandate
+	repo := &mockMandateRepository{
+		createFn: func(_ context.Context, m *entity.Mandate) error {
+			persisted = m
+			return nil
+		},
+	}
+	uc := usecase.NewCreateMandateUseCase(repo, &mockKnownUserRepository{}, testLogger())
+
+	startAt := time.Now().Add(24 * time.Hour)
+	out, err := uc.Execute(context.Background(), usecase.CreateMandateInput{
+		FromUserID:  "user-1",
+		ToUserID:    "user-2",
+		Amount:      1000,
+		Description: "rent",
+		Schedule:    "@monthly",
+		StartAt:     startAt,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.ID != persisted.ID || out.Status != string(entity.MandateActive) || out.Currency != entity.DefaultCurrency {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if !out.NextRunAt.Equal(startAt) || out.NextRunAt.Location() != time.UTC {
+		t.Fatalf("expected the first run at %v in UTC, got %v", startAt, out.NextRunAt)
+	}
+	eventType, payload := repo.lastEvent(t)
+	if eventType != entity.EventMandateCreated || payload["mandateId"] != out.ID || payload["schedule"] != "@monthly" {
+		t.Fatalf("unexpected event %s: %v", eventType, payload)
+	}
+}
+
+func TestCreateMandateUseCase_StartsNowByDefault(t *testing.T) {
+	uc := usecase.NewCreateMandateUseCase(&mockMandateRepository{}, &mockKnownUserRepository{}, testLogger())
+
+	before := time.Now().UTC()
+	out, err := uc.Execute(context.Background(), usecase.CreateMandateInput{
+		FromUserID: "user-1",
+		ToUserID:   "user-2",
+		Amount:     1000,
+		Schedule:   "@every 24h",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.NextRunAt.Before(before) || out.NextRunAt.After(time.Now().UTC()) {
+		t.Fatalf("expected the first run now, got %v", out.NextRunAt)
+	}
+}
+
+func TestCreateMandateUseCase_ValidationErrors(t *testing.T) {
+	endBeforeStart := time.Now().Add(time.Hour)
+	cases := map[string]usecase.CreateMandateInput{
+		"same user":        {ToUserID: "user-1", Amount: 1000, Schedule: "@daily"},
+		"no amount":        {ToUserID: "user-2", Schedule: "@daily"},
+		"bad schedule":     {ToUserID: "user-2", Amount: 1000, Schedule: "0 9 1 * *"},
+		"start in past":    {ToUserID: "user-2", Amount: 1000, Schedule: "@daily", StartAt: time.Now().Add(-time.Hour)},
+		"start too far":    {ToUserID: "user-2", Amount: 1000, Schedule: "@daily", StartAt: time.Now().Add(usecase.MaxScheduleAhead + time.Hour)},
+		"end before start": {ToUserID: "user-2", Amount: 1000, Schedule: "@daily", StartAt: time.Now().Add(2 * time.Hour), EndAt: &endBeforeStart},
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockMandateRepository{
+				createFn: func(_ context.Context, _ *entity.Mandate) error {
+					t.Fatal("repository must not be called")
+					return nil
+				},
+			}
+			uc := usecase.NewCreateMandateUseCase(repo, &mockKnownUserRepository{}, testLogger())
+			input.FromUserID = "user-1"
+
+			_, err := uc.Execute(context.Background(), input)
+
+			_ = assertException(t, err, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestCreateMandateUseCase_Errors(t *testing.T) {
+	input := usecase.CreateMandateInput{FromUserID: "user-1", ToUserID: "user-2", Amount: 1000, Schedule: "@weekly"}
+
+	t.Run("unknown user", func(t *testing.T) {
+		users := &mockKnownUserRepository{
+			findMissingFn: func(_ context.Context, _ []string) ([]string, error) {
+				return []string{"user-2"}, nil
+			},
+		}
+		_, err := usecase.NewCreateMandateUseCase(&mockMandateRepository{}, users, testLogger()).Execute(context.Background(), input)
+		_ = assertException(t, err, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("persist", func(t *testing.T) {
+		repo := &mockMandateRepository{
+			createFn: func(_ context.Context, _ *entity.Mandate) error {
+				return errors.New("db error")
+			},
+		}
+		_, err := usecase.NewCreateMandateUseCase(repo, &mockKnownUserRepository{}, testLogger()).Execute(context.Background(), input)
+		_ = assertException(t, err, http.StatusInternalServerError)
+	})
+}
@@ -2,8 +2,14 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"transaction-service/internal/core/domain/entity"
 	"transaction-service/internal/core/domain/ports"
@@ -12,27 +18,68 @@ import (
 
 type (
 	CreateInput struct {
-		FromUserID     string
-		ToUserID       string
-		Amount         int64
+		FromUserID string
+		ToUserID   string
+		Amount     int64
+		// Currency defaults to entity.DefaultCurrency. A ToCurrency other
+		// than Currency credits the receiver in it at ConversionRate.
+		Currency       string
+		ToCurrency     string
+		ConversionRate string
 		Description    string
 		IdempotencyKey string
+		// ReplayAnyRequest replays the transaction IdempotencyKey already
+		// created even when it was for a different request. The mandate
+		// runner sets it: a mandate edited between a run and its retry
+		// has still run.
+		ReplayAnyRequest bool
+		// Metadata is the client's own references, within the limits of
+		// entity.ValidateMetadata.
+		Metadata map[string]string
+		// AuthorizeOnly creates an AUTHORIZED hold instead of a transfer. It
+		// expires after HoldTTL, or DefaultHoldTTL when zero.
+		AuthorizeOnly bool
+		HoldTTL       time.Duration
+		// ExecuteAt schedules a transfer for later; zero executes it now.
+		ExecuteAt time.Time
 	}
 
 	CreateOutput struct {
 		ID         string
 		Status     string
+		Currency   string
+		Conversion *entity.Conversion
+		ExpiresAt  *time.Time
+		ExecuteAt  *time.Time
 		Idempotent bool
 	}
 
 	CreateTransactionUseCase struct {
 		repo   ports.TransactionRepository
+		users  ports.KnownUserRepository
+		risk   ports.RiskEvaluator
 		logger *slog.Logger
 	}
 )
 
-func NewCreateTransactionUseCase(repo ports.TransactionRepository, logger *slog.Logger) *CreateTransactionUseCase {
-	return &CreateTransactionUseCase{repo: repo, logger: logger}
+const (
+	DefaultHoldTTL = 7 * 24 * time.Hour
+	MaxHoldTTL     = 30 * 24 * time.Hour
+
+	MaxScheduleAhead = 365 * 24 * time.Hour
+)
+
+var (
+	errHoldConversion       = errors.New("holds cannot convert between currencies")
+	errHoldScheduled        = errors.New("holds cannot be scheduled")
+	errIdempotencyKeyReused = errors.New("idempotency key was already used with a different request")
+	// errDeclined answers a transfer the risk rules rejected without telling
+	// the caller which rule fired.
+	errDeclined = errors.New("transaction declined")
+)
+
+func NewCreateTransactionUseCase(repo ports.TransactionRepository, users ports.KnownUserRepository, risk ports.RiskEvaluator, logger *slog.Logger) *CreateTransactionUseCase {
+	return &CreateTransactionUseCase{repo: repo, users: users, risk: risk, logger: logger}
 }
 
 func (uc *CreateTransactionUseCase) Execute(ctx context.Context, input CreateInput) (*CreateOutput, error) {
@@ -40,49 +87,65 @@ func (uc *CreateTransactionUseCase) Execute(ctx context.Context, input CreateInp
 		slog.Bool("has_idempotency_key", input.IdempotencyKey != ""),
 	)
 
+	requestHash := fingerprint(input)
 	if input.IdempotencyKey != "" {
-		existing, err := uc.repo.FindByIdempotencyKey(ctx, input.IdempotencyKey)
-		if err != nil {
-			uc.logger.ErrorContext(ctx, "find by idempotency key failed", slog.String("error", err.Error()))
-			return nil, apperrors.Unexpected(apperrors.WithError(err))
-		}
-		if existing != nil {
-			uc.logger.InfoContext(ctx, "idempotent request — returning existing transaction",
-				slog.String("transaction_id", existing.ID),
-			)
-			return &CreateOutput{
-				ID:         existing.ID,
-				Status:     string(existing.Status),
-				Idempotent: true,
-			}, nil
+		if out, err := uc.replay(ctx, input, requestHash); out != nil || err != nil {
+			return out, err
 		}
 	}
 
-	tx, err := entity.NewTransaction(input.FromUserID, input.ToUserID, input.Amount, input.Description)
+	tx, eventType, err := uc.newTransaction(input)
 	if err != nil {
 		uc.logger.WarnContext(ctx, "transaction validation failed", slog.String("reason", err.Error()))
 		return nil, apperrors.BadRequest(apperrors.WithMessage(err.Error()))
 	}
 
+	if err := uc.ensureKnownUsers(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if err := assess(ctx, uc.risk, uc.logger, tx, nil); err != nil {
+		return nil, err
+	}
+	eventType = assessedEventType(tx, eventType)
+
 	if input.IdempotencyKey != "" {
 		tx.IdempotencyKey = &input.IdempotencyKey
+		tx.RequestHash = &requestHash
 	}
 
-	payload, err := json.Marshal(map[string]any{
-		"transactionId": tx.ID,
-		"fromUserId":    tx.FromUserID,
-		"toUserId":      tx.ToUserID,
-		"amount":        tx.Amount,
-		"description":   tx.Description,
-	})
+	outbox, err := newCreatedOutbox(tx, eventType)
 	if err != nil {
 		uc.logger.ErrorContext(ctx, "marshal outbox payload failed", slog.String("error", err.Error()))
 		return nil, apperrors.Unexpected(apperrors.WithError(err))
 	}
 
-	outbox := entity.NewOutbox("TransactionCreated", string(payload))
-
-	if err := uc.repo.Create(ctx, tx, outbox); err != nil {
+	if err := uc.repo.Create(ctx, tx, entity.NewStatusChange(tx, "", entity.ActorAPI, riskReason(tx)), outbox); err != nil {
+		if errors.Is(err, entity.ErrDuplicateIdempotencyKey) {
+			// A concurrent duplicate won the race; answer as its retry.
+			uc.logger.InfoContext(ctx, "idempotency key taken concurrently — replaying winner")
+			out, err := uc.replay(ctx, input, requestHash)
+			if out == nil && err == nil {
+				return nil, apperrors.Unexpected(apperrors.WithError(entity.ErrDuplicateIdempotencyKey))
+			}
+			return out, err
+		}
+		if errors.Is(err, entity.ErrInsufficientFunds) {
+			uc.logger.WarnContext(ctx, "insufficient funds",
+				slog.String("from_user_id", tx.FromUserID),
+				slog.Int64("amount", tx.Amount),
+			)
+			return nil, apperrors.UnprocessableEntity(apperrors.WithMessage(entity.ErrInsufficientFunds.Error()))
+		}
+		var exceeded *entity.LimitExceededError
+		if errors.As(err, &exceeded) {
+			uc.logger.WarnContext(ctx, "transfer limit exceeded",
+				slog.String("from_user_id", tx.FromUserID),
+				slog.String("limit", exceeded.Limit),
+				slog.Int64("amount", tx.Amount),
+			)
+			return nil, apperrors.UnprocessableEntity(apperrors.WithMessage(exceeded.Error()))
+		}
 		uc.logger.ErrorContext(ctx, "persist transaction failed", slog.String("error", err.Error()))
 		return nil, apperrors.Unexpected(apperrors.WithError(err))
 	}
@@ -91,9 +154,276 @@ func (uc *CreateTransactionUseCase) Execute(ctx context.Context, input CreateInp
 		slog.String("transaction_id", tx.ID),
 		slog.String("status", string(tx.Status)),
 	)
+	if tx.Status == entity.StatusRejected {
+		return nil, apperrors.UnprocessableEntity(apperrors.WithMessage(errDeclined.Error()))
+	}
 
 	return &CreateOutput{
-		ID:     tx.ID,
-		Status: string(tx.Status),
+		ID:         tx.ID,
+		Status:     string(tx.Status),
+		Currency:   tx.Currency,
+		Conversion: tx.Conversion,
+		ExpiresAt:  tx.ExpiresAt,
+		ExecuteAt:  tx.ExecuteAt,
 	}, nil
 }
+
+// replay returns the transaction the sender already created with the
+// request's idempotency key, or nil when there is none. A key reused for a
+// different request is rejected unless input.ReplayAnyRequest is set.
+func (uc *CreateTransactionUseCase) replay(ctx context.Context, input CreateInput, requestHash string) (*CreateOutput, error) {
+	existing, err := uc.repo.FindByIdempotencyKey(ctx, input.FromUserID, input.IdempotencyKey)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "find by idempotency key failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if existing == nil {
+		return nil, nil
+	}
+	if !input.ReplayAnyRequest && !sameRequest(existing, requestHash) {
+		uc.logger.WarnContext(ctx, "idempotency key reused with a different request",
+			slog.String("transaction_id", existing.ID),
+		)
+		return nil, apperrors.UnprocessableEntity(apperrors.WithMessage(errIdempotencyKeyReused.Error()))
+	}
+
+	uc.logger.InfoContext(ctx, "idempotent request — returning existing transaction",
+		slog.String("transaction_id", existing.ID),
+	)
+	if existing.Status == entity.StatusRejected {
+		return nil, apperrors.UnprocessableEntity(apperrors.WithMessage(errDeclined.Error()))
+	}
+	return &CreateOutput{
+		ID:         existing.ID,
+		Status:     string(existing.Status),
+		Currency:   existing.Currency,
+		Conversion: existing.Conversion,
+		ExpiresAt:  existing.ExpiresAt,
+		ExecuteAt:  existing.ExecuteAt,
+		Idempotent: true,
+	}, nil
+}
+
+// newTransaction builds either a PENDING transfer, which settlement picks up
+// from its TransactionCreated event, a SCHEDULED transfer, which emits that
+// event when it is released, or an AUTHORIZED hold.
+func (uc *CreateTransactionUseCase) newTransaction(input CreateInput) (*entity.Transaction, string, error) {
+	if !input.AuthorizeOnly {
+		tx, err := newTransfer(input)
+		if err != nil || input.ExecuteAt.IsZero() {
+			return tx, entity.EventTransactionCreated, err
+		}
+		return tx, entity.EventTransactionScheduled, schedule(tx, input.ExecuteAt)
+	}
+
+	if !input.ExecuteAt.IsZero() {
+		return nil, "", errHoldScheduled
+	}
+
+	currency := input.Currency
+	if currency == "" {
+		currency = entity.DefaultCurrency
+	}
+
+	if (input.ToCurrency != "" && input.ToCurrency != currency) || input.ConversionRate != "" {
+		return nil, "", errHoldConversion
+	}
+
+	ttl := input.HoldTTL
+	if ttl == 0 {
+		ttl = DefaultHoldTTL
+	}
+	if ttl < time.Second || ttl > MaxHoldTTL {
+		return nil, "", fmt.Errorf("hold ttl must be between 1s and %s", MaxHoldTTL)
+	}
+	tx, err := entity.NewAuthorization(input.FromUserID, input.ToUserID, input.Amount, currency, input.Description, time.Now().UTC().Add(ttl))
+	if err != nil {
+		return nil, "", err
+	}
+	return tx, entity.EventTransactionAuthorized, tx.Annotate(input.Metadata)
+}
+
+// newTransfer builds a PENDING transfer with the client's metadata,
+// converting it when input names a different currency for the receiver.
+func newTransfer(input CreateInput) (*entity.Transaction, error) {
+	currency := input.Currency
+	if currency == "" {
+		currency = entity.DefaultCurrency
+	}
+	tx, err := entity.NewTransaction(input.FromUserID, input.ToUserID, input.Amount, currency, input.Description)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.ConvertTo(input.ToCurrency, input.ConversionRate); err != nil {
+		return nil, err
+	}
+	if err := tx.Annotate(input.Metadata); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// schedule defers tx until executeAt, which must lie in the future but no
+// further than MaxScheduleAhead.
+func schedule(tx *entity.Transaction, executeAt time.Time) error {
+	now := time.Now().UTC()
+	if !executeAt.After(now) {
+		return errors.New("execute_at must be in the future")
+	}
+	if executeAt.After(now.Add(MaxScheduleAhead)) {
+		return fmt.Errorf("execute_at must be at most %d days ahead", MaxScheduleAhead/(24*time.Hour))
+	}
+	return tx.Schedule(executeAt.UTC())
+}
+
+// newCreatedOutbox builds the event announcing a new transaction.
+func newCreatedOutbox(tx *entity.Transaction, eventType string) (*entity.Outbox, error) {
+	payload, err := json.Marshal(map[string]any{
+		"transactionId": tx.ID,
+		"fromUserId":    tx.FromUserID,
+		"toUserId":      tx.ToUserID,
+		"amount":        tx.Amount,
+		"currency":      tx.Currency,
+		"conversion":    tx.Conversion,
+		"description":   tx.Description,
+		"status":        tx.Status,
+		"expiresAt":     tx.ExpiresAt,
+		"executeAt":     tx.ExecuteAt,
+		"risk":          riskPayload(tx.Risk),
+		"metadata":      tx.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entity.NewOutbox(eventType, tx.ID, string(payload)), nil
+}
+
+// riskPayload is the risk decision as announced in outbox events, or nil for
+// a transaction created before decisions were recorded.
+func riskPayload(risk *entity.RiskAssessment) any {
+	if risk == nil {
+		return nil
+	}
+	return map[string]any{"decision": risk.Decision, "reasons": risk.Reasons}
+}
+
+// assess asks the risk evaluator about tx and records its decision on it. An
+// evaluator that cannot decide fails the request rather than letting the
+// transfer through unchecked.
+func assess(ctx context.Context, risk ports.RiskEvaluator, logger *slog.Logger, tx *entity.Transaction, pending []*entity.Transaction) error {
+	assessment, err := risk.Evaluate(ctx, tx, pending)
+	if err == nil {
+		err = tx.Assess(assessment)
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, "risk evaluation failed", slog.String("error", err.Error()))
+		return apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if assessment.Decision != entity.RiskAllow {
+		logger.WarnContext(ctx, "transfer flagged by risk rules",
+			slog.String("from_user_id", tx.FromUserID),
+			slog.String("decision", string(assessment.Decision)),
+			slog.String("reasons", strings.Join(assessment.Reasons, "; ")),
+		)
+	}
+	return nil
+}
+
+// assessedEventType is the event announcing tx: HELD and REJECTED transfers
+// have their own, the others keep eventType.
+func assessedEventType(tx *entity.Transaction, eventType string) string {
+	switch tx.Status {
+	case entity.StatusHeld:
+		return entity.EventTransactionHeld
+	case entity.StatusRejected:
+		return entity.EventTransactionRejected
+	}
+	return eventType
+}
+
+// riskReason is the status change reason of a transaction the risk rules
+// held or rejected.
+func riskReason(tx *entity.Transaction) string {
+	if tx.Risk == nil || tx.Risk.Decision == entity.RiskAllow {
+		return ""
+	}
+	return strings.Join(tx.Risk.Reasons, "; ")
+}
+
+// sameRequest reports whether existing, found by a request's idempotency
+// key, was created by that same request. Rows from before request hashes
+// were recorded always match.
+func sameRequest(existing *entity.Transaction, requestHash string) bool {
+	return existing.RequestHash == nil || *existing.RequestHash == requestHash
+}
+
+// fingerprint hashes the request as it will be carried out: defaults are
+// filled in, so omitting a field and sending its default value are the same
+// request, and the hold expiry only counts for holds. The execution time and
+// metadata are left out of requests without them so their hashes stay what
+// they were before transfers could be scheduled or annotated.
+func fingerprint(input CreateInput) string {
+	currency := input.Currency
+	if currency == "" {
+		currency = entity.DefaultCurrency
+	}
+	toCurrency := input.ToCurrency
+	if toCurrency == currency {
+		toCurrency = ""
+	}
+	var executeAt string
+	if !input.ExecuteAt.IsZero() {
+		executeAt = input.ExecuteAt.UTC().Format(time.RFC3339Nano)
+	}
+	var holdTTL time.Duration
+	if input.AuthorizeOnly {
+		holdTTL = input.HoldTTL
+		if holdTTL == 0 {
+			holdTTL = DefaultHoldTTL
+		}
+	}
+
+	canonical, _ := json.Marshal(struct {
+		FromUserID     string            `json:"from_user_id"`
+		ToUserID       string            `json:"to_user_id"`
+		Amount         int64             `json:"amount"`
+		Currency       string            `json:"currency"`
+		ToCurrency     string            `json:"to_currency"`
+		ConversionRate string            `json:"conversion_rate"`
+		Description    string            `json:"description"`
+		AuthorizeOnly  bool              `json:"authorize_only"`
+		HoldTTLSeconds int64             `json:"hold_ttl_seconds"`
+		ExecuteAt      string            `json:"execute_at,omitempty"`
+		Metadata       map[string]string `json:"metadata,omitempty"`
+	}{
+		input.FromUserID, input.ToUserID, input.Amount, currency, toCurrency,
+		input.ConversionRate, input.Description, input.AuthorizeOnly, int64(holdTTL / time.Second),
+		executeAt, input.Metadata,
+	})
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// ensureKnownUsers rejects transfers whose participants have not been seen in
+// the users-service UserCreated stream.
+func (uc *CreateTransactionUseCase) ensureKnownUsers(ctx context.Context, tx *entity.Transaction) error {
+	return requireKnownUsers(ctx, uc.users, uc.logger, tx.FromUserID, tx.ToUserID)
+}
+
+// requireKnownUsers rejects userIDs missing from the known users projection
+// with 422.
+func requireKnownUsers(ctx context.Context, users ports.KnownUserRepository, logger *slog.Logger, userIDs ...string) error {
+	missing, err := users.FindMissing(ctx, userIDs)
+	if err != nil {
+		logger.ErrorContext(ctx, "find missing users failed", slog.String("error", err.Error()))
+		return apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	logger.WarnContext(ctx, "request rejected — unknown participants", slog.Int("count", len(missing)))
+	return apperrors.UnprocessableEntity(
+		apperrors.WithMessage(fmt.Sprintf("unknown user: %s", strings.Join(missing, ", "))),
+	)
+}
@@ -2,13 +2,18 @@ package usecase_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
 	apperrors "transaction-service/internal/core/errors"
 	"transaction-service/internal/core/usecase"
 )
@@ -18,19 +23,51 @@ func testLogger() *slog.Logger {
 }
 
 type mockTransactionRepository struct {
-	createFn               func(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error
-	findByIDFn             func(ctx context.Context, id string) (*entity.Transaction, error)
-	findByIdempotencyKeyFn func(ctx context.Context, key string) (*entity.Transaction, error)
-	getBalanceFn           func(ctx context.Context, userID string) (int64, error)
+	createFn                 func(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error
+	createBatchFn            func(ctx context.Context, writes []ports.TransactionWrite, atomic bool) ([]error, error)
+	findByIDFn               func(ctx context.Context, id string) (*entity.Transaction, error)
+	findByIdempotencyKeyFn   func(ctx context.Context, fromUserID, key string) (*entity.Transaction, error)
+	findByIdempotencyKeysFn  func(ctx context.Context, refs []ports.IdempotencyRef) ([]*entity.Transaction, error)
+	getBalanceFn             func(ctx context.Context, userID, currency string) (int64, error)
+	getBalanceAsOfFn         func(ctx context.Context, userID, currency string, asOf time.Time) (int64, error)
+	settleFn                 func(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error
+	listByUserFn             func(ctx context.Context, q ports.TransactionQuery, limit int) ([]*entity.Transaction, error)
+	searchFn                 func(ctx context.Context, q ports.SearchQuery, limit int) ([]entity.SearchHit, error)
+	reverseFn                func(ctx context.Context, reversal *entity.Transaction, outbox *entity.Outbox) error
+	fundFn                   func(ctx context.Context, funding *entity.Transaction, outbox *entity.Outbox) error
+	getAvailableBalanceFn    func(ctx context.Context, userID, currency string) (int64, error)
+	captureHoldFn            func(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error
+	releaseHoldFn            func(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error
+	findExpiredHoldsFn       func(ctx context.Context, now time.Time, limit int) ([]*entity.Transaction, error)
+	findStatusHistoryFn      func(ctx context.Context, transactionID string) ([]*entity.StatusChange, error)
+	releaseIdempotencyKeysFn func(ctx context.Context, before time.Time, limit int) (int64, error)
+	findDueScheduledFn       func(ctx context.Context, now time.Time, limit int) ([]*entity.Transaction, error)
+	resolveScheduledFn       func(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error
+	resolveHeldFn            func(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error
+	streamStatementFn        func(ctx context.Context, q ports.StatementQuery, opening func(int64) error, posting func(entity.StatementLine) error) error
+
+	// changes collects every status change passed to a write method.
+	changes []*entity.StatusChange
 }
 
-func (m *mockTransactionRepository) Create(ctx context.Context, tx *entity.Transaction, outbox *entity.Outbox) error {
+func (m *mockTransactionRepository) Create(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	m.changes = append(m.changes, change)
 	if m.createFn != nil {
 		return m.createFn(ctx, tx, outbox)
 	}
 	return nil
 }
 
+func (m *mockTransactionRepository) CreateBatch(ctx context.Context, writes []ports.TransactionWrite, atomic bool) ([]error, error) {
+	for _, w := range writes {
+		m.changes = append(m.changes, w.Change)
+	}
+	if m.createBatchFn != nil {
+		return m.createBatchFn(ctx, writes, atomic)
+	}
+	return make([]error, len(writes)), nil
+}
+
 func (m *mockTransactionRepository) FindByID(ctx context.Context, id string) (*entity.Transaction, error) {
 	if m.findByIDFn != nil {
 		return m.findByIDFn(ctx, id)
@@ -38,20 +75,167 @@ func (m *mockTransactionRepository) FindByID(ctx context.Context, id string) (*e
 	return nil, nil
 }
 
-func (m *mockTransactionRepository) FindByIdempotencyKey(ctx context.Context, key string) (*entity.Transaction, error) {
+func (m *mockTransactionRepository) FindByIdempotencyKey(ctx context.Context, fromUserID, key string) (*entity.Transaction, error) {
 	if m.findByIdempotencyKeyFn != nil {
-		return m.findByIdempotencyKeyFn(ctx, key)
+		return m.findByIdempotencyKeyFn(ctx, fromUserID, key)
 	}
 	return nil, nil
 }
 
-func (m *mockTransactionRepository) GetBalance(ctx context.Context, userID string) (int64, error) {
+func (m *mockTransactionRepository) FindByIdempotencyKeys(ctx context.Context, refs []ports.IdempotencyRef) ([]*entity.Transaction, error) {
+	if m.findByIdempotencyKeysFn != nil {
+		return m.findByIdempotencyKeysFn(ctx, refs)
+	}
+	return nil, nil
+}
+
+func (m *mockTransactionRepository) GetBalance(ctx context.Context, userID, currency string) (int64, error) {
 	if m.getBalanceFn != nil {
-		return m.getBalanceFn(ctx, userID)
+		return m.getBalanceFn(ctx, userID, currency)
+	}
+	return 0, nil
+}
+
+func (m *mockTransactionRepository) GetBalanceAsOf(ctx context.Context, userID, currency string, asOf time.Time) (int64, error) {
+	if m.getBalanceAsOfFn != nil {
+		return m.getBalanceAsOfFn(ctx, userID, currency, asOf)
+	}
+	return 0, nil
+}
+
+func (m *mockTransactionRepository) Settle(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	m.changes = append(m.changes, change)
+	if m.settleFn != nil {
+		return m.settleFn(ctx, tx, outbox)
+	}
+	return nil
+}
+
+func (m *mockTransactionRepository) ListByUser(ctx context.Context, q ports.TransactionQuery, limit int) ([]*entity.Transaction, error) {
+	if m.listByUserFn != nil {
+		return m.listByUserFn(ctx, q, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockTransactionRepository) Search(ctx context.Context, q ports.SearchQuery, limit int) ([]entity.SearchHit, error) {
+	if m.searchFn != nil {
+		return m.searchFn(ctx, q, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockTransactionRepository) Reverse(ctx context.Context, reversal *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	m.changes = append(m.changes, change)
+	if m.reverseFn != nil {
+		return m.reverseFn(ctx, reversal, outbox)
+	}
+	return nil
+}
+
+func (m *mockTransactionRepository) Fund(ctx context.Context, funding *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	m.changes = append(m.changes, change)
+	if m.fundFn != nil {
+		return m.fundFn(ctx, funding, outbox)
+	}
+	return nil
+}
+
+func (m *mockTransactionRepository) GetAvailableBalance(ctx context.Context, userID, currency string) (int64, error) {
+	if m.getAvailableBalanceFn != nil {
+		return m.getAvailableBalanceFn(ctx, userID, currency)
 	}
 	return 0, nil
 }
 
+func (m *mockTransactionRepository) CaptureHold(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	m.changes = append(m.changes, change)
+	if m.captureHoldFn != nil {
+		return m.captureHoldFn(ctx, tx, outbox)
+	}
+	return nil
+}
+
+func (m *mockTransactionRepository) ReleaseHold(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	m.changes = append(m.changes, change)
+	if m.releaseHoldFn != nil {
+		return m.releaseHoldFn(ctx, tx, outbox)
+	}
+	return nil
+}
+
+func (m *mockTransactionRepository) FindExpiredHolds(ctx context.Context, now time.Time, limit int) ([]*entity.Transaction, error) {
+	if m.findExpiredHoldsFn != nil {
+		return m.findExpiredHoldsFn(ctx, now, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockTransactionRepository) FindStatusHistory(ctx context.Context, transactionID string) ([]*entity.StatusChange, error) {
+	if m.findStatusHistoryFn != nil {
+		return m.findStatusHistoryFn(ctx, transactionID)
+	}
+	return nil, nil
+}
+
+func (m *mockTransactionRepository) ReleaseIdempotencyKeys(ctx context.Context, before time.Time, limit int) (int64, error) {
+	if m.releaseIdempotencyKeysFn != nil {
+		return m.releaseIdempotencyKeysFn(ctx, before, limit)
+	}
+	return 0, nil
+}
+
+func (m *mockTransactionRepository) FindDueScheduled(ctx context.Context, now time.Time, limit int) ([]*entity.Transaction, error) {
+	if m.findDueScheduledFn != nil {
+		return m.findDueScheduledFn(ctx, now, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockTransactionRepository) ResolveScheduled(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	m.changes = append(m.changes, change)
+	if m.resolveScheduledFn != nil {
+		return m.resolveScheduledFn(ctx, tx, outbox)
+	}
+	return nil
+}
+
+func (m *mockTransactionRepository) ResolveHeld(ctx context.Context, tx *entity.Transaction, change *entity.StatusChange, outbox *entity.Outbox) error {
+	m.changes = append(m.changes, change)
+	if m.resolveHeldFn != nil {
+		return m.resolveHeldFn(ctx, tx, outbox)
+	}
+	return nil
+}
+
+func (m *mockTransactionRepository) StreamStatement(ctx context.Context, q ports.StatementQuery, opening func(int64) error, posting func(entity.StatementLine) error) error {
+	if m.streamStatementFn != nil {
+		return m.streamStatementFn(ctx, q, opening, posting)
+	}
+	return opening(0)
+}
+
+// mockRiskEvaluator allows every transfer unless evaluateFn or screenFn
+// decides.
+type mockRiskEvaluator struct {
+	evaluateFn func(ctx context.Context, tx *entity.Transaction, pending []*entity.Transaction) (entity.RiskAssessment, error)
+	screenFn   func(tx *entity.Transaction) []string
+}
+
+func (m *mockRiskEvaluator) Evaluate(ctx context.Context, tx *entity.Transaction, pending []*entity.Transaction) (entity.RiskAssessment, error) {
+	if m.evaluateFn != nil {
+		return m.evaluateFn(ctx, tx, pending)
+	}
+	return entity.RiskAssessment{Decision: entity.RiskAllow}, nil
+}
+
+func (m *mockRiskEvaluator) Screen(tx *entity.Transaction) []string {
+	if m.screenFn != nil {
+		return m.screenFn(tx)
+	}
+	return nil
+}
+
 func assertException(t *testing.T, err error, expectedCode int) *apperrors.Exception {
 	t.Helper()
 	exc, ok := err.(*apperrors.Exception)
@@ -66,7 +250,7 @@ func assertException(t *testing.T, err error, expectedCode int) *apperrors.Excep
 
 func TestCreateTransactionUseCase_Success(t *testing.T) {
 	repo := &mockTransactionRepository{}
-	uc := usecase.NewCreateTransactionUseCase(repo, testLogger())
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
 
 	out, err := uc.Execute(context.Background(), usecase.CreateInput{
 		FromUserID:  "user-1",
@@ -84,6 +268,7 @@ func TestCreateTransactionUseCase_Success(t *testing.T) {
 	if out.Status != string(entity.StatusPending) {
 		t.Fatalf("expected status PENDING, got %s", out.Status)
 	}
+	assertStatusChange(t, repo, out.ID, "", entity.StatusPending, entity.ActorAPI)
 }
 
 func TestCreateTransactionUseCase_SavesOutboxEvent(t *testing.T) {
@@ -95,7 +280,7 @@ func TestCreateTransactionUseCase_SavesOutboxEvent(t *testing.T) {
 			return nil
 		},
 	}
-	uc := usecase.NewCreateTransactionUseCase(repo, testLogger())
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
 
 	_, err := uc.Execute(context.Background(), usecase.CreateInput{
 		FromUserID: "user-1",
@@ -117,9 +302,79 @@ func TestCreateTransactionUseCase_SavesOutboxEvent(t *testing.T) {
 	}
 }
 
+func TestCreateTransactionUseCase_Metadata(t *testing.T) {
+	var (
+		created *entity.Transaction
+		outbox  *entity.Outbox
+	)
+	repo := &mockTransactionRepository{
+		createFn: func(_ context.Context, tx *entity.Transaction, o *entity.Outbox) error {
+			created, outbox = tx, o
+			return nil
+		},
+	}
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	for name, authorizeOnly := range map[string]bool{"transfer": false, "hold": true} {
+		t.Run(name, func(t *testing.T) {
+			_, err := uc.Execute(context.Background(), usecase.CreateInput{
+				FromUserID:    "user-1",
+				ToUserID:      "user-2",
+				Amount:        500,
+				AuthorizeOnly: authorizeOnly,
+				Metadata:      map[string]string{"order_id": "ord-42", "invoice": "INV-7"},
+			})
+
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if created.Metadata["order_id"] != "ord-42" || created.Metadata["invoice"] != "INV-7" {
+				t.Fatalf("expected the metadata on the transaction, got %v", created.Metadata)
+			}
+			var payload struct {
+				Metadata map[string]string `json:"metadata"`
+			}
+			if err := json.Unmarshal([]byte(outbox.Payload), &payload); err != nil {
+				t.Fatalf("expected a JSON payload, got %v", err)
+			}
+			if payload.Metadata["order_id"] != "ord-42" {
+				t.Fatalf("expected the metadata in the %s payload, got %s", outbox.Type, outbox.Payload)
+			}
+		})
+	}
+}
+
+func TestCreateTransactionUseCase_MetadataLimits(t *testing.T) {
+	tooMany := make(map[string]string)
+	for i := 0; i <= entity.MaxMetadataKeys; i++ {
+		tooMany[fmt.Sprintf("key-%d", i)] = "v"
+	}
+	cases := map[string]usecase.CreateInput{
+		"too many keys": {Metadata: tooMany},
+		"long value":    {Metadata: map[string]string{"note": strings.Repeat("v", entity.MaxMetadataValueLength+1)}},
+		"hold long key": {AuthorizeOnly: true, Metadata: map[string]string{strings.Repeat("k", entity.MaxMetadataKeyLength+1): "v"}},
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockTransactionRepository{
+				createFn: func(context.Context, *entity.Transaction, *entity.Outbox) error {
+					t.Fatal("expected nothing persisted")
+					return nil
+				},
+			}
+			uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+			input.FromUserID, input.ToUserID, input.Amount = "user-1", "user-2", 500
+
+			_, err := uc.Execute(context.Background(), input)
+
+			_ = assertException(t, err, http.StatusBadRequest)
+		})
+	}
+}
+
 func TestCreateTransactionUseCase_SameUser(t *testing.T) {
 	repo := &mockTransactionRepository{}
-	uc := usecase.NewCreateTransactionUseCase(repo, testLogger())
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
 
 	_, err := uc.Execute(context.Background(), usecase.CreateInput{
 		FromUserID: "user-1",
@@ -137,7 +392,7 @@ func TestCreateTransactionUseCase_InvalidAmount(t *testing.T) {
 	cases := []int64{0, -1, -100}
 	for _, amount := range cases {
 		repo := &mockTransactionRepository{}
-		uc := usecase.NewCreateTransactionUseCase(repo, testLogger())
+		uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
 
 		_, err := uc.Execute(context.Background(), usecase.CreateInput{
 			FromUserID: "user-1",
@@ -158,7 +413,7 @@ func TestCreateTransactionUseCase_RepositoryError(t *testing.T) {
 			return errors.New("db error")
 		},
 	}
-	uc := usecase.NewCreateTransactionUseCase(repo, testLogger())
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
 
 	_, err := uc.Execute(context.Background(), usecase.CreateInput{
 		FromUserID: "user-1",
@@ -169,17 +424,57 @@ func TestCreateTransactionUseCase_RepositoryError(t *testing.T) {
 	_ = assertException(t, err, http.StatusInternalServerError)
 }
 
+func TestCreateTransactionUseCase_InsufficientFunds(t *testing.T) {
+	repo := &mockTransactionRepository{
+		createFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+			return fmt.Errorf("create: %w", entity.ErrInsufficientFunds)
+		},
+	}
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.CreateInput{
+		FromUserID: "user-1",
+		ToUserID:   "user-2",
+		Amount:     100,
+	})
+
+	exc := assertException(t, err, http.StatusUnprocessableEntity)
+	if exc.Message != entity.ErrInsufficientFunds.Error() {
+		t.Fatalf("expected message %q, got %q", entity.ErrInsufficientFunds.Error(), exc.Message)
+	}
+}
+
+func TestCreateTransactionUseCase_LimitExceeded(t *testing.T) {
+	repo := &mockTransactionRepository{
+		createFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+			return &entity.LimitExceededError{Limit: entity.LimitDailyOutgoing, Max: 5000}
+		},
+	}
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.CreateInput{
+		FromUserID: "user-1",
+		ToUserID:   "user-2",
+		Amount:     100,
+	})
+
+	exc := assertException(t, err, http.StatusUnprocessableEntity)
+	if !strings.Contains(exc.Message, entity.LimitDailyOutgoing) {
+		t.Fatalf("expected the message to name the limit, got %q", exc.Message)
+	}
+}
+
 func TestCreateTransactionUseCase_IdempotencyKeyReturnsExisting(t *testing.T) {
 	existing := &entity.Transaction{
 		ID:     "tx-existing",
 		Status: entity.StatusPending,
 	}
 	repo := &mockTransactionRepository{
-		findByIdempotencyKeyFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+		findByIdempotencyKeyFn: func(_ context.Context, _, _ string) (*entity.Transaction, error) {
 			return existing, nil
 		},
 	}
-	uc := usecase.NewCreateTransactionUseCase(repo, testLogger())
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
 
 	out, err := uc.Execute(context.Background(), usecase.CreateInput{
 		FromUserID:     "user-1",
@@ -201,11 +496,11 @@ func TestCreateTransactionUseCase_IdempotencyKeyReturnsExisting(t *testing.T) {
 
 func TestCreateTransactionUseCase_IdempotencyKeyRepositoryError(t *testing.T) {
 	repo := &mockTransactionRepository{
-		findByIdempotencyKeyFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+		findByIdempotencyKeyFn: func(_ context.Context, _, _ string) (*entity.Transaction, error) {
 			return nil, errors.New("db error")
 		},
 	}
-	uc := usecase.NewCreateTransactionUseCase(repo, testLogger())
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
 
 	_, err := uc.Execute(context.Background(), usecase.CreateInput{
 		FromUserID:     "user-1",
@@ -220,7 +515,7 @@ func TestCreateTransactionUseCase_IdempotencyKeyRepositoryError(t *testing.T) {
 func TestCreateTransactionUseCase_NewTransactionWithIdempotencyKey(t *testing.T) {
 	var capturedTx *entity.Transaction
 	repo := &mockTransactionRepository{
-		findByIdempotencyKeyFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+		findByIdempotencyKeyFn: func(_ context.Context, _, _ string) (*entity.Transaction, error) {
 			return nil, nil
 		},
 		createFn: func(_ context.Context, tx *entity.Transaction, _ *entity.Outbox) error {
@@ -228,7 +523,7 @@ func TestCreateTransactionUseCase_NewTransactionWithIdempotencyKey(t *testing.T)
 			return nil
 		},
 	}
-	uc := usecase.NewCreateTransactionUseCase(repo, testLogger())
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
 
 	out, err := uc.Execute(context.Background(), usecase.CreateInput{
 		FromUserID:     "user-1",
@@ -256,7 +551,7 @@ func TestCreateTransactionUseCase_DoesNotCallRepoOnValidationError(t *testing.T)
 			return nil
 		},
 	}
-	uc := usecase.NewCreateTransactionUseCase(repo, testLogger())
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
 
 	_, _ = uc.Execute(context.Background(), usecase.CreateInput{
 		FromUserID: "",
@@ -268,3 +563,484 @@ func TestCreateTransactionUseCase_DoesNotCallRepoOnValidationError(t *testing.T)
 		t.Fatal("repository should not be called when validation fails")
 	}
 }
+
+func TestCreateTransactionUseCase_AuthorizeOnlyPlacesHold(t *testing.T) {
+	var (
+		persisted *entity.Transaction
+		outbox    *entity.Outbox
+	)
+	repo := &mockTransactionRepository{
+		createFn: func(_ context.Context, tx *entity.Transaction, o *entity.Outbox) error {
+			persisted, outbox = tx, o
+			return nil
+		},
+	}
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	before := time.Now().UTC()
+	out, err := uc.Execute(context.Background(), usecase.CreateInput{
+		FromUserID:    "user-1",
+		ToUserID:      "user-2",
+		Amount:        1000,
+		AuthorizeOnly: true,
+		HoldTTL:       time.Hour,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Status != string(entity.StatusAuthorized) {
+		t.Fatalf("expected status AUTHORIZED, got %s", out.Status)
+	}
+	if out.ExpiresAt == nil || out.ExpiresAt.Before(before.Add(time.Hour)) {
+		t.Fatalf("expected expiry an hour out, got %v", out.ExpiresAt)
+	}
+	if persisted.AuthorizedAmount == nil || *persisted.AuthorizedAmount != 1000 {
+		t.Fatalf("expected authorized amount 1000, got %v", persisted.AuthorizedAmount)
+	}
+	if outbox.Type != entity.EventTransactionAuthorized {
+		t.Fatalf("expected event %s, got %s", entity.EventTransactionAuthorized, outbox.Type)
+	}
+}
+
+func TestCreateTransactionUseCase_AuthorizeOnlyDefaultTTL(t *testing.T) {
+	uc := usecase.NewCreateTransactionUseCase(&mockTransactionRepository{}, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.CreateInput{
+		FromUserID:    "user-1",
+		ToUserID:      "user-2",
+		Amount:        1000,
+		AuthorizeOnly: true,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if until := time.Until(*out.ExpiresAt); until < usecase.DefaultHoldTTL-time.Minute || until > usecase.DefaultHoldTTL {
+		t.Fatalf("expected default ttl %s, got %s", usecase.DefaultHoldTTL, until)
+	}
+}
+
+func TestCreateTransactionUseCase_AuthorizeOnlyInvalidTTL(t *testing.T) {
+	for _, ttl := range []time.Duration{-time.Second, 500 * time.Millisecond, usecase.MaxHoldTTL + time.Second} {
+		t.Run(ttl.String(), func(t *testing.T) {
+			repo := &mockTransactionRepository{
+				createFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+					t.Fatal("repository must not be called")
+					return nil
+				},
+			}
+			uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+			_, err := uc.Execute(context.Background(), usecase.CreateInput{
+				FromUserID:    "user-1",
+				ToUserID:      "user-2",
+				Amount:        1000,
+				AuthorizeOnly: true,
+				HoldTTL:       ttl,
+			})
+
+			_ = assertException(t, err, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestCreateTransactionUseCase_ExecuteAtSchedulesTransfer(t *testing.T) {
+	var (
+		persisted *entity.Transaction
+		outbox    *entity.Outbox
+	)
+	repo := &mockTransactionRepository{
+		createFn: func(_ context.Context, tx *entity.Transaction, o *entity.Outbox) error {
+			persisted, outbox = tx, o
+			return nil
+		},
+	}
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	executeAt := time.Now().Add(24 * time.Hour)
+	out, err := uc.Execute(context.Background(), usecase.CreateInput{
+		FromUserID: "user-1",
+		ToUserID:   "user-2",
+		Amount:     1000,
+		ExecuteAt:  executeAt,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Status != string(entity.StatusScheduled) {
+		t.Fatalf("expected status SCHEDULED, got %s", out.Status)
+	}
+	if out.ExecuteAt == nil || !out.ExecuteAt.Equal(executeAt) || out.ExecuteAt.Location() != time.UTC {
+		t.Fatalf("expected execute_at %v in UTC, got %v", executeAt, out.ExecuteAt)
+	}
+	if persisted.Status != entity.StatusScheduled || persisted.ProcessedAt != nil {
+		t.Fatalf("expected an unprocessed SCHEDULED transfer, got %+v", persisted)
+	}
+	if outbox.Type != entity.EventTransactionScheduled {
+		t.Fatalf("expected event %s, got %s", entity.EventTransactionScheduled, outbox.Type)
+	}
+	assertStatusChange(t, repo, out.ID, "", entity.StatusScheduled, entity.ActorAPI)
+}
+
+func TestCreateTransactionUseCase_InvalidExecuteAt(t *testing.T) {
+	cases := map[string]usecase.CreateInput{
+		"past":          {ExecuteAt: time.Now().Add(-time.Minute)},
+		"too far ahead": {ExecuteAt: time.Now().Add(usecase.MaxScheduleAhead + time.Hour)},
+		"hold":          {ExecuteAt: time.Now().Add(time.Hour), AuthorizeOnly: true},
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockTransactionRepository{
+				createFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+					t.Fatal("repository must not be called")
+					return nil
+				},
+			}
+			uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+			input.FromUserID, input.ToUserID, input.Amount = "user-1", "user-2", 1000
+
+			_, err := uc.Execute(context.Background(), input)
+
+			_ = assertException(t, err, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestCreateTransactionUseCase_BetweenCurrencies(t *testing.T) {
+	var (
+		persisted *entity.Transaction
+		outbox    *entity.Outbox
+	)
+	repo := &mockTransactionRepository{
+		createFn: func(_ context.Context, tx *entity.Transaction, o *entity.Outbox) error {
+			persisted, outbox = tx, o
+			return nil
+		},
+	}
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.CreateInput{
+		FromUserID:     "user-1",
+		ToUserID:       "user-2",
+		Amount:         1000,
+		Currency:       "USD",
+		ToCurrency:     "EUR",
+		ConversionRate: "0.9215",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Currency != "USD" || out.Conversion == nil || out.Conversion.Amount != 922 {
+		t.Fatalf("expected 1000 USD converted to 922 EUR, got %+v", out)
+	}
+	if persisted.Conversion == nil || persisted.Conversion.Rate != "0.9215" {
+		t.Fatalf("expected the rate to be persisted, got %+v", persisted.Conversion)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(outbox.Payload), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	conversion, _ := payload["conversion"].(map[string]any)
+	if payload["currency"] != "USD" || conversion["currency"] != "EUR" || conversion["amount"] != float64(922) {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+}
+
+func TestCreateTransactionUseCase_DefaultsCurrency(t *testing.T) {
+	var persisted *entity.Transaction
+	repo := &mockTransactionRepository{
+		createFn: func(_ context.Context, tx *entity.Transaction, _ *entity.Outbox) error {
+			persisted = tx
+			return nil
+		},
+	}
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.CreateInput{FromUserID: "user-1", ToUserID: "user-2", Amount: 100})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Currency != entity.DefaultCurrency || persisted.Currency != entity.DefaultCurrency || persisted.Conversion != nil {
+		t.Fatalf("expected a plain %s transfer, got %+v", entity.DefaultCurrency, persisted)
+	}
+}
+
+func TestCreateTransactionUseCase_CurrencyValidation(t *testing.T) {
+	cases := map[string]usecase.CreateInput{
+		"unsupported currency":    {Currency: "XYZ"},
+		"missing rate":            {ToCurrency: "EUR"},
+		"rate without currency":   {ConversionRate: "1.1"},
+		"invalid rate":            {ToCurrency: "EUR", ConversionRate: "1,1"},
+		"hold between currencies": {ToCurrency: "EUR", ConversionRate: "1.1", AuthorizeOnly: true},
+		"hold with rate":          {ConversionRate: "1.1", AuthorizeOnly: true},
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockTransactionRepository{
+				createFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+					t.Fatal("repository must not be called")
+					return nil
+				},
+			}
+			uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+			input.FromUserID, input.ToUserID, input.Amount = "user-1", "user-2", 1000
+
+			_, err := uc.Execute(context.Background(), input)
+
+			_ = assertException(t, err, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestCreateTransactionUseCase_IdempotencyKeyFingerprintsRequest(t *testing.T) {
+	var stored *entity.Transaction
+	repo := &mockTransactionRepository{
+		findByIdempotencyKeyFn: func(_ context.Context, fromUserID, key string) (*entity.Transaction, error) {
+			if fromUserID != "user-1" || key != "key-abc" {
+				t.Fatalf("expected key-abc of user-1, got %s of %s", key, fromUserID)
+			}
+			return stored, nil
+		},
+		createFn: func(_ context.Context, tx *entity.Transaction, _ *entity.Outbox) error {
+			stored = tx
+			return nil
+		},
+	}
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+	input := usecase.CreateInput{FromUserID: "user-1", ToUserID: "user-2", Amount: 100, IdempotencyKey: "key-abc"}
+
+	first, err := uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if stored.RequestHash == nil || len(*stored.RequestHash) != 64 {
+		t.Fatalf("expected a SHA-256 request hash, got %v", stored.RequestHash)
+	}
+
+	// Spelling out the default currency is the same request.
+	retry := input
+	retry.Currency = entity.DefaultCurrency
+	out, err := uc.Execute(context.Background(), retry)
+	if err != nil {
+		t.Fatalf("expected the retry to replay, got: %v", err)
+	}
+	if !out.Idempotent || out.ID != first.ID {
+		t.Fatalf("expected the first transaction back, got %+v", out)
+	}
+
+	changed := map[string]func(*usecase.CreateInput){
+		"amount":      func(in *usecase.CreateInput) { in.Amount = 101 },
+		"recipient":   func(in *usecase.CreateInput) { in.ToUserID = "user-3" },
+		"description": func(in *usecase.CreateInput) { in.Description = "other" },
+		"hold":        func(in *usecase.CreateInput) { in.AuthorizeOnly = true },
+		"execute_at":  func(in *usecase.CreateInput) { in.ExecuteAt = time.Now().Add(time.Hour) },
+		"metadata":    func(in *usecase.CreateInput) { in.Metadata = map[string]string{"order_id": "ord-1"} },
+	}
+	for name, change := range changed {
+		t.Run(name, func(t *testing.T) {
+			reused := input
+			change(&reused)
+
+			_, err := uc.Execute(context.Background(), reused)
+
+			_ = assertException(t, err, http.StatusUnprocessableEntity)
+		})
+	}
+}
+
+func TestCreateTransactionUseCase_IdempotencyKeyWithoutHashReplays(t *testing.T) {
+	// Keys stored before requests were fingerprinted carry no hash.
+	existing := &entity.Transaction{ID: "tx-legacy", Status: entity.StatusCompleted}
+	repo := &mockTransactionRepository{
+		findByIdempotencyKeyFn: func(_ context.Context, _, _ string) (*entity.Transaction, error) {
+			return existing, nil
+		},
+	}
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.CreateInput{
+		FromUserID: "user-1", ToUserID: "user-2", Amount: 999, IdempotencyKey: "key-old",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !out.Idempotent || out.ID != "tx-legacy" {
+		t.Fatalf("expected the legacy transaction back, got %+v", out)
+	}
+}
+
+func TestCreateTransactionUseCase_ConcurrentDuplicate(t *testing.T) {
+	input := usecase.CreateInput{FromUserID: "user-1", ToUserID: "user-2", Amount: 100, IdempotencyKey: "key-race"}
+
+	// newRepo simulates losing the race: the first lookup misses, Create
+	// hits the unique index, and the lookup after it finds the winner.
+	newRepo := func(winner func(lost *entity.Transaction) *entity.Transaction) *mockTransactionRepository {
+		var lost *entity.Transaction
+		return &mockTransactionRepository{
+			findByIdempotencyKeyFn: func(_ context.Context, _, _ string) (*entity.Transaction, error) {
+				if lost == nil {
+					return nil, nil
+				}
+				return winner(lost), nil
+			},
+			createFn: func(_ context.Context, tx *entity.Transaction, _ *entity.Outbox) error {
+				lost = tx
+				return fmt.Errorf("create: %w", entity.ErrDuplicateIdempotencyKey)
+			},
+		}
+	}
+
+	t.Run("returns the winner", func(t *testing.T) {
+		repo := newRepo(func(lost *entity.Transaction) *entity.Transaction {
+			return &entity.Transaction{ID: "tx-winner", Status: entity.StatusPending, RequestHash: lost.RequestHash}
+		})
+		uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+		out, err := uc.Execute(context.Background(), input)
+
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if out.ID != "tx-winner" || !out.Idempotent {
+			t.Fatalf("expected the winner replayed, got %+v", out)
+		}
+	})
+
+	t.Run("winner made a different request", func(t *testing.T) {
+		other := strings.Repeat("f", 64)
+		repo := newRepo(func(*entity.Transaction) *entity.Transaction {
+			return &entity.Transaction{ID: "tx-winner", Status: entity.StatusPending, RequestHash: &other}
+		})
+		uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+		_, err := uc.Execute(context.Background(), input)
+
+		_ = assertException(t, err, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("winner no longer found", func(t *testing.T) {
+		repo := newRepo(func(*entity.Transaction) *entity.Transaction { return nil })
+		uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+
+		_, err := uc.Execute(context.Background(), input)
+
+		_ = assertException(t, err, http.StatusInternalServerError)
+	})
+}
+
+func riskEvaluator(decision entity.RiskDecision, reasons ...string) *mockRiskEvaluator {
+	return &mockRiskEvaluator{
+		evaluateFn: func(context.Context, *entity.Transaction, []*entity.Transaction) (entity.RiskAssessment, error) {
+			return entity.RiskAssessment{Decision: decision, Reasons: reasons}, nil
+		},
+	}
+}
+
+func TestCreateTransactionUseCase_RiskDecisions(t *testing.T) {
+	cases := []struct {
+		decision entity.RiskDecision
+		status   entity.TransactionStatus
+		event    string
+		reason   string
+	}{
+		{entity.RiskAllow, entity.StatusPending, entity.EventTransactionCreated, ""},
+		{entity.RiskReview, entity.StatusHeld, entity.EventTransactionHeld, "velocity; new recipient"},
+		{entity.RiskDeny, entity.StatusRejected, entity.EventTransactionRejected, "velocity; new recipient"},
+	}
+	for _, c := range cases {
+		t.Run(string(c.decision), func(t *testing.T) {
+			var (
+				persisted *entity.Transaction
+				outbox    *entity.Outbox
+			)
+			repo := &mockTransactionRepository{
+				createFn: func(_ context.Context, tx *entity.Transaction, o *entity.Outbox) error {
+					persisted, outbox = tx, o
+					return nil
+				},
+			}
+			uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, riskEvaluator(c.decision, "velocity", "new recipient"), testLogger())
+
+			out, err := uc.Execute(context.Background(), usecase.CreateInput{FromUserID: "user-1", ToUserID: "user-2", Amount: 500})
+
+			if c.decision == entity.RiskDeny {
+				exc := assertException(t, err, http.StatusUnprocessableEntity)
+				if exc.Message != "transaction declined" {
+					t.Fatalf("expected the reasons withheld, got %q", exc.Message)
+				}
+			} else if err != nil || out.Status != string(c.status) {
+				t.Fatalf("expected status %s, got %+v, %v", c.status, out, err)
+			}
+			if persisted == nil || persisted.Status != c.status || persisted.Risk.Decision != c.decision {
+				t.Fatalf("expected a %s transaction persisted, got %+v", c.status, persisted)
+			}
+			if outbox.Type != c.event {
+				t.Fatalf("expected event %s, got %s", c.event, outbox.Type)
+			}
+			var payload struct {
+				Risk struct {
+					Decision string   `json:"decision"`
+					Reasons  []string `json:"reasons"`
+				} `json:"risk"`
+			}
+			if err := json.Unmarshal([]byte(outbox.Payload), &payload); err != nil {
+				t.Fatalf("expected a JSON payload, got %v", err)
+			}
+			if payload.Risk.Decision != string(c.decision) || len(payload.Risk.Reasons) != 2 {
+				t.Fatalf("expected the decision in the event, got %+v", payload.Risk)
+			}
+			change := assertStatusChange(t, repo, persisted.ID, "", c.status, entity.ActorAPI)
+			if change.Reason != c.reason {
+				t.Fatalf("expected reason %q, got %q", c.reason, change.Reason)
+			}
+		})
+	}
+}
+
+func TestCreateTransactionUseCase_RiskEvaluatorErrorFailsClosed(t *testing.T) {
+	repo := &mockTransactionRepository{
+		createFn: func(context.Context, *entity.Transaction, *entity.Outbox) error {
+			t.Fatal("expected nothing persisted")
+			return nil
+		},
+	}
+	risk := &mockRiskEvaluator{
+		evaluateFn: func(context.Context, *entity.Transaction, []*entity.Transaction) (entity.RiskAssessment, error) {
+			return entity.RiskAssessment{}, errors.New("db down")
+		},
+	}
+	uc := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, risk, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.CreateInput{FromUserID: "user-1", ToUserID: "user-2", Amount: 500})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
+
+func TestCreateTransactionUseCase_ReplaysRejectedAsDeclined(t *testing.T) {
+	input := usecase.CreateInput{FromUserID: "user-1", ToUserID: "user-2", Amount: 500, IdempotencyKey: "key-1"}
+	hash := ""
+	repo := &mockTransactionRepository{
+		createFn: func(_ context.Context, tx *entity.Transaction, _ *entity.Outbox) error {
+			hash = *tx.RequestHash
+			return nil
+		},
+	}
+	_, _ = usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, riskEvaluator(entity.RiskDeny), testLogger()).
+		Execute(context.Background(), input)
+	repo.findByIdempotencyKeyFn = func(context.Context, string, string) (*entity.Transaction, error) {
+		return &entity.Transaction{ID: "tx-1", Status: entity.StatusRejected, RequestHash: &hash}, nil
+	}
+
+	_, err := usecase.NewCreateTransactionUseCase(repo, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger()).
+		Execute(context.Background(), input)
+
+	exc := assertException(t, err, http.StatusUnprocessableEntity)
+	if exc.Message != "transaction declined" {
+		t.Fatalf("expected the replay declined, got %q", exc.Message)
+	}
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+const defaultExpireBatchSize = 100
+
+type (
+	ExpireHoldsInput struct {
+		BatchSize int
+	}
+
+	ExpireHoldsOutput struct {
+		Expired int
+		Skipped int
+	}
+
+	ExpireHoldsUseCase struct {
+		repo   ports.TransactionRepository
+		logger *slog.Logger
+	}
+)
+
+func NewExpireHoldsUseCase(repo ports.TransactionRepository, logger *slog.Logger) *ExpireHoldsUseCase {
+	return &ExpireHoldsUseCase{repo: repo, logger: logger}
+}
+
+// Execute releases every AUTHORIZED hold whose expiry has passed and emits
+// TransactionExpired for each. Holds captured or voided while the sweep runs
+// are skipped.
+func (uc *ExpireHoldsUseCase) Execute(ctx context.Context, input ExpireHoldsInput) (*ExpireHoldsOutput, error) {
+	batchSize := input.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultExpireBatchSize
+	}
+
+	out := &ExpireHoldsOutput{}
+	for {
+		now := time.Now().UTC()
+		holds, err := uc.repo.FindExpiredHolds(ctx, now, batchSize)
+		if err != nil {
+			uc.logger.ErrorContext(ctx, "find expired holds failed", slog.String("error", err.Error()))
+			return nil, apperrors.Unexpected(apperrors.WithError(err))
+		}
+
+		expiredBefore := out.Expired
+		for _, hold := range holds {
+			if err := hold.Expire(now); err != nil {
+				out.Skipped++
+				continue
+			}
+			if err := releaseHold(ctx, uc.repo, hold, entity.EventTransactionExpired, entity.ActorScheduler); err != nil {
+				if errors.Is(err, entity.ErrInvalidStatusTransition) {
+					out.Skipped++
+					continue
+				}
+				uc.logger.ErrorContext(ctx, "persist expiry failed",
+					slog.String("transaction_id", hold.ID),
+					slog.String("error", err.Error()),
+				)
+				return nil, apperrors.Unexpected(apperrors.WithError(err))
+			}
+			out.Expired++
+			uc.logger.InfoContext(ctx, "hold expired", slog.String("transaction_id", hold.ID))
+		}
+
+		// A page that expired nothing cannot shrink the backlog; leave the
+		// rest to the next run instead of spinning.
+		if len(holds) < batchSize || out.Expired == expiredBefore {
+			break
+		}
+	}
+
+	if out.Expired > 0 || out.Skipped > 0 {
+		uc.logger.InfoContext(ctx, "expire holds finished",
+			slog.Int("expired", out.Expired),
+			slog.Int("skipped", out.Skipped),
+		)
+	}
+
+	return out, nil
+}
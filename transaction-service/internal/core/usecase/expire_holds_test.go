@@ -0,0 +1,138 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+func expiredHold(id string) *entity.Transaction {
+	hold := holdFixture()
+	hold.ID = id
+	past := time.Now().UTC().Add(-time.Minute)
+	hold.ExpiresAt = &past
+	return hold
+}
+
+func TestExpireHoldsUseCase_PagesUntilShortPage(t *testing.T) {
+	pages := [][]*entity.Transaction{
+		{expiredHold("tx-1"), expiredHold("tx-2")},
+		{expiredHold("tx-3")},
+	}
+	var released []string
+	repo := &mockTransactionRepository{
+		findExpiredHoldsFn: func(_ context.Context, _ time.Time, limit int) ([]*entity.Transaction, error) {
+			if limit != 2 {
+				t.Fatalf("expected limit 2, got %d", limit)
+			}
+			page := pages[0]
+			pages = pages[1:]
+			return page, nil
+		},
+		releaseHoldFn: func(_ context.Context, tx *entity.Transaction, o *entity.Outbox) error {
+			if tx.Status != entity.StatusExpired || o.Type != entity.EventTransactionExpired {
+				t.Fatalf("expected EXPIRED hold with %s event, got %s/%s", entity.EventTransactionExpired, tx.Status, o.Type)
+			}
+			released = append(released, tx.ID)
+			return nil
+		},
+	}
+	uc := usecase.NewExpireHoldsUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ExpireHoldsInput{BatchSize: 2})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Expired != 3 || out.Skipped != 0 || len(released) != 3 {
+		t.Fatalf("expected 3 holds expired, got %+v (released %v)", out, released)
+	}
+	for _, c := range repo.changes {
+		if c.ToStatus != entity.StatusExpired || c.Actor != entity.ActorScheduler {
+			t.Fatalf("expected expiries by the scheduler, got %+v", c)
+		}
+	}
+}
+
+func TestExpireHoldsUseCase_SkipsHoldsSettledConcurrently(t *testing.T) {
+	notYetExpired := holdFixture()
+	calls := 0
+	repo := &mockTransactionRepository{
+		findExpiredHoldsFn: func(_ context.Context, _ time.Time, _ int) ([]*entity.Transaction, error) {
+			calls++
+			if calls > 1 {
+				return nil, nil
+			}
+			return []*entity.Transaction{expiredHold("tx-1"), expiredHold("tx-2"), notYetExpired}, nil
+		},
+		releaseHoldFn: func(_ context.Context, tx *entity.Transaction, _ *entity.Outbox) error {
+			if tx.ID == "tx-2" {
+				return entity.ErrInvalidStatusTransition
+			}
+			return nil
+		},
+	}
+	uc := usecase.NewExpireHoldsUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ExpireHoldsInput{BatchSize: 3})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Expired != 1 || out.Skipped != 2 {
+		t.Fatalf("expected 1 expired and 2 skipped, got %+v", out)
+	}
+	if calls != 2 {
+		t.Fatalf("expected a second page after a full one, got %d calls", calls)
+	}
+}
+
+func TestExpireHoldsUseCase_StopsWhenPageExpiresNothing(t *testing.T) {
+	calls := 0
+	repo := &mockTransactionRepository{
+		findExpiredHoldsFn: func(_ context.Context, _ time.Time, _ int) ([]*entity.Transaction, error) {
+			calls++
+			return []*entity.Transaction{holdFixture()}, nil
+		},
+	}
+	uc := usecase.NewExpireHoldsUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ExpireHoldsInput{BatchSize: 1})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 1 || out.Skipped != 1 {
+		t.Fatalf("expected a single page with one skip, got %d calls and %+v", calls, out)
+	}
+}
+
+func TestExpireHoldsUseCase_Errors(t *testing.T) {
+	t.Run("find", func(t *testing.T) {
+		repo := &mockTransactionRepository{
+			findExpiredHoldsFn: func(_ context.Context, _ time.Time, _ int) ([]*entity.Transaction, error) {
+				return nil, errors.New("db error")
+			},
+		}
+		_, err := usecase.NewExpireHoldsUseCase(repo, testLogger()).Execute(context.Background(), usecase.ExpireHoldsInput{})
+		_ = assertException(t, err, http.StatusInternalServerError)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		repo := &mockTransactionRepository{
+			findExpiredHoldsFn: func(_ context.Context, _ time.Time, _ int) ([]*entity.Transaction, error) {
+				return []*entity.Transaction{expiredHold("tx-1")}, nil
+			},
+			releaseHoldFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+				return errors.New("db error")
+			},
+		}
+		_, err := usecase.NewExpireHoldsUseCase(repo, testLogger()).Execute(context.Background(), usecase.ExpireHoldsInput{})
+		_ = assertException(t, err, http.StatusInternalServerError)
+	})
+}
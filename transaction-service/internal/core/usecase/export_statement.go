@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+const (
+	StatementFormatCSV = "csv"
+	StatementFormatOFX = "ofx"
+)
+
+type (
+	// StatementInput selects the postings of UserID's account in Currency
+	// (default USD) from From, inclusive, to To, exclusive and defaulting to
+	// now. Format is csv (the default) or ofx.
+	StatementInput struct {
+		UserID   string
+		Currency string
+		Format   string
+		From     time.Time
+		To       time.Time
+	}
+
+	// StatementSink receives a statement as it is rendered. Start is called
+	// once, with the media type and a file name, before anything is written;
+	// an error returned after it means the statement was cut short.
+	StatementSink interface {
+		io.Writer
+		Start(contentType, filename string)
+	}
+
+	ExportStatementUseCase struct {
+		repo   ports.TransactionRepository
+		logger *slog.Logger
+	}
+)
+
+func NewExportStatementUseCase(repo ports.TransactionRepository, logger *slog.Logger) *ExportStatementUseCase {
+	return &ExportStatementUseCase{repo: repo, logger: logger}
+}
+
+// Execute writes the statement of the period to sink as the postings are
+// read, each with the running balance after it, between the opening and the
+// closing balance. Nothing is written when the input is invalid or the
+// opening balance cannot be read.
+func (uc *ExportStatementUseCase) Execute(ctx context.Context, input StatementInput, sink StatementSink) error {
+	uc.logger.InfoContext(ctx, "export statement request", slog.String("user_id", input.UserID))
+
+	header, err := newStatementHeader(input, time.Now().UTC())
+	if err != nil {
+		uc.logger.WarnContext(ctx, "export statement validation failed", slog.String("reason", err.Error()))
+		return apperrors.BadRequest(apperrors.WithMessage(err.Error()))
+	}
+
+	out := newStatementWriter(header.format, sink)
+	balance, lines := int64(0), 0
+	q := ports.StatementQuery{
+		UserID:   header.userID,
+		Currency: header.currency,
+		From:     header.from,
+		To:       header.to,
+	}
+	err = uc.repo.StreamStatement(ctx, q,
+		func(opening int64) error {
+			balance = opening
+			header.opening = opening
+			sink.Start(out.contentType(), header.filename())
+			return out.begin(header)
+		},
+		func(line entity.StatementLine) error {
+			balance += line.Amount
+			line.Balance = balance
+			lines++
+			return out.line(line)
+		},
+	)
+	if err == nil {
+		err = out.end(balance)
+	}
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "export statement failed",
+			slog.String("user_id", input.UserID),
+			slog.Int("lines_written", lines),
+			slog.String("error", err.Error()),
+		)
+		return apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	uc.logger.InfoContext(ctx, "statement exported",
+		slog.String("user_id", input.UserID),
+		slog.Int("lines", lines),
+	)
+
+	return nil
+}
+
+// statementHeader is what a statement says about itself besides its lines.
+type statementHeader struct {
+	userID    string
+	currency  string
+	exponent  int
+	format    string
+	from      time.Time
+	to        time.Time
+	opening   int64
+	generated time.Time
+}
+
+func newStatementHeader(input StatementInput, now time.Time) (statementHeader, error) {
+	h := statementHeader{
+		userID:    input.UserID,
+		currency:  input.Currency,
+		format:    strings.ToLower(input.Format),
+		from:      input.From.UTC(),
+		to:        input.To.UTC(),
+		generated: now,
+	}
+	if h.userID == "" {
+		return h, errors.New("user_id is required")
+	}
+	userID, err := canonicalUUID(h.userID)
+	if err != nil {
+		return h, errors.New("user_id must be a UUID")
+	}
+	h.userID = userID
+	if h.currency == "" {
+		h.currency = entity.DefaultCurrency
+	}
+	exp, err := entity.MinorUnits(h.currency)
+	if err != nil {
+		return h, err
+	}
+	h.exponent = exp
+	switch h.format {
+	case "":
+		h.format = StatementFormatCSV
+	case StatementFormatCSV, StatementFormatOFX:
+	default:
+		return h, fmt.Errorf("format must be %s or %s", StatementFormatCSV, StatementFormatOFX)
+	}
+	if input.From.IsZero() {
+		return h, errors.New("from is required")
+	}
+	if input.To.IsZero() {
+		h.to = now
+	}
+	if !h.from.Before(h.to) {
+		return h, errors.New("from must be before to")
+	}
+	return h, nil
+}
+
+func (h statementHeader) filename() string {
+	return fmt.Sprintf("statement-%s-%s-%s.%s",
+		h.currency, h.from.Format("20060102"), h.to.Format("20060102"), h.format)
+}
@@ -0,0 +1,219 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	"transaction-service/internal/core/usecase"
+)
+
+// statementBuffer collects a statement and what it was started with.
+type statementBuffer struct {
+	bytes.Buffer
+	contentType string
+	filename    string
+	starts      int
+}
+
+func (b *statementBuffer) Start(contentType, filename string) {
+	b.contentType, b.filename = contentType, filename
+	b.starts++
+}
+
+var statementFrom = time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+// statementRepo streams an opening balance of 1000 and two postings.
+func statementRepo(captured *ports.StatementQuery) *mockTransactionRepository {
+	return &mockTransactionRepository{
+		streamStatementFn: func(_ context.Context, q ports.StatementQuery, opening func(int64) error, posting func(entity.StatementLine) error) error {
+			if captured != nil {
+				*captured = q
+			}
+			if err := opening(1000); err != nil {
+				return err
+			}
+			lines := []entity.StatementLine{
+				{TransactionID: "tx-1", PostedAt: statementFrom.Add(time.Hour), Description: "=HYPERLINK(\"x\")", CounterpartyID: "user-2", Amount: 500},
+				{TransactionID: "tx-2", PostedAt: statementFrom.Add(2 * time.Hour), Description: "rent <Sept> & fees", CounterpartyID: "user-3", Amount: -250},
+			}
+			for _, l := range lines {
+				if err := posting(l); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func TestExportStatementUseCase_WritesCSVWithRunningBalance(t *testing.T) {
+	var q ports.StatementQuery
+	uc := usecase.NewExportStatementUseCase(statementRepo(&q), testLogger())
+	var out statementBuffer
+
+	err := uc.Execute(context.Background(), usecase.StatementInput{UserID: validUserID, From: statementFrom}, &out)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if q.UserID != validUserID || q.Currency != "USD" || !q.From.Equal(statementFrom) || time.Since(q.To) > time.Minute {
+		t.Fatalf("expected the USD postings from the start of the period until now, got %+v", q)
+	}
+	if out.starts != 1 || out.contentType != "text/csv; charset=utf-8" || !strings.HasSuffix(out.filename, ".csv") {
+		t.Fatalf("expected one CSV start, got %d %q %q", out.starts, out.contentType, out.filename)
+	}
+	rows, err := csv.NewReader(&out.Buffer).ReadAll()
+	if err != nil {
+		t.Fatalf("expected valid CSV, got %v", err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("expected header, opening, 2 lines and closing, got %v", rows)
+	}
+	want := [][]string{
+		{"date", "transaction_id", "description", "counterparty", "amount", "currency", "balance"},
+		{"2026-09-01T00:00:00Z", "", "Opening balance", "", "", "USD", "10.00"},
+		{"2026-09-01T01:00:00Z", "tx-1", "'=HYPERLINK(\"x\")", "user-2", "5.00", "USD", "15.00"},
+		{"2026-09-01T02:00:00Z", "tx-2", "rent <Sept> & fees", "user-3", "-2.50", "USD", "12.50"},
+	}
+	for i, row := range want {
+		if strings.Join(rows[i], "|") != strings.Join(row, "|") {
+			t.Fatalf("row %d: expected %v, got %v", i, row, rows[i])
+		}
+	}
+	if closing := rows[4]; closing[2] != "Closing balance" || closing[6] != "12.50" {
+		t.Fatalf("expected a closing balance of 12.50, got %v", closing)
+	}
+}
+
+func TestExportStatementUseCase_WritesOFX(t *testing.T) {
+	uc := usecase.NewExportStatementUseCase(statementRepo(nil), testLogger())
+	var out statementBuffer
+
+	err := uc.Execute(context.Background(), usecase.StatementInput{
+		UserID: validUserID,
+		Format: "OFX",
+		From:   statementFrom,
+		To:     statementFrom.Add(24 * time.Hour),
+	}, &out)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.contentType != "application/x-ofx" || out.filename != "statement-USD-20260901-20260902.ofx" {
+		t.Fatalf("expected an OFX file, got %q %q", out.contentType, out.filename)
+	}
+	var doc struct {
+		Statement struct {
+			Currency  string `xml:"CURDEF"`
+			AccountID string `xml:"BANKACCTFROM>ACCTID"`
+			Start     string `xml:"BANKTRANLIST>DTSTART"`
+			Lines     []struct {
+				Type   string `xml:"TRNTYPE"`
+				Amount string `xml:"TRNAMT"`
+				FITID  string `xml:"FITID"`
+				Name   string `xml:"NAME"`
+			} `xml:"BANKTRANLIST>STMTTRN"`
+			Closing string `xml:"LEDGERBAL>BALAMT"`
+			Opening string `xml:"BALLIST>BAL>VALUE"`
+		} `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS"`
+	}
+	if err := xml.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatalf("expected well-formed OFX, got %v\n%s", err, out.String())
+	}
+	s := doc.Statement
+	if s.Currency != "USD" || s.AccountID != validUserID || s.Start != "20260901000000.000[0:GMT]" {
+		t.Fatalf("unexpected statement header: %+v", s)
+	}
+	if len(s.Lines) != 2 || s.Lines[0].Type != "CREDIT" || s.Lines[1].Type != "DEBIT" || s.Lines[1].Amount != "-2.50" {
+		t.Fatalf("expected a credit and a debit, got %+v", s.Lines)
+	}
+	if s.Lines[1].FITID != "tx-2" || s.Lines[1].Name != "rent <Sept> & fees" {
+		t.Fatalf("expected the escaped description as name, got %+v", s.Lines[1])
+	}
+	if s.Opening != "10.00" || s.Closing != "12.50" {
+		t.Fatalf("expected opening 10.00 and closing 12.50, got %s and %s", s.Opening, s.Closing)
+	}
+}
+
+func TestExportStatementUseCase_EmptyPeriodClosesAtOpening(t *testing.T) {
+	repo := &mockTransactionRepository{
+		streamStatementFn: func(_ context.Context, _ ports.StatementQuery, opening func(int64) error, _ func(entity.StatementLine) error) error {
+			return opening(1500)
+		},
+	}
+	uc := usecase.NewExportStatementUseCase(repo, testLogger())
+	var out statementBuffer
+
+	err := uc.Execute(context.Background(), usecase.StatementInput{UserID: validUserID, Currency: "JPY", From: statementFrom}, &out)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	rows, err := csv.NewReader(&out.Buffer).ReadAll()
+	if err != nil {
+		t.Fatalf("expected valid CSV, got %v", err)
+	}
+	if len(rows) != 3 || rows[1][6] != "1500" || rows[2][6] != "1500" {
+		t.Fatalf("expected equal opening and closing balances in whole yen, got %v", rows)
+	}
+}
+
+func TestExportStatementUseCase_Errors(t *testing.T) {
+	failing := func(context.Context, ports.StatementQuery, func(int64) error, func(entity.StatementLine) error) error {
+		return errors.New("db error")
+	}
+	midway := func(ctx context.Context, q ports.StatementQuery, opening func(int64) error, posting func(entity.StatementLine) error) error {
+		if err := opening(0); err != nil {
+			return err
+		}
+		return errors.New("connection reset")
+	}
+	valid := usecase.StatementInput{UserID: validUserID, From: statementFrom}
+	with := func(edit func(*usecase.StatementInput)) usecase.StatementInput {
+		in := valid
+		edit(&in)
+		return in
+	}
+
+	cases := []struct {
+		name    string
+		input   usecase.StatementInput
+		stream  func(context.Context, ports.StatementQuery, func(int64) error, func(entity.StatementLine) error) error
+		code    int
+		started bool
+	}{
+		{"missing user", with(func(in *usecase.StatementInput) { in.UserID = "" }), nil, http.StatusBadRequest, false},
+		{"user not a UUID", with(func(in *usecase.StatementInput) { in.UserID = "user-1" }), nil, http.StatusBadRequest, false},
+		{"unknown currency", with(func(in *usecase.StatementInput) { in.Currency = "XXX" }), nil, http.StatusBadRequest, false},
+		{"unknown format", with(func(in *usecase.StatementInput) { in.Format = "pdf" }), nil, http.StatusBadRequest, false},
+		{"missing from", with(func(in *usecase.StatementInput) { in.From = time.Time{} }), nil, http.StatusBadRequest, false},
+		{"empty period", with(func(in *usecase.StatementInput) { in.To = statementFrom }), nil, http.StatusBadRequest, false},
+		{"opening balance error", valid, failing, http.StatusInternalServerError, false},
+		{"error while streaming", valid, midway, http.StatusInternalServerError, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := usecase.NewExportStatementUseCase(&mockTransactionRepository{streamStatementFn: tc.stream}, testLogger())
+			var out statementBuffer
+
+			err := uc.Execute(context.Background(), tc.input, &out)
+
+			_ = assertException(t, err, tc.code)
+			if started := out.starts > 0; started != tc.started {
+				t.Fatalf("expected started=%v, got %v", tc.started, started)
+			}
+			if !tc.started && out.Len() > 0 {
+				t.Fatalf("expected nothing written, got %q", out.String())
+			}
+		})
+	}
+}
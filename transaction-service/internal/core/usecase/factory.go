@@ -7,15 +7,59 @@ import (
 )
 
 type Factory struct {
-	Create  *CreateTransactionUseCase
-	Status  *GetTransactionStatusUseCase
-	Balance *GetBalanceUseCase
+	Create        *CreateTransactionUseCase
+	Batch         *CreateBatchUseCase
+	Status        *GetTransactionStatusUseCase
+	Balance       *GetBalanceUseCase
+	Settle        *SettleTransactionUseCase
+	Sync          *SyncKnownUserUseCase
+	List          *ListTransactionsUseCase
+	Search        *SearchTransactionsUseCase
+	Reverse       *ReverseTransactionUseCase
+	Capture       *CaptureHoldUseCase
+	Void          *VoidHoldUseCase
+	Expire        *ExpireHoldsUseCase
+	History       *GetTransactionHistoryUseCase
+	ReleaseKeys   *ReleaseIdempotencyKeysUseCase
+	Cancel        *CancelScheduledUseCase
+	Scheduled     *ReleaseScheduledUseCase
+	Review        *ReviewHeldUseCase
+	Statement     *ExportStatementUseCase
+	CreateMandate *CreateMandateUseCase
+	GetMandate    *GetMandateUseCase
+	ListMandates  *ListMandatesUseCase
+	UpdateMandate *UpdateMandateUseCase
+	CancelMandate *CancelMandateUseCase
+	RunMandates   *RunMandatesUseCase
 }
 
-func NewFactory(repo ports.TransactionRepository, logger *slog.Logger) *Factory {
+func NewFactory(repo ports.TransactionRepository, mandates ports.MandateRepository, users ports.KnownUserRepository, risk ports.RiskEvaluator, logger *slog.Logger) *Factory {
+	create := NewCreateTransactionUseCase(repo, users, risk, logger)
 	return &Factory{
-		Create:  NewCreateTransactionUseCase(repo, logger),
-		Status:  NewGetTransactionStatusUseCase(repo, logger),
-		Balance: NewGetBalanceUseCase(repo, logger),
+		Create:      create,
+		Batch:       NewCreateBatchUseCase(repo, users, risk, logger),
+		Status:      NewGetTransactionStatusUseCase(repo, logger),
+		Balance:     NewGetBalanceUseCase(repo, logger),
+		Settle:      NewSettleTransactionUseCase(repo, risk, logger),
+		Sync:        NewSyncKnownUserUseCase(users, logger),
+		List:        NewListTransactionsUseCase(repo, logger),
+		Search:      NewSearchTransactionsUseCase(repo, logger),
+		Reverse:     NewReverseTransactionUseCase(repo, logger),
+		Capture:     NewCaptureHoldUseCase(repo, logger),
+		Void:        NewVoidHoldUseCase(repo, logger),
+		Expire:      NewExpireHoldsUseCase(repo, logger),
+		History:     NewGetTransactionHistoryUseCase(repo, logger),
+		ReleaseKeys: NewReleaseIdempotencyKeysUseCase(repo, logger),
+		Cancel:      NewCancelScheduledUseCase(repo, logger),
+		Scheduled:   NewReleaseScheduledUseCase(repo, logger),
+		Review:      NewReviewHeldUseCase(repo, logger),
+		Statement:   NewExportStatementUseCase(repo, logger),
+
+		CreateMandate: NewCreateMandateUseCase(mandates, users, logger),
+		GetMandate:    NewGetMandateUseCase(mandates, logger),
+		ListMandates:  NewListMandatesUseCase(mandates, logger),
+		UpdateMandate: NewUpdateMandateUseCase(mandates, logger),
+		CancelMandate: NewCancelMandateUseCase(mandates, logger),
+		RunMandates:   NewRunMandatesUseCase(mandates, create, logger),
 	}
 }
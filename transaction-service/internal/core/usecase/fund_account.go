@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	// FundInput deposits Amount into UserID's account. Operator is the
+	// person paying it in, recorded in the status history.
+	FundInput struct {
+		UserID string
+		Amount int64
+		// Currency defaults to entity.DefaultCurrency.
+		Currency    string
+		Description string
+		Operator    string
+	}
+
+	FundOutput struct {
+		ID       string
+		UserID   string
+		Amount   int64
+		Currency string
+		Status   string
+	}
+
+	FundAccountUseCase struct {
+		repo   ports.TransactionRepository
+		users  ports.KnownUserRepository
+		logger *slog.Logger
+	}
+)
+
+var errOperatorRequired = errors.New("operator is required")
+
+func NewFundAccountUseCase(repo ports.TransactionRepository, users ports.KnownUserRepository, logger *slog.Logger) *FundAccountUseCase {
+	return &FundAccountUseCase{repo: repo, users: users, logger: logger}
+}
+
+// Execute pays a deposit from entity.FundingAccount into a known user's
+// account and emits AccountFunded. It is how money enters the service: the
+// account, the ledger and the balances projection are credited together.
+func (uc *FundAccountUseCase) Execute(ctx context.Context, input FundInput) (*FundOutput, error) {
+	uc.logger.InfoContext(ctx, "fund account request",
+		slog.String("user_id", input.UserID),
+		slog.Int64("amount", input.Amount),
+		slog.String("operator", input.Operator),
+	)
+
+	userID, err := canonicalUUID(input.UserID)
+	if err != nil {
+		uc.logger.WarnContext(ctx, "fund validation failed", slog.String("reason", "user_id must be a UUID"))
+		return nil, apperrors.BadRequest(apperrors.WithMessage("user_id must be a UUID"))
+	}
+	if input.Operator == "" {
+		uc.logger.WarnContext(ctx, "fund validation failed", slog.String("reason", errOperatorRequired.Error()))
+		return nil, apperrors.BadRequest(apperrors.WithMessage(errOperatorRequired.Error()))
+	}
+	currency := input.Currency
+	if currency == "" {
+		currency = entity.DefaultCurrency
+	}
+	funding, err := entity.NewFunding(userID, input.Amount, currency, input.Description)
+	if err != nil {
+		uc.logger.WarnContext(ctx, "fund validation failed", slog.String("reason", err.Error()))
+		return nil, apperrors.BadRequest(apperrors.WithMessage(err.Error()))
+	}
+
+	if err := requireKnownUsers(ctx, uc.users, uc.logger, userID); err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"transactionId": funding.ID,
+		"toUserId":      funding.ToUserID,
+		"amount":        funding.Amount,
+		"currency":      funding.Currency,
+		"status":        funding.Status,
+		"processedAt":   funding.ProcessedAt,
+	})
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "marshal outbox payload failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	outbox := entity.NewOutbox(entity.EventAccountFunded, funding.ID, string(payload))
+	change := entity.NewStatusChange(funding, "", entity.ActorAdmin, "funded by "+input.Operator)
+	if err := uc.repo.Fund(ctx, funding, change, outbox); err != nil {
+		uc.logger.ErrorContext(ctx, "persist funding failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	uc.logger.InfoContext(ctx, "account funded",
+		slog.String("transaction_id", funding.ID),
+		slog.String("user_id", funding.ToUserID),
+		slog.Int64("amount", funding.Amount),
+		slog.String("currency", funding.Currency),
+	)
+
+	return &FundOutput{
+		ID:       funding.ID,
+		UserID:   funding.ToUserID,
+		Amount:   funding.Amount,
+		Currency: funding.Currency,
+		Status:   string(funding.Status),
+	}, nil
+}
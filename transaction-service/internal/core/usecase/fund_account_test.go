@@ -0,0 +1,104 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+func TestFundAccountUseCase_Success(t *testing.T) {
+	var (
+		persisted *entity.Transaction
+		outbox    *entity.Outbox
+	)
+	repo := &mockTransactionRepository{
+		fundFn: func(_ context.Context, funding *entity.Transaction, o *entity.Outbox) error {
+			persisted, outbox = funding, o
+			return nil
+		},
+	}
+	uc := usecase.NewFundAccountUseCase(repo, &mockKnownUserRepository{}, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.FundInput{
+		UserID: validUserID, Amount: 1000, Description: "opening deposit", Operator: "alice",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.ID != persisted.ID || out.UserID != validUserID || out.Amount != 1000 || out.Currency != "USD" || out.Status != "COMPLETED" {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if persisted.FromUserID != entity.FundingAccount || persisted.ToUserID != validUserID {
+		t.Fatalf("expected a deposit from the funding account, got %s -> %s", persisted.FromUserID, persisted.ToUserID)
+	}
+	if outbox.Type != entity.EventAccountFunded || outbox.AggregateID != persisted.ID {
+		t.Fatalf("unexpected outbox event %s for %s", outbox.Type, outbox.AggregateID)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(outbox.Payload), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload["toUserId"] != validUserID || payload["amount"] != float64(1000) {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+	change := assertStatusChange(t, repo, persisted.ID, "", entity.StatusCompleted, entity.ActorAdmin)
+	if change.Reason != "funded by alice" {
+		t.Fatalf("unexpected reason %q", change.Reason)
+	}
+}
+
+func TestFundAccountUseCase_InvalidInput(t *testing.T) {
+	cases := map[string]usecase.FundInput{
+		"user id not a UUID": {UserID: "user-1", Amount: 100, Operator: "alice"},
+		"zero amount":        {UserID: validUserID, Operator: "alice"},
+		"unknown currency":   {UserID: validUserID, Amount: 100, Currency: "XXX", Operator: "alice"},
+		"no operator":        {UserID: validUserID, Amount: 100},
+	}
+	for name, in := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockTransactionRepository{
+				fundFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+					t.Fatal("repository should not be called for invalid input")
+					return nil
+				},
+			}
+			uc := usecase.NewFundAccountUseCase(repo, &mockKnownUserRepository{}, testLogger())
+
+			_, err := uc.Execute(context.Background(), in)
+
+			_ = assertException(t, err, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestFundAccountUseCase_UnknownUser(t *testing.T) {
+	users := &mockKnownUserRepository{
+		findMissingFn: func(_ context.Context, ids []string) ([]string, error) {
+			return ids, nil
+		},
+	}
+	uc := usecase.NewFundAccountUseCase(&mockTransactionRepository{}, users, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.FundInput{UserID: validUserID, Amount: 100, Operator: "alice"})
+
+	_ = assertException(t, err, http.StatusUnprocessableEntity)
+}
+
+func TestFundAccountUseCase_PersistError(t *testing.T) {
+	repo := &mockTransactionRepository{
+		fundFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+			return errors.New("db error")
+		},
+	}
+	uc := usecase.NewFundAccountUseCase(repo, &mockKnownUserRepository{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.FundInput{UserID: validUserID, Amount: 100, Operator: "alice"})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
@@ -3,7 +3,9 @@ package usecase
 import (
 	"context"
 	"log/slog"
+	"time"
 
+	"transaction-service/internal/core/domain/entity"
 	"transaction-service/internal/core/domain/ports"
 	apperrors "transaction-service/internal/core/errors"
 )
@@ -11,11 +13,23 @@ import (
 type (
 	BalanceInput struct {
 		UserID string
+		// Currency defaults to entity.DefaultCurrency.
+		Currency string
+		// AsOf, when set, asks for the balance at that time instead of now.
+		AsOf time.Time
 	}
 
+	// BalanceOutput reports the ledger balance in Currency, i.e. settled
+	// postings, as Balance and what the user can spend now, net of open holds
+	// and pending outgoing transfers, as Available. A point-in-time read
+	// reports the cutoff it used, to the microsecond the ledger keeps, as
+	// AsOf and leaves out Available, which is only known for the present.
 	BalanceOutput struct {
-		UserID  string
-		Balance int64
+		UserID    string
+		Currency  string
+		Balance   int64
+		Available *int64     `json:",omitempty"`
+		AsOf      *time.Time `json:",omitempty"`
 	}
 
 	GetBalanceUseCase struct {
@@ -36,16 +50,63 @@ func (uc *GetBalanceUseCase) Execute(ctx context.Context, input BalanceInput) (*
 		return nil, apperrors.BadRequest(apperrors.WithMessage("user_id is required"))
 	}
 
-	balance, err := uc.repo.GetBalance(ctx, input.UserID)
+	currency := input.Currency
+	if currency == "" {
+		currency = entity.DefaultCurrency
+	}
+	if _, err := entity.MinorUnits(currency); err != nil {
+		uc.logger.WarnContext(ctx, "get balance validation failed", slog.String("reason", err.Error()))
+		return nil, apperrors.BadRequest(apperrors.WithMessage(err.Error()))
+	}
+
+	if !input.AsOf.IsZero() {
+		return uc.balanceAsOf(ctx, input.UserID, currency, input.AsOf)
+	}
+
+	balance, err := uc.repo.GetBalance(ctx, input.UserID, currency)
 	if err != nil {
 		uc.logger.ErrorContext(ctx, "get balance failed", slog.String("error", err.Error()))
 		return nil, apperrors.Unexpected(apperrors.WithError(err))
 	}
 
+	available, err := uc.repo.GetAvailableBalance(ctx, input.UserID, currency)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "get available balance failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
 	uc.logger.InfoContext(ctx, "balance retrieved")
 
 	return &BalanceOutput{
-		UserID:  input.UserID,
-		Balance: balance,
+		UserID:    input.UserID,
+		Currency:  currency,
+		Balance:   balance,
+		Available: &available,
+	}, nil
+}
+
+// balanceAsOf sums the postings of the transactions processed at or before
+// asOf, truncated to the ledger's microsecond precision so the cutoff
+// reported is the one compared against.
+func (uc *GetBalanceUseCase) balanceAsOf(ctx context.Context, userID, currency string, asOf time.Time) (*BalanceOutput, error) {
+	cutoff := asOf.UTC().Truncate(time.Microsecond)
+	if cutoff.After(time.Now()) {
+		uc.logger.WarnContext(ctx, "get balance validation failed", slog.String("reason", "as_of is in the future"))
+		return nil, apperrors.BadRequest(apperrors.WithMessage("as_of must not be in the future"))
+	}
+
+	balance, err := uc.repo.GetBalanceAsOf(ctx, userID, currency, cutoff)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "get balance as of failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	uc.logger.InfoContext(ctx, "balance retrieved", slog.Time("as_of", cutoff))
+
+	return &BalanceOutput{
+		UserID:   userID,
+		Currency: currency,
+		Balance:  balance,
+		AsOf:     &cutoff,
 	}, nil
 }
@@ -5,15 +5,22 @@ import (
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"transaction-service/internal/core/usecase"
 )
 
 func TestGetBalanceUseCase_Success(t *testing.T) {
 	repo := &mockTransactionRepository{
-		getBalanceFn: func(_ context.Context, _ string) (int64, error) {
+		getBalanceFn: func(_ context.Context, _, currency string) (int64, error) {
+			if currency != "USD" {
+				t.Fatalf("expected the default currency USD, got %s", currency)
+			}
 			return 1500, nil
 		},
+		getAvailableBalanceFn: func(_ context.Context, _, _ string) (int64, error) {
+			return 900, nil
+		},
 	}
 	uc := usecase.NewGetBalanceUseCase(repo, testLogger())
 
@@ -28,6 +35,105 @@ func TestGetBalanceUseCase_Success(t *testing.T) {
 	if out.Balance != 1500 {
 		t.Fatalf("expected balance 1500, got %d", out.Balance)
 	}
+	if out.Available == nil || *out.Available != 900 {
+		t.Fatalf("expected available balance 900, got %v", out.Available)
+	}
+	if out.AsOf != nil {
+		t.Fatalf("expected no cutoff on a current balance, got %v", out.AsOf)
+	}
+	if out.Currency != "USD" {
+		t.Fatalf("expected currency USD, got %s", out.Currency)
+	}
+}
+
+func TestGetBalanceUseCase_ReadsRequestedCurrency(t *testing.T) {
+	var balanceCurrency, availableCurrency string
+	repo := &mockTransactionRepository{
+		getBalanceFn: func(_ context.Context, _, currency string) (int64, error) {
+			balanceCurrency = currency
+			return 0, nil
+		},
+		getAvailableBalanceFn: func(_ context.Context, _, currency string) (int64, error) {
+			availableCurrency = currency
+			return 0, nil
+		},
+	}
+	uc := usecase.NewGetBalanceUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.BalanceInput{UserID: "user-1", Currency: "JPY"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Currency != "JPY" || balanceCurrency != "JPY" || availableCurrency != "JPY" {
+		t.Fatalf("expected JPY balances, got %s (read %s and %s)", out.Currency, balanceCurrency, availableCurrency)
+	}
+}
+
+func TestGetBalanceUseCase_AsOfReportsCutoff(t *testing.T) {
+	asOf := time.Date(2026, 9, 30, 23, 59, 59, 999_999_999, time.FixedZone("CEST", 2*60*60))
+	var cutoff time.Time
+	repo := &mockTransactionRepository{
+		getBalanceAsOfFn: func(_ context.Context, _, _ string, at time.Time) (int64, error) {
+			cutoff = at
+			return 700, nil
+		},
+		getBalanceFn: func(_ context.Context, _, _ string) (int64, error) {
+			t.Fatal("expected the current balance not to be read")
+			return 0, nil
+		},
+	}
+	uc := usecase.NewGetBalanceUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.BalanceInput{UserID: "user-1", AsOf: asOf})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	want := time.Date(2026, 9, 30, 21, 59, 59, 999_999_000, time.UTC)
+	if !cutoff.Equal(want) || cutoff.Location() != time.UTC {
+		t.Fatalf("expected the cutoff truncated to microseconds in UTC, got %v", cutoff)
+	}
+	if out.Balance != 700 || out.AsOf == nil || !out.AsOf.Equal(want) {
+		t.Fatalf("expected the balance at the reported cutoff, got %+v", out)
+	}
+	if out.Available != nil {
+		t.Fatalf("expected no available balance for a past cutoff, got %d", *out.Available)
+	}
+}
+
+func TestGetBalanceUseCase_AsOfErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		asOf time.Time
+		err  error
+		code int
+	}{
+		{"future cutoff", time.Now().Add(time.Hour), nil, http.StatusBadRequest},
+		{"repository error", time.Now().Add(-time.Hour), errors.New("db error"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTransactionRepository{
+				getBalanceAsOfFn: func(_ context.Context, _, _ string, _ time.Time) (int64, error) {
+					return 0, tc.err
+				},
+			}
+			uc := usecase.NewGetBalanceUseCase(repo, testLogger())
+
+			_, err := uc.Execute(context.Background(), usecase.BalanceInput{UserID: "user-1", AsOf: tc.asOf})
+
+			_ = assertException(t, err, tc.code)
+		})
+	}
+}
+
+func TestGetBalanceUseCase_UnsupportedCurrency(t *testing.T) {
+	uc := usecase.NewGetBalanceUseCase(&mockTransactionRepository{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.BalanceInput{UserID: "user-1", Currency: "usd"})
+
+	_ = assertException(t, err, http.StatusBadRequest)
 }
 
 func TestGetBalanceUseCase_EmptyUserID(t *testing.T) {
@@ -41,7 +147,20 @@ func TestGetBalanceUseCase_EmptyUserID(t *testing.T) {
 
 func TestGetBalanceUseCase_RepositoryError(t *testing.T) {
 	repo := &mockTransactionRepository{
-		getBalanceFn: func(_ context.Context, _ string) (int64, error) {
+		getBalanceFn: func(_ context.Context, _, _ string) (int64, error) {
+			return 0, errors.New("db error")
+		},
+	}
+	uc := usecase.NewGetBalanceUseCase(repo, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.BalanceInput{UserID: "user-1"})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
+
+func TestGetBalanceUseCase_AvailableBalanceError(t *testing.T) {
+	repo := &mockTransactionRepository{
+		getAvailableBalanceFn: func(_ context.Context, _, _ string) (int64, error) {
 			return 0, errors.New("db error")
 		},
 	}
@@ -54,15 +173,66 @@ func TestGetBalanceUseCase_RepositoryError(t *testing.T) {
 
 func TestNewFactory_WiresAllUseCases(t *testing.T) {
 	repo := &mockTransactionRepository{}
-	f := usecase.NewFactory(repo, testLogger())
+	f := usecase.NewFactory(repo, &mockMandateRepository{}, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
 
 	if f.Create == nil {
 		t.Fatal("expected Create use case to be non-nil")
 	}
+	if f.Batch == nil {
+		t.Fatal("expected Batch use case to be non-nil")
+	}
 	if f.Status == nil {
 		t.Fatal("expected Status use case to be non-nil")
 	}
 	if f.Balance == nil {
 		t.Fatal("expected Balance use case to be non-nil")
 	}
+	if f.Settle == nil {
+		t.Fatal("expected Settle use case to be non-nil")
+	}
+	if f.Sync == nil {
+		t.Fatal("expected Sync use case to be non-nil")
+	}
+	if f.List == nil {
+		t.Fatal("expected List use case to be non-nil")
+	}
+	if f.Search == nil {
+		t.Fatal("expected Search use case to be non-nil")
+	}
+	if f.Reverse == nil {
+		t.Fatal("expected Reverse use case to be non-nil")
+	}
+	if f.Capture == nil {
+		t.Fatal("expected Capture use case to be non-nil")
+	}
+	if f.Void == nil {
+		t.Fatal("expected Void use case to be non-nil")
+	}
+	if f.Expire == nil {
+		t.Fatal("expected Expire use case to be non-nil")
+	}
+	if f.History == nil {
+		t.Fatal("expected History use case to be non-nil")
+	}
+	if f.ReleaseKeys == nil {
+		t.Fatal("expected ReleaseKeys use case to be non-nil")
+	}
+	if f.Cancel == nil {
+		t.Fatal("expected Cancel use case to be non-nil")
+	}
+	if f.Scheduled == nil {
+		t.Fatal("expected Scheduled use case to be non-nil")
+	}
+	if f.Review == nil {
+		t.Fatal("expected Review use case to be non-nil")
+	}
+	if f.Statement == nil {
+		t.Fatal("expected Statement use case to be non-nil")
+	}
+	if f.CreateMandate == nil || f.GetMandate == nil || f.ListMandates == nil {
+		t.Fatal("expected mandate read and create use cases to be non-nil")
+	}
+	if f.UpdateMandate == nil || f.CancelMandate == nil || f.RunMandates == nil {
+		t.Fatal("expected mandate update, cancel and run use cases to be non-nil")
+	}
 }
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	MandateItem struct {
+		ID          string     `json:"id"`
+		FromUserID  string     `json:"from_user_id"`
+		ToUserID    string     `json:"to_user_id"`
+		Amount      int64      `json:"amount"`
+		Currency    string     `json:"currency"`
+		Description string     `json:"description"`
+		Schedule    string     `json:"schedule"`
+		StartAt     time.Time  `json:"start_at"`
+		EndAt       *time.Time `json:"end_at,omitempty"`
+		NextRunAt   time.Time  `json:"next_run_at"`
+		Occurrences int64      `json:"occurrences"`
+		Status      string     `json:"status"`
+		CreatedAt   time.Time  `json:"created_at"`
+		UpdatedAt   time.Time  `json:"updated_at"`
+	}
+
+	GetMandateUseCase struct {
+		mandates ports.MandateRepository
+		logger   *slog.Logger
+	}
+)
+
+func NewGetMandateUseCase(mandates ports.MandateRepository, logger *slog.Logger) *GetMandateUseCase {
+	return &GetMandateUseCase{mandates: mandates, logger: logger}
+}
+
+func (uc *GetMandateUseCase) Execute(ctx context.Context, id string) (*MandateItem, error) {
+	uc.logger.InfoContext(ctx, "get mandate request", slog.String("mandate_id", id))
+
+	m, err := findMandate(ctx, uc.mandates, uc.logger, id)
+	if err != nil {
+		return nil, err
+	}
+	item := newMandateItem(m)
+	return &item, nil
+}
+
+func findMandate(ctx context.Context, mandates ports.MandateRepository, logger *slog.Logger, id string) (*entity.Mandate, error) {
+	if id == "" {
+		logger.WarnContext(ctx, "mandate validation failed", slog.String("reason", "mandate_id is required"))
+		return nil, apperrors.BadRequest(apperrors.WithMessage("mandate_id is required"))
+	}
+
+	m, err := mandates.FindByID(ctx, id)
+	if err != nil {
+		logger.ErrorContext(ctx, "find mandate failed",
+			slog.String("mandate_id", id),
+			slog.String("error", err.Error()),
+		)
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if m == nil {
+		logger.WarnContext(ctx, "mandate not found", slog.String("mandate_id", id))
+		return nil, apperrors.NotFound(apperrors.WithMessage("mandate not found"))
+	}
+	return m, nil
+}
+
+func newMandateItem(m *entity.Mandate) MandateItem {
+	return MandateItem{
+		ID:          m.ID,
+		FromUserID:  m.FromUserID,
+		ToUserID:    m.ToUserID,
+		Amount:      m.Amount,
+		Currency:    m.Currency,
+		Description: m.Description,
+		Schedule:    m.Schedule,
+		StartAt:     m.StartAt,
+		EndAt:       m.EndAt,
+		NextRunAt:   m.NextRunAt,
+		Occurrences: m.Occurrence,
+		Status:      string(m.Status),
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+// newMandateOutbox builds a mandate event from its current state; extra
+// carries what only some events have, such as the transaction of a run.
+func newMandateOutbox(m *entity.Mandate, eventType string, extra map[string]any) (*entity.Outbox, error) {
+	fields := map[string]any{
+		"mandateId":   m.ID,
+		"fromUserId":  m.FromUserID,
+		"toUserId":    m.ToUserID,
+		"amount":      m.Amount,
+		"currency":    m.Currency,
+		"description": m.Description,
+		"schedule":    m.Schedule,
+		"startAt":     m.StartAt,
+		"endAt":       m.EndAt,
+		"nextRunAt":   m.NextRunAt,
+		"status":      m.Status,
+	}
+	for k, v := range extra {
+		fields[k] = v
+	}
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	return entity.NewOutbox(eventType, m.ID, string(payload)), nil
+}
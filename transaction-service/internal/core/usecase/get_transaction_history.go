@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	StatusChangeItem struct {
+		FromStatus string    `json:"from_status,omitempty"`
+		ToStatus   string    `json:"to_status"`
+		Actor      string    `json:"actor"`
+		Reason     string    `json:"reason,omitempty"`
+		ChangedAt  time.Time `json:"changed_at"`
+	}
+
+	HistoryOutput struct {
+		TransactionID string             `json:"transaction_id"`
+		Changes       []StatusChangeItem `json:"changes"`
+	}
+
+	GetTransactionHistoryUseCase struct {
+		repo   ports.TransactionRepository
+		logger *slog.Logger
+	}
+)
+
+func NewGetTransactionHistoryUseCase(repo ports.TransactionRepository, logger *slog.Logger) *GetTransactionHistoryUseCase {
+	return &GetTransactionHistoryUseCase{repo: repo, logger: logger}
+}
+
+// Execute returns every status the transaction went through, oldest first.
+func (uc *GetTransactionHistoryUseCase) Execute(ctx context.Context, id string) (*HistoryOutput, error) {
+	uc.logger.InfoContext(ctx, "get transaction history request", slog.String("transaction_id", id))
+
+	tx, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "find transaction failed",
+			slog.String("transaction_id", id),
+			slog.String("error", err.Error()),
+		)
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if tx == nil {
+		uc.logger.WarnContext(ctx, "transaction not found", slog.String("transaction_id", id))
+		return nil, apperrors.NotFound(apperrors.WithMessage("transaction not found"))
+	}
+
+	changes, err := uc.repo.FindStatusHistory(ctx, tx.ID)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "find status history failed",
+			slog.String("transaction_id", tx.ID),
+			slog.String("error", err.Error()),
+		)
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	out := &HistoryOutput{TransactionID: tx.ID, Changes: make([]StatusChangeItem, 0, len(changes))}
+	for _, c := range changes {
+		out.Changes = append(out.Changes, StatusChangeItem{
+			FromStatus: string(c.FromStatus),
+			ToStatus:   string(c.ToStatus),
+			Actor:      c.Actor,
+			Reason:     c.Reason,
+			ChangedAt:  c.ChangedAt,
+		})
+	}
+
+	uc.logger.InfoContext(ctx, "transaction history found",
+		slog.String("transaction_id", tx.ID),
+		slog.Int("count", len(out.Changes)),
+	)
+
+	return out, nil
+}
@@ -0,0 +1,97 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+// assertStatusChange checks that repo was given exactly one status change,
+// for transactionID from one status to another by actor, and returns it.
+func assertStatusChange(t *testing.T, repo *mockTransactionRepository, transactionID string, from, to entity.TransactionStatus, actor string) *entity.StatusChange {
+	t.Helper()
+	if len(repo.changes) != 1 {
+		t.Fatalf("expected 1 status change, got %d", len(repo.changes))
+	}
+	c := repo.changes[0]
+	if c.TransactionID != transactionID || c.FromStatus != from || c.ToStatus != to || c.Actor != actor {
+		t.Fatalf("expected %s %q -> %q by %s, got %+v", transactionID, from, to, actor, c)
+	}
+	return c
+}
+
+func TestGetTransactionHistoryUseCase_Success(t *testing.T) {
+	created := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return pendingTransaction(), nil
+		},
+		findStatusHistoryFn: func(_ context.Context, id string) ([]*entity.StatusChange, error) {
+			if id != "tx-1" {
+				t.Fatalf("expected history of tx-1, got %s", id)
+			}
+			return []*entity.StatusChange{
+				{TransactionID: id, ToStatus: entity.StatusPending, Actor: entity.ActorAPI, ChangedAt: created},
+				{TransactionID: id, FromStatus: entity.StatusPending, ToStatus: entity.StatusFailed,
+					Actor: entity.ActorSettlement, Reason: "insufficient funds", ChangedAt: created.Add(time.Second)},
+			}, nil
+		},
+	}
+	uc := usecase.NewGetTransactionHistoryUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), "tx-1")
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.TransactionID != "tx-1" || len(out.Changes) != 2 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	first, second := out.Changes[0], out.Changes[1]
+	if first.FromStatus != "" || first.ToStatus != "PENDING" || !first.ChangedAt.Equal(created) {
+		t.Fatalf("unexpected first change: %+v", first)
+	}
+	if second.FromStatus != "PENDING" || second.ToStatus != "FAILED" || second.Actor != "settlement" || second.Reason != "insufficient funds" {
+		t.Fatalf("unexpected second change: %+v", second)
+	}
+}
+
+func TestGetTransactionHistoryUseCase_NotFound(t *testing.T) {
+	uc := usecase.NewGetTransactionHistoryUseCase(&mockTransactionRepository{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), "tx-unknown")
+
+	_ = assertException(t, err, http.StatusNotFound)
+}
+
+func TestGetTransactionHistoryUseCase_RepositoryErrors(t *testing.T) {
+	cases := map[string]*mockTransactionRepository{
+		"find transaction": {
+			findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+				return nil, errors.New("db error")
+			},
+		},
+		"find history": {
+			findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+				return pendingTransaction(), nil
+			},
+			findStatusHistoryFn: func(_ context.Context, _ string) ([]*entity.StatusChange, error) {
+				return nil, errors.New("db error")
+			},
+		},
+	}
+	for name, repo := range cases {
+		t.Run(name, func(t *testing.T) {
+			uc := usecase.NewGetTransactionHistoryUseCase(repo, testLogger())
+
+			_, err := uc.Execute(context.Background(), "tx-1")
+
+			_ = assertException(t, err, http.StatusInternalServerError)
+		})
+	}
+}
@@ -3,15 +3,27 @@ package usecase
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"transaction-service/internal/core/domain/ports"
 	apperrors "transaction-service/internal/core/errors"
 )
 
 type (
+	// StatusOutput is the full transaction. ReversalOf is set on reversals,
+	// AuthorizedAmount and ExpiresAt on holds, and Risk on transfers the
+	// risk rules assessed.
 	StatusOutput struct {
-		ID     string
-		Status string
+		TransactionItem
+		ReversalOf       *string    `json:"reversal_of,omitempty"`
+		AuthorizedAmount *int64     `json:"authorized_amount,omitempty"`
+		ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+		Risk             *RiskItem  `json:"risk,omitempty"`
+	}
+
+	RiskItem struct {
+		Decision string   `json:"decision"`
+		Reasons  []string `json:"reasons,omitempty"`
 	}
 
 	GetTransactionStatusUseCase struct {
@@ -45,8 +57,14 @@ func (uc *GetTransactionStatusUseCase) Execute(ctx context.Context, id string) (
 		slog.String("status", string(tx.Status)),
 	)
 
-	return &StatusOutput{
-		ID:     tx.ID,
-		Status: string(tx.Status),
-	}, nil
+	out := &StatusOutput{
+		TransactionItem:  newTransactionItem(tx),
+		ReversalOf:       tx.ReversalOf,
+		AuthorizedAmount: tx.AuthorizedAmount,
+		ExpiresAt:        tx.ExpiresAt,
+	}
+	if tx.Risk != nil {
+		out.Risk = &RiskItem{Decision: string(tx.Risk.Decision), Reasons: tx.Risk.Reasons}
+	}
+	return out, nil
 }
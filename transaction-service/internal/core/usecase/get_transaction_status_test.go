@@ -34,6 +34,50 @@ func TestGetTransactionStatusUseCase_Success(t *testing.T) {
 	}
 }
 
+func TestGetTransactionStatusUseCase_ReturnsFullTransaction(t *testing.T) {
+	hold := holdFixture()
+	repo := &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return hold, nil
+		},
+	}
+	uc := usecase.NewGetTransactionStatusUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), hold.ID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.FromUserID != hold.FromUserID || out.ToUserID != hold.ToUserID || out.Amount != hold.Amount || out.Currency != hold.Currency {
+		t.Fatalf("expected the transfer details, got %+v", out)
+	}
+	if !out.CreatedAt.Equal(hold.CreatedAt) || out.ExpiresAt != hold.ExpiresAt || out.AuthorizedAmount != hold.AuthorizedAmount {
+		t.Fatalf("expected the hold timestamps and amount, got %+v", out)
+	}
+}
+
+func TestGetTransactionStatusUseCase_ReturnsRiskDecision(t *testing.T) {
+	repo := &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, id string) (*entity.Transaction, error) {
+			return &entity.Transaction{
+				ID:     id,
+				Status: entity.StatusHeld,
+				Risk:   &entity.RiskAssessment{Decision: entity.RiskReview, Reasons: []string{"new recipient"}},
+			}, nil
+		},
+	}
+	uc := usecase.NewGetTransactionStatusUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), "tx-1")
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Risk == nil || out.Risk.Decision != "REVIEW" || len(out.Risk.Reasons) != 1 {
+		t.Fatalf("expected the risk decision, got %+v", out.Risk)
+	}
+}
+
 func TestGetTransactionStatusUseCase_NotFound(t *testing.T) {
 	repo := &mockTransactionRepository{
 		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
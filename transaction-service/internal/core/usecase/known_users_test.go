@@ -0,0 +1,223 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+type mockKnownUserRepository struct {
+	saveFn        func(ctx context.Context, user *entity.KnownUser) (bool, error)
+	findMissingFn func(ctx context.Context, userIDs []string) ([]string, error)
+}
+
+func (m *mockKnownUserRepository) Save(ctx context.Context, user *entity.KnownUser) (bool, error) {
+	if m.saveFn != nil {
+		return m.saveFn(ctx, user)
+	}
+	return true, nil
+}
+
+func (m *mockKnownUserRepository) FindMissing(ctx context.Context, userIDs []string) ([]string, error) {
+	if m.findMissingFn != nil {
+		return m.findMissingFn(ctx, userIDs)
+	}
+	return nil, nil
+}
+
+type mockUserEventSource struct {
+	pages [][]*entity.KnownUser
+	err   error
+	calls int
+}
+
+func (m *mockUserEventSource) ListUserCreated(_ context.Context, _ time.Time, _ string, _ int) ([]*entity.KnownUser, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.calls >= len(m.pages) {
+		return nil, nil
+	}
+	page := m.pages[m.calls]
+	m.calls++
+	return page, nil
+}
+
+const validUserID = "7f9c2c1e-4a57-4c4b-9d1a-0d3c5e0b8a11"
+
+func TestCreateTransactionUseCase_UnknownUser(t *testing.T) {
+	called := false
+	repo := &mockTransactionRepository{
+		createFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+			called = true
+			return nil
+		},
+	}
+	users := &mockKnownUserRepository{
+		findMissingFn: func(_ context.Context, _ []string) ([]string, error) {
+			return []string{"user-2"}, nil
+		},
+	}
+	uc := usecase.NewCreateTransactionUseCase(repo, users, &mockRiskEvaluator{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.CreateInput{
+		FromUserID: "user-1",
+		ToUserID:   "user-2",
+		Amount:     100,
+	})
+
+	exc := assertException(t, err, http.StatusUnprocessableEntity)
+	if exc.Message != "unknown user: user-2" {
+		t.Fatalf("unexpected message: %q", exc.Message)
+	}
+	if called {
+		t.Fatal("repository should not be called for unknown users")
+	}
+}
+
+func TestCreateTransactionUseCase_KnownUsersError(t *testing.T) {
+	users := &mockKnownUserRepository{
+		findMissingFn: func(_ context.Context, _ []string) ([]string, error) {
+			return nil, errors.New("db error")
+		},
+	}
+	uc := usecase.NewCreateTransactionUseCase(&mockTransactionRepository{}, users, &mockRiskEvaluator{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.CreateInput{
+		FromUserID: "user-1",
+		ToUserID:   "user-2",
+		Amount:     100,
+	})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
+
+func TestSyncKnownUserUseCase_Success(t *testing.T) {
+	var saved *entity.KnownUser
+	users := &mockKnownUserRepository{
+		saveFn: func(_ context.Context, user *entity.KnownUser) (bool, error) {
+			saved = user
+			return true, nil
+		},
+	}
+	uc := usecase.NewSyncKnownUserUseCase(users, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.SyncKnownUserInput{
+		UserID:  validUserID,
+		EventID: "evt-1",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !out.Created {
+		t.Fatal("expected Created to be true")
+	}
+	if saved == nil || saved.UserID != validUserID || saved.SourceEventID != "evt-1" {
+		t.Fatalf("unexpected saved user: %+v", saved)
+	}
+	if saved.RegisteredAt.IsZero() {
+		t.Fatal("expected RegisteredAt to default to now")
+	}
+}
+
+func TestSyncKnownUserUseCase_AlreadyKnown(t *testing.T) {
+	users := &mockKnownUserRepository{
+		saveFn: func(_ context.Context, _ *entity.KnownUser) (bool, error) {
+			return false, nil
+		},
+	}
+	uc := usecase.NewSyncKnownUserUseCase(users, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.SyncKnownUserInput{UserID: validUserID})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Created {
+		t.Fatal("expected Created to be false for a duplicate event")
+	}
+}
+
+func TestSyncKnownUserUseCase_InvalidUserID(t *testing.T) {
+	uc := usecase.NewSyncKnownUserUseCase(&mockKnownUserRepository{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.SyncKnownUserInput{UserID: "not-a-uuid"})
+
+	_ = assertException(t, err, http.StatusBadRequest)
+}
+
+func TestSyncKnownUserUseCase_RepositoryError(t *testing.T) {
+	users := &mockKnownUserRepository{
+		saveFn: func(_ context.Context, _ *entity.KnownUser) (bool, error) {
+			return false, errors.New("db error")
+		},
+	}
+	uc := usecase.NewSyncKnownUserUseCase(users, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.SyncKnownUserInput{UserID: validUserID})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
+
+func TestBackfillKnownUsersUseCase_WalksAllPages(t *testing.T) {
+	source := &mockUserEventSource{pages: [][]*entity.KnownUser{
+		{
+			{UserID: validUserID, SourceEventID: "evt-1"},
+			{UserID: "8a1d3c7e-1b2c-4d5e-8f90-1a2b3c4d5e6f", SourceEventID: "evt-2"},
+		},
+		{
+			{UserID: "not-a-uuid", SourceEventID: "evt-3"},
+		},
+	}}
+	seen := 0
+	users := &mockKnownUserRepository{
+		saveFn: func(_ context.Context, _ *entity.KnownUser) (bool, error) {
+			seen++
+			return seen == 1, nil
+		},
+	}
+	uc := usecase.NewBackfillKnownUsersUseCase(source, users, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.BackfillInput{BatchSize: 2})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Scanned != 3 || out.Inserted != 1 || out.Skipped != 1 {
+		t.Fatalf("unexpected result: %+v", out)
+	}
+	if source.calls != 2 {
+		t.Fatalf("expected 2 page reads, got %d", source.calls)
+	}
+}
+
+func TestBackfillKnownUsersUseCase_SourceError(t *testing.T) {
+	source := &mockUserEventSource{err: errors.New("db error")}
+	uc := usecase.NewBackfillKnownUsersUseCase(source, &mockKnownUserRepository{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.BackfillInput{})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
+
+func TestBackfillKnownUsersUseCase_SaveError(t *testing.T) {
+	source := &mockUserEventSource{pages: [][]*entity.KnownUser{
+		{{UserID: validUserID, SourceEventID: "evt-1"}},
+	}}
+	users := &mockKnownUserRepository{
+		saveFn: func(_ context.Context, _ *entity.KnownUser) (bool, error) {
+			return false, errors.New("db error")
+		},
+	}
+	uc := usecase.NewBackfillKnownUsersUseCase(source, users, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.BackfillInput{})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
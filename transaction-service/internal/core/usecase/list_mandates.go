@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type ListMandatesUseCase struct {
+	mandates ports.MandateRepository
+	logger   *slog.Logger
+}
+
+func NewListMandatesUseCase(mandates ports.MandateRepository, logger *slog.Logger) *ListMandatesUseCase {
+	return &ListMandatesUseCase{mandates: mandates, logger: logger}
+}
+
+// Execute returns every mandate the user pays, newest first, including
+// cancelled and ended ones.
+func (uc *ListMandatesUseCase) Execute(ctx context.Context, userID string) ([]MandateItem, error) {
+	uc.logger.InfoContext(ctx, "list mandates request", slog.String("user_id", userID))
+
+	if userID == "" {
+		uc.logger.WarnContext(ctx, "list mandates validation failed", slog.String("reason", "user_id is required"))
+		return nil, apperrors.BadRequest(apperrors.WithMessage("user_id is required"))
+	}
+
+	mandates, err := uc.mandates.ListByUser(ctx, userID)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "list mandates failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	items := make([]MandateItem, 0, len(mandates))
+	for _, m := range mandates {
+		items = append(items, newMandateItem(m))
+	}
+	return items, nil
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+type (
+	ListInput struct {
+		UserID    string
+		Direction string
+		Status    string
+		MinAmount *int64
+		MaxAmount *int64
+		From      time.Time
+		To        time.Time
+		Cursor    string
+		Limit     int
+		// Metadata keeps the transactions carrying every one of its
+		// key/value pairs.
+		Metadata map[string]string
+	}
+
+	TransactionItem struct {
+		ID          string             `json:"id"`
+		FromUserID  string             `json:"from_user_id"`
+		ToUserID    string             `json:"to_user_id"`
+		Amount      int64              `json:"amount"`
+		Currency    string             `json:"currency"`
+		Conversion  *entity.Conversion `json:"conversion,omitempty"`
+		Description string             `json:"description"`
+		Status      string             `json:"status"`
+		CreatedAt   time.Time          `json:"created_at"`
+		ProcessedAt *time.Time         `json:"processed_at,omitempty"`
+		ExecuteAt   *time.Time         `json:"execute_at,omitempty"`
+		Metadata    map[string]string  `json:"metadata,omitempty"`
+	}
+
+	ListOutput struct {
+		Items      []TransactionItem `json:"items"`
+		NextCursor string            `json:"next_cursor,omitempty"`
+	}
+
+	ListTransactionsUseCase struct {
+		repo   ports.TransactionRepository
+		logger *slog.Logger
+	}
+)
+
+func NewListTransactionsUseCase(repo ports.TransactionRepository, logger *slog.Logger) *ListTransactionsUseCase {
+	return &ListTransactionsUseCase{repo: repo, logger: logger}
+}
+
+// Execute returns one page of the user's transactions, newest first.
+// NextCursor is empty on the last page.
+func (uc *ListTransactionsUseCase) Execute(ctx context.Context, input ListInput) (*ListOutput, error) {
+	uc.logger.InfoContext(ctx, "list transactions request", slog.String("user_id", input.UserID))
+
+	q, limit, err := buildTransactionQuery(input)
+	if err != nil {
+		uc.logger.WarnContext(ctx, "list transactions validation failed", slog.String("reason", err.Error()))
+		return nil, apperrors.BadRequest(apperrors.WithMessage(err.Error()))
+	}
+
+	// One extra row tells whether another page exists.
+	txs, err := uc.repo.ListByUser(ctx, q, limit+1)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "list transactions failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	out := &ListOutput{Items: make([]TransactionItem, 0, min(len(txs), limit))}
+	if len(txs) > limit {
+		txs = txs[:limit]
+		last := txs[limit-1]
+		out.NextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+	for _, tx := range txs {
+		out.Items = append(out.Items, newTransactionItem(tx))
+	}
+
+	uc.logger.InfoContext(ctx, "transactions listed",
+		slog.String("user_id", input.UserID),
+		slog.Int("count", len(out.Items)),
+	)
+
+	return out, nil
+}
+
+func newTransactionItem(tx *entity.Transaction) TransactionItem {
+	return TransactionItem{
+		ID:          tx.ID,
+		FromUserID:  tx.FromUserID,
+		ToUserID:    tx.ToUserID,
+		Amount:      tx.Amount,
+		Currency:    tx.Currency,
+		Conversion:  tx.Conversion,
+		Description: tx.Description,
+		Status:      string(tx.Status),
+		CreatedAt:   tx.CreatedAt,
+		ProcessedAt: tx.ProcessedAt,
+		ExecuteAt:   tx.ExecuteAt,
+		Metadata:    tx.Metadata,
+	}
+}
+
+func buildTransactionQuery(input ListInput) (ports.TransactionQuery, int, error) {
+	q := ports.TransactionQuery{
+		UserID:    input.UserID,
+		MinAmount: input.MinAmount,
+		MaxAmount: input.MaxAmount,
+		From:      input.From,
+		To:        input.To,
+	}
+
+	if input.UserID == "" {
+		return q, 0, errors.New("user_id is required")
+	}
+	userID, err := canonicalUUID(input.UserID)
+	if err != nil {
+		return q, 0, errors.New("user_id must be a UUID")
+	}
+	q.UserID = userID
+
+	switch d := ports.Direction(input.Direction); d {
+	case ports.DirectionAny, ports.DirectionSent, ports.DirectionReceived:
+		q.Direction = d
+	default:
+		return q, 0, fmt.Errorf("direction must be %q or %q", ports.DirectionSent, ports.DirectionReceived)
+	}
+
+	switch s := entity.TransactionStatus(strings.ToUpper(input.Status)); s {
+	case "", entity.StatusPending, entity.StatusProcessing, entity.StatusCompleted, entity.StatusFailed,
+		entity.StatusAuthorized, entity.StatusVoided, entity.StatusExpired,
+		entity.StatusScheduled, entity.StatusCancelled, entity.StatusHeld, entity.StatusRejected:
+		q.Status = s
+	default:
+		return q, 0, fmt.Errorf("unknown status %q", input.Status)
+	}
+
+	if err := entity.ValidateMetadata(input.Metadata); err != nil {
+		return q, 0, err
+	}
+	if len(input.Metadata) > 0 {
+		q.Metadata = input.Metadata
+	}
+
+	if input.MinAmount != nil && input.MaxAmount != nil && *input.MinAmount > *input.MaxAmount {
+		return q, 0, errors.New("min_amount must not exceed max_amount")
+	}
+	if !input.From.IsZero() && !input.To.IsZero() && !input.From.Before(input.To) {
+		return q, 0, errors.New("from must be before to")
+	}
+
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	if limit < 1 || limit > maxListLimit {
+		return q, 0, fmt.Errorf("limit must be between 1 and %d", maxListLimit)
+	}
+
+	if input.Cursor != "" {
+		createdAt, id, err := decodeCursor(input.Cursor)
+		if err != nil {
+			return q, 0, err
+		}
+		q.BeforeCreatedAt, q.BeforeID = createdAt, id
+	}
+
+	return q, limit, nil
+}
+
+// The cursor is opaque to clients: base64url("<created_at RFC3339Nano>|<id>").
+func encodeCursor(createdAt time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt.UTC().Format(time.RFC3339Nano) + "|" + id))
+}
+
+func decodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", errors.New("invalid cursor")
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return time.Time{}, "", errors.New("invalid cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, "", errors.New("invalid cursor")
+	}
+	if id, err = canonicalUUID(id); err != nil {
+		return time.Time{}, "", errors.New("invalid cursor")
+	}
+	return createdAt, id, nil
+}
+
+// canonicalUUID rejects anything Postgres would refuse to cast to uuid, so
+// bad input is a 400 rather than a failed query, and returns the
+// lower-case hyphenated form.
+func canonicalUUID(s string) (string, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
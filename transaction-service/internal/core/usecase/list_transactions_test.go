@@ -0,0 +1,193 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	"transaction-service/internal/core/usecase"
+)
+
+func listFixture(n int) []*entity.Transaction {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	txs := make([]*entity.Transaction, n)
+	for i := range txs {
+		txs[i] = &entity.Transaction{
+			ID:         fmt.Sprintf("00000000-0000-4000-8000-%012d", i+1),
+			FromUserID: validUserID,
+			ToUserID:   "user-2",
+			Amount:     int64(100 * (i + 1)),
+			Status:     entity.StatusCompleted,
+			CreatedAt:  base.Add(-time.Duration(i) * time.Minute),
+		}
+	}
+	return txs
+}
+
+func TestListTransactionsUseCase_DefaultsAndMapping(t *testing.T) {
+	var captured ports.TransactionQuery
+	var capturedLimit int
+	repo := &mockTransactionRepository{
+		listByUserFn: func(_ context.Context, q ports.TransactionQuery, limit int) ([]*entity.Transaction, error) {
+			captured, capturedLimit = q, limit
+			return listFixture(2), nil
+		},
+	}
+	uc := usecase.NewListTransactionsUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ListInput{UserID: validUserID})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if capturedLimit != 21 {
+		t.Fatalf("expected default page size 20 plus one look-ahead row, got %d", capturedLimit)
+	}
+	if captured.UserID != validUserID || captured.Direction != ports.DirectionAny || !captured.BeforeCreatedAt.IsZero() {
+		t.Fatalf("unexpected query: %+v", captured)
+	}
+	if len(out.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(out.Items))
+	}
+	if out.Items[1].ID != "00000000-0000-4000-8000-000000000002" || out.Items[1].Amount != 200 || out.Items[1].Status != "COMPLETED" {
+		t.Fatalf("unexpected item: %+v", out.Items[1])
+	}
+	if out.NextCursor != "" {
+		t.Fatalf("expected no next cursor on the last page, got %q", out.NextCursor)
+	}
+}
+
+func TestListTransactionsUseCase_PassesFilters(t *testing.T) {
+	var captured ports.TransactionQuery
+	repo := &mockTransactionRepository{
+		listByUserFn: func(_ context.Context, q ports.TransactionQuery, _ int) ([]*entity.Transaction, error) {
+			captured = q
+			return nil, nil
+		},
+	}
+	uc := usecase.NewListTransactionsUseCase(repo, testLogger())
+	minAmount, maxAmount := int64(10), int64(500)
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	out, err := uc.Execute(context.Background(), usecase.ListInput{
+		UserID:    validUserID,
+		Direction: "received",
+		Status:    "completed",
+		MinAmount: &minAmount,
+		MaxAmount: &maxAmount,
+		From:      from,
+		To:        to,
+		Metadata:  map[string]string{"order_id": "ord-42"},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Items == nil || len(out.Items) != 0 {
+		t.Fatalf("expected an empty, non-nil page, got %#v", out.Items)
+	}
+	if captured.Direction != ports.DirectionReceived || captured.Status != entity.StatusCompleted {
+		t.Fatalf("unexpected direction/status: %+v", captured)
+	}
+	if *captured.MinAmount != 10 || *captured.MaxAmount != 500 || !captured.From.Equal(from) || !captured.To.Equal(to) {
+		t.Fatalf("unexpected ranges: %+v", captured)
+	}
+	if len(captured.Metadata) != 1 || captured.Metadata["order_id"] != "ord-42" {
+		t.Fatalf("expected the metadata filter, got %v", captured.Metadata)
+	}
+}
+
+func TestListTransactionsUseCase_CursorRoundTrip(t *testing.T) {
+	fixture := listFixture(3)
+	var second ports.TransactionQuery
+	calls := 0
+	repo := &mockTransactionRepository{
+		listByUserFn: func(_ context.Context, q ports.TransactionQuery, limit int) ([]*entity.Transaction, error) {
+			calls++
+			if calls == 1 {
+				return fixture[:limit], nil
+			}
+			second = q
+			return fixture[2:], nil
+		},
+	}
+	uc := usecase.NewListTransactionsUseCase(repo, testLogger())
+
+	first, err := uc.Execute(context.Background(), usecase.ListInput{UserID: validUserID, Limit: 2})
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+	if len(first.Items) != 2 || first.NextCursor == "" {
+		t.Fatalf("expected a full page with a cursor, got %d items, cursor %q", len(first.Items), first.NextCursor)
+	}
+
+	page, err := uc.Execute(context.Background(), usecase.ListInput{UserID: validUserID, Limit: 2, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if second.BeforeID != fixture[1].ID || !second.BeforeCreatedAt.Equal(fixture[1].CreatedAt) {
+		t.Fatalf("expected cursor at %s, got %s at %v", fixture[1].ID, second.BeforeID, second.BeforeCreatedAt)
+	}
+	if len(page.Items) != 1 || page.NextCursor != "" {
+		t.Fatalf("expected a final page of 1, got %d items, cursor %q", len(page.Items), page.NextCursor)
+	}
+}
+
+func TestListTransactionsUseCase_ValidationErrors(t *testing.T) {
+	minAmount, maxAmount := int64(500), int64(10)
+	from := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	cases := map[string]usecase.ListInput{
+		"missing user":       {},
+		"user not a uuid":    {UserID: "user-1"},
+		"bad direction":      {UserID: validUserID, Direction: "sideways"},
+		"bad status":         {UserID: validUserID, Status: "LOST"},
+		"inverted amounts":   {UserID: validUserID, MinAmount: &minAmount, MaxAmount: &maxAmount},
+		"inverted dates":     {UserID: validUserID, From: from, To: from},
+		"empty metadata key": {UserID: validUserID, Metadata: map[string]string{"": "ord-42"}},
+		"limit too large":    {UserID: validUserID, Limit: 101},
+		"negative limit":     {UserID: validUserID, Limit: -1},
+		"cursor not base64":  {UserID: validUserID, Cursor: "%%%"},
+		"cursor without id":  {UserID: validUserID, Cursor: base64.RawURLEncoding.EncodeToString([]byte("2026-01-01T00:00:00Z"))},
+		"cursor bad instant": {UserID: validUserID, Cursor: base64.RawURLEncoding.EncodeToString([]byte("yesterday|" + validUserID))},
+		"cursor id not uuid": {UserID: validUserID, Cursor: base64.RawURLEncoding.EncodeToString([]byte("2026-01-01T00:00:00Z|tx-1"))},
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			called := false
+			repo := &mockTransactionRepository{
+				listByUserFn: func(_ context.Context, _ ports.TransactionQuery, _ int) ([]*entity.Transaction, error) {
+					called = true
+					return nil, nil
+				},
+			}
+			uc := usecase.NewListTransactionsUseCase(repo, testLogger())
+
+			_, err := uc.Execute(context.Background(), input)
+
+			_ = assertException(t, err, http.StatusBadRequest)
+			if called {
+				t.Fatal("repository must not be called on invalid input")
+			}
+		})
+	}
+}
+
+func TestListTransactionsUseCase_RepositoryError(t *testing.T) {
+	repo := &mockTransactionRepository{
+		listByUserFn: func(_ context.Context, _ ports.TransactionQuery, _ int) ([]*entity.Transaction, error) {
+			return nil, errors.New("db error")
+		},
+	}
+	uc := usecase.NewListTransactionsUseCase(repo, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.ListInput{UserID: validUserID})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
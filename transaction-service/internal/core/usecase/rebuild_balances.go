@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	RebuildBalancesOutput struct {
+		Corrected int64
+	}
+
+	RebuildBalancesUseCase struct {
+		ledger ports.LedgerRepository
+		logger *slog.Logger
+	}
+)
+
+func NewRebuildBalancesUseCase(ledger ports.LedgerRepository, logger *slog.Logger) *RebuildBalancesUseCase {
+	return &RebuildBalancesUseCase{ledger: ledger, logger: logger}
+}
+
+// Execute recomputes the balances projection from the ledger postings. It is
+// safe to run while the service is live.
+func (uc *RebuildBalancesUseCase) Execute(ctx context.Context) (*RebuildBalancesOutput, error) {
+	uc.logger.InfoContext(ctx, "rebuild balances started")
+
+	corrected, err := uc.ledger.RebuildBalances(ctx)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "rebuild balances failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	uc.logger.InfoContext(ctx, "rebuild balances finished", slog.Int64("corrected", corrected))
+
+	return &RebuildBalancesOutput{Corrected: corrected}, nil
+}
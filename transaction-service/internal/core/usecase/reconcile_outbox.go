@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+// Default thresholds after which an outbox event counts as stuck, and the
+// default number of discrepancies of each kind a report lists.
+const (
+	DefaultStuckPendingAfter    = 5 * time.Minute
+	DefaultStuckProcessingAfter = 15 * time.Minute
+	DefaultStuckFailedAfter     = time.Hour
+	DefaultReconcileSampleSize  = 100
+)
+
+type (
+	// ReconcileInput sets how long an event may sit in each status. Zero
+	// values take the defaults above.
+	ReconcileInput struct {
+		PendingAfter    time.Duration
+		ProcessingAfter time.Duration
+		FailedAfter     time.Duration
+		SampleSize      int
+	}
+
+	ReconcileOutboxUseCase struct {
+		repo   ports.ReconciliationRepository
+		logger *slog.Logger
+	}
+)
+
+func NewReconcileOutboxUseCase(repo ports.ReconciliationRepository, logger *slog.Logger) *ReconcileOutboxUseCase {
+	return &ReconcileOutboxUseCase{repo: repo, logger: logger}
+}
+
+// Execute checks that every transaction has an outbox event, that every
+// event has its aggregate and that no event sat in PENDING, PROCESSING or
+// FAILED past its threshold. Like the ledger check, it only reports; the
+// caller decides whether discrepancies are fatal.
+func (uc *ReconcileOutboxUseCase) Execute(ctx context.Context, input ReconcileInput) (*entity.ReconciliationReport, error) {
+	input = input.withDefaults()
+	if input.PendingAfter < 0 || input.ProcessingAfter < 0 || input.FailedAfter < 0 || input.SampleSize < 0 {
+		return nil, apperrors.BadRequest(apperrors.WithMessage("thresholds and sample size must not be negative"))
+	}
+
+	uc.logger.InfoContext(ctx, "outbox reconciliation started",
+		slog.Duration("pending_after", input.PendingAfter),
+		slog.Duration("processing_after", input.ProcessingAfter),
+		slog.Duration("failed_after", input.FailedAfter),
+	)
+
+	now := time.Now().UTC()
+	checks := []struct {
+		kind string
+		find func() (entity.DiscrepancySample, error)
+	}{
+		{entity.DiscrepancyMissingEvent, func() (entity.DiscrepancySample, error) {
+			return uc.repo.FindMissingEvents(ctx, input.SampleSize)
+		}},
+		{entity.DiscrepancyOrphanEvent, func() (entity.DiscrepancySample, error) {
+			return uc.repo.FindOrphanEvents(ctx, input.SampleSize)
+		}},
+		{entity.DiscrepancyStuckPending, func() (entity.DiscrepancySample, error) {
+			return uc.repo.FindStuckEvents(ctx, entity.OutboxStatusPending, now.Add(-input.PendingAfter), input.SampleSize)
+		}},
+		{entity.DiscrepancyStuckProcessing, func() (entity.DiscrepancySample, error) {
+			return uc.repo.FindStuckEvents(ctx, entity.OutboxStatusProcessing, now.Add(-input.ProcessingAfter), input.SampleSize)
+		}},
+		{entity.DiscrepancyStuckFailed, func() (entity.DiscrepancySample, error) {
+			return uc.repo.FindStuckEvents(ctx, entity.OutboxStatusFailed, now.Add(-input.FailedAfter), input.SampleSize)
+		}},
+	}
+
+	report := &entity.ReconciliationReport{
+		CheckedAt:     now,
+		Counts:        make(map[string]int64, len(checks)),
+		Discrepancies: []entity.Discrepancy{},
+	}
+	for _, check := range checks {
+		sample, err := check.find()
+		if err != nil {
+			uc.logger.ErrorContext(ctx, "outbox reconciliation failed",
+				slog.String("kind", check.kind),
+				slog.String("error", err.Error()),
+			)
+			return nil, apperrors.Unexpected(apperrors.WithError(err))
+		}
+		report.Counts[check.kind] = sample.Count
+		report.Discrepancies = append(report.Discrepancies, sample.Items...)
+		if sample.Count > 0 {
+			uc.logger.WarnContext(ctx, "outbox discrepancies found",
+				slog.String("kind", check.kind),
+				slog.Int64("count", sample.Count),
+			)
+		}
+	}
+
+	uc.logger.InfoContext(ctx, "outbox reconciliation finished", slog.Int64("discrepancies", report.Total()))
+
+	return report, nil
+}
+
+func (in ReconcileInput) withDefaults() ReconcileInput {
+	if in.PendingAfter == 0 {
+		in.PendingAfter = DefaultStuckPendingAfter
+	}
+	if in.ProcessingAfter == 0 {
+		in.ProcessingAfter = DefaultStuckProcessingAfter
+	}
+	if in.FailedAfter == 0 {
+		in.FailedAfter = DefaultStuckFailedAfter
+	}
+	if in.SampleSize == 0 {
+		in.SampleSize = DefaultReconcileSampleSize
+	}
+	return in
+}
@@ -0,0 +1,131 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+type mockReconciliationRepository struct {
+	findMissingEventsFn func(ctx context.Context, limit int) (entity.DiscrepancySample, error)
+	findOrphanEventsFn  func(ctx context.Context, limit int) (entity.DiscrepancySample, error)
+	findStuckEventsFn   func(ctx context.Context, status entity.OutboxStatus, before time.Time, limit int) (entity.DiscrepancySample, error)
+}
+
+func (m *mockReconciliationRepository) FindMissingEvents(ctx context.Context, limit int) (entity.DiscrepancySample, error) {
+	if m.findMissingEventsFn != nil {
+		return m.findMissingEventsFn(ctx, limit)
+	}
+	return entity.DiscrepancySample{}, nil
+}
+
+func (m *mockReconciliationRepository) FindOrphanEvents(ctx context.Context, limit int) (entity.DiscrepancySample, error) {
+	if m.findOrphanEventsFn != nil {
+		return m.findOrphanEventsFn(ctx, limit)
+	}
+	return entity.DiscrepancySample{}, nil
+}
+
+func (m *mockReconciliationRepository) FindStuckEvents(ctx context.Context, status entity.OutboxStatus, before time.Time, limit int) (entity.DiscrepancySample, error) {
+	if m.findStuckEventsFn != nil {
+		return m.findStuckEventsFn(ctx, status, before, limit)
+	}
+	return entity.DiscrepancySample{}, nil
+}
+
+func TestReconcileOutboxUseCase_CleanReportCountsEveryKind(t *testing.T) {
+	uc := usecase.NewReconcileOutboxUseCase(&mockReconciliationRepository{}, testLogger())
+
+	report, err := uc.Execute(context.Background(), usecase.ReconcileInput{})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(report.Counts) != 5 || report.Total() != 0 || len(report.Discrepancies) != 0 {
+		t.Fatalf("expected five zero counts and no discrepancies, got %+v", report)
+	}
+	if report.Discrepancies == nil {
+		t.Fatal("expected an empty list, not null, in the report")
+	}
+}
+
+func TestReconcileOutboxUseCase_ReportsDiscrepancies(t *testing.T) {
+	cutoffs := map[entity.OutboxStatus]time.Time{}
+	var limits []int
+	repo := &mockReconciliationRepository{
+		findMissingEventsFn: func(_ context.Context, limit int) (entity.DiscrepancySample, error) {
+			limits = append(limits, limit)
+			return entity.DiscrepancySample{Count: 3, Items: []entity.Discrepancy{
+				{Kind: entity.DiscrepancyMissingEvent, AggregateID: "tx-1"},
+				{Kind: entity.DiscrepancyMissingEvent, AggregateID: "tx-2"},
+			}}, nil
+		},
+		findOrphanEventsFn: func(_ context.Context, limit int) (entity.DiscrepancySample, error) {
+			limits = append(limits, limit)
+			return entity.DiscrepancySample{}, nil
+		},
+		findStuckEventsFn: func(_ context.Context, status entity.OutboxStatus, before time.Time, limit int) (entity.DiscrepancySample, error) {
+			limits = append(limits, limit)
+			cutoffs[status] = before
+			if status != entity.OutboxStatusFailed {
+				return entity.DiscrepancySample{}, nil
+			}
+			return entity.DiscrepancySample{Count: 1, Items: []entity.Discrepancy{{Kind: entity.DiscrepancyStuckFailed, EventID: "ev-1"}}}, nil
+		},
+	}
+	uc := usecase.NewReconcileOutboxUseCase(repo, testLogger())
+
+	report, err := uc.Execute(context.Background(), usecase.ReconcileInput{PendingAfter: time.Minute, SampleSize: 2})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.Counts[entity.DiscrepancyMissingEvent] != 3 || report.Counts[entity.DiscrepancyStuckFailed] != 1 || report.Total() != 4 {
+		t.Fatalf("unexpected counts: %v", report.Counts)
+	}
+	if len(report.Discrepancies) != 3 {
+		t.Fatalf("expected the sampled discrepancies, got %+v", report.Discrepancies)
+	}
+	for _, limit := range limits {
+		if limit != 2 {
+			t.Fatalf("expected every check sampled by 2, got %v", limits)
+		}
+	}
+	thresholds := map[entity.OutboxStatus]time.Duration{
+		entity.OutboxStatusPending:    time.Minute,
+		entity.OutboxStatusProcessing: usecase.DefaultStuckProcessingAfter,
+		entity.OutboxStatusFailed:     usecase.DefaultStuckFailedAfter,
+	}
+	for status, after := range thresholds {
+		if age := report.CheckedAt.Sub(cutoffs[status]); age != after {
+			t.Fatalf("expected %s events older than %v, got %v", status, after, age)
+		}
+	}
+}
+
+func TestReconcileOutboxUseCase_Errors(t *testing.T) {
+	t.Run("negative threshold", func(t *testing.T) {
+		uc := usecase.NewReconcileOutboxUseCase(&mockReconciliationRepository{}, testLogger())
+
+		_, err := uc.Execute(context.Background(), usecase.ReconcileInput{FailedAfter: -time.Second})
+
+		_ = assertException(t, err, http.StatusBadRequest)
+	})
+	t.Run("repository error", func(t *testing.T) {
+		repo := &mockReconciliationRepository{
+			findOrphanEventsFn: func(context.Context, int) (entity.DiscrepancySample, error) {
+				return entity.DiscrepancySample{}, errors.New("db error")
+			},
+		}
+		uc := usecase.NewReconcileOutboxUseCase(repo, testLogger())
+
+		_, err := uc.Execute(context.Background(), usecase.ReconcileInput{})
+
+		_ = assertException(t, err, http.StatusInternalServerError)
+	})
+}
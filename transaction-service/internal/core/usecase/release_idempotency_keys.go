@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+const (
+	// DefaultIdempotencyKeyRetention is how long a sender's idempotency key
+	// keeps replaying its transaction before the key may be reused.
+	DefaultIdempotencyKeyRetention = 24 * time.Hour
+
+	defaultReleaseKeysBatchSize = 1000
+)
+
+type (
+	ReleaseIdempotencyKeysInput struct {
+		Retention time.Duration
+		BatchSize int
+	}
+
+	ReleaseIdempotencyKeysOutput struct {
+		Released int64
+	}
+
+	ReleaseIdempotencyKeysUseCase struct {
+		repo   ports.TransactionRepository
+		logger *slog.Logger
+	}
+)
+
+func NewReleaseIdempotencyKeysUseCase(repo ports.TransactionRepository, logger *slog.Logger) *ReleaseIdempotencyKeysUseCase {
+	return &ReleaseIdempotencyKeysUseCase{repo: repo, logger: logger}
+}
+
+// Execute clears the idempotency keys of transactions older than the
+// retention period, DefaultIdempotencyKeyRetention when zero. It works in
+// batches so one run never holds many row locks at once.
+func (uc *ReleaseIdempotencyKeysUseCase) Execute(ctx context.Context, input ReleaseIdempotencyKeysInput) (*ReleaseIdempotencyKeysOutput, error) {
+	retention := input.Retention
+	if retention <= 0 {
+		retention = DefaultIdempotencyKeyRetention
+	}
+	batchSize := input.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultReleaseKeysBatchSize
+	}
+
+	before := time.Now().UTC().Add(-retention)
+	out := &ReleaseIdempotencyKeysOutput{}
+	for {
+		released, err := uc.repo.ReleaseIdempotencyKeys(ctx, before, batchSize)
+		if err != nil {
+			uc.logger.ErrorContext(ctx, "release idempotency keys failed", slog.String("error", err.Error()))
+			return nil, apperrors.Unexpected(apperrors.WithError(err))
+		}
+		out.Released += released
+		if released < int64(batchSize) {
+			break
+		}
+	}
+
+	if out.Released > 0 {
+		uc.logger.InfoContext(ctx, "idempotency keys released",
+			slog.Int64("released", out.Released),
+			slog.Time("before", before),
+		)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,72 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/usecase"
+)
+
+func TestReleaseIdempotencyKeysUseCase_BatchesUntilShortBatch(t *testing.T) {
+	batches := []int64{2, 2, 1}
+	var cutoffs []time.Time
+	repo := &mockTransactionRepository{
+		releaseIdempotencyKeysFn: func(_ context.Context, before time.Time, limit int) (int64, error) {
+			if limit != 2 {
+				t.Fatalf("expected limit 2, got %d", limit)
+			}
+			cutoffs = append(cutoffs, before)
+			released := batches[0]
+			batches = batches[1:]
+			return released, nil
+		},
+	}
+	uc := usecase.NewReleaseIdempotencyKeysUseCase(repo, testLogger())
+
+	start := time.Now().UTC()
+	out, err := uc.Execute(context.Background(), usecase.ReleaseIdempotencyKeysInput{Retention: time.Hour, BatchSize: 2})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Released != 5 || len(cutoffs) != 3 {
+		t.Fatalf("expected 5 keys released in 3 batches, got %+v in %d", out, len(cutoffs))
+	}
+	if cutoff := cutoffs[0]; cutoff.After(start.Add(-time.Hour).Add(time.Second)) || cutoff.Before(start.Add(-time.Hour).Add(-time.Second)) {
+		t.Fatalf("expected a cutoff one hour ago, got %v", cutoff)
+	}
+}
+
+func TestReleaseIdempotencyKeysUseCase_DefaultRetention(t *testing.T) {
+	var cutoff time.Time
+	repo := &mockTransactionRepository{
+		releaseIdempotencyKeysFn: func(_ context.Context, before time.Time, _ int) (int64, error) {
+			cutoff = before
+			return 0, nil
+		},
+	}
+	uc := usecase.NewReleaseIdempotencyKeysUseCase(repo, testLogger())
+
+	if _, err := uc.Execute(context.Background(), usecase.ReleaseIdempotencyKeysInput{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if age := time.Since(cutoff); age < usecase.DefaultIdempotencyKeyRetention || age > usecase.DefaultIdempotencyKeyRetention+time.Minute {
+		t.Fatalf("expected a cutoff %s ago, got %s", usecase.DefaultIdempotencyKeyRetention, age)
+	}
+}
+
+func TestReleaseIdempotencyKeysUseCase_RepositoryError(t *testing.T) {
+	repo := &mockTransactionRepository{
+		releaseIdempotencyKeysFn: func(_ context.Context, _ time.Time, _ int) (int64, error) {
+			return 0, errors.New("db error")
+		},
+	}
+	uc := usecase.NewReleaseIdempotencyKeysUseCase(repo, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.ReleaseIdempotencyKeysInput{})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+const defaultReleaseBatchSize = 100
+
+type (
+	ReleaseScheduledInput struct {
+		BatchSize int
+	}
+
+	ReleaseScheduledOutput struct {
+		Released int
+		Failed   int
+		Skipped  int
+	}
+
+	ReleaseScheduledUseCase struct {
+		repo   ports.TransactionRepository
+		logger *slog.Logger
+	}
+)
+
+func NewReleaseScheduledUseCase(repo ports.TransactionRepository, logger *slog.Logger) *ReleaseScheduledUseCase {
+	return &ReleaseScheduledUseCase{repo: repo, logger: logger}
+}
+
+// Execute releases every SCHEDULED transfer that is due. Funded ones become
+// PENDING and emit the TransactionCreated event settlement waits for; those
+// the sender cannot pay fail with TransactionFailed. Transfers cancelled
+// while the run goes on are skipped.
+func (uc *ReleaseScheduledUseCase) Execute(ctx context.Context, input ReleaseScheduledInput) (*ReleaseScheduledOutput, error) {
+	batchSize := input.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultReleaseBatchSize
+	}
+
+	out := &ReleaseScheduledOutput{}
+	for {
+		now := time.Now().UTC()
+		due, err := uc.repo.FindDueScheduled(ctx, now, batchSize)
+		if err != nil {
+			uc.logger.ErrorContext(ctx, "find due scheduled transactions failed", slog.String("error", err.Error()))
+			return nil, apperrors.Unexpected(apperrors.WithError(err))
+		}
+
+		resolvedBefore := out.Released + out.Failed
+		for _, tx := range due {
+			if err := uc.release(ctx, tx, now, out); err != nil {
+				return nil, err
+			}
+		}
+
+		// A page that resolved nothing cannot shrink the backlog; leave the
+		// rest to the next run instead of spinning.
+		if len(due) < batchSize || out.Released+out.Failed == resolvedBefore {
+			break
+		}
+	}
+
+	if out.Released > 0 || out.Failed > 0 || out.Skipped > 0 {
+		uc.logger.InfoContext(ctx, "release scheduled transactions finished",
+			slog.Int("released", out.Released),
+			slog.Int("failed", out.Failed),
+			slog.Int("skipped", out.Skipped),
+		)
+	}
+
+	return out, nil
+}
+
+// release tries tx as PENDING first and only fails it when the repository
+// finds the sender short of funds.
+func (uc *ReleaseScheduledUseCase) release(ctx context.Context, tx *entity.Transaction, now time.Time, out *ReleaseScheduledOutput) error {
+	released := *tx
+	if err := released.Release(now); err != nil {
+		out.Skipped++
+		return nil
+	}
+	outbox, err := newCreatedOutbox(&released, entity.EventTransactionCreated)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "marshal outbox payload failed", slog.String("error", err.Error()))
+		return apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	err = resolveScheduled(ctx, uc.repo, &released, outbox, entity.ActorScheduler, "", now)
+	switch {
+	case err == nil:
+		out.Released++
+		uc.logger.InfoContext(ctx, "scheduled transaction released", slog.String("transaction_id", tx.ID))
+		return nil
+	case errors.Is(err, entity.ErrInvalidStatusTransition):
+		out.Skipped++
+		return nil
+	case !errors.Is(err, entity.ErrInsufficientFunds):
+		uc.logger.ErrorContext(ctx, "persist release failed",
+			slog.String("transaction_id", tx.ID),
+			slog.String("error", err.Error()),
+		)
+		return apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	reason := entity.ErrInsufficientFunds.Error()
+	if err := tx.FailScheduled(now); err != nil {
+		out.Skipped++
+		return nil
+	}
+	outbox, err = newScheduledOutbox(tx, entity.EventTransactionFailed, reason)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "marshal outbox payload failed", slog.String("error", err.Error()))
+		return apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if err := resolveScheduled(ctx, uc.repo, tx, outbox, entity.ActorScheduler, reason, now); err != nil {
+		if errors.Is(err, entity.ErrInvalidStatusTransition) {
+			out.Skipped++
+			return nil
+		}
+		uc.logger.ErrorContext(ctx, "persist release failure failed",
+			slog.String("transaction_id", tx.ID),
+			slog.String("error", err.Error()),
+		)
+		return apperrors.Unexpected(apperrors.WithError(err))
+	}
+	out.Failed++
+	uc.logger.WarnContext(ctx, "scheduled transaction failed — insufficient funds",
+		slog.String("transaction_id", tx.ID),
+		slog.String("from_user_id", tx.FromUserID),
+		slog.Int64("amount", tx.Amount),
+	)
+	return nil
+}
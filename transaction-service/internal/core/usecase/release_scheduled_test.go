@@ -0,0 +1,185 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+func dueScheduled(id string) *entity.Transaction {
+	return scheduledFixture(id, time.Now().UTC().Add(-time.Minute))
+}
+
+func TestReleaseScheduledUseCase_PagesUntilShortPage(t *testing.T) {
+	pages := [][]*entity.Transaction{
+		{dueScheduled("tx-1"), dueScheduled("tx-2")},
+		{dueScheduled("tx-3")},
+	}
+	var released []string
+	repo := &mockTransactionRepository{
+		findDueScheduledFn: func(_ context.Context, _ time.Time, limit int) ([]*entity.Transaction, error) {
+			if limit != 2 {
+				t.Fatalf("expected limit 2, got %d", limit)
+			}
+			page := pages[0]
+			pages = pages[1:]
+			return page, nil
+		},
+		resolveScheduledFn: func(_ context.Context, tx *entity.Transaction, o *entity.Outbox) error {
+			if tx.Status != entity.StatusPending || tx.ProcessedAt != nil || o.Type != entity.EventTransactionCreated {
+				t.Fatalf("expected an unprocessed PENDING transfer with %s event, got %s/%s", entity.EventTransactionCreated, tx.Status, o.Type)
+			}
+			released = append(released, tx.ID)
+			return nil
+		},
+	}
+	uc := usecase.NewReleaseScheduledUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ReleaseScheduledInput{BatchSize: 2})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Released != 3 || out.Failed != 0 || out.Skipped != 0 || len(released) != 3 {
+		t.Fatalf("expected 3 transfers released, got %+v (released %v)", out, released)
+	}
+	for _, c := range repo.changes {
+		if c.FromStatus != entity.StatusScheduled || c.ToStatus != entity.StatusPending || c.Actor != entity.ActorScheduler {
+			t.Fatalf("expected releases by the scheduler, got %+v", c)
+		}
+	}
+}
+
+func TestReleaseScheduledUseCase_FailsUnfundedTransfers(t *testing.T) {
+	calls := 0
+	var failed *entity.Transaction
+	repo := &mockTransactionRepository{
+		findDueScheduledFn: func(_ context.Context, _ time.Time, _ int) ([]*entity.Transaction, error) {
+			return []*entity.Transaction{dueScheduled("tx-1")}, nil
+		},
+		resolveScheduledFn: func(_ context.Context, tx *entity.Transaction, o *entity.Outbox) error {
+			calls++
+			if tx.Status == entity.StatusPending {
+				return entity.ErrInsufficientFunds
+			}
+			if o.Type != entity.EventTransactionFailed {
+				t.Fatalf("expected event %s, got %s", entity.EventTransactionFailed, o.Type)
+			}
+			failed = tx
+			return nil
+		},
+	}
+	uc := usecase.NewReleaseScheduledUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ReleaseScheduledInput{BatchSize: 10})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Failed != 1 || out.Released != 0 || calls != 2 {
+		t.Fatalf("expected 1 failed transfer after 2 attempts, got %+v and %d calls", out, calls)
+	}
+	if failed.Status != entity.StatusFailed || failed.ProcessedAt == nil {
+		t.Fatalf("expected a processed FAILED transfer, got %+v", failed)
+	}
+	last := repo.changes[len(repo.changes)-1]
+	if last.ToStatus != entity.StatusFailed || last.Reason != entity.ErrInsufficientFunds.Error() {
+		t.Fatalf("expected a FAILED change for insufficient funds, got %+v", last)
+	}
+}
+
+func TestReleaseScheduledUseCase_SkipsTransfersResolvedConcurrently(t *testing.T) {
+	notYetDue := scheduledFixture("tx-3", time.Now().UTC().Add(time.Hour))
+	calls := 0
+	repo := &mockTransactionRepository{
+		findDueScheduledFn: func(_ context.Context, _ time.Time, _ int) ([]*entity.Transaction, error) {
+			calls++
+			if calls > 1 {
+				return nil, nil
+			}
+			return []*entity.Transaction{dueScheduled("tx-1"), dueScheduled("tx-2"), notYetDue, dueScheduled("tx-4")}, nil
+		},
+		resolveScheduledFn: func(_ context.Context, tx *entity.Transaction, _ *entity.Outbox) error {
+			switch {
+			case tx.ID == "tx-2":
+				return entity.ErrInvalidStatusTransition
+			case tx.ID == "tx-4" && tx.Status == entity.StatusPending:
+				return entity.ErrInsufficientFunds
+			case tx.ID == "tx-4":
+				return entity.ErrInvalidStatusTransition
+			}
+			return nil
+		},
+	}
+	uc := usecase.NewReleaseScheduledUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ReleaseScheduledInput{BatchSize: 4})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Released != 1 || out.Skipped != 3 {
+		t.Fatalf("expected 1 released and 3 skipped, got %+v", out)
+	}
+	if calls != 2 {
+		t.Fatalf("expected a second page after a full one, got %d calls", calls)
+	}
+}
+
+func TestReleaseScheduledUseCase_StopsWhenPageReleasesNothing(t *testing.T) {
+	calls := 0
+	repo := &mockTransactionRepository{
+		findDueScheduledFn: func(_ context.Context, _ time.Time, _ int) ([]*entity.Transaction, error) {
+			calls++
+			return []*entity.Transaction{scheduledFixture("tx-1", time.Now().UTC().Add(time.Hour))}, nil
+		},
+	}
+	uc := usecase.NewReleaseScheduledUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ReleaseScheduledInput{BatchSize: 1})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 1 || out.Skipped != 1 {
+		t.Fatalf("expected a single page with one skip, got %d calls and %+v", calls, out)
+	}
+}
+
+func TestReleaseScheduledUseCase_Errors(t *testing.T) {
+	t.Run("find", func(t *testing.T) {
+		repo := &mockTransactionRepository{
+			findDueScheduledFn: func(_ context.Context, _ time.Time, _ int) ([]*entity.Transaction, error) {
+				return nil, errors.New("db error")
+			},
+		}
+		_, err := usecase.NewReleaseScheduledUseCase(repo, testLogger()).Execute(context.Background(), usecase.ReleaseScheduledInput{})
+		_ = assertException(t, err, http.StatusInternalServerError)
+	})
+
+	for name, failedErr := range map[string]error{"release": nil, "fail": errors.New("db error")} {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockTransactionRepository{
+				findDueScheduledFn: func(_ context.Context, _ time.Time, _ int) ([]*entity.Transaction, error) {
+					return []*entity.Transaction{dueScheduled("tx-1")}, nil
+				},
+				resolveScheduledFn: func(_ context.Context, tx *entity.Transaction, _ *entity.Outbox) error {
+					if tx.Status == entity.StatusFailed {
+						return failedErr
+					}
+					if failedErr == nil {
+						return errors.New("db error")
+					}
+					return entity.ErrInsufficientFunds
+				},
+			}
+			_, err := usecase.NewReleaseScheduledUseCase(repo, testLogger()).Execute(context.Background(), usecase.ReleaseScheduledInput{})
+			_ = assertException(t, err, http.StatusInternalServerError)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+// replayPublishTimeout bounds the wait for the broker to confirm one event.
+const replayPublishTimeout = 5 * time.Second
+
+type (
+	// ReplayInput selects the PROCESSED events to republish. Batch events
+	// are read per page and at most Rate published per second.
+	ReplayInput struct {
+		Query  ports.ReplayQuery
+		Batch  int
+		Rate   int
+		DryRun bool
+	}
+
+	// ReplayOutput counts the matching events, those the broker accepted and
+	// those it returned because nothing was bound to take them.
+	ReplayOutput struct {
+		Matched   int `json:"matched"`
+		Published int `json:"published"`
+		Returned  int `json:"returned"`
+	}
+
+	ReplayEventsUseCase struct {
+		repo   ports.OutboxRepository
+		pub    ports.EventPublisher
+		clock  ports.Clock
+		logger *slog.Logger
+	}
+)
+
+// NewReplayEventsUseCase may be given a nil publisher for dry runs.
+func NewReplayEventsUseCase(repo ports.OutboxRepository, pub ports.EventPublisher, clock ports.Clock, logger *slog.Logger) *ReplayEventsUseCase {
+	return &ReplayEventsUseCase{repo: repo, pub: pub, clock: clock, logger: logger}
+}
+
+// Execute pages through the matching events and republishes them, spacing
+// the publishes so live traffic keeps priority. A returned event does not
+// stop the replay, but the run fails once it is over so the operator sees
+// how many were lost; any other publish error stops it at once.
+func (uc *ReplayEventsUseCase) Execute(ctx context.Context, input ReplayInput) (*ReplayOutput, error) {
+	if input.Query.Type == "" && input.Query.AggregateID == "" {
+		return nil, apperrors.BadRequest(apperrors.WithMessage("replay needs an event type and/or an aggregate ID"))
+	}
+	if input.Batch < 1 || input.Rate < 1 {
+		return nil, apperrors.BadRequest(apperrors.WithMessage("batch and rate must be positive"))
+	}
+	if !input.DryRun && uc.pub == nil {
+		return nil, apperrors.BadRequest(apperrors.WithMessage("replay needs a publisher unless it is a dry run"))
+	}
+
+	out := &ReplayOutput{}
+	q := input.Query
+	interval := time.Second / time.Duration(input.Rate)
+	var next time.Time
+	for {
+		events, err := uc.repo.FetchProcessed(ctx, q, input.Batch)
+		if err != nil {
+			return out, fmt.Errorf("fetch processed events: %w", err)
+		}
+
+		for _, event := range events {
+			out.Matched++
+			if input.DryRun {
+				continue
+			}
+
+			if wait := next.Sub(uc.clock.Now()); wait > 0 {
+				if err := uc.clock.Sleep(ctx, wait); err != nil {
+					return out, err
+				}
+			}
+			next = uc.clock.Now().Add(interval)
+
+			pubCtx, cancel := context.WithTimeout(ctx, replayPublishTimeout)
+			err := uc.pub.Publish(pubCtx, event)
+			cancel()
+			switch {
+			case errors.Is(err, ports.ErrUnroutable):
+				out.Returned++
+				uc.logger.WarnContext(ctx, "replayed event returned unroutable",
+					slog.String("event_id", event.ID),
+					slog.String("type", event.Type),
+				)
+			case err != nil:
+				return out, err
+			default:
+				out.Published++
+			}
+		}
+
+		if len(events) < input.Batch {
+			break
+		}
+		last := events[len(events)-1]
+		q.AfterCreatedAt, q.AfterID = last.CreatedAt, last.ID
+	}
+
+	if out.Returned > 0 {
+		return out, fmt.Errorf("%d of %d replayed events were returned unroutable", out.Returned, out.Matched)
+	}
+	return out, nil
+}
@@ -0,0 +1,252 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	"transaction-service/internal/core/usecase"
+)
+
+// fakeOutboxRepository pages through events the way FetchProcessed does,
+// recording every query.
+type fakeOutboxRepository struct {
+	events  []*entity.Outbox
+	queries []ports.ReplayQuery
+	err     error
+}
+
+func (f *fakeOutboxRepository) FetchPending(context.Context, int) ([]*entity.Outbox, error) {
+	return nil, nil
+}
+func (f *fakeOutboxRepository) MarkProcessed(context.Context, string) error { return nil }
+func (f *fakeOutboxRepository) MarkFailed(context.Context, string) error    { return nil }
+func (f *fakeOutboxRepository) MarkForRetry(context.Context, string) error  { return nil }
+
+func (f *fakeOutboxRepository) FetchProcessed(_ context.Context, q ports.ReplayQuery, limit int) ([]*entity.Outbox, error) {
+	f.queries = append(f.queries, q)
+	if f.err != nil {
+		return nil, f.err
+	}
+	start := 0
+	if q.AfterID != "" {
+		for i, e := range f.events {
+			if e.ID == q.AfterID {
+				start = i + 1
+			}
+		}
+	}
+	return f.events[start:min(start+limit, len(f.events))], nil
+}
+
+// fakePublisher records what it published at which fake time, and fails
+// the events listed in errs.
+type fakePublisher struct {
+	clock     *fakeClock
+	published []string
+	at        []time.Duration
+	errs      map[string]error
+}
+
+func (p *fakePublisher) Publish(_ context.Context, event *entity.Outbox) error {
+	if err := p.errs[event.ID]; err != nil {
+		return err
+	}
+	p.published = append(p.published, event.ID)
+	p.at = append(p.at, p.clock.now.Sub(p.clock.start))
+	return nil
+}
+
+// fakeClock only moves when slept on.
+type fakeClock struct {
+	start, now time.Time
+	sleeps     []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	t := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &fakeClock{start: t, now: t}
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	return nil
+}
+
+func replayFixture(n int) []*entity.Outbox {
+	events := make([]*entity.Outbox, n)
+	for i := range events {
+		events[i] = &entity.Outbox{ID: fmt.Sprintf("evt-%d", i), Type: "TransactionCreated", CreatedAt: time.Date(2026, 1, 1, 0, i, 0, 0, time.UTC)}
+	}
+	return events
+}
+
+func TestReplayEventsUseCase_PagesAndPaces(t *testing.T) {
+	repo := &fakeOutboxRepository{events: replayFixture(5)}
+	clock := newFakeClock()
+	pub := &fakePublisher{clock: clock}
+	uc := usecase.NewReplayEventsUseCase(repo, pub, clock, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ReplayInput{
+		Query: ports.ReplayQuery{Type: "TransactionCreated"},
+		Batch: 2,
+		Rate:  4,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if *out != (usecase.ReplayOutput{Matched: 5, Published: 5}) {
+		t.Fatalf("unexpected output %+v", out)
+	}
+	if !reflect.DeepEqual(pub.published, []string{"evt-0", "evt-1", "evt-2", "evt-3", "evt-4"}) {
+		t.Fatalf("expected every event in order, got %v", pub.published)
+	}
+	step := 250 * time.Millisecond
+	if !reflect.DeepEqual(pub.at, []time.Duration{0, step, 2 * step, 3 * step, 4 * step}) {
+		t.Fatalf("expected one publish every %s, got %v", step, pub.at)
+	}
+
+	// Three pages: two full ones, then a short one that ends the replay.
+	if len(repo.queries) != 3 {
+		t.Fatalf("expected 3 pages, got %d", len(repo.queries))
+	}
+	if q := repo.queries[1]; q.Type != "TransactionCreated" || q.AfterID != "evt-1" || !q.AfterCreatedAt.Equal(repo.events[1].CreatedAt) {
+		t.Fatalf("expected the second page to start after evt-1, got %+v", q)
+	}
+	if repo.queries[2].AfterID != "evt-3" {
+		t.Fatalf("expected the last page to start after evt-3, got %+v", repo.queries[2])
+	}
+}
+
+func TestReplayEventsUseCase_StopsAfterAnEmptyPage(t *testing.T) {
+	repo := &fakeOutboxRepository{events: replayFixture(4)}
+	clock := newFakeClock()
+	uc := usecase.NewReplayEventsUseCase(repo, &fakePublisher{clock: clock}, clock, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ReplayInput{Query: ports.ReplayQuery{Type: "TransactionCreated"}, Batch: 2, Rate: 100})
+
+	if err != nil || out.Published != 4 {
+		t.Fatalf("expected 4 events published, got %+v, %v", out, err)
+	}
+	if len(repo.queries) != 3 || repo.queries[2].AfterID != "evt-3" {
+		t.Fatalf("expected a last, empty page after evt-3, got %+v", repo.queries)
+	}
+}
+
+func TestReplayEventsUseCase_DryRunOnlyCounts(t *testing.T) {
+	repo := &fakeOutboxRepository{events: replayFixture(3)}
+	clock := newFakeClock()
+	uc := usecase.NewReplayEventsUseCase(repo, nil, clock, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ReplayInput{Query: ports.ReplayQuery{AggregateID: "tx-1"}, Batch: 10, Rate: 1, DryRun: true})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if *out != (usecase.ReplayOutput{Matched: 3}) || len(clock.sleeps) != 0 {
+		t.Fatalf("expected 3 matches without waiting, got %+v after %v", out, clock.sleeps)
+	}
+}
+
+func TestReplayEventsUseCase_CountsReturnedEventsAndFails(t *testing.T) {
+	repo := &fakeOutboxRepository{events: replayFixture(3)}
+	clock := newFakeClock()
+	pub := &fakePublisher{clock: clock, errs: map[string]error{
+		"evt-1": fmt.Errorf("replay event evt-1: %w", ports.ErrUnroutable),
+	}}
+	uc := usecase.NewReplayEventsUseCase(repo, pub, clock, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ReplayInput{Query: ports.ReplayQuery{Type: "TransactionCreated"}, Batch: 10, Rate: 10})
+
+	if err == nil || err.Error() != "1 of 3 replayed events were returned unroutable" {
+		t.Fatalf("expected the run to fail on the returned event, got %v", err)
+	}
+	if *out != (usecase.ReplayOutput{Matched: 3, Published: 2, Returned: 1}) {
+		t.Fatalf("expected the replay to go on past the returned event, got %+v", out)
+	}
+}
+
+func TestReplayEventsUseCase_StopsOnPublishError(t *testing.T) {
+	boom := errors.New("channel closed")
+	repo := &fakeOutboxRepository{events: replayFixture(3)}
+	clock := newFakeClock()
+	pub := &fakePublisher{clock: clock, errs: map[string]error{"evt-1": boom}}
+	uc := usecase.NewReplayEventsUseCase(repo, pub, clock, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ReplayInput{Query: ports.ReplayQuery{Type: "TransactionCreated"}, Batch: 10, Rate: 10})
+
+	if !errors.Is(err, boom) || out.Published != 1 {
+		t.Fatalf("expected the replay to stop after evt-0, got %+v, %v", out, err)
+	}
+}
+
+func TestReplayEventsUseCase_FetchError(t *testing.T) {
+	repo := &fakeOutboxRepository{err: errors.New("db down")}
+	uc := usecase.NewReplayEventsUseCase(repo, nil, newFakeClock(), testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.ReplayInput{Query: ports.ReplayQuery{Type: "TransactionCreated"}, Batch: 10, Rate: 10, DryRun: true})
+
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestReplayEventsUseCase_CancelledWhileWaiting(t *testing.T) {
+	repo := &fakeOutboxRepository{events: replayFixture(3)}
+	clock := newFakeClock()
+	ctx, cancel := context.WithCancel(context.Background())
+	pub := &fakePublisher{clock: clock}
+	uc := usecase.NewReplayEventsUseCase(repo, publishThen(pub, cancel), clock, testLogger())
+
+	out, err := uc.Execute(ctx, usecase.ReplayInput{Query: ports.ReplayQuery{Type: "TransactionCreated"}, Batch: 10, Rate: 1})
+
+	if !errors.Is(err, context.Canceled) || out.Published != 1 {
+		t.Fatalf("expected the replay to stop while waiting for its second slot, got %+v, %v", out, err)
+	}
+}
+
+type publishThenFn struct {
+	pub   ports.EventPublisher
+	after func()
+}
+
+func (p publishThenFn) Publish(ctx context.Context, event *entity.Outbox) error {
+	err := p.pub.Publish(ctx, event)
+	p.after()
+	return err
+}
+
+func publishThen(pub ports.EventPublisher, after func()) ports.EventPublisher {
+	return publishThenFn{pub: pub, after: after}
+}
+
+func TestReplayEventsUseCase_InvalidInput(t *testing.T) {
+	cases := map[string]usecase.ReplayInput{
+		"no filter":    {Batch: 10, Rate: 10},
+		"zero batch":   {Query: ports.ReplayQuery{Type: "TransactionCreated"}, Rate: 10},
+		"zero rate":    {Query: ports.ReplayQuery{Type: "TransactionCreated"}, Batch: 10},
+		"no publisher": {Query: ports.ReplayQuery{Type: "TransactionCreated"}, Batch: 10, Rate: 10},
+	}
+	for name, in := range cases {
+		t.Run(name, func(t *testing.T) {
+			uc := usecase.NewReplayEventsUseCase(&fakeOutboxRepository{}, nil, newFakeClock(), testLogger())
+
+			_, err := uc.Execute(context.Background(), in)
+
+			_ = assertException(t, err, http.StatusBadRequest)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	ReverseInput struct {
+		TransactionID string
+		// Amount to give back; zero reverses the full original amount.
+		Amount      int64
+		Description string
+	}
+
+	ReverseOutput struct {
+		ID         string
+		ReversalOf string
+		Amount     int64
+		Status     string
+	}
+
+	ReverseTransactionUseCase struct {
+		repo   ports.TransactionRepository
+		logger *slog.Logger
+	}
+)
+
+func NewReverseTransactionUseCase(repo ports.TransactionRepository, logger *slog.Logger) *ReverseTransactionUseCase {
+	return &ReverseTransactionUseCase{repo: repo, logger: logger}
+}
+
+// Execute creates a compensating transaction for a COMPLETED one and emits a
+// TransactionReversed event. Partial reversals are allowed as long as their
+// total never exceeds the original amount.
+func (uc *ReverseTransactionUseCase) Execute(ctx context.Context, input ReverseInput) (*ReverseOutput, error) {
+	uc.logger.InfoContext(ctx, "reverse transaction request", slog.String("transaction_id", input.TransactionID))
+
+	if input.TransactionID == "" {
+		uc.logger.WarnContext(ctx, "reverse validation failed", slog.String("reason", "transaction_id is required"))
+		return nil, apperrors.BadRequest(apperrors.WithMessage("transaction_id is required"))
+	}
+	if input.Amount < 0 {
+		uc.logger.WarnContext(ctx, "reverse validation failed", slog.String("reason", entity.ErrAmountMustBePositive.Error()))
+		return nil, apperrors.BadRequest(apperrors.WithMessage(entity.ErrAmountMustBePositive.Error()))
+	}
+
+	original, err := uc.repo.FindByID(ctx, input.TransactionID)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "find transaction failed",
+			slog.String("transaction_id", input.TransactionID),
+			slog.String("error", err.Error()),
+		)
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if original == nil {
+		uc.logger.WarnContext(ctx, "transaction not found", slog.String("transaction_id", input.TransactionID))
+		return nil, apperrors.NotFound(apperrors.WithMessage("transaction not found"))
+	}
+
+	amount := input.Amount
+	if amount == 0 {
+		amount = original.Amount
+	}
+
+	reversal, err := entity.NewReversal(original, amount, input.Description)
+	if err != nil {
+		uc.logger.WarnContext(ctx, "reversal rejected",
+			slog.String("transaction_id", original.ID),
+			slog.String("reason", err.Error()),
+		)
+		if exc := reversalRejection(err); exc != nil {
+			return nil, exc
+		}
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"transactionId":         reversal.ID,
+		"reversedTransactionId": original.ID,
+		"fromUserId":            reversal.FromUserID,
+		"toUserId":              reversal.ToUserID,
+		"amount":                reversal.Amount,
+		"currency":              reversal.Currency,
+		"status":                reversal.Status,
+		"processedAt":           reversal.ProcessedAt,
+	})
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "marshal outbox payload failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	outbox := entity.NewOutbox(entity.EventTransactionReversed, original.ID, string(payload))
+	change := entity.NewStatusChange(reversal, "", entity.ActorAPI, "reversal of "+original.ID)
+	if err := uc.repo.Reverse(ctx, reversal, change, outbox); err != nil {
+		if exc := reversalRejection(err); exc != nil {
+			uc.logger.WarnContext(ctx, "reversal rejected",
+				slog.String("transaction_id", original.ID),
+				slog.String("reason", err.Error()),
+			)
+			return nil, exc
+		}
+		uc.logger.ErrorContext(ctx, "persist reversal failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	uc.logger.InfoContext(ctx, "transaction reversed",
+		slog.String("transaction_id", original.ID),
+		slog.String("reversal_id", reversal.ID),
+		slog.Int64("amount", reversal.Amount),
+	)
+
+	return &ReverseOutput{
+		ID:         reversal.ID,
+		ReversalOf: original.ID,
+		Amount:     reversal.Amount,
+		Status:     string(reversal.Status),
+	}, nil
+}
+
+// reversalRejection maps the business rules a reversal can break to an
+// Exception, or returns nil when err is not one of them.
+func reversalRejection(err error) *apperrors.Exception {
+	if errors.Is(err, entity.ErrReversalExceedsOriginal) {
+		return apperrors.Conflict(apperrors.WithMessage(entity.ErrReversalExceedsOriginal.Error()))
+	}
+	for _, rule := range []error{
+		entity.ErrNotReversible, entity.ErrConvertedNotReversible, entity.ErrInsufficientFunds, entity.ErrAmountMustBePositive,
+	} {
+		if errors.Is(err, rule) {
+			return apperrors.UnprocessableEntity(apperrors.WithMessage(rule.Error()))
+		}
+	}
+	return nil
+}
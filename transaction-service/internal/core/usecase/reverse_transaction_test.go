@@ -0,0 +1,186 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+func completedFixture() *entity.Transaction {
+	at := time.Now().UTC()
+	return &entity.Transaction{
+		ID:          "tx-original",
+		Amount:      500,
+		Currency:    "USD",
+		FromUserID:  "user-1",
+		ToUserID:    "user-2",
+		Status:      entity.StatusCompleted,
+		CreatedAt:   at,
+		ProcessedAt: &at,
+	}
+}
+
+func TestReverseTransactionUseCase_FullReversal(t *testing.T) {
+	var (
+		persisted *entity.Transaction
+		outbox    *entity.Outbox
+	)
+	repo := &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return completedFixture(), nil
+		},
+		reverseFn: func(_ context.Context, reversal *entity.Transaction, o *entity.Outbox) error {
+			persisted, outbox = reversal, o
+			return nil
+		},
+	}
+	uc := usecase.NewReverseTransactionUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ReverseInput{TransactionID: "tx-original"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Amount != 500 || out.ReversalOf != "tx-original" || out.Status != "COMPLETED" || out.ID != persisted.ID {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if persisted.FromUserID != "user-2" || persisted.ToUserID != "user-1" {
+		t.Fatalf("expected money to flow back, got %s -> %s", persisted.FromUserID, persisted.ToUserID)
+	}
+	if outbox.Type != entity.EventTransactionReversed || outbox.AggregateID != "tx-original" {
+		t.Fatalf("unexpected outbox event %s for %s", outbox.Type, outbox.AggregateID)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(outbox.Payload), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload["reversedTransactionId"] != "tx-original" || payload["transactionId"] != persisted.ID {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+	change := assertStatusChange(t, repo, persisted.ID, "", entity.StatusCompleted, entity.ActorAPI)
+	if change.Reason != "reversal of tx-original" {
+		t.Fatalf("unexpected reason %q", change.Reason)
+	}
+}
+
+func TestReverseTransactionUseCase_PartialReversal(t *testing.T) {
+	repo := &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return completedFixture(), nil
+		},
+	}
+	uc := usecase.NewReverseTransactionUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ReverseInput{TransactionID: "tx-original", Amount: 120})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Amount != 120 {
+		t.Fatalf("expected amount 120, got %d", out.Amount)
+	}
+}
+
+func TestReverseTransactionUseCase_InputValidation(t *testing.T) {
+	uc := usecase.NewReverseTransactionUseCase(&mockTransactionRepository{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.ReverseInput{})
+	_ = assertException(t, err, http.StatusBadRequest)
+
+	_, err = uc.Execute(context.Background(), usecase.ReverseInput{TransactionID: "tx-original", Amount: -1})
+	_ = assertException(t, err, http.StatusBadRequest)
+}
+
+func TestReverseTransactionUseCase_NotFound(t *testing.T) {
+	uc := usecase.NewReverseTransactionUseCase(&mockTransactionRepository{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.ReverseInput{TransactionID: "missing"})
+
+	_ = assertException(t, err, http.StatusNotFound)
+}
+
+func TestReverseTransactionUseCase_FindError(t *testing.T) {
+	repo := &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return nil, errors.New("db error")
+		},
+	}
+	uc := usecase.NewReverseTransactionUseCase(repo, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.ReverseInput{TransactionID: "tx-original"})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
+
+func TestReverseTransactionUseCase_RejectedByEntity(t *testing.T) {
+	pending := completedFixture()
+	pending.Status = entity.StatusPending
+	converted := completedFixture()
+	converted.Conversion = &entity.Conversion{Currency: "EUR", Rate: "0.9", Amount: 450}
+
+	cases := []struct {
+		name     string
+		original *entity.Transaction
+		amount   int64
+		code     int
+	}{
+		{"not completed", pending, 0, http.StatusUnprocessableEntity},
+		{"between currencies", converted, 0, http.StatusUnprocessableEntity},
+		{"above original", completedFixture(), 501, http.StatusConflict},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			repo := &mockTransactionRepository{
+				findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+					return tc.original, nil
+				},
+				reverseFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+					called = true
+					return nil
+				},
+			}
+			uc := usecase.NewReverseTransactionUseCase(repo, testLogger())
+
+			_, err := uc.Execute(context.Background(), usecase.ReverseInput{TransactionID: "tx-original", Amount: tc.amount})
+
+			_ = assertException(t, err, tc.code)
+			if called {
+				t.Fatal("repository must not be called for a rejected reversal")
+			}
+		})
+	}
+}
+
+func TestReverseTransactionUseCase_RejectedByRepository(t *testing.T) {
+	cases := map[error]int{
+		entity.ErrReversalExceedsOriginal: http.StatusConflict,
+		entity.ErrInsufficientFunds:       http.StatusUnprocessableEntity,
+		entity.ErrNotReversible:           http.StatusUnprocessableEntity,
+		errors.New("db error"):            http.StatusInternalServerError,
+	}
+	for repoErr, code := range cases {
+		t.Run(repoErr.Error(), func(t *testing.T) {
+			repo := &mockTransactionRepository{
+				findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+					return completedFixture(), nil
+				},
+				reverseFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+					return fmt.Errorf("reverse: %w", repoErr)
+				},
+			}
+			uc := usecase.NewReverseTransactionUseCase(repo, testLogger())
+
+			_, err := uc.Execute(context.Background(), usecase.ReverseInput{TransactionID: "tx-original"})
+
+			_ = assertException(t, err, code)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	// ReviewInput approves or rejects a HELD transaction. Reviewer is the
+	// operator deciding it; both they and the optional Reason are recorded
+	// in its status history.
+	ReviewInput struct {
+		ID       string
+		Approve  bool
+		Reviewer string
+		Reason   string
+	}
+
+	ReviewOutput struct {
+		ID     string
+		Status string
+	}
+
+	ReviewHeldUseCase struct {
+		repo   ports.TransactionRepository
+		logger *slog.Logger
+	}
+)
+
+var (
+	errNotHeld          = errors.New("only held transactions can be reviewed")
+	errReviewerRequired = errors.New("reviewer is required")
+)
+
+func NewReviewHeldUseCase(repo ports.TransactionRepository, logger *slog.Logger) *ReviewHeldUseCase {
+	return &ReviewHeldUseCase{repo: repo, logger: logger}
+}
+
+// Execute settles a transaction the risk rules held. Approved, it goes on as
+// it would have without the hold and emits TransactionCreated,
+// TransactionAuthorized or TransactionScheduled; a sender who can no longer
+// pay leaves it HELD. Rejected, it emits TransactionRejected.
+func (uc *ReviewHeldUseCase) Execute(ctx context.Context, input ReviewInput) (*ReviewOutput, error) {
+	uc.logger.InfoContext(ctx, "review held transaction request",
+		slog.String("transaction_id", input.ID),
+		slog.Bool("approve", input.Approve),
+		slog.String("reviewer", input.Reviewer),
+	)
+
+	if input.ID == "" {
+		uc.logger.WarnContext(ctx, "review validation failed", slog.String("reason", "transaction_id is required"))
+		return nil, apperrors.BadRequest(apperrors.WithMessage("transaction_id is required"))
+	}
+	if input.Reviewer == "" {
+		uc.logger.WarnContext(ctx, "review validation failed", slog.String("reason", errReviewerRequired.Error()))
+		return nil, apperrors.BadRequest(apperrors.WithMessage(errReviewerRequired.Error()))
+	}
+
+	tx, err := uc.repo.FindByID(ctx, input.ID)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "find transaction failed",
+			slog.String("transaction_id", input.ID),
+			slog.String("error", err.Error()),
+		)
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if tx == nil {
+		uc.logger.WarnContext(ctx, "transaction not found", slog.String("transaction_id", input.ID))
+		return nil, apperrors.NotFound(apperrors.WithMessage("transaction not found"))
+	}
+
+	now := time.Now().UTC()
+	eventType := entity.EventTransactionRejected
+	if input.Approve {
+		err = tx.Approve()
+		eventType = approvedEventType(tx.Status)
+	} else {
+		err = tx.Reject(now)
+	}
+	if err != nil {
+		uc.logger.WarnContext(ctx, "review rejected",
+			slog.String("transaction_id", tx.ID),
+			slog.String("status", string(tx.Status)),
+		)
+		return nil, apperrors.Conflict(apperrors.WithMessage(errNotHeld.Error()))
+	}
+
+	outbox, err := newCreatedOutbox(tx, eventType)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "marshal outbox payload failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	change := entity.NewStatusChange(tx, entity.StatusHeld, entity.ActorAdmin, reviewReason(input))
+	change.ChangedAt = now
+	if err := uc.repo.ResolveHeld(ctx, tx, change, outbox); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidStatusTransition):
+			uc.logger.WarnContext(ctx, "held transaction reviewed concurrently", slog.String("transaction_id", tx.ID))
+			return nil, apperrors.Conflict(apperrors.WithMessage(errNotHeld.Error()))
+		case errors.Is(err, entity.ErrInsufficientFunds):
+			uc.logger.WarnContext(ctx, "insufficient funds",
+				slog.String("from_user_id", tx.FromUserID),
+				slog.Int64("amount", tx.Amount),
+			)
+			return nil, apperrors.UnprocessableEntity(apperrors.WithMessage(entity.ErrInsufficientFunds.Error()))
+		}
+		uc.logger.ErrorContext(ctx, "persist review failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	uc.logger.InfoContext(ctx, "held transaction reviewed",
+		slog.String("transaction_id", tx.ID),
+		slog.String("status", string(tx.Status)),
+		slog.String("reviewer", input.Reviewer),
+	)
+
+	return &ReviewOutput{ID: tx.ID, Status: string(tx.Status)}, nil
+}
+
+// approvedEventType is the event a transaction approved into status emits,
+// the one it would have been created with.
+func approvedEventType(status entity.TransactionStatus) string {
+	switch status {
+	case entity.StatusScheduled:
+		return entity.EventTransactionScheduled
+	case entity.StatusAuthorized:
+		return entity.EventTransactionAuthorized
+	}
+	return entity.EventTransactionCreated
+}
+
+// reviewReason is the status history reason for a review: the decision and
+// who took it, then the reviewer's note if any.
+func reviewReason(input ReviewInput) string {
+	reason := "rejected by " + input.Reviewer
+	if input.Approve {
+		reason = "approved by " + input.Reviewer
+	}
+	if input.Reason != "" {
+		reason += ": " + input.Reason
+	}
+	return reason
+}
@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+// heldFixture is a transfer the risk rules held; shape makes it a hold or a
+// scheduled transfer instead.
+func heldFixture(shape func(*entity.Transaction)) *entity.Transaction {
+	tx := &entity.Transaction{
+		ID:         "tx-1",
+		Amount:     1000,
+		Currency:   "USD",
+		FromUserID: "user-1",
+		ToUserID:   "user-2",
+		Status:     entity.StatusHeld,
+		Risk:       &entity.RiskAssessment{Decision: entity.RiskReview, Reasons: []string{"new recipient"}},
+		CreatedAt:  time.Now().UTC(),
+	}
+	if shape != nil {
+		shape(tx)
+	}
+	return tx
+}
+
+func TestReviewHeldUseCase_Approves(t *testing.T) {
+	executeAt := time.Now().UTC().Add(time.Hour)
+	cases := []struct {
+		name   string
+		shape  func(*entity.Transaction)
+		status entity.TransactionStatus
+		event  string
+	}{
+		{"transfer", nil, entity.StatusPending, entity.EventTransactionCreated},
+		{"hold", func(tx *entity.Transaction) { tx.AuthorizedAmount = &tx.Amount }, entity.StatusAuthorized, entity.EventTransactionAuthorized},
+		{"scheduled", func(tx *entity.Transaction) { tx.ExecuteAt = &executeAt }, entity.StatusScheduled, entity.EventTransactionScheduled},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var (
+				persisted *entity.Transaction
+				outbox    *entity.Outbox
+			)
+			repo := &mockTransactionRepository{
+				findByIDFn: func(context.Context, string) (*entity.Transaction, error) {
+					return heldFixture(c.shape), nil
+				},
+				resolveHeldFn: func(_ context.Context, tx *entity.Transaction, o *entity.Outbox) error {
+					persisted, outbox = tx, o
+					return nil
+				},
+			}
+			uc := usecase.NewReviewHeldUseCase(repo, testLogger())
+
+			out, err := uc.Execute(context.Background(), usecase.ReviewInput{ID: "tx-1", Approve: true, Reviewer: "alice", Reason: "looks fine"})
+
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if out.Status != string(c.status) || persisted.Status != c.status || persisted.ProcessedAt != nil {
+				t.Fatalf("expected an unprocessed %s transaction, got %+v", c.status, persisted)
+			}
+			if outbox.Type != c.event {
+				t.Fatalf("expected event %s, got %s", c.event, outbox.Type)
+			}
+			change := assertStatusChange(t, repo, "tx-1", entity.StatusHeld, c.status, entity.ActorAdmin)
+			if change.Reason != "approved by alice: looks fine" {
+				t.Fatalf("expected the reviewer and reason recorded, got %q", change.Reason)
+			}
+		})
+	}
+}
+
+func TestReviewHeldUseCase_Rejects(t *testing.T) {
+	var outbox *entity.Outbox
+	repo := &mockTransactionRepository{
+		findByIDFn: func(context.Context, string) (*entity.Transaction, error) {
+			return heldFixture(nil), nil
+		},
+		resolveHeldFn: func(_ context.Context, _ *entity.Transaction, o *entity.Outbox) error {
+			outbox = o
+			return nil
+		},
+	}
+	uc := usecase.NewReviewHeldUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.ReviewInput{ID: "tx-1", Reviewer: "bob"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Status != string(entity.StatusRejected) {
+		t.Fatalf("expected REJECTED, got %+v", out)
+	}
+	if outbox.Type != entity.EventTransactionRejected {
+		t.Fatalf("expected event %s, got %s", entity.EventTransactionRejected, outbox.Type)
+	}
+	change := assertStatusChange(t, repo, "tx-1", entity.StatusHeld, entity.StatusRejected, entity.ActorAdmin)
+	if change.Reason != "rejected by bob" {
+		t.Fatalf("expected the reviewer recorded, got %q", change.Reason)
+	}
+}
+
+func TestReviewHeldUseCase_Errors(t *testing.T) {
+	held := func(context.Context, string) (*entity.Transaction, error) { return heldFixture(nil), nil }
+	cases := []struct {
+		name        string
+		id          string
+		reviewer    string
+		findByID    func(context.Context, string) (*entity.Transaction, error)
+		resolveHeld func(context.Context, *entity.Transaction, *entity.Outbox) error
+		want        int
+	}{
+		{"missing id", "", "alice", nil, nil, http.StatusBadRequest},
+		{"missing reviewer", "tx-1", "", nil, nil, http.StatusBadRequest},
+		{"lookup fails", "tx-1", "alice", func(context.Context, string) (*entity.Transaction, error) {
+			return nil, errors.New("db down")
+		}, nil, http.StatusInternalServerError},
+		{"not found", "tx-1", "alice", nil, nil, http.StatusNotFound},
+		{"not held", "tx-1", "alice", func(context.Context, string) (*entity.Transaction, error) {
+			return heldFixture(func(tx *entity.Transaction) { tx.Status = entity.StatusCompleted }), nil
+		}, nil, http.StatusConflict},
+		{"reviewed concurrently", "tx-1", "alice", held, func(context.Context, *entity.Transaction, *entity.Outbox) error {
+			return fmt.Errorf("resolve: %w", entity.ErrInvalidStatusTransition)
+		}, http.StatusConflict},
+		{"insufficient funds", "tx-1", "alice", held, func(context.Context, *entity.Transaction, *entity.Outbox) error {
+			return entity.ErrInsufficientFunds
+		}, http.StatusUnprocessableEntity},
+		{"persist fails", "tx-1", "alice", held, func(context.Context, *entity.Transaction, *entity.Outbox) error {
+			return errors.New("db down")
+		}, http.StatusInternalServerError},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			repo := &mockTransactionRepository{findByIDFn: c.findByID, resolveHeldFn: c.resolveHeld}
+			uc := usecase.NewReviewHeldUseCase(repo, testLogger())
+
+			_, err := uc.Execute(context.Background(), usecase.ReviewInput{ID: c.id, Approve: true, Reviewer: c.reviewer})
+
+			_ = assertException(t, err, c.want)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+const defaultMandateBatchSize = 100
+
+type (
+	RunMandatesInput struct {
+		BatchSize int
+	}
+
+	RunMandatesOutput struct {
+		Executed int
+		Failed   int
+		Skipped  int
+	}
+
+	RunMandatesUseCase struct {
+		mandates ports.MandateRepository
+		create   *CreateTransactionUseCase
+		logger   *slog.Logger
+	}
+)
+
+func NewRunMandatesUseCase(mandates ports.MandateRepository, create *CreateTransactionUseCase, logger *slog.Logger) *RunMandatesUseCase {
+	return &RunMandatesUseCase{mandates: mandates, create: create, logger: logger}
+}
+
+// Execute runs the due occurrence of every ACTIVE mandate. Each run creates
+// its transfer through CreateTransactionUseCase under the occurrence's
+// idempotency key, so a run retried after a crash replays the transfer it
+// already made instead of paying again. Runs the create path rejects, such
+// as for insufficient funds, emit MandateFailed and are not retried; the
+// mandate moves on to its next occurrence either way. A mandate behind by
+// several occurrences catches up one per pass.
+func (uc *RunMandatesUseCase) Execute(ctx context.Context, input RunMandatesInput) (*RunMandatesOutput, error) {
+	batchSize := input.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultMandateBatchSize
+	}
+
+	out := &RunMandatesOutput{}
+	seen := map[string]bool{}
+	for {
+		now := time.Now().UTC()
+		due, err := uc.mandates.FindDue(ctx, now, batchSize)
+		if err != nil {
+			uc.logger.ErrorContext(ctx, "find due mandates failed", slog.String("error", err.Error()))
+			return nil, apperrors.Unexpected(apperrors.WithError(err))
+		}
+
+		ranBefore := out.Executed + out.Failed
+		for _, m := range due {
+			if seen[m.ID] {
+				continue
+			}
+			seen[m.ID] = true
+			if err := uc.run(ctx, m, now, out); err != nil {
+				return nil, err
+			}
+		}
+
+		// A page that ran nothing new cannot shrink the backlog; leave the
+		// rest to the next pass instead of spinning.
+		if len(due) < batchSize || out.Executed+out.Failed == ranBefore {
+			break
+		}
+	}
+
+	if out.Executed > 0 || out.Failed > 0 || out.Skipped > 0 {
+		uc.logger.InfoContext(ctx, "run mandates finished",
+			slog.Int("executed", out.Executed),
+			slog.Int("failed", out.Failed),
+			slog.Int("skipped", out.Skipped),
+		)
+	}
+
+	return out, nil
+}
+
+func (uc *RunMandatesUseCase) run(ctx context.Context, m *entity.Mandate, now time.Time, out *RunMandatesOutput) error {
+	occurrence, runAt := m.Occurrence, m.NextRunAt
+	// A retry after a crash before Advance finds the transfer of the first
+	// attempt under the occurrence key, even if the mandate was edited in
+	// between, and reports the run executed.
+	created, err := uc.create.Execute(ctx, CreateInput{
+		FromUserID:       m.FromUserID,
+		ToUserID:         m.ToUserID,
+		Amount:           m.Amount,
+		Currency:         m.Currency,
+		Description:      m.Description,
+		IdempotencyKey:   m.OccurrenceKey(),
+		ReplayAnyRequest: true,
+	})
+
+	eventType := entity.EventMandateExecuted
+	extra := map[string]any{"occurrence": occurrence, "runAt": runAt}
+	if err == nil {
+		extra["transactionId"] = created.ID
+	} else {
+		var exc *apperrors.Exception
+		if !errors.As(err, &exc) || exc.Code >= http.StatusInternalServerError {
+			// The run is retried on the next pass under the same key.
+			uc.logger.ErrorContext(ctx, "mandate run failed",
+				slog.String("mandate_id", m.ID),
+				slog.String("error", err.Error()),
+			)
+			return err
+		}
+		eventType = entity.EventMandateFailed
+		extra["reason"] = exc.Message
+	}
+
+	if err := m.Advance(now); err != nil {
+		out.Skipped++
+		return nil
+	}
+	outbox, err := newMandateOutbox(m, eventType, extra)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "marshal outbox payload failed", slog.String("error", err.Error()))
+		return apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if err := uc.mandates.Advance(ctx, m, outbox); err != nil {
+		if errors.Is(err, entity.ErrMandateChanged) {
+			// Another pass advanced it, or it was cancelled while this run
+			// created its transfer.
+			uc.logger.WarnContext(ctx, "mandate changed during its run", slog.String("mandate_id", m.ID))
+			out.Skipped++
+			return nil
+		}
+		uc.logger.ErrorContext(ctx, "persist mandate run failed",
+			slog.String("mandate_id", m.ID),
+			slog.String("error", err.Error()),
+		)
+		return apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	if eventType == entity.EventMandateFailed {
+		out.Failed++
+		uc.logger.WarnContext(ctx, "mandate run rejected",
+			slog.String("mandate_id", m.ID),
+			slog.Int64("occurrence", occurrence),
+			slog.String("reason", extra["reason"].(string)),
+		)
+		return nil
+	}
+	out.Executed++
+	uc.logger.InfoContext(ctx, "mandate executed",
+		slog.String("mandate_id", m.ID),
+		slog.Int64("occurrence", occurrence),
+		slog.String("transaction_id", created.ID),
+	)
+	return nil
+}
@@ -0,0 +1,214 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+func newRunMandates(mandates *mockMandateRepository, txs *mockTransactionRepository) *usecase.RunMandatesUseCase {
+	create := usecase.NewCreateTransactionUseCase(txs, &mockKnownUserRepository{}, &mockRiskEvaluator{}, testLogger())
+	return usecase.NewRunMandatesUseCase(mandates, create, testLogger())
+}
+
+func dueMandates(ms ...*entity.Mandate) func(context.Context, time.Time, int) ([]*entity.Mandate, error) {
+	return func(_ context.Context, _ time.Time, _ int) ([]*entity.Mandate, error) {
+		return ms, nil
+	}
+}
+
+func TestRunMandatesUseCase_Executes(t *testing.T) {
+	m := mandateFixture()
+	start := m.StartAt
+	var advanced *entity.Mandate
+	mandates := &mockMandateRepository{
+		findDueFn: dueMandates(m),
+		advanceFn: func(_ context.Context, m *entity.Mandate) error {
+			advanced = m
+			return nil
+		},
+	}
+	var created *entity.Transaction
+	txs := &mockTransactionRepository{
+		getAvailableBalanceFn: func(_ context.Context, _, _ string) (int64, error) {
+			return 10_000, nil
+		},
+		createFn: func(_ context.Context, tx *entity.Transaction, _ *entity.Outbox) error {
+			created = tx
+			return nil
+		},
+	}
+
+	out, err := newRunMandates(mandates, txs).Execute(context.Background(), usecase.RunMandatesInput{})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Executed != 1 || out.Failed != 0 || out.Skipped != 0 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if created == nil || created.IdempotencyKey == nil || *created.IdempotencyKey != "mandate-mandate-1-0" || created.Amount != m.Amount {
+		t.Fatalf("expected a transfer under the occurrence key, got %+v", created)
+	}
+	if advanced == nil || advanced.Occurrence != 1 || !advanced.NextRunAt.Equal(start.AddDate(0, 1, 0)) {
+		t.Fatalf("expected the mandate advanced one month, got %+v", advanced)
+	}
+	eventType, payload := mandates.lastEvent(t)
+	if eventType != entity.EventMandateExecuted || payload["transactionId"] != created.ID || payload["occurrence"] != float64(0) {
+		t.Fatalf("unexpected event %s: %v", eventType, payload)
+	}
+}
+
+func TestRunMandatesUseCase_RejectedRunFails(t *testing.T) {
+	mandates := &mockMandateRepository{findDueFn: dueMandates(mandateFixture())}
+	txs := &mockTransactionRepository{
+		createFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+			return entity.ErrInsufficientFunds
+		},
+	}
+
+	out, err := newRunMandates(mandates, txs).Execute(context.Background(), usecase.RunMandatesInput{})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Executed != 0 || out.Failed != 1 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	eventType, payload := mandates.lastEvent(t)
+	if eventType != entity.EventMandateFailed || payload["reason"] != entity.ErrInsufficientFunds.Error() {
+		t.Fatalf("unexpected event %s: %v", eventType, payload)
+	}
+}
+
+func TestRunMandatesUseCase_RetryAfterEditReplaysFirstAttempt(t *testing.T) {
+	m := mandateFixture()
+	m.Amount = 2500 // edited since the first attempt created its transfer
+	hash := "hash of the 1000 transfer"
+	first := &entity.Transaction{ID: "tx-first", Amount: 1000, Currency: "USD", Status: entity.StatusPending, RequestHash: &hash}
+	mandates := &mockMandateRepository{findDueFn: dueMandates(m)}
+	txs := &mockTransactionRepository{
+		findByIdempotencyKeyFn: func(_ context.Context, _, key string) (*entity.Transaction, error) {
+			if key != m.OccurrenceKey() {
+				t.Fatalf("unexpected key %s", key)
+			}
+			return first, nil
+		},
+		createFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+			t.Fatal("a retry must not create a second transfer")
+			return nil
+		},
+	}
+
+	out, err := newRunMandates(mandates, txs).Execute(context.Background(), usecase.RunMandatesInput{})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Executed != 1 || out.Failed != 0 {
+		t.Fatalf("expected the run executed, got %+v", out)
+	}
+	eventType, payload := mandates.lastEvent(t)
+	if eventType != entity.EventMandateExecuted || payload["transactionId"] != first.ID {
+		t.Fatalf("expected MandateExecuted for the first transfer, got %s: %v", eventType, payload)
+	}
+}
+
+func TestRunMandatesUseCase_UnexpectedErrorAborts(t *testing.T) {
+	mandates := &mockMandateRepository{
+		findDueFn: dueMandates(mandateFixture()),
+		advanceFn: func(_ context.Context, _ *entity.Mandate) error {
+			t.Fatal("a run that did not complete must not advance")
+			return nil
+		},
+	}
+	txs := &mockTransactionRepository{
+		createFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+			return errors.New("db error")
+		},
+	}
+
+	_, err := newRunMandates(mandates, txs).Execute(context.Background(), usecase.RunMandatesInput{})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
+
+func TestRunMandatesUseCase_SkipsChangedMandate(t *testing.T) {
+	mandates := &mockMandateRepository{
+		findDueFn: dueMandates(mandateFixture()),
+		advanceFn: func(_ context.Context, _ *entity.Mandate) error {
+			return entity.ErrMandateChanged
+		},
+	}
+
+	out, err := newRunMandates(mandates, &mockTransactionRepository{}).Execute(context.Background(), usecase.RunMandatesInput{})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Executed != 0 || out.Skipped != 1 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+}
+
+func TestRunMandatesUseCase_Errors(t *testing.T) {
+	t.Run("find due", func(t *testing.T) {
+		mandates := &mockMandateRepository{
+			findDueFn: func(_ context.Context, _ time.Time, _ int) ([]*entity.Mandate, error) {
+				return nil, errors.New("db error")
+			},
+		}
+		_, err := newRunMandates(mandates, &mockTransactionRepository{}).Execute(context.Background(), usecase.RunMandatesInput{})
+		_ = assertException(t, err, http.StatusInternalServerError)
+	})
+
+	t.Run("advance", func(t *testing.T) {
+		mandates := &mockMandateRepository{
+			findDueFn: dueMandates(mandateFixture()),
+			advanceFn: func(_ context.Context, _ *entity.Mandate) error {
+				return errors.New("db error")
+			},
+		}
+		_, err := newRunMandates(mandates, &mockTransactionRepository{}).Execute(context.Background(), usecase.RunMandatesInput{})
+		_ = assertException(t, err, http.StatusInternalServerError)
+	})
+}
+
+func TestRunMandatesUseCase_PagesOncePerMandate(t *testing.T) {
+	first, second, third := mandateFixture(), mandateFixture(), mandateFixture()
+	second.ID, third.ID = "mandate-2", "mandate-3"
+	calls := 0
+	mandates := &mockMandateRepository{
+		findDueFn: func(_ context.Context, _ time.Time, limit int) ([]*entity.Mandate, error) {
+			if limit != 2 {
+				t.Fatalf("expected limit 2, got %d", limit)
+			}
+			calls++
+			switch calls {
+			case 1:
+				return []*entity.Mandate{first, second}, nil
+			default:
+				// first is due again after catching up one occurrence; it
+				// must wait for the next pass.
+				return []*entity.Mandate{first, third}, nil
+			}
+		},
+	}
+
+	out, err := newRunMandates(mandates, &mockTransactionRepository{}).Execute(context.Background(), usecase.RunMandatesInput{BatchSize: 2})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Executed != 3 {
+		t.Fatalf("expected each mandate to run once, got %+v", out)
+	}
+	if calls != 3 {
+		t.Fatalf("expected paging to stop once a page ran nothing new, got %d calls", calls)
+	}
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+// maxSearchQueryLength bounds the search text, in characters.
+const maxSearchQueryLength = 200
+
+type (
+	SearchInput struct {
+		Query  string
+		UserID string
+		Cursor string
+		Limit  int
+	}
+
+	// SearchItem is a matched transaction with its relevance and a snippet
+	// of its description, HTML-escaped with the matches wrapped in <mark>.
+	SearchItem struct {
+		TransactionItem
+		Rank    float32 `json:"rank"`
+		Snippet string  `json:"snippet"`
+	}
+
+	SearchOutput struct {
+		Items      []SearchItem `json:"items"`
+		NextCursor string       `json:"next_cursor,omitempty"`
+	}
+
+	SearchTransactionsUseCase struct {
+		repo   ports.TransactionRepository
+		logger *slog.Logger
+	}
+)
+
+func NewSearchTransactionsUseCase(repo ports.TransactionRepository, logger *slog.Logger) *SearchTransactionsUseCase {
+	return &SearchTransactionsUseCase{repo: repo, logger: logger}
+}
+
+// Execute returns one page of the transactions whose description matches
+// the query, most relevant first. NextCursor is empty on the last page.
+func (uc *SearchTransactionsUseCase) Execute(ctx context.Context, input SearchInput) (*SearchOutput, error) {
+	uc.logger.InfoContext(ctx, "search transactions request", slog.String("user_id", input.UserID))
+
+	q, limit, err := buildSearchQuery(input)
+	if err != nil {
+		uc.logger.WarnContext(ctx, "search transactions validation failed", slog.String("reason", err.Error()))
+		return nil, apperrors.BadRequest(apperrors.WithMessage(err.Error()))
+	}
+
+	// One extra row tells whether another page exists.
+	hits, err := uc.repo.Search(ctx, q, limit+1)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "search transactions failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	out := &SearchOutput{Items: make([]SearchItem, 0, min(len(hits), limit))}
+	if len(hits) > limit {
+		hits = hits[:limit]
+		last := hits[limit-1]
+		out.NextCursor = encodeSearchCursor(last.Rank, last.Transaction.CreatedAt, last.Transaction.ID)
+	}
+	for _, hit := range hits {
+		out.Items = append(out.Items, SearchItem{
+			TransactionItem: newTransactionItem(hit.Transaction),
+			Rank:            hit.Rank,
+			Snippet:         hit.HighlightHTML(),
+		})
+	}
+
+	uc.logger.InfoContext(ctx, "transactions searched",
+		slog.String("user_id", input.UserID),
+		slog.Int("count", len(out.Items)),
+	)
+
+	return out, nil
+}
+
+func buildSearchQuery(input SearchInput) (ports.SearchQuery, int, error) {
+	q := ports.SearchQuery{Text: strings.TrimSpace(input.Query)}
+
+	if q.Text == "" {
+		return q, 0, errors.New("q is required")
+	}
+	if utf8.RuneCountInString(q.Text) > maxSearchQueryLength {
+		return q, 0, fmt.Errorf("q must be at most %d characters", maxSearchQueryLength)
+	}
+	if input.UserID != "" {
+		userID, err := canonicalUUID(input.UserID)
+		if err != nil {
+			return q, 0, errors.New("user_id must be a UUID")
+		}
+		q.UserID = userID
+	}
+
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	if limit < 1 || limit > maxListLimit {
+		return q, 0, fmt.Errorf("limit must be between 1 and %d", maxListLimit)
+	}
+
+	if input.Cursor != "" {
+		rank, createdAt, id, err := decodeSearchCursor(input.Cursor)
+		if err != nil {
+			return q, 0, err
+		}
+		q.AfterRank, q.AfterCreatedAt, q.AfterID = rank, createdAt, id
+	}
+
+	return q, limit, nil
+}
+
+// The search cursor extends the list cursor with the rank it sorts on first:
+// base64url("<rank>|<created_at RFC3339Nano>|<id>"). The rank is written
+// with the shortest digits that read back as the same float32, so the next
+// page resumes exactly after the last row.
+func encodeSearchCursor(rank float32, createdAt time.Time, id string) string {
+	raw := strconv.FormatFloat(float64(rank), 'g', -1, 32) + "|" + createdAt.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSearchCursor(cursor string) (float32, time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, time.Time{}, "", errors.New("invalid cursor")
+	}
+	parts := strings.SplitN(string(raw), "|", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return 0, time.Time{}, "", errors.New("invalid cursor")
+	}
+	rank, err := strconv.ParseFloat(parts[0], 32)
+	if err != nil {
+		return 0, time.Time{}, "", errors.New("invalid cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[1])
+	if err != nil {
+		return 0, time.Time{}, "", errors.New("invalid cursor")
+	}
+	id, err := canonicalUUID(parts[2])
+	if err != nil {
+		return 0, time.Time{}, "", errors.New("invalid cursor")
+	}
+	return float32(rank), createdAt, id, nil
+}
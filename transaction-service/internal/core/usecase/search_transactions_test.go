@@ -0,0 +1,101 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	"transaction-service/internal/core/usecase"
+)
+
+func searchFixture(n int) []entity.SearchHit {
+	hits := make([]entity.SearchHit, n)
+	for i, tx := range listFixture(n) {
+		tx.Description = "refund of invoice 42"
+		hits[i] = entity.SearchHit{Transaction: tx, Rank: 0.1 / float32(i+1), Snippet: "\x02refund\x03 of <invoice> 42"}
+	}
+	return hits
+}
+
+func TestSearchTransactionsUseCase_MapsHitsAndPaginates(t *testing.T) {
+	var captured []ports.SearchQuery
+	var capturedLimit int
+	repo := &mockTransactionRepository{
+		searchFn: func(_ context.Context, q ports.SearchQuery, limit int) ([]entity.SearchHit, error) {
+			captured, capturedLimit = append(captured, q), limit
+			if q.AfterID != "" {
+				return nil, nil
+			}
+			return searchFixture(limit), nil
+		},
+	}
+	uc := usecase.NewSearchTransactionsUseCase(repo, testLogger())
+
+	first, err := uc.Execute(context.Background(), usecase.SearchInput{Query: "  refund  ", UserID: validUserID, Limit: 2})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if capturedLimit != 3 || captured[0].Text != "refund" || captured[0].UserID != validUserID || captured[0].AfterID != "" {
+		t.Fatalf("expected a trimmed query for a page of 2 plus one look-ahead row, got %+v (limit %d)", captured[0], capturedLimit)
+	}
+	if len(first.Items) != 2 || first.NextCursor == "" {
+		t.Fatalf("expected a full page and a cursor, got %+v", first)
+	}
+	item := first.Items[1]
+	if item.ID != "00000000-0000-4000-8000-000000000002" || item.Rank != 0.05 || item.Snippet != "<mark>refund</mark> of &lt;invoice&gt; 42" {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+
+	if _, err := uc.Execute(context.Background(), usecase.SearchInput{Query: "refund", Cursor: first.NextCursor}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	last := searchFixture(2)[1]
+	if q := captured[1]; q.AfterRank != last.Rank || !q.AfterCreatedAt.Equal(last.Transaction.CreatedAt) || q.AfterID != last.Transaction.ID {
+		t.Fatalf("expected the next page to start after %s, got %+v", last.Transaction.ID, q)
+	}
+}
+
+func TestSearchTransactionsUseCase_ValidationErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		input usecase.SearchInput
+	}{
+		{"missing query", usecase.SearchInput{Query: "   "}},
+		{"long query", usecase.SearchInput{Query: strings.Repeat("a", 201)}},
+		{"limit too large", usecase.SearchInput{Query: "refund", Limit: 101}},
+		{"cursor not base64", usecase.SearchInput{Query: "refund", Cursor: "%%%"}},
+		{"cursor without rank", usecase.SearchInput{Query: "refund", Cursor: base64.RawURLEncoding.EncodeToString([]byte("2026-01-01T00:00:00Z|" + validUserID))}},
+		{"cursor with bad rank", usecase.SearchInput{Query: "refund", Cursor: base64.RawURLEncoding.EncodeToString([]byte("high|2026-01-01T00:00:00Z|" + validUserID))}},
+		{"cursor with bad time", usecase.SearchInput{Query: "refund", Cursor: base64.RawURLEncoding.EncodeToString([]byte("0.1|yesterday|" + validUserID))}},
+		{"cursor id not uuid", usecase.SearchInput{Query: "refund", Cursor: base64.RawURLEncoding.EncodeToString([]byte("0.1|2026-01-01T00:00:00Z|tx-a"))}},
+		{"user not a uuid", usecase.SearchInput{Query: "refund", UserID: "user-1"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := usecase.NewSearchTransactionsUseCase(&mockTransactionRepository{}, testLogger())
+
+			_, err := uc.Execute(context.Background(), tc.input)
+
+			_ = assertException(t, err, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestSearchTransactionsUseCase_RepositoryError(t *testing.T) {
+	repo := &mockTransactionRepository{
+		searchFn: func(context.Context, ports.SearchQuery, int) ([]entity.SearchHit, error) {
+			return nil, errors.New("db error")
+		},
+	}
+	uc := usecase.NewSearchTransactionsUseCase(repo, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.SearchInput{Query: "refund"})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
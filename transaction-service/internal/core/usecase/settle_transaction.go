@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	SettleInput struct {
+		TransactionID string
+	}
+
+	SettleOutput struct {
+		ID      string
+		Status  string
+		Settled bool
+	}
+
+	SettleTransactionUseCase struct {
+		repo   ports.TransactionRepository
+		risk   ports.RiskEvaluator
+		logger *slog.Logger
+	}
+)
+
+func NewSettleTransactionUseCase(repo ports.TransactionRepository, risk ports.RiskEvaluator, logger *slog.Logger) *SettleTransactionUseCase {
+	return &SettleTransactionUseCase{repo: repo, risk: risk, logger: logger}
+}
+
+// Execute settles a PENDING transaction as COMPLETED or FAILED and emits the
+// matching outbox event. It FAILS when the risk screen no longer clears its
+// parties, e.g. one was blocklisted after the transfer was accepted.
+// Transactions that were already settled are reported with Settled=false so
+// redelivered messages are acknowledged without effect.
+func (uc *SettleTransactionUseCase) Execute(ctx context.Context, input SettleInput) (*SettleOutput, error) {
+	uc.logger.InfoContext(ctx, "settle transaction request", slog.String("transaction_id", input.TransactionID))
+
+	if input.TransactionID == "" {
+		uc.logger.WarnContext(ctx, "settle validation failed", slog.String("reason", "transaction_id is required"))
+		return nil, apperrors.BadRequest(apperrors.WithMessage("transaction_id is required"))
+	}
+
+	tx, err := uc.repo.FindByID(ctx, input.TransactionID)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "find transaction failed",
+			slog.String("transaction_id", input.TransactionID),
+			slog.String("error", err.Error()),
+		)
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if tx == nil {
+		uc.logger.WarnContext(ctx, "transaction not found", slog.String("transaction_id", input.TransactionID))
+		return nil, apperrors.NotFound(apperrors.WithMessage("transaction not found"))
+	}
+
+	if tx.Status != entity.StatusPending {
+		uc.logger.InfoContext(ctx, "transaction already settled — skipping",
+			slog.String("transaction_id", tx.ID),
+			slog.String("status", string(tx.Status)),
+		)
+		return &SettleOutput{ID: tx.ID, Status: string(tx.Status)}, nil
+	}
+
+	now := time.Now().UTC()
+	eventType := entity.EventTransactionCompleted
+	reason := ""
+	if reasons := uc.risk.Screen(tx); len(reasons) > 0 {
+		eventType = entity.EventTransactionFailed
+		reason = strings.Join(reasons, "; ")
+		uc.logger.WarnContext(ctx, "transaction failed screening",
+			slog.String("transaction_id", tx.ID),
+			slog.String("reason", reason),
+		)
+		_ = tx.Fail(now)
+	} else {
+		_ = tx.Complete(now)
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"transactionId": tx.ID,
+		"fromUserId":    tx.FromUserID,
+		"toUserId":      tx.ToUserID,
+		"amount":        tx.Amount,
+		"currency":      tx.Currency,
+		"conversion":    tx.Conversion,
+		"status":        tx.Status,
+		"processedAt":   tx.ProcessedAt,
+		"reason":        reason,
+	})
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "marshal outbox payload failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	change := entity.NewStatusChange(tx, entity.StatusPending, entity.ActorSettlement, reason)
+	if err := uc.repo.Settle(ctx, tx, change, entity.NewOutbox(eventType, tx.ID, string(payload))); err != nil {
+		if errors.Is(err, entity.ErrInvalidStatusTransition) {
+			uc.logger.InfoContext(ctx, "transaction settled concurrently — skipping",
+				slog.String("transaction_id", tx.ID),
+			)
+			return &SettleOutput{ID: tx.ID}, nil
+		}
+		uc.logger.ErrorContext(ctx, "persist settlement failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	uc.logger.InfoContext(ctx, "transaction settled",
+		slog.String("transaction_id", tx.ID),
+		slog.String("status", string(tx.Status)),
+	)
+
+	return &SettleOutput{
+		ID:      tx.ID,
+		Status:  string(tx.Status),
+		Settled: true,
+	}, nil
+}
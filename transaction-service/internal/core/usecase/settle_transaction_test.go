@@ -0,0 +1,197 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+func pendingTransaction() *entity.Transaction {
+	return &entity.Transaction{
+		ID:         "tx-1",
+		Amount:     100,
+		Currency:   "USD",
+		FromUserID: "user-1",
+		ToUserID:   "user-2",
+		Status:     entity.StatusPending,
+	}
+}
+
+func TestSettleTransactionUseCase_Completes(t *testing.T) {
+	var capturedTx *entity.Transaction
+	var capturedOutbox *entity.Outbox
+	repo := &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return pendingTransaction(), nil
+		},
+		settleFn: func(_ context.Context, tx *entity.Transaction, outbox *entity.Outbox) error {
+			capturedTx = tx
+			capturedOutbox = outbox
+			return nil
+		},
+	}
+	uc := usecase.NewSettleTransactionUseCase(repo, &mockRiskEvaluator{}, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.SettleInput{TransactionID: "tx-1"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !out.Settled {
+		t.Fatal("expected Settled to be true")
+	}
+	if out.Status != string(entity.StatusCompleted) {
+		t.Fatalf("expected status COMPLETED, got '%s'", out.Status)
+	}
+	if capturedTx == nil || capturedTx.ProcessedAt == nil {
+		t.Fatal("expected processed_at to be set on the settled transaction")
+	}
+	if capturedOutbox == nil || capturedOutbox.Type != entity.EventTransactionCompleted {
+		t.Fatalf("expected outbox type '%s'", entity.EventTransactionCompleted)
+	}
+}
+
+func TestSettleTransactionUseCase_FailsScreenedTransfer(t *testing.T) {
+	var capturedTx *entity.Transaction
+	var capturedOutbox *entity.Outbox
+	repo := &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return pendingTransaction(), nil
+		},
+		settleFn: func(_ context.Context, tx *entity.Transaction, outbox *entity.Outbox) error {
+			capturedTx, capturedOutbox = tx, outbox
+			return nil
+		},
+	}
+	screener := &mockRiskEvaluator{
+		screenFn: func(tx *entity.Transaction) []string {
+			if tx.ToUserID != "user-2" {
+				t.Fatalf("expected the settled transfer screened, got %+v", tx)
+			}
+			return []string{"sender is blocklisted", "recipient is blocklisted"}
+		},
+	}
+	uc := usecase.NewSettleTransactionUseCase(repo, screener, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.SettleInput{TransactionID: "tx-1"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !out.Settled || out.Status != string(entity.StatusFailed) {
+		t.Fatalf("expected a settled FAILED transfer, got %+v", out)
+	}
+	if capturedTx.ProcessedAt == nil {
+		t.Fatal("expected processed_at to be set on the failed transaction")
+	}
+	if capturedOutbox == nil || capturedOutbox.Type != entity.EventTransactionFailed {
+		t.Fatalf("expected outbox type '%s'", entity.EventTransactionFailed)
+	}
+	const reason = "sender is blocklisted; recipient is blocklisted"
+	if !strings.Contains(capturedOutbox.Payload, reason) {
+		t.Fatalf("expected failure reason in payload, got: %s", capturedOutbox.Payload)
+	}
+	change := assertStatusChange(t, repo, "tx-1", entity.StatusPending, entity.StatusFailed, entity.ActorSettlement)
+	if change.Reason != reason {
+		t.Fatalf("expected failure reason in the status change, got %q", change.Reason)
+	}
+}
+
+func TestSettleTransactionUseCase_AlreadySettled(t *testing.T) {
+	called := false
+	repo := &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			tx := pendingTransaction()
+			tx.Status = entity.StatusCompleted
+			return tx, nil
+		},
+		settleFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+			called = true
+			return nil
+		},
+	}
+	uc := usecase.NewSettleTransactionUseCase(repo, &mockRiskEvaluator{}, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.SettleInput{TransactionID: "tx-1"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Settled {
+		t.Fatal("expected Settled to be false for an already settled transaction")
+	}
+	if called {
+		t.Fatal("repository Settle should not be called for a settled transaction")
+	}
+}
+
+func TestSettleTransactionUseCase_SettledConcurrently(t *testing.T) {
+	repo := &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return pendingTransaction(), nil
+		},
+		settleFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+			return entity.ErrInvalidStatusTransition
+		},
+	}
+	uc := usecase.NewSettleTransactionUseCase(repo, &mockRiskEvaluator{}, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.SettleInput{TransactionID: "tx-1"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Settled {
+		t.Fatal("expected Settled to be false when another consumer won the race")
+	}
+}
+
+func TestSettleTransactionUseCase_EmptyID(t *testing.T) {
+	uc := usecase.NewSettleTransactionUseCase(&mockTransactionRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.SettleInput{})
+
+	_ = assertException(t, err, http.StatusBadRequest)
+}
+
+func TestSettleTransactionUseCase_NotFound(t *testing.T) {
+	uc := usecase.NewSettleTransactionUseCase(&mockTransactionRepository{}, &mockRiskEvaluator{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.SettleInput{TransactionID: "tx-unknown"})
+
+	_ = assertException(t, err, http.StatusNotFound)
+}
+
+func TestSettleTransactionUseCase_FindError(t *testing.T) {
+	repo := &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return nil, errors.New("db error")
+		},
+	}
+	uc := usecase.NewSettleTransactionUseCase(repo, &mockRiskEvaluator{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.SettleInput{TransactionID: "tx-1"})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
+
+func TestSettleTransactionUseCase_SettleError(t *testing.T) {
+	repo := &mockTransactionRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Transaction, error) {
+			return pendingTransaction(), nil
+		},
+		settleFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+			return errors.New("db error")
+		},
+	}
+	uc := usecase.NewSettleTransactionUseCase(repo, &mockRiskEvaluator{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.SettleInput{TransactionID: "tx-1"})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"transaction-service/internal/core/domain/entity"
+)
+
+// statementWriter renders a statement in one format: begin once, line per
+// posting, then end with the closing balance, which flushes the output.
+type statementWriter interface {
+	contentType() string
+	begin(h statementHeader) error
+	line(l entity.StatementLine) error
+	end(closing int64) error
+}
+
+func newStatementWriter(format string, w io.Writer) statementWriter {
+	if format == StatementFormatOFX {
+		return &ofxStatement{w: bufio.NewWriter(w)}
+	}
+	return &csvStatement{w: csv.NewWriter(w)}
+}
+
+// csvStatement writes one row per posting between an opening and a closing
+// balance row. Amounts are signed decimals in the major currency unit.
+type csvStatement struct {
+	w      *csv.Writer
+	header statementHeader
+}
+
+func (s *csvStatement) contentType() string { return "text/csv; charset=utf-8" }
+
+func (s *csvStatement) begin(h statementHeader) error {
+	s.header = h
+	if err := s.w.Write([]string{"date", "transaction_id", "description", "counterparty", "amount", "currency", "balance"}); err != nil {
+		return err
+	}
+	return s.balanceRow(h.from, "Opening balance", h.opening)
+}
+
+func (s *csvStatement) line(l entity.StatementLine) error {
+	return s.w.Write([]string{
+		l.PostedAt.UTC().Format(time.RFC3339),
+		l.TransactionID,
+		csvText(l.Description),
+		l.CounterpartyID,
+		entity.FormatAmount(l.Amount, s.header.exponent),
+		s.header.currency,
+		entity.FormatAmount(l.Balance, s.header.exponent),
+	})
+}
+
+func (s *csvStatement) end(closing int64) error {
+	if err := s.balanceRow(s.header.to, "Closing balance", closing); err != nil {
+		return err
+	}
+	s.w.Flush()
+	return s.w.Error()
+}
+
+func (s *csvStatement) balanceRow(at time.Time, label string, balance int64) error {
+	return s.w.Write([]string{
+		at.Format(time.RFC3339), "", label, "", "", s.header.currency,
+		entity.FormatAmount(balance, s.header.exponent),
+	})
+}
+
+// csvText keeps a spreadsheet from reading user-supplied text as a formula.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// ofxBankID fills the routing number OFX requires of a bank account, which
+// accounts here do not have.
+const ofxBankID = "000000000"
+
+// ofxStatement writes an OFX 2.2 bank statement. OFX has no running balance
+// per transaction, so the lines carry the signed amounts and the opening and
+// closing balances go in BALLIST and LEDGERBAL.
+type ofxStatement struct {
+	w      *bufio.Writer
+	header statementHeader
+}
+
+func (s *ofxStatement) contentType() string { return "application/x-ofx" }
+
+func (s *ofxStatement) begin(h statementHeader) error {
+	s.header = h
+	_, err := fmt.Fprintf(s.w, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS><DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE></SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1><STMTTRNRS><TRNUID>0</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<STMTRS><CURDEF>%s</CURDEF>
+<BANKACCTFROM><BANKID>%s</BANKID><ACCTID>%s</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>
+<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>
+`, ofxTime(h.generated), h.currency, ofxBankID, ofxText(h.userID), ofxTime(h.from), ofxTime(h.to))
+	return err
+}
+
+func (s *ofxStatement) line(l entity.StatementLine) error {
+	kind := "CREDIT"
+	if l.Amount < 0 {
+		kind = "DEBIT"
+	}
+	_, err := fmt.Fprintf(s.w, "<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%s</FITID><NAME>%s</NAME><MEMO>%s</MEMO></STMTTRN>\n",
+		kind, ofxTime(l.PostedAt), entity.FormatAmount(l.Amount, s.header.exponent), l.TransactionID,
+		ofxText(ofxName(l)), ofxText("Counterparty "+l.CounterpartyID))
+	return err
+}
+
+func (s *ofxStatement) end(closing int64) error {
+	h := s.header
+	if _, err := fmt.Fprintf(s.w, `</BANKTRANLIST>
+<LEDGERBAL><BALAMT>%s</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL>
+<BALLIST><BAL><NAME>Opening balance</NAME><DESC>Ledger balance at DTSTART</DESC><BALTYPE>DOLLAR</BALTYPE><VALUE>%s</VALUE><DTASOF>%s</DTASOF></BAL></BALLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`, entity.FormatAmount(closing, h.exponent), ofxTime(h.to), entity.FormatAmount(h.opening, h.exponent), ofxTime(h.from)); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:GMT]"
+}
+
+// ofxName is the description cut to the 32 characters OFX allows in NAME.
+func ofxName(l entity.StatementLine) string {
+	name := l.Description
+	if name == "" {
+		name = "Transfer"
+	}
+	if utf8.RuneCountInString(name) > 32 {
+		name = string([]rune(name)[:32])
+	}
+	return name
+}
+
+func ofxText(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+
+	"github.com/google/uuid"
+)
+
+type (
+	SyncKnownUserInput struct {
+		UserID     string
+		EventID    string
+		OccurredAt time.Time
+	}
+
+	SyncKnownUserOutput struct {
+		UserID  string
+		Created bool
+	}
+
+	SyncKnownUserUseCase struct {
+		users  ports.KnownUserRepository
+		logger *slog.Logger
+	}
+)
+
+func NewSyncKnownUserUseCase(users ports.KnownUserRepository, logger *slog.Logger) *SyncKnownUserUseCase {
+	return &SyncKnownUserUseCase{users: users, logger: logger}
+}
+
+// Execute projects one UserCreated event into known_users. Redelivered or
+// replayed events for a user that is already known report Created=false.
+func (uc *SyncKnownUserUseCase) Execute(ctx context.Context, input SyncKnownUserInput) (*SyncKnownUserOutput, error) {
+	if _, err := uuid.Parse(input.UserID); err != nil {
+		uc.logger.WarnContext(ctx, "sync known user validation failed",
+			slog.String("event_id", input.EventID),
+			slog.String("reason", "user_id must be a UUID"),
+		)
+		return nil, apperrors.BadRequest(apperrors.WithMessage("user_id must be a UUID"))
+	}
+
+	registeredAt := input.OccurredAt
+	if registeredAt.IsZero() {
+		registeredAt = time.Now().UTC()
+	}
+
+	created, err := uc.users.Save(ctx, &entity.KnownUser{
+		UserID:        input.UserID,
+		SourceEventID: input.EventID,
+		RegisteredAt:  registeredAt.UTC(),
+	})
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "save known user failed",
+			slog.String("event_id", input.EventID),
+			slog.String("error", err.Error()),
+		)
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	uc.logger.InfoContext(ctx, "known user synced",
+		slog.String("user_id", input.UserID),
+		slog.Bool("created", created),
+	)
+
+	return &SyncKnownUserOutput{UserID: input.UserID, Created: created}, nil
+}
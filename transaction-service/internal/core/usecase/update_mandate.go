@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	// UpdateMandateInput changes the terms of a mandate's future runs. Nil
+	// fields are left as they are.
+	UpdateMandateInput struct {
+		ID          string
+		Amount      *int64
+		Description *string
+		EndAt       *time.Time
+	}
+
+	UpdateMandateUseCase struct {
+		mandates ports.MandateRepository
+		logger   *slog.Logger
+	}
+)
+
+func NewUpdateMandateUseCase(mandates ports.MandateRepository, logger *slog.Logger) *UpdateMandateUseCase {
+	return &UpdateMandateUseCase{mandates: mandates, logger: logger}
+}
+
+// Execute amends an ACTIVE mandate and emits MandateUpdated. A run already
+// under way keeps the terms it started with.
+func (uc *UpdateMandateUseCase) Execute(ctx context.Context, input UpdateMandateInput) (*MandateItem, error) {
+	uc.logger.InfoContext(ctx, "update mandate request", slog.String("mandate_id", input.ID))
+
+	m, err := findMandate(ctx, uc.mandates, uc.logger, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var endAt *time.Time
+	if input.EndAt != nil {
+		end := input.EndAt.UTC()
+		endAt = &end
+	}
+	if err := m.Amend(input.Amount, input.Description, endAt, time.Now().UTC()); err != nil {
+		uc.logger.WarnContext(ctx, "update mandate rejected",
+			slog.String("mandate_id", m.ID),
+			slog.String("reason", err.Error()),
+		)
+		return nil, mandateRejection(err)
+	}
+
+	if err := saveMandate(ctx, uc.mandates, uc.logger, m, entity.EventMandateUpdated); err != nil {
+		return nil, err
+	}
+
+	uc.logger.InfoContext(ctx, "mandate updated", slog.String("mandate_id", m.ID))
+
+	item := newMandateItem(m)
+	return &item, nil
+}
+
+// saveMandate writes a mandate changed through the API with its event.
+// Losing to a concurrent change is a conflict the caller can retry.
+func saveMandate(ctx context.Context, mandates ports.MandateRepository, logger *slog.Logger, m *entity.Mandate, eventType string) error {
+	outbox, err := newMandateOutbox(m, eventType, nil)
+	if err != nil {
+		logger.ErrorContext(ctx, "marshal outbox payload failed", slog.String("error", err.Error()))
+		return apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if err := mandates.Update(ctx, m, outbox); err != nil {
+		if errors.Is(err, entity.ErrMandateChanged) {
+			logger.WarnContext(ctx, "mandate changed concurrently", slog.String("mandate_id", m.ID))
+			return apperrors.Conflict(apperrors.WithMessage(entity.ErrMandateChanged.Error()))
+		}
+		logger.ErrorContext(ctx, "persist mandate failed", slog.String("error", err.Error()))
+		return apperrors.Unexpected(apperrors.WithError(err))
+	}
+	return nil
+}
+
+// mandateRejection maps the errors of a mandate change to an Exception. A
+// mandate that is no longer ACTIVE cannot change anymore.
+func mandateRejection(err error) *apperrors.Exception {
+	if errors.Is(err, entity.ErrMandateNotActive) {
+		return apperrors.Conflict(apperrors.WithMessage(entity.ErrMandateNotActive.Error()))
+	}
+	return apperrors.BadRequest(apperrors.WithMessage(err.Error()))
+}
@@ -0,0 +1,165 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+func mandateRepoWith(m *entity.Mandate) *mockMandateRepository {
+	return &mockMandateRepository{
+		findByIDFn: func(_ context.Context, id string) (*entity.Mandate, error) {
+			if m == nil || m.ID != id {
+				return nil, nil
+			}
+			return m, nil
+		},
+	}
+}
+
+func TestGetMandateUseCase(t *testing.T) {
+	uc := usecase.NewGetMandateUseCase(mandateRepoWith(mandateFixture()), testLogger())
+
+	out, err := uc.Execute(context.Background(), "mandate-1")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.ID != "mandate-1" || out.Schedule != "@monthly" || out.Occurrences != 0 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+
+	_, err = uc.Execute(context.Background(), "missing")
+	_ = assertException(t, err, http.StatusNotFound)
+
+	_, err = uc.Execute(context.Background(), "")
+	_ = assertException(t, err, http.StatusBadRequest)
+
+	failing := &mockMandateRepository{
+		findByIDFn: func(_ context.Context, _ string) (*entity.Mandate, error) {
+			return nil, errors.New("db error")
+		},
+	}
+	_, err = usecase.NewGetMandateUseCase(failing, testLogger()).Execute(context.Background(), "mandate-1")
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
+
+func TestListMandatesUseCase(t *testing.T) {
+	repo := &mockMandateRepository{
+		listByUserFn: func(_ context.Context, fromUserID string) ([]*entity.Mandate, error) {
+			if fromUserID == "broken" {
+				return nil, errors.New("db error")
+			}
+			return []*entity.Mandate{mandateFixture()}, nil
+		},
+	}
+	uc := usecase.NewListMandatesUseCase(repo, testLogger())
+
+	items, err := uc.Execute(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "mandate-1" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+
+	_, err = uc.Execute(context.Background(), "")
+	_ = assertException(t, err, http.StatusBadRequest)
+
+	_, err = uc.Execute(context.Background(), "broken")
+	_ = assertException(t, err, http.StatusInternalServerError)
+}
+
+func TestUpdateMandateUseCase_Success(t *testing.T) {
+	repo := mandateRepoWith(mandateFixture())
+	uc := usecase.NewUpdateMandateUseCase(repo, testLogger())
+
+	amount, description := int64(2500), "rent + parking"
+	endAt := time.Now().Add(365 * 24 * time.Hour)
+	out, err := uc.Execute(context.Background(), usecase.UpdateMandateInput{
+		ID:          "mandate-1",
+		Amount:      &amount,
+		Description: &description,
+		EndAt:       &endAt,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Amount != amount || out.Description != description || out.EndAt == nil || !out.EndAt.Equal(endAt) {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	eventType, payload := repo.lastEvent(t)
+	if eventType != entity.EventMandateUpdated || payload["amount"] != float64(amount) {
+		t.Fatalf("unexpected event %s: %v", eventType, payload)
+	}
+}
+
+func TestUpdateMandateUseCase_Errors(t *testing.T) {
+	amount := int64(2500)
+
+	t.Run("not found", func(t *testing.T) {
+		uc := usecase.NewUpdateMandateUseCase(mandateRepoWith(nil), testLogger())
+		_, err := uc.Execute(context.Background(), usecase.UpdateMandateInput{ID: "mandate-1", Amount: &amount})
+		_ = assertException(t, err, http.StatusNotFound)
+	})
+
+	t.Run("invalid amount", func(t *testing.T) {
+		zero := int64(0)
+		uc := usecase.NewUpdateMandateUseCase(mandateRepoWith(mandateFixture()), testLogger())
+		_, err := uc.Execute(context.Background(), usecase.UpdateMandateInput{ID: "mandate-1", Amount: &zero})
+		_ = assertException(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("not active", func(t *testing.T) {
+		m := mandateFixture()
+		m.Status = entity.MandateEnded
+		uc := usecase.NewUpdateMandateUseCase(mandateRepoWith(m), testLogger())
+		_, err := uc.Execute(context.Background(), usecase.UpdateMandateInput{ID: "mandate-1", Amount: &amount})
+		_ = assertException(t, err, http.StatusConflict)
+	})
+
+	t.Run("changed concurrently", func(t *testing.T) {
+		repo := mandateRepoWith(mandateFixture())
+		repo.updateFn = func(_ context.Context, _ *entity.Mandate) error {
+			return entity.ErrMandateChanged
+		}
+		_, err := usecase.NewUpdateMandateUseCase(repo, testLogger()).Execute(context.Background(), usecase.UpdateMandateInput{ID: "mandate-1", Amount: &amount})
+		_ = assertException(t, err, http.StatusConflict)
+	})
+
+	t.Run("persist", func(t *testing.T) {
+		repo := mandateRepoWith(mandateFixture())
+		repo.updateFn = func(_ context.Context, _ *entity.Mandate) error {
+			return errors.New("db error")
+		}
+		_, err := usecase.NewUpdateMandateUseCase(repo, testLogger()).Execute(context.Background(), usecase.UpdateMandateInput{ID: "mandate-1", Amount: &amount})
+		_ = assertException(t, err, http.StatusInternalServerError)
+	})
+}
+
+func TestCancelMandateUseCase(t *testing.T) {
+	repo := mandateRepoWith(mandateFixture())
+	uc := usecase.NewCancelMandateUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), "mandate-1")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.Status != string(entity.MandateCancelled) {
+		t.Fatalf("expected CANCELLED, got %s", out.Status)
+	}
+	if eventType, _ := repo.lastEvent(t); eventType != entity.EventMandateCancelled {
+		t.Fatalf("expected %s, got %s", entity.EventMandateCancelled, eventType)
+	}
+
+	_, err = uc.Execute(context.Background(), "mandate-1")
+	_ = assertException(t, err, http.StatusConflict)
+
+	_, err = uc.Execute(context.Background(), "missing")
+	_ = assertException(t, err, http.StatusNotFound)
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/domain/ports"
+	apperrors "transaction-service/internal/core/errors"
+)
+
+type (
+	VoidOutput struct {
+		ID       string
+		Status   string
+		Released int64
+	}
+
+	VoidHoldUseCase struct {
+		repo   ports.TransactionRepository
+		logger *slog.Logger
+	}
+)
+
+func NewVoidHoldUseCase(repo ports.TransactionRepository, logger *slog.Logger) *VoidHoldUseCase {
+	return &VoidHoldUseCase{repo: repo, logger: logger}
+}
+
+// Execute releases an AUTHORIZED hold back to the sender and emits
+// TransactionVoided. A hold past its expiry that the scheduler has not swept
+// yet can still be voided.
+func (uc *VoidHoldUseCase) Execute(ctx context.Context, id string) (*VoidOutput, error) {
+	uc.logger.InfoContext(ctx, "void hold request", slog.String("transaction_id", id))
+
+	hold, err := findHold(ctx, uc.repo, uc.logger, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := hold.Void(time.Now().UTC()); err != nil {
+		uc.logger.WarnContext(ctx, "void rejected",
+			slog.String("transaction_id", hold.ID),
+			slog.String("reason", err.Error()),
+		)
+		return nil, holdRejection(err)
+	}
+
+	if err := releaseHold(ctx, uc.repo, hold, entity.EventTransactionVoided, entity.ActorAPI); err != nil {
+		if errors.Is(err, entity.ErrInvalidStatusTransition) {
+			uc.logger.WarnContext(ctx, "hold settled concurrently", slog.String("transaction_id", hold.ID))
+			return nil, holdRejection(err)
+		}
+		uc.logger.ErrorContext(ctx, "persist void failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+
+	uc.logger.InfoContext(ctx, "hold voided", slog.String("transaction_id", hold.ID))
+
+	return &VoidOutput{
+		ID:       hold.ID,
+		Status:   string(hold.Status),
+		Released: *hold.AuthorizedAmount,
+	}, nil
+}
+
+// releaseHold writes a voided or expired hold with its outbox event and the
+// status change made by actor.
+func releaseHold(ctx context.Context, repo ports.TransactionRepository, hold *entity.Transaction, eventType, actor string) error {
+	payload, err := json.Marshal(map[string]any{
+		"transactionId":  hold.ID,
+		"fromUserId":     hold.FromUserID,
+		"toUserId":       hold.ToUserID,
+		"currency":       hold.Currency,
+		"releasedAmount": hold.AuthorizedAmount,
+		"status":         hold.Status,
+		"expiresAt":      hold.ExpiresAt,
+		"processedAt":    hold.ProcessedAt,
+	})
+	if err != nil {
+		return err
+	}
+	change := entity.NewStatusChange(hold, entity.StatusAuthorized, actor, "")
+	return repo.ReleaseHold(ctx, hold, change, entity.NewOutbox(eventType, hold.ID, string(payload)))
+}
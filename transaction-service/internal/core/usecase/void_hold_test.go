@@ -0,0 +1,95 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"transaction-service/internal/core/domain/entity"
+	"transaction-service/internal/core/usecase"
+)
+
+func TestVoidHoldUseCase_ReleasesHold(t *testing.T) {
+	var (
+		persisted *entity.Transaction
+		outbox    *entity.Outbox
+	)
+	repo := holdRepository(holdFixture())
+	repo.releaseHoldFn = func(_ context.Context, tx *entity.Transaction, o *entity.Outbox) error {
+		persisted, outbox = tx, o
+		return nil
+	}
+	uc := usecase.NewVoidHoldUseCase(repo, testLogger())
+
+	out, err := uc.Execute(context.Background(), "tx-hold")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Status != string(entity.StatusVoided) || out.Released != 1000 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if persisted.Status != entity.StatusVoided || persisted.ProcessedAt == nil {
+		t.Fatalf("expected a processed VOIDED hold, got %+v", persisted)
+	}
+	if outbox.Type != entity.EventTransactionVoided {
+		t.Fatalf("expected event %s, got %s", entity.EventTransactionVoided, outbox.Type)
+	}
+	assertStatusChange(t, repo, "tx-hold", entity.StatusAuthorized, entity.StatusVoided, entity.ActorAPI)
+}
+
+func TestVoidHoldUseCase_VoidsExpiredHoldNotYetSwept(t *testing.T) {
+	hold := holdFixture()
+	past := time.Now().UTC().Add(-time.Minute)
+	hold.ExpiresAt = &past
+	uc := usecase.NewVoidHoldUseCase(holdRepository(hold), testLogger())
+
+	if _, err := uc.Execute(context.Background(), "tx-hold"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestVoidHoldUseCase_Errors(t *testing.T) {
+	captured := holdFixture()
+	captured.Status = entity.StatusCompleted
+
+	cases := []struct {
+		name       string
+		id         string
+		find       func(context.Context, string) (*entity.Transaction, error)
+		releaseErr error
+		code       int
+	}{
+		{"missing id", "", nil, nil, http.StatusBadRequest},
+		{"not found", "tx-hold", nil, nil, http.StatusNotFound},
+		{"find error", "tx-hold", func(context.Context, string) (*entity.Transaction, error) {
+			return nil, errors.New("db error")
+		}, nil, http.StatusInternalServerError},
+		{"not an open hold", "tx-hold", func(context.Context, string) (*entity.Transaction, error) {
+			return captured, nil
+		}, nil, http.StatusConflict},
+		{"settled concurrently", "tx-hold", func(context.Context, string) (*entity.Transaction, error) {
+			return holdFixture(), nil
+		}, entity.ErrInvalidStatusTransition, http.StatusConflict},
+		{"release error", "tx-hold", func(context.Context, string) (*entity.Transaction, error) {
+			return holdFixture(), nil
+		}, errors.New("db error"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTransactionRepository{
+				findByIDFn: tc.find,
+				releaseHoldFn: func(_ context.Context, _ *entity.Transaction, _ *entity.Outbox) error {
+					return tc.releaseErr
+				},
+			}
+			uc := usecase.NewVoidHoldUseCase(repo, testLogger())
+
+			_, err := uc.Execute(context.Background(), tc.id)
+
+			_ = assertException(t, err, tc.code)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+BEGIN;
+
+-- Every amount is in minor units of an ISO 4217 currency. Rows written
+-- before currencies existed were all in one unit and become USD; the
+-- defaults are dropped afterwards so no writer can leave the currency out.
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS currency        CHAR(3) NOT NULL DEFAULT 'USD',
+    ADD COLUMN IF NOT EXISTS to_currency     CHAR(3) NULL,
+    ADD COLUMN IF NOT EXISTS to_amount       BIGINT  NULL,
+    ADD COLUMN IF NOT EXISTS conversion_rate NUMERIC NULL;
+ALTER TABLE transactions ALTER COLUMN currency DROP DEFAULT;
+
+-- A transfer between currencies records the rate it was made at and what
+-- the receiver was credited; a plain transfer records neither.
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_transactions_conversion;
+ALTER TABLE transactions ADD CONSTRAINT chk_transactions_conversion CHECK (
+    (to_currency IS NULL AND to_amount IS NULL AND conversion_rate IS NULL)
+    OR (to_currency IS NOT NULL AND to_currency <> currency AND to_amount > 0 AND conversion_rate > 0)
+);
+
+-- Accounts and the balances projection are kept per user per currency.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE accounts ALTER COLUMN currency DROP DEFAULT;
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_pkey;
+ALTER TABLE accounts ADD CONSTRAINT accounts_pkey PRIMARY KEY (user_id, currency);
+
+ALTER TABLE balances ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE balances ALTER COLUMN currency DROP DEFAULT;
+ALTER TABLE balances DROP CONSTRAINT IF EXISTS balances_pkey;
+ALTER TABLE balances ADD CONSTRAINT balances_pkey PRIMARY KEY (user_id, currency);
+
+-- Adding a column with a default rewrites no rows, so the append-only
+-- trigger does not fire.
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE ledger_entries ALTER COLUMN currency DROP DEFAULT;
+
+-- Postings now balance per currency: a transfer between currencies goes
+-- through the FX clearing account, whose postings only net out within each
+-- currency.
+CREATE OR REPLACE FUNCTION ledger_entries_check_balanced() RETURNS trigger AS $$
+DECLARE
+    total BIGINT;
+BEGIN
+    SELECT COALESCE(SUM(amount), 0) INTO total
+    FROM ledger_entries
+    WHERE transaction_id = NEW.transaction_id
+      AND currency = NEW.currency;
+
+    IF total <> 0 THEN
+        RAISE EXCEPTION 'ledger postings for transaction % sum to % %', NEW.transaction_id, total, NEW.currency
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMIT;
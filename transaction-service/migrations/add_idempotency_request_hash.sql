@@ -0,0 +1,21 @@
+BEGIN;
+
+-- Fingerprint of the request that created a transaction with an
+-- idempotency key: a retry must match it, a reused key with another payload
+-- is rejected. Rows keyed before this migration have none and are trusted.
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS request_hash CHAR(64) NULL;
+
+-- Keys are scoped per sender instead of globally.
+DROP INDEX IF EXISTS uidx_transactions_idempotency_key;
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_transactions_from_user_idempotency_key
+    ON transactions (from_user_id, idempotency_key)
+    WHERE idempotency_key IS NOT NULL;
+
+-- Lets the scheduler find keys past their retention without scanning the
+-- transactions that never had one.
+CREATE INDEX IF NOT EXISTS idx_transactions_idempotency_created_at
+    ON transactions (created_at)
+    WHERE idempotency_key IS NOT NULL;
+
+COMMIT;
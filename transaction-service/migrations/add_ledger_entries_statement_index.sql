@@ -0,0 +1,11 @@
+BEGIN;
+
+-- GET /users/{userId}/statement sums a user's postings in one currency
+-- before the period and walks those within it in (created_at, id) order.
+-- It covers the single-column user index, which is dropped.
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_user_currency_created
+    ON ledger_entries (user_id, currency, created_at, id);
+
+DROP INDEX IF EXISTS idx_ledger_entries_user_id;
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+-- The decision of the risk rules on each transaction and the reasons of the
+-- rules that fired. NULL on transactions created before the rules existed
+-- and on reversals, which are never assessed.
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS risk_decision VARCHAR(10) NULL,
+    ADD COLUMN IF NOT EXISTS risk_reasons  TEXT[]      NULL;
+
+-- Lets the new-recipient rule find an earlier payment to the same receiver.
+CREATE INDEX IF NOT EXISTS idx_transactions_from_user_to_user
+    ON transactions (from_user_id, to_user_id)
+    WHERE transaction_status = 'COMPLETED';
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+-- Future-dated transfers. A SCHEDULED row has moved no funds; the scheduler
+-- releases it as PENDING once execute_at has passed.
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS execute_at TIMESTAMP NULL;
+
+-- Lets the scheduler find due transfers without scanning settled history.
+CREATE INDEX IF NOT EXISTS idx_transactions_scheduled_execute_at
+    ON transactions (execute_at)
+    WHERE transaction_status = 'SCHEDULED';
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Authorize/capture holds. An AUTHORIZED row has only debited the sender's
+-- account; capture credits the receiver and writes the ledger postings.
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS authorized_amount BIGINT NULL CHECK (authorized_amount > 0),
+    ADD COLUMN IF NOT EXISTS expires_at        TIMESTAMP NULL;
+
+-- Lets the scheduler find stale holds without scanning settled history.
+CREATE INDEX IF NOT EXISTS idx_transactions_authorized_expires_at
+    ON transactions (expires_at)
+    WHERE transaction_status = 'AUTHORIZED';
+
+COMMIT;
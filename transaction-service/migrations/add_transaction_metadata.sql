@@ -0,0 +1,13 @@
+BEGIN;
+
+-- Client-supplied references (order ID, invoice number, ...) as a flat
+-- object of string values. NULL when the client sent none.
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS metadata JSONB NULL;
+
+-- Answers metadata @> '{"key": "value"}' lookups; jsonb_path_ops indexes
+-- only containment, which keeps it smaller than the default operator class.
+CREATE INDEX IF NOT EXISTS idx_transactions_metadata
+    ON transactions USING GIN (metadata jsonb_path_ops);
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+-- Lexemes of the description for full-text search. A stored generated
+-- column stays in step with every insert; adding it rewrites the table.
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS description_tsv TSVECTOR
+        GENERATED ALWAYS AS (to_tsvector('english', description)) STORED;
+
+-- Answers description_tsv @@ query for GET /transactions:search.
+CREATE INDEX IF NOT EXISTS idx_transactions_description_tsv
+    ON transactions USING GIN (description_tsv);
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+-- A reversal is a COMPLETED transaction flowing back from the original
+-- receiver to the original sender. The sum of the reversals of a transaction
+-- never exceeds its amount; the repository enforces it under a row lock.
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS reversal_of UUID NULL REFERENCES transactions (id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_reversal_of
+    ON transactions (reversal_of)
+    WHERE reversal_of IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Keyset pagination of GET /users/{userId}/transactions walks one of these
+-- per direction in (created_at, id) DESC order. They cover the single-column
+-- user indexes, which are dropped.
+CREATE INDEX IF NOT EXISTS idx_transactions_from_user_created
+    ON transactions (from_user_id, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_to_user_created
+    ON transactions (to_user_id, created_at DESC, id DESC);
+
+DROP INDEX IF EXISTS idx_transactions_from_user_id;
+DROP INDEX IF EXISTS idx_transactions_to_user_id;
+
+COMMIT;
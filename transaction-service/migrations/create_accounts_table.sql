@@ -0,0 +1,35 @@
+BEGIN;
+
+-- Spendable balance per user. Transaction creation and settlement lock the
+-- rows with SELECT ... FOR UPDATE and create the missing ones at zero.
+CREATE TABLE IF NOT EXISTS accounts (
+    user_id     UUID PRIMARY KEY,
+    balance     BIGINT NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_accounts_balance_non_negative
+    CHECK (balance >= 0)
+);
+
+-- Open the accounts of the users with history at what it left them, like
+-- the ledger and the balances projection are backfilled. The sender is
+-- debited when a transaction is created and refunded if it fails, while the
+-- receiver is only paid once it completes: PENDING transfers count against
+-- their senders, and only COMPLETED ones count for their receivers. The
+-- amounts predate currencies and become USD with add_currency.sql. History
+-- from before funds were checked can net below zero; such an account opens
+-- empty rather than overdrawn. Seeded only while the table is empty, so a
+-- rerun never overwrites live balances.
+INSERT INTO accounts (user_id, balance)
+SELECT user_id, GREATEST(SUM(amount), 0)
+FROM transactions t
+CROSS JOIN LATERAL (VALUES
+    (t.from_user_id, -t.amount, TRUE),
+    (t.to_user_id, t.amount, t.transaction_status = 'COMPLETED')
+) AS p (user_id, amount, counted)
+WHERE t.transaction_status IN ('PENDING', 'COMPLETED')
+  AND p.counted
+  AND NOT EXISTS (SELECT 1 FROM accounts)
+GROUP BY user_id;
+
+COMMIT;
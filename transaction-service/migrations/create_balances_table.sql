@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Read model of ledger_entries: one row per user with the sum of their
+-- postings, updated in the same DB transaction that writes the postings.
+CREATE TABLE IF NOT EXISTS balances (
+    user_id     UUID PRIMARY KEY,
+    balance     BIGINT NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO balances (user_id, balance)
+SELECT user_id, SUM(amount)
+FROM ledger_entries
+GROUP BY user_id
+ON CONFLICT (user_id) DO UPDATE SET balance = EXCLUDED.balance, updated_at = NOW();
+
+COMMIT;
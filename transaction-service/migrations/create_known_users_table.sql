@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS known_users (
+    user_id          UUID PRIMARY KEY,
+    source_event_id  UUID NULL,
+    registered_at    TIMESTAMP NOT NULL,
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,63 @@
+BEGIN;
+
+-- Double-entry postings written when a transaction is COMPLETED. Debits are
+-- negative, credits positive; the postings of a transaction sum to zero.
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id  UUID NOT NULL REFERENCES transactions (id),
+    user_id         UUID NOT NULL,
+    amount          BIGINT NOT NULL CHECK (amount <> 0),
+    created_at      TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_transaction_id
+    ON ledger_entries (transaction_id);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_user_id
+    ON ledger_entries (user_id);
+
+-- Checked at commit so every posting of a transaction is visible at once.
+CREATE OR REPLACE FUNCTION ledger_entries_check_balanced() RETURNS trigger AS $$
+DECLARE
+    total BIGINT;
+BEGIN
+    SELECT COALESCE(SUM(amount), 0) INTO total
+    FROM ledger_entries
+    WHERE transaction_id = NEW.transaction_id;
+
+    IF total <> 0 THEN
+        RAISE EXCEPTION 'ledger postings for transaction % sum to %', NEW.transaction_id, total
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_ledger_entries_balanced ON ledger_entries;
+CREATE CONSTRAINT TRIGGER trg_ledger_entries_balanced
+    AFTER INSERT ON ledger_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger_entries_check_balanced();
+
+-- The books are append-only: corrections are new postings, never edits.
+CREATE OR REPLACE FUNCTION ledger_entries_reject_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger_entries is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_ledger_entries_append_only ON ledger_entries;
+CREATE TRIGGER trg_ledger_entries_append_only
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_entries_reject_change();
+
+-- Post the transactions completed before the ledger existed.
+INSERT INTO ledger_entries (transaction_id, user_id, amount, created_at)
+SELECT t.id, p.user_id, p.amount, COALESCE(t.processed_at, t.created_at)
+FROM transactions t
+CROSS JOIN LATERAL (VALUES (t.from_user_id, -t.amount), (t.to_user_id, t.amount)) AS p (user_id, amount)
+WHERE t.transaction_status = 'COMPLETED'
+  AND t.amount > 0
+  AND NOT EXISTS (SELECT 1 FROM ledger_entries l WHERE l.transaction_id = t.id);
+
+COMMIT;
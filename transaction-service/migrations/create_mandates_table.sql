@@ -0,0 +1,34 @@
+BEGIN;
+
+-- Recurring transfer mandates. The scheduler creates one transaction per
+-- occurrence through the regular create path, keyed by mandate and
+-- occurrence, then advances occurrence and next_run_at.
+CREATE TABLE IF NOT EXISTS mandates (
+    id           UUID        PRIMARY KEY,
+    from_user_id UUID        NOT NULL,
+    to_user_id   UUID        NOT NULL,
+    amount       BIGINT      NOT NULL CHECK (amount > 0),
+    currency     CHAR(3)     NOT NULL,
+    description  TEXT        NOT NULL DEFAULT '',
+    schedule     VARCHAR(64) NOT NULL,
+    start_at     TIMESTAMP   NOT NULL,
+    end_at       TIMESTAMP   NULL,
+    next_run_at  TIMESTAMP   NOT NULL,
+    occurrence   BIGINT      NOT NULL DEFAULT 0,
+    status       VARCHAR(20) NOT NULL,
+    version      BIGINT      NOT NULL DEFAULT 0,
+    created_at   TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP   NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_mandates_users_different CHECK (from_user_id <> to_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mandates_from_user_created
+    ON mandates (from_user_id, created_at DESC);
+
+-- Lets the scheduler find due mandates without scanning ended ones.
+CREATE INDEX IF NOT EXISTS idx_mandates_active_next_run_at
+    ON mandates (next_run_at)
+    WHERE status = 'ACTIVE';
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+-- Outgoing transfer limits per currency. The row without a user_id holds the
+-- defaults for its currency; a user's row overrides the columns it sets.
+-- NULL means unlimited, or for an override, the default. Amounts are minor
+-- units of currency.
+CREATE TABLE IF NOT EXISTS transaction_limits (
+    user_id          UUID      NULL,
+    currency         CHAR(3)   NOT NULL,
+    max_amount       BIGINT    NULL CHECK (max_amount > 0),
+    daily_outgoing   BIGINT    NULL CHECK (daily_outgoing > 0),
+    monthly_outgoing BIGINT    NULL CHECK (monthly_outgoing > 0),
+    hourly_transfers BIGINT    NULL CHECK (hourly_transfers > 0),
+    updated_at       TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_transaction_limits_user_currency
+    ON transaction_limits (user_id, currency)
+    WHERE user_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_transaction_limits_default_currency
+    ON transaction_limits (currency)
+    WHERE user_id IS NULL;
+
+INSERT INTO transaction_limits (user_id, currency, max_amount, daily_outgoing, monthly_outgoing, hourly_transfers)
+VALUES (NULL, 'USD', 100000000, 500000000, 2000000000, 600)
+ON CONFLICT DO NOTHING;
+
+COMMIT;
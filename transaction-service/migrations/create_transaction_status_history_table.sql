@@ -0,0 +1,30 @@
+BEGIN;
+
+-- One row per status a transaction entered, written in the same DB
+-- transaction as the change. from_status is NULL for the status the
+-- transaction was created with; seq orders changes made at the same instant.
+CREATE TABLE IF NOT EXISTS transaction_status_history (
+    id             UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    seq            BIGSERIAL    NOT NULL,
+    transaction_id UUID         NOT NULL REFERENCES transactions (id),
+    from_status    VARCHAR(50)  NULL,
+    to_status      VARCHAR(50)  NOT NULL,
+    actor          VARCHAR(50)  NOT NULL,
+    reason         TEXT         NOT NULL DEFAULT '',
+    changed_at     TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_status_history_transaction
+    ON transaction_status_history (transaction_id, changed_at, seq);
+
+-- Transactions that predate the history get a single entry for the status
+-- they are in now; the path that led there was never recorded.
+INSERT INTO transaction_status_history (transaction_id, to_status, actor, reason, changed_at)
+SELECT t.id, t.transaction_status, 'migration', 'status before history was recorded',
+       COALESCE(t.processed_at, t.created_at)
+FROM transactions t
+WHERE NOT EXISTS (
+    SELECT 1 FROM transaction_status_history h WHERE h.transaction_id = t.id
+);
+
+COMMIT;
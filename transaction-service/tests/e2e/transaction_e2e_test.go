@@ -4,26 +4,46 @@ package e2e_test
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 
 	"transaction-service/infra/repository"
+	"transaction-service/internal/core/domain/entity"
+	apperrors "transaction-service/internal/core/errors"
 	"transaction-service/internal/core/handler"
+	"transaction-service/internal/core/risk"
 	"transaction-service/internal/core/usecase"
 )
 
-var testServer *httptest.Server
+var (
+	testServer *httptest.Server
+	testDB     *sql.DB
+	riskEngine *risk.Engine
+)
+
+// The suite's risk rules deny transfers involving blockedUser and hold first
+// payments of at least reviewAmount USD to a recipient for review.
+var blockedUser = uuid.NewString()
+
+const reviewAmount = 50_000_000
 
 func TestMain(m *testing.M) {
 	db, err := connectDB()
@@ -39,12 +59,18 @@ func TestMain(m *testing.M) {
 	}
 
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	testDB = db
 	repo := repository.NewTransactionRepository(db)
-	f := usecase.NewFactory(repo, logger)
+	riskEngine = risk.NewEngine(risk.Rules{
+		Blocklist:    []string{blockedUser},
+		NewRecipient: &risk.NewRecipientRule{MinAmount: map[string]int64{"USD": reviewAmount}, Decision: entity.RiskReview},
+	}, repository.NewRiskHistoryRepository(db))
+	f := usecase.NewFactory(repo, repository.NewMandateRepository(db), repository.NewKnownUserRepository(db), riskEngine, logger)
 	h := handler.NewHandlerFactory(f)
 
 	mux := http.NewServeMux()
 	h.RegisterRoutes(mux)
+	handler.NewMandateHandlerFactory(f).RegisterRoutes(mux)
 	testServer = httptest.NewServer(mux)
 	defer testServer.Close()
 
@@ -79,6 +105,24 @@ var migrationOrder = []string{
 	"create_outbox_table.sql",
 	"add_idempotency_key.sql",
 	"add_outbox_retry.sql",
+	"create_known_users_table.sql",
+	"add_outbox_aggregate_id.sql",
+	"create_accounts_table.sql",
+	"create_ledger_entries_table.sql",
+	"create_balances_table.sql",
+	"add_transactions_user_listing_indexes.sql",
+	"add_transactions_reversal_of.sql",
+	"add_transaction_holds.sql",
+	"add_currency.sql",
+	"create_transaction_status_history_table.sql",
+	"add_idempotency_request_hash.sql",
+	"add_scheduled_transactions.sql",
+	"create_mandates_table.sql",
+	"create_transaction_limits_table.sql",
+	"add_risk_decisions.sql",
+	"add_ledger_entries_statement_index.sql",
+	"add_transaction_metadata.sql",
+	"add_transaction_search.sql",
 }
 
 func runMigrations(db *sql.DB) error {
@@ -144,6 +188,65 @@ func decodeResponse(t *testing.T, resp *http.Response) apiResponse {
 	return out
 }
 
+// newKnownUser registers a fresh user in the known_users projection, the way
+// the users-service UserCreated consumer would, and returns its ID.
+func newKnownUser(t *testing.T) string {
+	t.Helper()
+	id := uuid.NewString()
+	if _, err := testDB.Exec(
+		`INSERT INTO known_users (user_id, registered_at) VALUES ($1, NOW())`, id,
+	); err != nil {
+		t.Fatalf("seed known user: %v", err)
+	}
+	return id
+}
+
+// newFundedUser registers a known user and deposits balance USD into their
+// account the way admin fund does, so the account, the ledger and the
+// balances projection all hold it.
+func newFundedUser(t *testing.T, balance int64) string {
+	t.Helper()
+	id := newKnownUser(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	fund := usecase.NewFundAccountUseCase(
+		repository.NewTransactionRepository(testDB), repository.NewKnownUserRepository(testDB), logger,
+	)
+	if _, err := fund.Execute(context.Background(), usecase.FundInput{
+		UserID: id, Amount: balance, Description: "e2e deposit", Operator: "e2e",
+	}); err != nil {
+		t.Fatalf("fund account: %v", err)
+	}
+	return id
+}
+
+// depositOf returns the ID of the deposit newFundedUser made for userID.
+func depositOf(t *testing.T, userID string) string {
+	t.Helper()
+	var id string
+	if err := testDB.QueryRow(
+		`SELECT id FROM transactions WHERE from_user_id = $1 AND to_user_id = $2`, entity.FundingAccount, userID,
+	).Scan(&id); err != nil {
+		t.Fatalf("find deposit: %v", err)
+	}
+	return id
+}
+
+func accountBalance(t *testing.T, userID string) int64 {
+	t.Helper()
+	return currencyAccountBalance(t, userID, "USD")
+}
+
+func currencyAccountBalance(t *testing.T, userID, currency string) int64 {
+	t.Helper()
+	var balance int64
+	if err := testDB.QueryRow(
+		`SELECT balance FROM accounts WHERE user_id = $1 AND currency = $2`, userID, currency,
+	).Scan(&balance); err != nil {
+		t.Fatalf("read account balance: %v", err)
+	}
+	return balance
+}
+
 // createTransaction is a helper that creates a transaction and returns its ID.
 func createTransaction(t *testing.T, fromUser, toUser string, amount int64) string {
 	t.Helper()
@@ -163,11 +266,30 @@ func createTransaction(t *testing.T, fromUser, toUser string, amount int64) stri
 	return id
 }
 
+// settleTransaction runs settlement the way cmd/settlement would on the
+// TransactionCreated event of id.
+func settleTransaction(t *testing.T, id string) *usecase.SettleOutput {
+	t.Helper()
+	return settleScreened(t, id, riskEngine)
+}
+
+// settleScreened settles id screening its parties with engine.
+func settleScreened(t *testing.T, id string, engine *risk.Engine) *usecase.SettleOutput {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	settle := usecase.NewSettleTransactionUseCase(repository.NewTransactionRepository(testDB), engine, logger)
+	out, err := settle.Execute(context.Background(), usecase.SettleInput{TransactionID: id})
+	if err != nil {
+		t.Fatalf("settle: %v", err)
+	}
+	return out
+}
+
 // ── Test cases ─────────────────────────────────────────────────────────────
 
 func TestE2E_CreateTransaction_Returns201(t *testing.T) {
-	fromUser := uuid.NewString()
-	toUser := uuid.NewString()
+	fromUser := newFundedUser(t, 500)
+	toUser := newKnownUser(t)
 
 	resp := doPost(t, "/api/v1/transactions", map[string]any{
 		"from_user_id": fromUser,
@@ -204,7 +326,7 @@ func TestE2E_CreateTransaction_InvalidBody_Returns400(t *testing.T) {
 }
 
 func TestE2E_CreateTransaction_SameUser_Returns400(t *testing.T) {
-	sameUser := uuid.NewString()
+	sameUser := newKnownUser(t)
 
 	resp := doPost(t, "/api/v1/transactions", map[string]any{
 		"from_user_id": sameUser,
@@ -218,8 +340,8 @@ func TestE2E_CreateTransaction_SameUser_Returns400(t *testing.T) {
 }
 
 func TestE2E_CreateTransaction_IdempotentRequest_Returns200(t *testing.T) {
-	fromUser := uuid.NewString()
-	toUser := uuid.NewString()
+	fromUser := newFundedUser(t, 750)
+	toUser := newKnownUser(t)
 	idempotencyKey := uuid.NewString()
 
 	payload := map[string]any{
@@ -248,9 +370,285 @@ func TestE2E_CreateTransaction_IdempotentRequest_Returns200(t *testing.T) {
 	}
 }
 
+func TestE2E_CreateTransaction_IdempotencyKeyReusedWithOtherPayload_Returns422(t *testing.T) {
+	fromUser := newFundedUser(t, 500)
+	toUser := newKnownUser(t)
+	headers := map[string]string{"Idempotency-Key": uuid.NewString()}
+
+	first := doPost(t, "/api/v1/transactions", map[string]any{
+		"from_user_id": fromUser, "to_user_id": toUser, "amount": 200,
+	}, headers)
+	if first.StatusCode != http.StatusCreated {
+		t.Fatalf("first request: expected 201, got %d", first.StatusCode)
+	}
+	first.Body.Close()
+
+	reused := doPost(t, "/api/v1/transactions", map[string]any{
+		"from_user_id": fromUser, "to_user_id": toUser, "amount": 300,
+	}, headers)
+	if reused.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("reused key: expected 422, got %d", reused.StatusCode)
+	}
+	reused.Body.Close()
+
+	if got := accountBalance(t, fromUser); got != 300 {
+		t.Fatalf("expected only the first transfer to move funds, sender has %d", got)
+	}
+}
+
+func TestE2E_CreateTransaction_IdempotencyKeysAreScopedPerSender(t *testing.T) {
+	toUser := newKnownUser(t)
+	headers := map[string]string{"Idempotency-Key": uuid.NewString()}
+
+	var ids []any
+	for range 2 {
+		fromUser := newFundedUser(t, 100)
+		resp := doPost(t, "/api/v1/transactions", map[string]any{
+			"from_user_id": fromUser, "to_user_id": toUser, "amount": 100,
+		}, headers)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201 for each sender, got %d", resp.StatusCode)
+		}
+		ids = append(ids, decodeResponse(t, resp).Data["id"])
+	}
+	if ids[0] == ids[1] {
+		t.Fatal("expected one transaction per sender")
+	}
+}
+
+func TestE2E_ReleaseIdempotencyKeys_FreesKeyForReuse(t *testing.T) {
+	fromUser := newFundedUser(t, 200)
+	toUser := newKnownUser(t)
+	key := uuid.NewString()
+	headers := map[string]string{"Idempotency-Key": key}
+	payload := map[string]any{"from_user_id": fromUser, "to_user_id": toUser, "amount": 100}
+
+	first := doPost(t, "/api/v1/transactions", payload, headers)
+	if first.StatusCode != http.StatusCreated {
+		t.Fatalf("first request: expected 201, got %d", first.StatusCode)
+	}
+	first.Body.Close()
+
+	// Age the transaction past the retention period.
+	if _, err := testDB.Exec(
+		`UPDATE transactions SET created_at = created_at - INTERVAL '2 hours' WHERE from_user_id = $1`, fromUser,
+	); err != nil {
+		t.Fatalf("age transaction: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	release := usecase.NewReleaseIdempotencyKeysUseCase(repository.NewTransactionRepository(testDB), logger)
+	if _, err := release.Execute(context.Background(), usecase.ReleaseIdempotencyKeysInput{Retention: time.Hour}); err != nil {
+		t.Fatalf("release keys: %v", err)
+	}
+
+	second := doPost(t, "/api/v1/transactions", payload, headers)
+	if second.StatusCode != http.StatusCreated {
+		t.Fatalf("after release: expected a new transaction (201), got %d", second.StatusCode)
+	}
+	second.Body.Close()
+}
+
+func TestE2E_CreateTransaction_UnknownUser_Returns422(t *testing.T) {
+	fromUser := newKnownUser(t)
+
+	resp := doPost(t, "/api/v1/transactions", map[string]any{
+		"from_user_id": fromUser,
+		"to_user_id":   uuid.NewString(),
+		"amount":       100,
+	}, nil)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", resp.StatusCode)
+	}
+}
+
+func TestE2E_CreateTransaction_InsufficientFunds_Returns422(t *testing.T) {
+	fromUser := newFundedUser(t, 99)
+	toUser := newKnownUser(t)
+
+	resp := doPost(t, "/api/v1/transactions", map[string]any{
+		"from_user_id": fromUser,
+		"to_user_id":   toUser,
+		"amount":       100,
+	}, nil)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", resp.StatusCode)
+	}
+	if got := accountBalance(t, fromUser); got != 99 {
+		t.Fatalf("expected sender balance 99 after rejection, got %d", got)
+	}
+}
+
+// TestE2E_CreateTransaction_ConcurrentDebits_NeverOverdraws fires more
+// transfers than the sender can afford at the same time; exactly the
+// affordable ones must succeed.
+func TestE2E_CreateTransaction_ConcurrentDebits_NeverOverdraws(t *testing.T) {
+	const (
+		workers = 40
+		amount  = 100
+		funded  = 10 * amount
+	)
+	fromUser := newFundedUser(t, funded)
+	toUser := newKnownUser(t)
+
+	codes := make([]int, workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := doPost(t, "/api/v1/transactions", map[string]any{
+				"from_user_id": fromUser,
+				"to_user_id":   toUser,
+				"amount":       amount,
+			}, nil)
+			resp.Body.Close()
+			codes[i] = resp.StatusCode
+		}()
+	}
+	wg.Wait()
+
+	created, rejected := 0, 0
+	for _, code := range codes {
+		switch code {
+		case http.StatusCreated:
+			created++
+		case http.StatusUnprocessableEntity:
+			rejected++
+		default:
+			t.Fatalf("unexpected status %d", code)
+		}
+	}
+	if created != funded/amount || rejected != workers-created {
+		t.Fatalf("expected %d created and %d rejected, got %d and %d",
+			funded/amount, workers-funded/amount, created, rejected)
+	}
+	if got := accountBalance(t, fromUser); got != 0 {
+		t.Fatalf("expected sender balance 0, got %d", got)
+	}
+	if got := accountBalance(t, toUser); got != 0 {
+		t.Fatalf("expected the receiver paid only on settlement, got %d", got)
+	}
+}
+
+// TestE2E_CreateTransaction_OppositeTransfers_DoNotDeadlock sends money back
+// and forth between two accounts concurrently; the sorted lock order must keep
+// every request from failing with a deadlock.
+func TestE2E_CreateTransaction_ConcurrentDuplicates_CreateOnce(t *testing.T) {
+	const (
+		workers = 20
+		amount  = 100
+	)
+	// Funded for exactly one transfer: a duplicate that is not recognised
+	// as one would be rejected for insufficient funds instead of replayed.
+	fromUser := newFundedUser(t, amount)
+	toUser := newKnownUser(t)
+	headers := map[string]string{"Idempotency-Key": uuid.NewString()}
+
+	codes := make([]int, workers)
+	ids := make([]any, workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := doPost(t, "/api/v1/transactions", map[string]any{
+				"from_user_id": fromUser,
+				"to_user_id":   toUser,
+				"amount":       amount,
+			}, headers)
+			codes[i] = resp.StatusCode
+			ids[i] = decodeResponse(t, resp).Data["id"]
+		}()
+	}
+	wg.Wait()
+
+	created := 0
+	for i, code := range codes {
+		switch code {
+		case http.StatusCreated:
+			created++
+		case http.StatusOK:
+		default:
+			t.Fatalf("request %d: expected 201 or 200, got %d", i, code)
+		}
+		if ids[i] != ids[0] {
+			t.Fatalf("request %d: expected transaction %v, got %v", i, ids[0], ids[i])
+		}
+	}
+	if created != 1 {
+		t.Fatalf("expected exactly one 201, got %d", created)
+	}
+	if got := accountBalance(t, fromUser); got != 0 {
+		t.Fatalf("expected the sender debited once, balance %d", got)
+	}
+}
+
+func TestE2E_Repository_CreateReportsDuplicateIdempotencyKey(t *testing.T) {
+	fromUser := newFundedUser(t, 200)
+	toUser := newKnownUser(t)
+	repo := repository.NewTransactionRepository(testDB)
+	key := uuid.NewString()
+
+	for i := range 2 {
+		tx, _ := entity.NewTransaction(fromUser, toUser, 100, "USD", "")
+		tx.IdempotencyKey = &key
+		err := repo.Create(context.Background(), tx, entity.NewStatusChange(tx, "", entity.ActorAPI, ""),
+			entity.NewOutbox(entity.EventTransactionCreated, tx.ID, "{}"))
+		switch {
+		case i == 0 && err != nil:
+			t.Fatalf("first create: %v", err)
+		case i == 1 && !errors.Is(err, entity.ErrDuplicateIdempotencyKey):
+			t.Fatalf("second create: expected ErrDuplicateIdempotencyKey, got %v", err)
+		}
+	}
+	if got := accountBalance(t, fromUser); got != 100 {
+		t.Fatalf("expected the duplicate to move no funds, sender has %d", got)
+	}
+}
+
+func TestE2E_CreateTransaction_OppositeTransfers_DoNotDeadlock(t *testing.T) {
+	const workers = 20
+	userA := newFundedUser(t, workers)
+	userB := newFundedUser(t, workers)
+
+	codes := make([]int, 2*workers)
+	var wg sync.WaitGroup
+	for i := range 2 * workers {
+		from, to := userA, userB
+		if i%2 == 1 {
+			from, to = userB, userA
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := doPost(t, "/api/v1/transactions", map[string]any{
+				"from_user_id": from,
+				"to_user_id":   to,
+				"amount":       1,
+			}, nil)
+			resp.Body.Close()
+			codes[i] = resp.StatusCode
+		}()
+	}
+	wg.Wait()
+
+	for i, code := range codes {
+		if code != http.StatusCreated {
+			t.Fatalf("request %d: expected 201, got %d", i, code)
+		}
+	}
+	if a, b := accountBalance(t, userA), accountBalance(t, userB); a != 0 || b != 0 {
+		t.Fatalf("expected both senders fully debited until settlement, got %d and %d", a, b)
+	}
+}
+
 func TestE2E_GetTransactionStatus_Returns200(t *testing.T) {
-	fromUser := uuid.NewString()
-	toUser := uuid.NewString()
+	fromUser := newFundedUser(t, 300)
+	toUser := newKnownUser(t)
 	id := createTransaction(t, fromUser, toUser, 300)
 
 	resp := doGet(t, "/api/v1/transactions/"+id)
@@ -290,9 +688,72 @@ func TestE2E_GetBalance_Returns200(t *testing.T) {
 	}
 }
 
+// balanceAsOf reads userID's USD balance at asOf and checks the cutoff the
+// response reports.
+func balanceAsOf(t *testing.T, userID string, asOf time.Time) float64 {
+	t.Helper()
+	resp := doGet(t, "/api/v1/balance/"+userID+"?as_of="+url.QueryEscape(asOf.Format(time.RFC3339Nano)))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("balance as of %s: expected 200, got %d", asOf, resp.StatusCode)
+	}
+	body := decodeResponse(t, resp)
+	if cutoff, _ := time.Parse(time.RFC3339Nano, body.Data["AsOf"].(string)); !cutoff.Equal(asOf) {
+		t.Fatalf("expected the cutoff %s reported, got %v", asOf, body.Data["AsOf"])
+	}
+	return body.Data["Balance"].(float64)
+}
+
+func transactionTimes(t *testing.T, id string) (created, processed time.Time) {
+	t.Helper()
+	if err := testDB.QueryRow(
+		`SELECT created_at, processed_at FROM transactions WHERE id = $1`, id,
+	).Scan(&created, &processed); err != nil {
+		t.Fatalf("read transaction times: %v", err)
+	}
+	return created, processed
+}
+
+func TestE2E_GetBalance_AsOfCountsTransactionsByProcessedAt(t *testing.T) {
+	fromUser := newFundedUser(t, 1000)
+	toUser := newKnownUser(t)
+
+	first := createTransaction(t, fromUser, toUser, 300)
+	settleTransaction(t, first)
+	second := createTransaction(t, fromUser, toUser, 200)
+	settleTransaction(t, second)
+	_, firstProcessed := transactionTimes(t, first)
+	secondCreated, secondProcessed := transactionTimes(t, second)
+
+	justBefore := secondProcessed.Add(-time.Microsecond)
+	if !secondCreated.Before(justBefore) {
+		t.Fatalf("expected the second transfer created before it was processed, got %s and %s", secondCreated, secondProcessed)
+	}
+
+	cases := []struct {
+		name string
+		asOf time.Time
+		want float64
+	}{
+		{"before the first settlement", firstProcessed.Add(-time.Microsecond), 0},
+		{"at the first settlement", firstProcessed, 300},
+		{"second created but not yet processed", justBefore, 300},
+		{"at the second settlement", secondProcessed, 500},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := balanceAsOf(t, toUser, tc.asOf); got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+			if got := balanceAsOf(t, fromUser, tc.asOf); got != 1000-tc.want {
+				t.Fatalf("expected the sender at %v, got %v", 1000-tc.want, got)
+			}
+		})
+	}
+}
+
 func TestE2E_GetTransactionStatus_PersistsInDatabase(t *testing.T) {
-	fromUser := uuid.NewString()
-	toUser := uuid.NewString()
+	fromUser := newFundedUser(t, 1000)
+	toUser := newKnownUser(t)
 	id := createTransaction(t, fromUser, toUser, 1000)
 
 	// Retrieve the same transaction twice to confirm persistence
@@ -304,3 +765,1410 @@ func TestE2E_GetTransactionStatus_PersistsInDatabase(t *testing.T) {
 		resp.Body.Close()
 	}
 }
+
+func TestE2E_GetTransactionHistory_RecordsEachTransition(t *testing.T) {
+	fromUser := newFundedUser(t, 400)
+	toUser := newKnownUser(t)
+	id := createTransaction(t, fromUser, toUser, 400)
+	settleTransaction(t, id)
+
+	body := decodeResponse(t, doGet(t, "/api/v1/transactions/"+id))
+	if body.Data["status"] != "COMPLETED" || body.Data["amount"] != float64(400) ||
+		body.Data["from_user_id"] != fromUser || body.Data["to_user_id"] != toUser || body.Data["processed_at"] == nil {
+		t.Fatalf("expected the full settled transaction, got %v", body.Data)
+	}
+
+	resp := doGet(t, "/api/v1/transactions/"+id+"/history")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	changes, _ := decodeResponse(t, resp).Data["changes"].([]any)
+	want := []struct{ from, to, actor string }{
+		{"", "PENDING", entity.ActorAPI},
+		{"PENDING", "COMPLETED", entity.ActorSettlement},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d status changes, got %v", len(want), changes)
+	}
+	for i, w := range want {
+		c, _ := changes[i].(map[string]any)
+		from, _ := c["from_status"].(string)
+		if from != w.from || c["to_status"] != w.to || c["actor"] != w.actor {
+			t.Fatalf("change %d: expected %q -> %q by %s, got %v", i, w.from, w.to, w.actor, c)
+		}
+	}
+}
+
+func TestE2E_Settlement_WritesBalancedPostings(t *testing.T) {
+	fromUser := newFundedUser(t, 400)
+	toUser := newKnownUser(t)
+	id := createTransaction(t, fromUser, toUser, 400)
+	if got := accountBalance(t, toUser); got != 0 {
+		t.Fatalf("expected the receiver unpaid while PENDING, got %d", got)
+	}
+
+	out := settleTransaction(t, id)
+	if out.Status != string(entity.StatusCompleted) {
+		t.Fatalf("expected COMPLETED, got %s", out.Status)
+	}
+	if got := accountBalance(t, toUser); got != 400 {
+		t.Fatalf("expected settlement to pay the receiver 400, got %d", got)
+	}
+
+	var entries int
+	var sum int64
+	if err := testDB.QueryRow(
+		`SELECT COUNT(*), COALESCE(SUM(amount), 0) FROM ledger_entries WHERE transaction_id = $1`, id,
+	).Scan(&entries, &sum); err != nil {
+		t.Fatalf("read postings: %v", err)
+	}
+	if entries != 2 || sum != 0 {
+		t.Fatalf("expected 2 postings summing to 0, got %d summing to %d", entries, sum)
+	}
+
+	// The sender's deposit of 400 is on the ledger too, so both ledger
+	// balances match what the accounts hold.
+	for user, want := range map[string]float64{fromUser: 0, toUser: 400} {
+		body := decodeResponse(t, doGet(t, "/api/v1/balance/"+user))
+		if body.Data["Balance"] != want {
+			t.Fatalf("expected balance %v for %s, got %v", want, user, body.Data["Balance"])
+		}
+	}
+
+	var projected int64
+	if err := testDB.QueryRow(`SELECT balance FROM balances WHERE user_id = $1 AND currency = 'USD'`, toUser).Scan(&projected); err != nil {
+		t.Fatalf("read projected balance: %v", err)
+	}
+	if projected != 400 {
+		t.Fatalf("expected projected balance 400, got %d", projected)
+	}
+
+	imbalances, err := repository.NewLedgerRepository(testDB).FindImbalances(context.Background())
+	if err != nil {
+		t.Fatalf("find imbalances: %v", err)
+	}
+	for _, i := range imbalances {
+		if i.TransactionID == id {
+			t.Fatalf("settled transaction reported as imbalanced: %+v", i)
+		}
+	}
+}
+
+func TestE2E_Fund_CreditsAccountLedgerAndBalances(t *testing.T) {
+	user := newFundedUser(t, 700)
+	deposit := depositOf(t, user)
+
+	if ledger, available := balances(t, user); ledger != 700 || available != 700 {
+		t.Fatalf("expected ledger and available at 700, got %v and %v", ledger, available)
+	}
+	if hasDrift(t, repository.NewLedgerRepository(testDB), user) {
+		t.Fatal("expected the projection to match the postings")
+	}
+	if got := outboxEvents(t, deposit, entity.EventAccountFunded); got != 1 {
+		t.Fatalf("expected one AccountFunded event, got %d", got)
+	}
+
+	changes, _ := decodeResponse(t, doGet(t, "/api/v1/transactions/"+deposit+"/history")).Data["changes"].([]any)
+	if len(changes) != 1 {
+		t.Fatalf("expected one status change, got %v", changes)
+	}
+	if c, _ := changes[0].(map[string]any); c["to_status"] != "COMPLETED" || c["actor"] != entity.ActorAdmin || c["reason"] != "funded by e2e" {
+		t.Fatalf("expected the deposit completed by the operator, got %v", c)
+	}
+}
+
+func TestE2E_Settlement_FailsTransferToANewlyBlocklistedUser(t *testing.T) {
+	fromUser := newFundedUser(t, 500)
+	toUser := newKnownUser(t)
+	id := createTransaction(t, fromUser, toUser, 300)
+
+	// The recipient is blocklisted after the transfer was accepted.
+	engine := risk.NewEngine(risk.Rules{Blocklist: []string{toUser}}, repository.NewRiskHistoryRepository(testDB))
+	out := settleScreened(t, id, engine)
+	if !out.Settled || out.Status != string(entity.StatusFailed) {
+		t.Fatalf("expected the transfer FAILED, got %+v", out)
+	}
+
+	if got := accountBalance(t, fromUser); got != 500 {
+		t.Fatalf("expected the sender refunded to 500, got %d", got)
+	}
+	if ledger, available := balances(t, toUser); ledger != 0 || available != 0 {
+		t.Fatalf("expected nothing paid to the receiver, got ledger %v available %v", ledger, available)
+	}
+	var entries int
+	if err := testDB.QueryRow(`SELECT COUNT(*) FROM ledger_entries WHERE transaction_id = $1`, id).Scan(&entries); err != nil {
+		t.Fatalf("count postings: %v", err)
+	}
+	if entries != 0 {
+		t.Fatalf("expected no postings for a failed transfer, got %d", entries)
+	}
+	if got := outboxEvents(t, id, entity.EventTransactionFailed); got != 1 {
+		t.Fatalf("expected one TransactionFailed event, got %d", got)
+	}
+
+	changes, _ := decodeResponse(t, doGet(t, "/api/v1/transactions/"+id+"/history")).Data["changes"].([]any)
+	if last, _ := changes[len(changes)-1].(map[string]any); last["to_status"] != "FAILED" || last["reason"] != "recipient is blocklisted" {
+		t.Fatalf("expected the failure reason in the history, got %v", changes)
+	}
+}
+
+func TestE2E_Ledger_RejectsUnbalancedPostings(t *testing.T) {
+	fromUser := newFundedUser(t, 100)
+	toUser := newKnownUser(t)
+	id := createTransaction(t, fromUser, toUser, 100)
+
+	_, err := testDB.Exec(
+		`INSERT INTO ledger_entries (transaction_id, user_id, currency, amount) VALUES ($1, $2, 'USD', -100)`, id, fromUser,
+	)
+	if err == nil {
+		t.Fatal("expected the balance trigger to reject a lone debit")
+	}
+}
+
+func TestE2E_RebuildBalances_RepairsDrift(t *testing.T) {
+	fromUser := newFundedUser(t, 250)
+	toUser := newKnownUser(t)
+	id := createTransaction(t, fromUser, toUser, 250)
+
+	settleTransaction(t, id)
+
+	if _, err := testDB.Exec(`UPDATE balances SET balance = 0 WHERE user_id = $1 AND currency = 'USD'`, toUser); err != nil {
+		t.Fatalf("corrupt projection: %v", err)
+	}
+
+	ledger := repository.NewLedgerRepository(testDB)
+	if !hasDrift(t, ledger, toUser) {
+		t.Fatal("expected drift for the corrupted user")
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if _, err := usecase.NewRebuildBalancesUseCase(ledger, logger).Execute(context.Background()); err != nil {
+		t.Fatalf("rebuild balances: %v", err)
+	}
+	if hasDrift(t, ledger, toUser) {
+		t.Fatal("expected no drift after rebuild")
+	}
+}
+
+func hasDrift(t *testing.T, ledger *repository.PostgresLedgerRepository, userID string) bool {
+	t.Helper()
+	drifts, err := ledger.FindBalanceDrift(context.Background())
+	if err != nil {
+		t.Fatalf("find balance drift: %v", err)
+	}
+	for _, d := range drifts {
+		if d.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func TestE2E_ListUserTransactions_PaginatesNewestFirst(t *testing.T) {
+	user := newFundedUser(t, 1000)
+	other := newFundedUser(t, 1000)
+
+	// The deposit that funded user is the oldest transaction they have.
+	created := []string{depositOf(t, user)}
+	for i := range 5 {
+		from, to := user, other
+		if i%2 == 1 {
+			from, to = other, user
+		}
+		created = append(created, createTransaction(t, from, to, int64(10*(i+1))))
+	}
+
+	var seen []string
+	cursor := ""
+	for page := 0; ; page++ {
+		if page > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+		path := "/api/v1/users/" + user + "/transactions?limit=2"
+		if cursor != "" {
+			path += "&cursor=" + cursor
+		}
+		resp := doGet(t, path)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		body := decodeResponse(t, resp)
+		for _, item := range body.Data["items"].([]any) {
+			seen = append(seen, item.(map[string]any)["id"].(string))
+		}
+		next, _ := body.Data["next_cursor"].(string)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if len(seen) != len(created) {
+		t.Fatalf("expected %d transactions across pages, got %d", len(created), len(seen))
+	}
+	for i, id := range seen {
+		if want := created[len(created)-1-i]; id != want {
+			t.Fatalf("position %d: expected %s (newest first), got %s", i, want, id)
+		}
+	}
+
+	resp := doGet(t, "/api/v1/users/"+user+"/transactions?direction=received&min_amount=30&max_amount=100")
+	body := decodeResponse(t, resp)
+	items := body.Data["items"].([]any)
+	if len(items) != 1 || items[0].(map[string]any)["id"] != created[4] {
+		t.Fatalf("expected only the received transfer of 40, got %v", items)
+	}
+}
+
+func TestE2E_ReverseTransaction_PartialThenExhausted(t *testing.T) {
+	fromUser := newFundedUser(t, 500)
+	toUser := newKnownUser(t)
+	id := createTransaction(t, fromUser, toUser, 500)
+	settleTransaction(t, id)
+
+	first := doPost(t, "/api/v1/transactions/"+id+"/reversal", map[string]any{"amount": 300}, nil)
+	if first.StatusCode != http.StatusCreated {
+		t.Fatalf("first reversal: expected 201, got %d", first.StatusCode)
+	}
+	reversalID, _ := decodeResponse(t, first).Data["id"].(string)
+
+	second := doPost(t, "/api/v1/transactions/"+id+"/reversal", map[string]any{"amount": 201}, nil)
+	second.Body.Close()
+	if second.StatusCode != http.StatusConflict {
+		t.Fatalf("reversal above the remaining amount: expected 409, got %d", second.StatusCode)
+	}
+
+	rest := doPost(t, "/api/v1/transactions/"+id+"/reversal", map[string]any{"amount": 200}, nil)
+	rest.Body.Close()
+	if rest.StatusCode != http.StatusCreated {
+		t.Fatalf("reversal of the remaining amount: expected 201, got %d", rest.StatusCode)
+	}
+
+	again := doPost(t, "/api/v1/transactions/"+id+"/reversal", nil, nil)
+	again.Body.Close()
+	if again.StatusCode != http.StatusConflict {
+		t.Fatalf("reversal of a fully reversed transaction: expected 409, got %d", again.StatusCode)
+	}
+
+	nested := doPost(t, "/api/v1/transactions/"+reversalID+"/reversal", nil, nil)
+	nested.Body.Close()
+	if nested.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("reversal of a reversal: expected 422, got %d", nested.StatusCode)
+	}
+
+	if got := accountBalance(t, fromUser); got != 500 {
+		t.Fatalf("expected the sender to be made whole, got %d", got)
+	}
+	var events int
+	if err := testDB.QueryRow(
+		`SELECT COUNT(*) FROM outbox WHERE type = 'TransactionReversed' AND aggregate_id = $1`, id,
+	).Scan(&events); err != nil {
+		t.Fatalf("count reversal events: %v", err)
+	}
+	if events != 2 {
+		t.Fatalf("expected 2 TransactionReversed events, got %d", events)
+	}
+}
+
+func TestE2E_ReverseTransaction_PendingReturns422(t *testing.T) {
+	fromUser := newFundedUser(t, 100)
+	toUser := newKnownUser(t)
+	id := createTransaction(t, fromUser, toUser, 100)
+
+	resp := doPost(t, "/api/v1/transactions/"+id+"/reversal", nil, nil)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", resp.StatusCode)
+	}
+}
+
+// authorizeHold places a hold of amount from fromUser to toUser and returns
+// its ID.
+func authorizeHold(t *testing.T, fromUser, toUser string, amount int64) string {
+	t.Helper()
+	resp := doPost(t, "/api/v1/transactions", map[string]any{
+		"from_user_id":   fromUser,
+		"to_user_id":     toUser,
+		"amount":         amount,
+		"authorize_only": true,
+	}, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("authorizeHold: expected 201, got %d", resp.StatusCode)
+	}
+	body := decodeResponse(t, resp)
+	if body.Data["status"] != string(entity.StatusAuthorized) || body.Data["expires_at"] == nil {
+		t.Fatalf("authorizeHold: unexpected response data: %v", body.Data)
+	}
+	return body.Data["id"].(string)
+}
+
+func balances(t *testing.T, userID string) (ledger, available float64) {
+	t.Helper()
+	body := decodeResponse(t, doGet(t, "/api/v1/balance/"+userID))
+	return body.Data["Balance"].(float64), body.Data["Available"].(float64)
+}
+
+func TestE2E_Hold_PartialCaptureReleasesRest(t *testing.T) {
+	fromUser := newFundedUser(t, 1000)
+	toUser := newKnownUser(t)
+	id := authorizeHold(t, fromUser, toUser, 600)
+
+	if ledger, available := balances(t, fromUser); ledger != 1000 || available != 400 {
+		t.Fatalf("expected the hold to reduce only the available balance, got ledger %v available %v", ledger, available)
+	}
+
+	resp := doPost(t, "/api/v1/transactions/"+id+"/capture", map[string]any{"amount": 450}, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("capture: expected 200, got %d", resp.StatusCode)
+	}
+	body := decodeResponse(t, resp)
+	if body.Data["captured"] != float64(450) || body.Data["released"] != float64(150) {
+		t.Fatalf("unexpected capture response: %v", body.Data)
+	}
+
+	if ledger, available := balances(t, fromUser); ledger != 550 || available != 550 {
+		t.Fatalf("expected sender ledger 550 available 550, got %v and %v", ledger, available)
+	}
+	if ledger, available := balances(t, toUser); ledger != 450 || available != 450 {
+		t.Fatalf("expected receiver ledger 450 available 450, got %v and %v", ledger, available)
+	}
+
+	again := doPost(t, "/api/v1/transactions/"+id+"/capture", nil, nil)
+	again.Body.Close()
+	if again.StatusCode != http.StatusConflict {
+		t.Fatalf("second capture: expected 409, got %d", again.StatusCode)
+	}
+}
+
+func TestE2E_Hold_VoidRestoresAvailableBalance(t *testing.T) {
+	fromUser := newFundedUser(t, 300)
+	toUser := newKnownUser(t)
+	id := authorizeHold(t, fromUser, toUser, 300)
+
+	overdraw := doPost(t, "/api/v1/transactions", map[string]any{
+		"from_user_id": fromUser,
+		"to_user_id":   toUser,
+		"amount":       1,
+	}, nil)
+	overdraw.Body.Close()
+	if overdraw.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("transfer against held funds: expected 422, got %d", overdraw.StatusCode)
+	}
+
+	resp := doPost(t, "/api/v1/transactions/"+id+"/void", nil, nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("void: expected 200, got %d", resp.StatusCode)
+	}
+	if got := accountBalance(t, fromUser); got != 300 {
+		t.Fatalf("expected the hold to be released, got %d", got)
+	}
+
+	capture := doPost(t, "/api/v1/transactions/"+id+"/capture", nil, nil)
+	capture.Body.Close()
+	if capture.StatusCode != http.StatusConflict {
+		t.Fatalf("capture after void: expected 409, got %d", capture.StatusCode)
+	}
+}
+
+func TestE2E_Hold_ExpiredHoldIsSwept(t *testing.T) {
+	fromUser := newFundedUser(t, 200)
+	toUser := newKnownUser(t)
+	id := authorizeHold(t, fromUser, toUser, 200)
+
+	if _, err := testDB.Exec(
+		`UPDATE transactions SET expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, id,
+	); err != nil {
+		t.Fatalf("backdate hold: %v", err)
+	}
+
+	capture := doPost(t, "/api/v1/transactions/"+id+"/capture", nil, nil)
+	capture.Body.Close()
+	if capture.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("capture of an expired hold: expected 422, got %d", capture.StatusCode)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	expire := usecase.NewExpireHoldsUseCase(repository.NewTransactionRepository(testDB), logger)
+	if _, err := expire.Execute(context.Background(), usecase.ExpireHoldsInput{}); err != nil {
+		t.Fatalf("expire holds: %v", err)
+	}
+
+	body := decodeResponse(t, doGet(t, "/api/v1/transactions/"+id))
+	if body.Data["Status"] != string(entity.StatusExpired) && body.Data["status"] != string(entity.StatusExpired) {
+		t.Fatalf("expected EXPIRED, got %v", body.Data)
+	}
+	if got := accountBalance(t, fromUser); got != 200 {
+		t.Fatalf("expected the expired hold to be released, got %d", got)
+	}
+	var events int
+	if err := testDB.QueryRow(
+		`SELECT COUNT(*) FROM outbox WHERE type = 'TransactionExpired' AND aggregate_id = $1`, id,
+	).Scan(&events); err != nil {
+		t.Fatalf("count expiry events: %v", err)
+	}
+	if events != 1 {
+		t.Fatalf("expected 1 TransactionExpired event, got %d", events)
+	}
+}
+
+func TestE2E_CreateTransaction_BetweenCurrenciesRequiresRate(t *testing.T) {
+	fromUser := newFundedUser(t, 1000)
+	toUser := newKnownUser(t)
+
+	resp := doPost(t, "/api/v1/transactions", map[string]any{
+		"from_user_id": fromUser,
+		"to_user_id":   toUser,
+		"amount":       1000,
+		"currency":     "USD",
+		"to_currency":  "EUR",
+	}, nil)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+	if got := accountBalance(t, fromUser); got != 1000 {
+		t.Fatalf("expected the sender to keep 1000, got %d", got)
+	}
+}
+
+func TestE2E_CreateTransaction_BetweenCurrenciesPostsThroughClearing(t *testing.T) {
+	fromUser := newFundedUser(t, 1000)
+	toUser := newKnownUser(t)
+
+	resp := doPost(t, "/api/v1/transactions", map[string]any{
+		"from_user_id":    fromUser,
+		"to_user_id":      toUser,
+		"amount":          1000,
+		"currency":        "USD",
+		"to_currency":     "JPY",
+		"conversion_rate": "151.237",
+	}, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	body := decodeResponse(t, resp)
+	id := body.Data["id"].(string)
+	conversion, _ := body.Data["conversion"].(map[string]any)
+	if conversion["currency"] != "JPY" || conversion["amount"] != float64(1512) {
+		t.Fatalf("expected 10.00 USD to credit 1512 JPY, got %v", body.Data)
+	}
+
+	if got := accountBalance(t, fromUser); got != 0 {
+		t.Fatalf("expected the sender's USD account to be debited, got %d", got)
+	}
+
+	settleTransaction(t, id)
+	if got := currencyAccountBalance(t, toUser, "JPY"); got != 1512 {
+		t.Fatalf("expected settlement to credit the receiver's JPY account 1512, got %d", got)
+	}
+
+	rows, err := testDB.Query(
+		`SELECT currency, SUM(amount), COUNT(*) FROM ledger_entries WHERE transaction_id = $1 GROUP BY currency`, id,
+	)
+	if err != nil {
+		t.Fatalf("read postings: %v", err)
+	}
+	defer rows.Close()
+	currencies := 0
+	for rows.Next() {
+		var (
+			currency string
+			sum      int64
+			count    int
+		)
+		if err := rows.Scan(&currency, &sum, &count); err != nil {
+			t.Fatalf("scan postings: %v", err)
+		}
+		if sum != 0 || count != 2 {
+			t.Fatalf("expected 2 postings summing to 0 in %s, got %d summing to %d", currency, count, sum)
+		}
+		currencies++
+	}
+	if currencies != 2 {
+		t.Fatalf("expected postings in 2 currencies, got %d", currencies)
+	}
+
+	jpy := decodeResponse(t, doGet(t, "/api/v1/balance/"+toUser+"?currency=JPY"))
+	if jpy.Data["Balance"] != float64(1512) || jpy.Data["Currency"] != "JPY" {
+		t.Fatalf("expected a JPY balance of 1512, got %v", jpy.Data)
+	}
+	usd := decodeResponse(t, doGet(t, "/api/v1/balance/"+toUser))
+	if usd.Data["Balance"] != float64(0) {
+		t.Fatalf("expected no USD balance, got %v", usd.Data)
+	}
+
+	reversal := doPost(t, "/api/v1/transactions/"+id+"/reversal", nil, nil)
+	reversal.Body.Close()
+	if reversal.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("reversal of a transfer between currencies: expected 422, got %d", reversal.StatusCode)
+	}
+}
+
+// batchOutcomes posts a batch and returns each item's outcome with the body.
+func batchOutcomes(t *testing.T, mode string, items []map[string]any) ([]string, apiResponse) {
+	t.Helper()
+	resp := doPost(t, "/api/v1/transactions:batch", map[string]any{"mode": mode, "items": items}, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("batch: expected 200, got %d", resp.StatusCode)
+	}
+	body := decodeResponse(t, resp)
+	results, _ := body.Data["items"].([]any)
+	outcomes := make([]string, len(results))
+	for i, r := range results {
+		item, _ := r.(map[string]any)
+		outcomes[i], _ = item["outcome"].(string)
+	}
+	return outcomes, body
+}
+
+func TestE2E_Batch_AtomicCannotSpendUnsettledCredits(t *testing.T) {
+	employer := newFundedUser(t, 100)
+	agency := newKnownUser(t)
+	contractor := newKnownUser(t)
+
+	// The first item only pays the agency once it settles, so the agency
+	// has nothing to pass on yet.
+	outcomes, _ := batchOutcomes(t, "atomic", []map[string]any{
+		{"from_user_id": employer, "to_user_id": agency, "amount": 100},
+		{"from_user_id": agency, "to_user_id": contractor, "amount": 80},
+	})
+
+	if want := []string{"aborted", "failed"}; fmt.Sprint(outcomes) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v", want, outcomes)
+	}
+	for user, want := range map[string]int64{employer: 100, agency: 0, contractor: 0} {
+		if got := accountBalance(t, user); got != want {
+			t.Fatalf("expected %s to hold %d, got %d", user, want, got)
+		}
+	}
+}
+
+func TestE2E_Batch_AtomicRollsBackEveryItem(t *testing.T) {
+	employer := newFundedUser(t, 300)
+	items := []map[string]any{
+		{"from_user_id": employer, "to_user_id": newKnownUser(t), "amount": 100, "idempotency_key": uuid.NewString()},
+		{"from_user_id": employer, "to_user_id": newKnownUser(t), "amount": 100},
+		{"from_user_id": employer, "to_user_id": newKnownUser(t), "amount": 500},
+	}
+
+	outcomes, body := batchOutcomes(t, "atomic", items)
+
+	if want := []string{"aborted", "aborted", "failed"}; fmt.Sprint(outcomes) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v", want, outcomes)
+	}
+	if body.Data["aborted"] != float64(2) || body.Data["failed"] != float64(1) {
+		t.Fatalf("unexpected summary: %v", body.Data)
+	}
+	if got := accountBalance(t, employer); got != 300 {
+		t.Fatalf("expected no funds moved, employer has %d", got)
+	}
+	var count int
+	if err := testDB.QueryRow(`SELECT COUNT(*) FROM transactions WHERE from_user_id = $1`, employer).Scan(&count); err != nil {
+		t.Fatalf("count transactions: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected no transactions committed, got %d", count)
+	}
+}
+
+func TestE2E_Batch_BestEffortCommitsAndReplays(t *testing.T) {
+	employer := newFundedUser(t, 300)
+	items := []map[string]any{
+		{"from_user_id": employer, "to_user_id": newKnownUser(t), "amount": 100, "idempotency_key": uuid.NewString()},
+		{"from_user_id": employer, "to_user_id": newKnownUser(t), "amount": 500, "idempotency_key": uuid.NewString()},
+		{"from_user_id": employer, "to_user_id": newKnownUser(t), "amount": 200, "idempotency_key": uuid.NewString()},
+	}
+
+	outcomes, body := batchOutcomes(t, "best_effort", items)
+	if want := []string{"created", "failed", "created"}; fmt.Sprint(outcomes) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v", want, outcomes)
+	}
+	if got := accountBalance(t, employer); got != 0 {
+		t.Fatalf("expected the created items debited, employer has %d", got)
+	}
+	results, _ := body.Data["items"].([]any)
+	first, _ := results[0].(map[string]any)
+	id, _ := first["id"].(string)
+	var events, changes int
+	if err := testDB.QueryRow(
+		`SELECT (SELECT COUNT(*) FROM outbox WHERE aggregate_id = $1 AND type = $2),
+		        (SELECT COUNT(*) FROM transaction_status_history WHERE transaction_id = $1)`,
+		id, entity.EventTransactionCreated,
+	).Scan(&events, &changes); err != nil {
+		t.Fatalf("read outbox and history: %v", err)
+	}
+	if events != 1 || changes != 1 {
+		t.Fatalf("expected one outbox event and one status change, got %d and %d", events, changes)
+	}
+
+	// Retrying the batch replays what was created and fails the rest again.
+	outcomes, body = batchOutcomes(t, "best_effort", items)
+	if want := []string{"existing", "failed", "existing"}; fmt.Sprint(outcomes) != fmt.Sprint(want) {
+		t.Fatalf("retry: expected %v, got %v", want, outcomes)
+	}
+	results, _ = body.Data["items"].([]any)
+	if replayed, _ := results[0].(map[string]any); replayed["id"] != id {
+		t.Fatalf("retry: expected transaction %s, got %v", id, replayed["id"])
+	}
+	if got := accountBalance(t, employer); got != 0 {
+		t.Fatalf("retry: expected no further debit, employer has %d", got)
+	}
+}
+
+// scheduleTransfer schedules a transfer of amount from fromUser to toUser an
+// hour from now and returns its ID.
+func scheduleTransfer(t *testing.T, fromUser, toUser string, amount int64) string {
+	t.Helper()
+	resp := doPost(t, "/api/v1/transactions", map[string]any{
+		"from_user_id": fromUser,
+		"to_user_id":   toUser,
+		"amount":       amount,
+		"execute_at":   time.Now().UTC().Add(time.Hour),
+	}, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("scheduleTransfer: expected 201, got %d", resp.StatusCode)
+	}
+	body := decodeResponse(t, resp)
+	if body.Data["status"] != string(entity.StatusScheduled) || body.Data["execute_at"] == nil {
+		t.Fatalf("scheduleTransfer: unexpected response data: %v", body.Data)
+	}
+	return body.Data["id"].(string)
+}
+
+// releaseDue makes id due and runs the scheduler's release job.
+func releaseDue(t *testing.T, id string) *usecase.ReleaseScheduledOutput {
+	t.Helper()
+	if _, err := testDB.Exec(
+		`UPDATE transactions SET execute_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, id,
+	); err != nil {
+		t.Fatalf("make transfer due: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	release := usecase.NewReleaseScheduledUseCase(repository.NewTransactionRepository(testDB), logger)
+	out, err := release.Execute(context.Background(), usecase.ReleaseScheduledInput{})
+	if err != nil {
+		t.Fatalf("release scheduled: %v", err)
+	}
+	return out
+}
+
+func transactionStatus(t *testing.T, id string) string {
+	t.Helper()
+	var status string
+	if err := testDB.QueryRow(`SELECT transaction_status FROM transactions WHERE id = $1`, id).Scan(&status); err != nil {
+		t.Fatalf("read transaction status: %v", err)
+	}
+	return status
+}
+
+func TestE2E_Scheduled_CancelBeforeExecution(t *testing.T) {
+	fromUser := newFundedUser(t, 500)
+	toUser := newKnownUser(t)
+	id := scheduleTransfer(t, fromUser, toUser, 500)
+
+	if got := accountBalance(t, fromUser); got != 500 {
+		t.Fatalf("expected scheduling to move no funds, got %d", got)
+	}
+
+	resp := doPost(t, "/api/v1/transactions/"+id+"/cancel", nil, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("cancel: expected 200, got %d", resp.StatusCode)
+	}
+	if body := decodeResponse(t, resp); body.Data["status"] != string(entity.StatusCancelled) {
+		t.Fatalf("unexpected cancel response: %v", body.Data)
+	}
+
+	releaseDue(t, id)
+	if got := transactionStatus(t, id); got != string(entity.StatusCancelled) {
+		t.Fatalf("expected the cancelled transfer to stay CANCELLED, got %s", got)
+	}
+	if got := accountBalance(t, fromUser); got != 500 {
+		t.Fatalf("expected a cancelled transfer to move no funds, got %d", got)
+	}
+
+	again := doPost(t, "/api/v1/transactions/"+id+"/cancel", nil, nil)
+	again.Body.Close()
+	if again.StatusCode != http.StatusConflict {
+		t.Fatalf("second cancel: expected 409, got %d", again.StatusCode)
+	}
+}
+
+func TestE2E_Scheduled_ReleasedWhenDue(t *testing.T) {
+	fromUser := newFundedUser(t, 500)
+	toUser := newKnownUser(t)
+	id := scheduleTransfer(t, fromUser, toUser, 300)
+
+	releaseDue(t, id)
+
+	if got := transactionStatus(t, id); got != string(entity.StatusPending) {
+		t.Fatalf("expected the released transfer to be PENDING, got %s", got)
+	}
+	if got := accountBalance(t, fromUser); got != 200 {
+		t.Fatalf("expected the sender debited on release, got %d", got)
+	}
+	var events int
+	if err := testDB.QueryRow(
+		`SELECT COUNT(*) FROM outbox WHERE type = 'TransactionCreated' AND aggregate_id = $1`, id,
+	).Scan(&events); err != nil {
+		t.Fatalf("count created events: %v", err)
+	}
+	if events != 1 {
+		t.Fatalf("expected 1 TransactionCreated event, got %d", events)
+	}
+
+	cancel := doPost(t, "/api/v1/transactions/"+id+"/cancel", nil, nil)
+	cancel.Body.Close()
+	if cancel.StatusCode != http.StatusConflict {
+		t.Fatalf("cancel after release: expected 409, got %d", cancel.StatusCode)
+	}
+
+	if out := settleTransaction(t, id); out.Status != string(entity.StatusCompleted) {
+		t.Fatalf("expected the released transfer to settle, got %+v", out)
+	}
+}
+
+func TestE2E_Scheduled_UnfundedTransferFailsOnRelease(t *testing.T) {
+	fromUser := newFundedUser(t, 100)
+	toUser := newKnownUser(t)
+	id := scheduleTransfer(t, fromUser, toUser, 300)
+
+	releaseDue(t, id)
+
+	if got := transactionStatus(t, id); got != string(entity.StatusFailed) {
+		t.Fatalf("expected the unfunded transfer to be FAILED, got %s", got)
+	}
+	if got := accountBalance(t, fromUser); got != 100 {
+		t.Fatalf("expected no funds moved, got %d", got)
+	}
+}
+
+// createMandate sets up a monthly mandate paying amount from fromUser to
+// toUser and returns its ID.
+func createMandate(t *testing.T, fromUser, toUser string, amount int64) string {
+	t.Helper()
+	resp := doPost(t, "/api/v1/mandates", map[string]any{
+		"from_user_id": fromUser,
+		"to_user_id":   toUser,
+		"amount":       amount,
+		"schedule":     "@monthly",
+		"start_at":     time.Now().UTC().Add(time.Hour),
+	}, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("createMandate: expected 201, got %d", resp.StatusCode)
+	}
+	body := decodeResponse(t, resp)
+	if body.Data["status"] != string(entity.MandateActive) {
+		t.Fatalf("createMandate: unexpected response data: %v", body.Data)
+	}
+	return body.Data["id"].(string)
+}
+
+// runMandateDue rewinds the mandate to before its occurrence-th run, the way
+// a scheduler that crashed before advancing it would find it, and runs the
+// scheduler's mandate job.
+func runMandateDue(t *testing.T, id string, occurrence int64) *usecase.RunMandatesOutput {
+	t.Helper()
+	if _, err := testDB.Exec(
+		`UPDATE mandates SET occurrence = $2, next_run_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, id, occurrence,
+	); err != nil {
+		t.Fatalf("make mandate due: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	create := usecase.NewCreateTransactionUseCase(
+		repository.NewTransactionRepository(testDB), repository.NewKnownUserRepository(testDB), riskEngine, logger,
+	)
+	run := usecase.NewRunMandatesUseCase(repository.NewMandateRepository(testDB), create, logger)
+	out, err := run.Execute(context.Background(), usecase.RunMandatesInput{})
+	if err != nil {
+		t.Fatalf("run mandates: %v", err)
+	}
+	return out
+}
+
+func outboxEvents(t *testing.T, id, eventType string) int {
+	t.Helper()
+	var events int
+	if err := testDB.QueryRow(
+		`SELECT COUNT(*) FROM outbox WHERE type = $1 AND aggregate_id = $2`, eventType, id,
+	).Scan(&events); err != nil {
+		t.Fatalf("count mandate events: %v", err)
+	}
+	return events
+}
+
+func TestE2E_Mandate_RunIsNeverChargedTwice(t *testing.T) {
+	fromUser := newFundedUser(t, 1000)
+	toUser := newKnownUser(t)
+	id := createMandate(t, fromUser, toUser, 300)
+
+	runMandateDue(t, id, 0)
+	// A scheduler that crashed after paying but before advancing retries
+	// the same occurrence.
+	runMandateDue(t, id, 0)
+
+	if got := accountBalance(t, fromUser); got != 700 {
+		t.Fatalf("expected one debit of 300, got balance %d", got)
+	}
+	var transfers int
+	if err := testDB.QueryRow(
+		`SELECT COUNT(*) FROM transactions WHERE from_user_id = $1 AND idempotency_key = $2`,
+		fromUser, fmt.Sprintf("mandate-%s-0", id),
+	).Scan(&transfers); err != nil {
+		t.Fatalf("count mandate transfers: %v", err)
+	}
+	if transfers != 1 {
+		t.Fatalf("expected 1 transfer for the occurrence, got %d", transfers)
+	}
+	if got := outboxEvents(t, id, entity.EventMandateExecuted); got != 2 {
+		t.Fatalf("expected both runs to report the same transfer, got %d events", got)
+	}
+
+	body := decodeResponse(t, doGet(t, "/api/v1/mandates/"+id))
+	if body.Data["occurrences"] != float64(1) || body.Data["status"] != string(entity.MandateActive) {
+		t.Fatalf("expected the mandate advanced past its first run, got %v", body.Data)
+	}
+}
+
+func TestE2E_Mandate_UnfundedRunFailsAndMovesOn(t *testing.T) {
+	fromUser := newFundedUser(t, 100)
+	toUser := newKnownUser(t)
+	id := createMandate(t, fromUser, toUser, 300)
+
+	runMandateDue(t, id, 0)
+
+	if got := accountBalance(t, fromUser); got != 100 {
+		t.Fatalf("expected no funds moved, got %d", got)
+	}
+	if got := outboxEvents(t, id, entity.EventMandateFailed); got != 1 {
+		t.Fatalf("expected 1 MandateFailed event, got %d", got)
+	}
+	body := decodeResponse(t, doGet(t, "/api/v1/mandates/"+id))
+	if body.Data["occurrences"] != float64(1) {
+		t.Fatalf("expected the failed run to advance the mandate, got %v", body.Data)
+	}
+}
+
+func TestE2E_Mandate_CancelledMandateStopsRunning(t *testing.T) {
+	fromUser := newFundedUser(t, 1000)
+	toUser := newKnownUser(t)
+	id := createMandate(t, fromUser, toUser, 300)
+
+	req, _ := http.NewRequest(http.MethodDelete, testServer.URL+"/api/v1/mandates/"+id, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE mandate: %v", err)
+	}
+	if body := decodeResponse(t, resp); resp.StatusCode != http.StatusOK || body.Data["status"] != string(entity.MandateCancelled) {
+		t.Fatalf("cancel: expected 200 CANCELLED, got %d %v", resp.StatusCode, body.Data)
+	}
+
+	runMandateDue(t, id, 0)
+
+	if got := accountBalance(t, fromUser); got != 1000 {
+		t.Fatalf("expected a cancelled mandate to move no funds, got %d", got)
+	}
+
+	list := decodeResponse(t, doGet(t, "/api/v1/users/"+fromUser+"/mandates"))
+	items, _ := list.Data["items"].([]any)
+	if len(items) != 1 {
+		t.Fatalf("expected the cancelled mandate to stay listed, got %v", list.Data)
+	}
+}
+
+// limitUser returns a funded user whose USD limits override column of the
+// defaults with value.
+func limitUser(t *testing.T, balance int64, column string, value int64) string {
+	t.Helper()
+	id := newFundedUser(t, balance)
+	query := fmt.Sprintf(`INSERT INTO transaction_limits (user_id, currency, %s) VALUES ($1, 'USD', $2)`, column)
+	if _, err := testDB.Exec(query, id, value); err != nil {
+		t.Fatalf("override limits: %v", err)
+	}
+	return id
+}
+
+func postTransfer(t *testing.T, fromUser, toUser string, amount int64) (int, apiResponse) {
+	t.Helper()
+	resp := doPost(t, "/api/v1/transactions", map[string]any{
+		"from_user_id": fromUser,
+		"to_user_id":   toUser,
+		"amount":       amount,
+	}, nil)
+	return resp.StatusCode, decodeResponse(t, resp)
+}
+
+func TestE2E_Limits_MaxAmountNamesTheLimit(t *testing.T) {
+	fromUser := limitUser(t, 1000, "max_amount", 300)
+	toUser := newKnownUser(t)
+
+	resp := doPost(t, "/api/v1/transactions", map[string]any{
+		"from_user_id": fromUser,
+		"to_user_id":   toUser,
+		"amount":       301,
+	}, nil)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", resp.StatusCode)
+	}
+	var problem struct {
+		Title string `json:"title"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if problem.Title != "transfer exceeds the max_amount limit of 300" {
+		t.Fatalf("expected the error to name the limit, got %q", problem.Title)
+	}
+
+	if code, _ := postTransfer(t, fromUser, toUser, 300); code != http.StatusCreated {
+		t.Fatalf("expected a transfer at the limit to pass, got %d", code)
+	}
+}
+
+func TestE2E_Limits_DailyTotalSurvivesConcurrency(t *testing.T) {
+	const workers, amount = 10, 100
+	fromUser := limitUser(t, 10_000, "daily_outgoing", 350)
+	toUser := newKnownUser(t)
+
+	codes := make([]int, workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes[i], _ = postTransfer(t, fromUser, toUser, amount)
+		}()
+	}
+	wg.Wait()
+
+	created := 0
+	for _, code := range codes {
+		switch code {
+		case http.StatusCreated:
+			created++
+		case http.StatusUnprocessableEntity:
+		default:
+			t.Fatalf("unexpected status %d", code)
+		}
+	}
+	if created != 3 {
+		t.Fatalf("expected exactly 3 transfers under the daily limit, got %d", created)
+	}
+	if got := accountBalance(t, fromUser); got != 10_000-3*amount {
+		t.Fatalf("expected 3 debits, got balance %d", got)
+	}
+}
+
+func TestE2E_Limits_FailedTransfersDoNotCount(t *testing.T) {
+	fromUser := limitUser(t, 1000, "hourly_transfers", 1)
+	toUser := newKnownUser(t)
+
+	code, body := postTransfer(t, fromUser, toUser, 100)
+	if code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", code)
+	}
+	if code, _ := postTransfer(t, fromUser, toUser, 100); code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected the second transfer within the hour rejected, got %d", code)
+	}
+
+	if _, err := testDB.Exec(
+		`UPDATE transactions SET transaction_status = 'FAILED' WHERE id = $1`, body.Data["id"],
+	); err != nil {
+		t.Fatalf("fail transfer: %v", err)
+	}
+	if code, _ := postTransfer(t, fromUser, toUser, 100); code != http.StatusCreated {
+		t.Fatalf("expected a failed transfer to free its slot, got %d", code)
+	}
+}
+
+func TestE2E_Limits_BatchCountsEarlierItems(t *testing.T) {
+	employer := limitUser(t, 1000, "daily_outgoing", 250)
+	items := make([]map[string]any, 3)
+	for i := range items {
+		items[i] = map[string]any{"from_user_id": employer, "to_user_id": newKnownUser(t), "amount": 100}
+	}
+
+	outcomes, body := batchOutcomes(t, "best_effort", items)
+	if want := []string{"created", "created", "failed"}; fmt.Sprint(outcomes) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v (%v)", want, outcomes, body.Data)
+	}
+	if got := accountBalance(t, employer); got != 800 {
+		t.Fatalf("expected 2 debits, got balance %d", got)
+	}
+}
+
+// ── Risk rules ─────────────────────────────────────────────────────────────
+
+// reviewTransfer reviews the HELD transfer id the way admin review does and
+// returns the HTTP code of its outcome and the resulting status.
+func reviewTransfer(t *testing.T, id string, approve bool) (int, string) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	review := usecase.NewReviewHeldUseCase(repository.NewTransactionRepository(testDB), logger)
+	out, err := review.Execute(context.Background(), usecase.ReviewInput{
+		ID: id, Approve: approve, Reviewer: "e2e", Reason: "e2e review",
+	})
+	var exc *apperrors.Exception
+	if errors.As(err, &exc) {
+		return exc.Code, ""
+	}
+	if err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	return http.StatusOK, out.Status
+}
+
+func TestE2E_Risk_LargeFirstPaymentIsHeldUntilApproved(t *testing.T) {
+	fromUser := newFundedUser(t, reviewAmount)
+	toUser := newKnownUser(t)
+
+	code, body := postTransfer(t, fromUser, toUser, reviewAmount)
+	if code != http.StatusCreated || body.Data["status"] != string(entity.StatusHeld) {
+		t.Fatalf("expected a HELD transfer, got %d %v", code, body.Data)
+	}
+	id := body.Data["id"].(string)
+	if got := accountBalance(t, fromUser); got != reviewAmount {
+		t.Fatalf("expected a held transfer to move no funds, got %d", got)
+	}
+
+	status := decodeResponse(t, doGet(t, "/api/v1/transactions/"+id))
+	if r, _ := status.Data["risk"].(map[string]any); r["decision"] != string(entity.RiskReview) {
+		t.Fatalf("expected the risk decision on the transaction, got %v", status.Data["risk"])
+	}
+
+	if code, status := reviewTransfer(t, id, true); code != http.StatusOK || status != string(entity.StatusPending) {
+		t.Fatalf("approve: expected PENDING, got %d %s", code, status)
+	}
+	changes, _ := decodeResponse(t, doGet(t, "/api/v1/transactions/"+id+"/history")).Data["changes"].([]any)
+	if last, _ := changes[len(changes)-1].(map[string]any); last["actor"] != entity.ActorAdmin || last["reason"] != "approved by e2e: e2e review" {
+		t.Fatalf("expected the reviewer in the history, got %v", changes)
+	}
+	if got := accountBalance(t, fromUser); got != 0 {
+		t.Fatalf("expected the approved transfer to move the funds, got %d", got)
+	}
+	if got := outboxEvents(t, id, entity.EventTransactionCreated); got != 1 {
+		t.Fatalf("expected TransactionCreated once approved, got %d", got)
+	}
+
+	if code, _ := reviewTransfer(t, id, true); code != http.StatusConflict {
+		t.Fatalf("second review: expected 409, got %d", code)
+	}
+}
+
+func TestE2E_Risk_UnfundedApprovalStaysHeld(t *testing.T) {
+	fromUser := newFundedUser(t, reviewAmount)
+	toUser := newKnownUser(t)
+	_, body := postTransfer(t, fromUser, toUser, reviewAmount)
+	id := body.Data["id"].(string)
+	if code, _ := postTransfer(t, fromUser, newKnownUser(t), 1); code != http.StatusCreated {
+		t.Fatalf("expected a small transfer to pass, got %d", code)
+	}
+
+	if code, _ := reviewTransfer(t, id, true); code != http.StatusUnprocessableEntity {
+		t.Fatalf("approve: expected 422, got %d", code)
+	}
+	if got := transactionStatus(t, id); got != string(entity.StatusHeld) {
+		t.Fatalf("expected the transfer to stay HELD, got %s", got)
+	}
+
+	if code, status := reviewTransfer(t, id, false); code != http.StatusOK || status != string(entity.StatusRejected) {
+		t.Fatalf("reject: expected REJECTED, got %d %s", code, status)
+	}
+	if got := outboxEvents(t, id, entity.EventTransactionRejected); got != 1 {
+		t.Fatalf("expected TransactionRejected once rejected, got %d", got)
+	}
+}
+
+func TestE2E_Risk_BlocklistedRecipientIsDeclined(t *testing.T) {
+	fromUser := newFundedUser(t, 500)
+	if _, err := testDB.Exec(
+		`INSERT INTO known_users (user_id, registered_at) VALUES ($1, NOW()) ON CONFLICT DO NOTHING`, blockedUser,
+	); err != nil {
+		t.Fatalf("seed blocked user: %v", err)
+	}
+
+	code, body := postTransfer(t, fromUser, blockedUser, 100)
+	if code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d %v", code, body)
+	}
+	if got := accountBalance(t, fromUser); got != 500 {
+		t.Fatalf("expected a declined transfer to move no funds, got %d", got)
+	}
+
+	var id string
+	var reasons string
+	if err := testDB.QueryRow(
+		`SELECT id, array_to_string(risk_reasons, '; ') FROM transactions
+		 WHERE from_user_id = $1 AND transaction_status = 'REJECTED' AND risk_decision = 'DENY'`, fromUser,
+	).Scan(&id, &reasons); err != nil {
+		t.Fatalf("expected the declined transfer recorded: %v", err)
+	}
+	if reasons != "recipient is blocklisted" {
+		t.Fatalf("expected the reason recorded, got %q", reasons)
+	}
+	if got := outboxEvents(t, id, entity.EventTransactionRejected); got != 1 {
+		t.Fatalf("expected TransactionRejected, got %d", got)
+	}
+}
+
+func getStatement(t *testing.T, userID string, params url.Values) (*http.Response, [][]string) {
+	t.Helper()
+	resp := doGet(t, "/api/v1/users/"+userID+"/statement?"+params.Encode())
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("statement: expected 200, got %d", resp.StatusCode)
+	}
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("statement: read CSV: %v", err)
+	}
+	return resp, rows
+}
+
+func TestE2E_Statement_RunningBalanceFromOpeningBalance(t *testing.T) {
+	fromUser := newFundedUser(t, 1000)
+	toUser := newKnownUser(t)
+	start := time.Now().UTC().Add(-time.Minute)
+
+	first := createTransaction(t, fromUser, toUser, 300)
+	settleTransaction(t, first)
+	second := createTransaction(t, fromUser, toUser, 200)
+	settleTransaction(t, second)
+
+	_, rows := getStatement(t, fromUser, url.Values{"from": {start.Format(time.RFC3339)}})
+	if len(rows) != 6 {
+		t.Fatalf("expected header, opening, the deposit, 2 postings and closing, got %v", rows)
+	}
+	if rows[2][1] != depositOf(t, fromUser) || rows[2][4] != "10.00" {
+		t.Fatalf("expected the deposit first, got %v", rows[2])
+	}
+	if rows[1][6] != "0.00" || rows[3][4] != "-3.00" || rows[3][6] != "7.00" || rows[4][4] != "-2.00" || rows[5][6] != "5.00" {
+		t.Fatalf("unexpected balances: %v", rows)
+	}
+	if rows[3][1] != first || rows[3][3] != toUser {
+		t.Fatalf("expected the first transfer with its counterparty, got %v", rows[3])
+	}
+
+	// A period starting at the second posting opens with the first.
+	var postedAt time.Time
+	if err := testDB.QueryRow(
+		`SELECT created_at FROM ledger_entries WHERE transaction_id = $1 AND user_id = $2`, second, fromUser,
+	).Scan(&postedAt); err != nil {
+		t.Fatalf("read posting: %v", err)
+	}
+	_, rows = getStatement(t, fromUser, url.Values{"from": {postedAt.Format(time.RFC3339Nano)}})
+	if len(rows) != 4 || rows[1][6] != "7.00" || rows[2][1] != second || rows[3][6] != "5.00" {
+		t.Fatalf("expected the second transfer after an opening balance of 7.00, got %v", rows)
+	}
+}
+
+func TestE2E_Statement_StreamsPastOneFetch(t *testing.T) {
+	fromUser, toUser := newKnownUser(t), newKnownUser(t)
+	start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+	const n = 1203
+	if _, err := testDB.Exec(`
+		WITH t AS (
+			INSERT INTO transactions (amount, currency, description, from_user_id, to_user_id, transaction_status, created_at, processed_at)
+			SELECT 1, 'USD', 'bulk', $1, $2, 'COMPLETED', $3::timestamp + i * interval '1 millisecond', $3::timestamp + i * interval '1 millisecond'
+			FROM generate_series(1, $4) AS i
+			RETURNING id, processed_at
+		)
+		INSERT INTO ledger_entries (transaction_id, user_id, currency, amount, created_at)
+		SELECT t.id, p.user_id, 'USD', p.amount, t.processed_at
+		FROM t CROSS JOIN (VALUES ($1::uuid, -1), ($2::uuid, 1)) AS p (user_id, amount)`,
+		fromUser, toUser, start, n,
+	); err != nil {
+		t.Fatalf("seed postings: %v", err)
+	}
+
+	resp, rows := getStatement(t, toUser, url.Values{"from": {start.Format(time.RFC3339)}, "format": {"csv"}})
+	if cd := resp.Header.Get("Content-Disposition"); cd == "" {
+		t.Fatal("expected the statement served as an attachment")
+	}
+	if len(rows) != n+3 {
+		t.Fatalf("expected %d postings, got %d rows", n, len(rows)-3)
+	}
+	if last := rows[n+1]; last[6] != "12.03" {
+		t.Fatalf("expected the running balance to reach 12.03, got %v", last)
+	}
+}
+
+func TestE2E_Statement_OFX(t *testing.T) {
+	fromUser := newFundedUser(t, 1000)
+	toUser := newKnownUser(t)
+	start := time.Now().UTC().Add(-time.Minute)
+	settleTransaction(t, createTransaction(t, fromUser, toUser, 250))
+
+	resp := doGet(t, "/api/v1/users/"+toUser+"/statement?format=ofx&from="+url.QueryEscape(start.Format(time.RFC3339)))
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/x-ofx" {
+		t.Fatalf("expected an OFX statement, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	for _, want := range []string{"<TRNTYPE>CREDIT</TRNTYPE>", "<TRNAMT>2.50</TRNAMT>", "<LEDGERBAL><BALAMT>2.50</BALAMT>"} {
+		if !bytes.Contains(body, []byte(want)) {
+			t.Fatalf("expected %s in\n%s", want, body)
+		}
+	}
+}
+
+func TestE2E_Metadata_FiltersListAndReachesOutbox(t *testing.T) {
+	fromUser := newFundedUser(t, 1000)
+	toUser := newKnownUser(t)
+	createTransaction(t, fromUser, toUser, 100)
+	resp := doPost(t, "/api/v1/transactions", map[string]any{
+		"from_user_id": fromUser,
+		"to_user_id":   toUser,
+		"amount":       200,
+		"metadata":     map[string]string{"order_id": "ord-42", "channel": "web"},
+	}, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	id, _ := decodeResponse(t, resp).Data["id"].(string)
+
+	body := decodeResponse(t, doGet(t, "/api/v1/users/"+fromUser+"/transactions?metadata[order_id]=ord-42"))
+	items := body.Data["items"].([]any)
+	if len(items) != 1 || items[0].(map[string]any)["id"] != id {
+		t.Fatalf("expected only the annotated transfer, got %v", items)
+	}
+	if metadata, _ := items[0].(map[string]any)["metadata"].(map[string]any); metadata["channel"] != "web" {
+		t.Fatalf("expected the metadata listed, got %v", items[0])
+	}
+
+	var payload []byte
+	if err := testDB.QueryRow(
+		`SELECT payload FROM outbox WHERE type = $1 AND aggregate_id = $2`, entity.EventTransactionCreated, id,
+	).Scan(&payload); err != nil {
+		t.Fatalf("read outbox payload: %v", err)
+	}
+	var event struct {
+		Metadata map[string]string `json:"metadata"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil || event.Metadata["order_id"] != "ord-42" {
+		t.Fatalf("expected the metadata in the TransactionCreated payload, got %s", payload)
+	}
+}
+
+func reconcile(t *testing.T) *entity.ReconciliationReport {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	uc := usecase.NewReconcileOutboxUseCase(repository.NewReconciliationRepository(testDB), logger)
+	report, err := uc.Execute(context.Background(), usecase.ReconcileInput{})
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	return report
+}
+
+func TestE2E_Reconcile_CountsBrokenOutboxRows(t *testing.T) {
+	before := reconcile(t)
+
+	fromUser := newFundedUser(t, 500)
+	toUser := newKnownUser(t)
+	id := createTransaction(t, fromUser, toUser, 500)
+	settleTransaction(t, id)
+	reversal := doPost(t, "/api/v1/transactions/"+id+"/reversal", nil, nil)
+	reversal.Body.Close()
+	if reversal.StatusCode != http.StatusCreated {
+		t.Fatalf("reversal: expected 201, got %d", reversal.StatusCode)
+	}
+
+	silent := uuid.NewString()
+	if _, err := testDB.Exec(`
+		INSERT INTO transactions (id, amount, description, from_user_id, to_user_id, transaction_status)
+		VALUES ($1, 100, 'written without an event', $2, $3, 'PENDING')
+	`, silent, fromUser, toUser); err != nil {
+		t.Fatalf("insert transaction: %v", err)
+	}
+	events := []struct {
+		eventType, aggregateID, status string
+		age                            time.Duration
+	}{
+		{entity.EventTransactionCompleted, uuid.NewString(), "PROCESSED", 0},
+		{entity.EventMandateCreated, id, "PROCESSED", 0},
+		{entity.EventTransactionCompleted, id, "FAILED", 2 * time.Hour},
+	}
+	for _, e := range events {
+		if _, err := testDB.Exec(`
+			INSERT INTO outbox (id, type, aggregate_id, payload, status, created_at)
+			VALUES ($1, $2, $3, '{}', $4, $5)
+		`, uuid.NewString(), e.eventType, e.aggregateID, e.status, time.Now().UTC().Add(-e.age)); err != nil {
+			t.Fatalf("insert outbox event: %v", err)
+		}
+	}
+
+	after := reconcile(t)
+	want := map[string]int64{
+		entity.DiscrepancyMissingEvent: 1,
+		entity.DiscrepancyOrphanEvent:  2,
+		entity.DiscrepancyStuckFailed:  1,
+	}
+	for kind, n := range want {
+		if got := after.Counts[kind] - before.Counts[kind]; got != n {
+			t.Fatalf("%s: expected %d new discrepancies, got %d", kind, n, got)
+		}
+	}
+}
+
+func createDescribed(t *testing.T, fromUser, toUser, description string) string {
+	t.Helper()
+	resp := doPost(t, "/api/v1/transactions", map[string]any{
+		"from_user_id": fromUser,
+		"to_user_id":   toUser,
+		"amount":       10,
+		"description":  description,
+	}, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d", resp.StatusCode)
+	}
+	id, _ := decodeResponse(t, resp).Data["id"].(string)
+	return id
+}
+
+func TestE2E_Search_RanksHighlightsAndPaginates(t *testing.T) {
+	fromUser := newFundedUser(t, 1000)
+	toUser := newKnownUser(t)
+	word := "zq" + uuid.NewString()[:8]
+	strong := createDescribed(t, fromUser, toUser, word+" refunds")
+	weak := createDescribed(t, fromUser, toUser, "monthly "+word+" payment for office rent & cleaning <urgent>")
+	createDescribed(t, fromUser, toUser, word+" fee")
+	createDescribed(t, newFundedUser(t, 100), toUser, word+" refund from someone else")
+
+	path := "/api/v1/transactions:search?limit=1&user_id=" + fromUser + "&q=" + url.QueryEscape(word+" -fee")
+	var seen []map[string]any
+	cursor := ""
+	for page := 0; ; page++ {
+		if page > 3 {
+			t.Fatal("pagination did not terminate")
+		}
+		resp := doGet(t, path+"&cursor="+cursor)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		body := decodeResponse(t, resp)
+		for _, item := range body.Data["items"].([]any) {
+			seen = append(seen, item.(map[string]any))
+		}
+		next, _ := body.Data["next_cursor"].(string)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if len(seen) != 2 || seen[0]["id"] != strong || seen[1]["id"] != weak {
+		t.Fatalf("expected the shorter, older description first and the fee excluded, got %v", seen)
+	}
+	if snippet := seen[1]["snippet"].(string); !strings.Contains(snippet, "<mark>"+word+"</mark>") || !strings.Contains(snippet, "&amp;") || strings.Contains(snippet, "<urgent>") {
+		t.Fatalf("expected an escaped snippet with the match marked, got %q", snippet)
+	}
+
+	body := decodeResponse(t, doGet(t, "/api/v1/transactions:search?q="+url.QueryEscape(`"`+word+` refund"`)))
+	if items := body.Data["items"].([]any); len(items) != 2 {
+		t.Fatalf("expected the phrase to match both refunds across users, got %v", items)
+	}
+}
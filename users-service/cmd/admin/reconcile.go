@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"users-service/config"
+	infradb "users-service/infra/db"
+	"users-service/infra/repository"
+	"users-service/internal/core/domain/entity"
+	"users-service/internal/core/usecase"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// runReconcile writes the outbox reconciliation report as JSON and its
+// counts as Prometheus metrics, then fails when it found discrepancies, so it
+// can run as a cron job that alerts on either.
+func runReconcile(ctx context.Context, logger *slog.Logger, cfg *config.Config, args []string) error {
+	fs := newFlagSet("reconcile")
+	pendingAfter := fs.Duration("pending-after", usecase.DefaultStuckPendingAfter, "report PENDING events older than this")
+	processingAfter := fs.Duration("processing-after", usecase.DefaultStuckProcessingAfter, "report PROCESSING events older than this")
+	failedAfter := fs.Duration("failed-after", usecase.DefaultStuckFailedAfter, "report FAILED events older than this")
+	sample := fs.Int("sample", usecase.DefaultReconcileSampleSize, "discrepancies of each kind listed in the report")
+	reportPath := fs.String("report", "", "write the JSON report to this file instead of stdout")
+	metricsFile := fs.String("metrics-file", "", "write the metrics to this file for the node_exporter textfile collector")
+	pushgateway := fs.String("pushgateway", "", "push the metrics to this Pushgateway URL")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := infradb.Connect(
+		cfg.Database.Host, cfg.Database.Port,
+		cfg.Database.User, cfg.Database.Password, cfg.Database.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	reconcile := usecase.NewReconcileOutboxUseCase(repository.NewReconciliationRepository(db), logger)
+	report, err := reconcile.Execute(ctx, usecase.ReconcileInput{
+		PendingAfter:    *pendingAfter,
+		ProcessingAfter: *processingAfter,
+		FailedAfter:     *failedAfter,
+		SampleSize:      *sample,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writeReport(*reportPath, report); err != nil {
+		return err
+	}
+	if err := exportMetrics(report, *metricsFile, *pushgateway); err != nil {
+		return err
+	}
+
+	if total := report.Total(); total > 0 {
+		return fmt.Errorf("outbox reconciliation found %d discrepancies", total)
+	}
+	return nil
+}
+
+func writeReport(path string, report *entity.ReconciliationReport) error {
+	var w io.Writer = os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("create report: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	return nil
+}
+
+// exportMetrics publishes the report through a registry of its own, so only
+// the reconciliation gauges reach the textfile or the Pushgateway.
+func exportMetrics(report *entity.ReconciliationReport, file, gateway string) error {
+	if file == "" && gateway == "" {
+		return nil
+	}
+
+	discrepancies := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "users_service",
+		Name:      "outbox_reconciliation_discrepancies",
+		Help:      "Discrepancies found by the last outbox reconciliation, by kind.",
+	}, []string{"kind"})
+	lastRun := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "users_service",
+		Name:      "outbox_reconciliation_last_run_timestamp_seconds",
+		Help:      "Unix time of the last completed outbox reconciliation.",
+	})
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(discrepancies, lastRun)
+
+	for kind, count := range report.Counts {
+		discrepancies.WithLabelValues(kind).Set(float64(count))
+	}
+	lastRun.Set(float64(report.CheckedAt.Unix()))
+
+	if file != "" {
+		if err := prometheus.WriteToTextfile(file, reg); err != nil {
+			return fmt.Errorf("write metrics file: %w", err)
+		}
+	}
+	if gateway != "" {
+		if err := push.New(gateway, "outbox_reconciliation").Gatherer(reg).Push(); err != nil {
+			return fmt.Errorf("push metrics: %w", err)
+		}
+	}
+	return nil
+}
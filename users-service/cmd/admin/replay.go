@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"users-service/config"
+	"users-service/infra/broker"
+	infradb "users-service/infra/db"
+	"users-service/infra/repository"
+	"users-service/internal/core/domain/ports"
+	"users-service/internal/core/usecase"
+)
+
+func runReplay(ctx context.Context, logger *slog.Logger, cfg *config.Config, args []string) error {
+	fs := newFlagSet("replay")
+	eventType := fs.String("type", "", "event type to replay, e.g. UserCreated")
+	aggregate := fs.String("aggregate", "", "only replay events of this aggregate ID")
+	from := fs.String("from", "", "RFC3339 lower bound (inclusive) on the event creation time")
+	to := fs.String("to", "", "RFC3339 upper bound (exclusive) on the event creation time")
+	exchange := fs.String("exchange", cfg.RabbitMQ.Exchange, `target exchange; "" publishes straight to the queue named by -routing-key`)
+	routingKey := fs.String("routing-key", "", "target routing key; defaults to the key derived from the event type")
+	rate := fs.Int("rate", 50, "maximum events published per second")
+	batch := fs.Int("batch", 100, "events read per page")
+	dryRun := fs.Bool("dry-run", false, "count matching events without publishing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	q := ports.ReplayQuery{Type: *eventType, AggregateID: *aggregate}
+	var err error
+	if q.From, err = parseTime(*from); err != nil {
+		return fmt.Errorf("-from: %w", err)
+	}
+	if q.To, err = parseTime(*to); err != nil {
+		return fmt.Errorf("-to: %w", err)
+	}
+
+	db, err := infradb.Connect(
+		cfg.Database.Host, cfg.Database.Port,
+		cfg.Database.User, cfg.Database.Password, cfg.Database.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	var pub ports.EventPublisher
+	if !*dryRun {
+		rabbit := broker.NewRabbitMQ(cfg.RabbitMQ.URL)
+		if err := rabbit.Connect(); err != nil {
+			return err
+		}
+		defer rabbit.Close()
+		if pub, err = broker.NewReplayPublisher(rabbit.Channel, *exchange, *routingKey); err != nil {
+			return err
+		}
+	}
+
+	logger.InfoContext(ctx, "replay started",
+		slog.String("type", q.Type),
+		slog.String("aggregate_id", q.AggregateID),
+		slog.String("exchange", *exchange),
+		slog.String("routing_key", *routingKey),
+		slog.Int("rate", *rate),
+		slog.Bool("dry_run", *dryRun),
+	)
+
+	replay := usecase.NewReplayEventsUseCase(repository.NewOutboxRepository(db), pub, systemClock{}, logger)
+	out, err := replay.Execute(ctx, usecase.ReplayInput{Query: q, Batch: *batch, Rate: *rate, DryRun: *dryRun})
+	if out != nil {
+		logger.InfoContext(ctx, "replay finished",
+			slog.Int("matched", out.Matched),
+			slog.Int("published", out.Published),
+			slog.Int("returned", out.Returned),
+		)
+	}
+	return err
+}
+
+// systemClock is the wall clock the replay is paced by.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func parseTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.UTC(), nil
+}
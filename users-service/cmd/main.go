@@ -15,6 +15,7 @@ import (
 	infradb "users-service/infra/db"
 	"users-service/infra/repository"
 	"users-service/internal/core/handler"
+	"users-service/internal/core/password"
 	"users-service/internal/core/usecase"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -54,10 +55,36 @@ func main() {
 		os.Exit(1)
 	}
 	defer rabbit.Close()
+
+	drifts, err := rabbit.DeclareTopology(broker.UserTopology(cfg.RabbitMQ.Exchange, broker.RetryPolicy{
+		Delay:       cfg.RabbitMQ.RetryDelay,
+		MaxAttempts: cfg.RabbitMQ.MaxAttempts,
+	}))
+	if err != nil {
+		logger.Error("failed to declare rabbitmq topology", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	for _, d := range drifts {
+		logger.Warn("rabbitmq topology drift",
+			slog.String("kind", d.Kind),
+			slog.String("name", d.Name),
+			slog.String("detail", d.Detail),
+		)
+	}
 	logger.Info("connected to rabbitmq")
 
+	hasher, err := password.NewArgon2id(password.Params{
+		Memory:      cfg.Password.MemoryKiB,
+		Iterations:  cfg.Password.Iterations,
+		Parallelism: cfg.Password.Parallelism,
+	})
+	if err != nil {
+		logger.Error("invalid password hashing config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
 	userRepo := repository.NewUserRepository(db)
-	userHandler := handler.NewHandlerFactory(usecase.NewFactory(userRepo, logger))
+	userHandler := handler.NewHandlerFactory(usecase.NewFactory(userRepo, hasher, logger))
 
 	mux := http.NewServeMux()
 	userHandler.RegisterRoutes(mux)
@@ -7,6 +7,7 @@ require (
 	github.com/lib/pq v1.11.2
 	github.com/prometheus/client_golang v1.23.2
 	github.com/rabbitmq/amqp091-go v1.10.0
+	golang.org/x/crypto v0.41.0
 )
 
 require (
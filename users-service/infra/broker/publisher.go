@@ -8,6 +8,7 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 
 	"users-service/internal/core/domain/entity"
+	"users-service/internal/core/domain/ports"
 )
 
 type RabbitMQPublisher struct {
@@ -31,20 +32,91 @@ func (p *RabbitMQPublisher) Publish(ctx context.Context, event *entity.Outbox) e
 		p.routingKey,
 		true,
 		false,
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         []byte(event.Payload),
-			DeliveryMode: amqp.Persistent,
-			MessageId:    event.ID,
-			Timestamp:    time.Now().UTC(),
-			Headers: amqp.Table{
-				"event_type":   event.Type,
-				"aggregate_id": event.ID,
-			},
-		},
+		publishing(event, nil),
 	)
 	if err != nil {
 		return fmt.Errorf("publish event %s: %w", event.ID, err)
 	}
 	return nil
 }
+
+// ReplayPublisher republishes historical outbox events. Messages keep the
+// original MessageId so consumers deduplicate them like a redelivery, and
+// carry a replay=true header so they can be told apart from live traffic.
+// Each publish waits for the broker's confirm, so an event nothing is bound
+// to take comes back as ports.ErrUnroutable instead of being dropped.
+type ReplayPublisher struct {
+	channel    *amqp.Channel
+	exchange   string
+	routingKey string
+	returns    chan amqp.Return
+}
+
+// NewReplayPublisher targets exchange with routingKey; an empty routingKey
+// falls back to the key derived from each event type. It puts ch in confirm
+// mode, so ch should not be shared with other publishers.
+func NewReplayPublisher(ch *amqp.Channel, exchange, routingKey string) (*ReplayPublisher, error) {
+	if err := ch.Confirm(false); err != nil {
+		return nil, fmt.Errorf("enable publisher confirms: %w", err)
+	}
+	return &ReplayPublisher{
+		channel:    ch,
+		exchange:   exchange,
+		routingKey: routingKey,
+		// The broker sends a return before the confirm of the same message,
+		// and publishes wait for their confirm, so one slot is enough.
+		returns: ch.NotifyReturn(make(chan amqp.Return, 1)),
+	}, nil
+}
+
+func (p *ReplayPublisher) Publish(ctx context.Context, event *entity.Outbox) error {
+	key := p.routingKey
+	if key == "" {
+		key = routingKey(event.Type)
+	}
+
+	confirm, err := p.channel.PublishWithDeferredConfirmWithContext(
+		ctx,
+		p.exchange,
+		key,
+		true,
+		false,
+		publishing(event, amqp.Table{"replay": true}),
+	)
+	if err != nil {
+		return fmt.Errorf("replay event %s: %w", event.ID, err)
+	}
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("replay event %s: wait for confirm: %w", event.ID, err)
+	}
+
+	select {
+	case ret := <-p.returns:
+		return fmt.Errorf("replay event %s to %q/%q: %w: %s", ret.MessageId, ret.Exchange, ret.RoutingKey, ports.ErrUnroutable, ret.ReplyText)
+	default:
+	}
+	if !acked {
+		return fmt.Errorf("replay event %s: nacked by the broker", event.ID)
+	}
+	return nil
+}
+
+func publishing(event *entity.Outbox, extraHeaders amqp.Table) amqp.Publishing {
+	headers := amqp.Table{
+		"event_type":   event.Type,
+		"aggregate_id": event.AggregateID,
+	}
+	for k, v := range extraHeaders {
+		headers[k] = v
+	}
+
+	return amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         []byte(event.Payload),
+		DeliveryMode: amqp.Persistent,
+		MessageId:    event.ID,
+		Timestamp:    time.Now().UTC(),
+		Headers:      headers,
+	}
+}
@@ -0,0 +1,194 @@
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type (
+	// Topology is the declarative set of exchanges, queues and bindings a
+	// process expects to exist on the broker.
+	Topology struct {
+		Exchanges []ExchangeSpec
+		Queues    []QueueSpec
+		Bindings  []BindingSpec
+	}
+
+	ExchangeSpec struct {
+		Name string
+		Kind string
+	}
+
+	QueueSpec struct {
+		Name string
+		Args amqp.Table
+	}
+
+	BindingSpec struct {
+		Queue      string
+		Exchange   string
+		RoutingKey string
+	}
+
+	// Subscription is one consumer's interest in one event type. Every
+	// subscription gets its own queue, so each consumer receives its own
+	// copy of the event instead of competing for it.
+	Subscription struct {
+		Consumer  string
+		EventType string
+	}
+
+	// RetryPolicy controls the delayed-retry queues: a failed message waits
+	// Delay in "<queue>.retry" and is dead-lettered to "<queue>.dlq" once it
+	// has been delivered MaxAttempts times.
+	RetryPolicy struct {
+		Delay       time.Duration
+		MaxAttempts int
+	}
+
+	// Drift describes an entity that already exists on the broker with
+	// arguments different from the expected ones.
+	Drift struct {
+		Kind   string
+		Name   string
+		Detail string
+	}
+)
+
+// DeadLetterExchange names the DLX of exchange.
+func DeadLetterExchange(exchange string) string { return exchange + ".dlx" }
+
+// DeadLetterQueue and RetryQueue name the DLQ and delayed-retry queue paired
+// with queue.
+func DeadLetterQueue(queue string) string { return queue + ".dlq" }
+func RetryQueue(queue string) string      { return queue + ".retry" }
+
+// Queue names the subscription's queue after its consumer and routing key,
+// e.g. "settlement.transaction.created".
+func (s Subscription) Queue() string {
+	return s.Consumer + "." + routingKey(s.EventType)
+}
+
+// EventTopology builds the topology for exchange with one durable queue per
+// subscription, bound to the event's routing key. Rejected messages go through
+// the DLX to the queue's own DLQ; retries are parked in a per-queue TTL queue
+// that dead-letters back to the main queue through the default exchange.
+func EventTopology(exchange string, retry RetryPolicy, subs ...Subscription) Topology {
+	dlx := DeadLetterExchange(exchange)
+
+	t := Topology{
+		Exchanges: []ExchangeSpec{
+			{Name: exchange, Kind: amqp.ExchangeTopic},
+			{Name: dlx, Kind: amqp.ExchangeTopic},
+		},
+	}
+
+	for _, sub := range subs {
+		queue := sub.Queue()
+		dlq := DeadLetterQueue(queue)
+		t.Queues = append(t.Queues,
+			QueueSpec{Name: queue, Args: amqp.Table{
+				"x-dead-letter-exchange":    dlx,
+				"x-dead-letter-routing-key": queue,
+			}},
+			QueueSpec{Name: RetryQueue(queue), Args: amqp.Table{
+				"x-message-ttl":             retry.Delay.Milliseconds(),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": queue,
+			}},
+			QueueSpec{Name: dlq},
+		)
+		t.Bindings = append(t.Bindings,
+			BindingSpec{Queue: queue, Exchange: exchange, RoutingKey: routingKey(sub.EventType)},
+			BindingSpec{Queue: dlq, Exchange: dlx, RoutingKey: queue},
+		)
+	}
+
+	return t
+}
+
+// KnownUsersSubscription is transaction-service's known users projector.
+var KnownUsersSubscription = Subscription{Consumer: "userprojector", EventType: "UserCreated"}
+
+// UserTopology is what the users service publishes to. transaction-service
+// declares the same entities for its known users projector, so both sides
+// must agree on the retry policy or the mismatch shows up as drift.
+func UserTopology(exchange string, retry RetryPolicy) Topology {
+	return EventTopology(exchange, retry, KnownUsersSubscription)
+}
+
+// DeclareTopology declares every entity of t. Declarations are idempotent;
+// an entity that exists with different arguments is reported as Drift and
+// left untouched instead of aborting the remaining declarations.
+func (r *RabbitMQ) DeclareTopology(t Topology) ([]Drift, error) {
+	ch, err := r.Connection.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("topology channel: %w", err)
+	}
+	defer func() { _ = ch.Close() }()
+
+	var drifts []Drift
+
+	// declare runs fn and, when the broker refuses it with PRECONDITION_FAILED,
+	// records the drift and reopens the channel the broker just closed.
+	declare := func(kind, name string, fn func(*amqp.Channel) error) error {
+		err := fn(ch)
+		if err == nil {
+			return nil
+		}
+		var amqpErr *amqp.Error
+		if !errors.As(err, &amqpErr) || amqpErr.Code != amqp.PreconditionFailed {
+			return fmt.Errorf("declare %s %s: %w", kind, name, err)
+		}
+		drifts = append(drifts, Drift{Kind: kind, Name: name, Detail: amqpErr.Reason})
+		if ch, err = r.Connection.Channel(); err != nil {
+			return fmt.Errorf("topology channel: %w", err)
+		}
+		return nil
+	}
+
+	for _, e := range t.Exchanges {
+		if err := declare("exchange", e.Name, func(ch *amqp.Channel) error {
+			return ch.ExchangeDeclare(e.Name, e.Kind, true, false, false, false, nil)
+		}); err != nil {
+			return drifts, err
+		}
+	}
+
+	for _, q := range t.Queues {
+		if err := declare("queue", q.Name, func(ch *amqp.Channel) error {
+			_, err := ch.QueueDeclare(q.Name, true, false, false, false, q.Args)
+			return err
+		}); err != nil {
+			return drifts, err
+		}
+	}
+
+	for _, b := range t.Bindings {
+		if err := declare("binding", b.Queue+"<-"+b.Exchange, func(ch *amqp.Channel) error {
+			return ch.QueueBind(b.Queue, b.RoutingKey, b.Exchange, false, nil)
+		}); err != nil {
+			return drifts, err
+		}
+	}
+
+	return drifts, nil
+}
+
+// routingKey maps an event type to its routing key,
+// e.g. "UserCreated" becomes "user.created".
+func routingKey(eventType string) string {
+	var b strings.Builder
+	for i, r := range eventType {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"users-service/internal/core/domain/entity"
+)
+
+type PostgresReconciliationRepository struct {
+	db *sql.DB
+}
+
+func NewReconciliationRepository(db *sql.DB) *PostgresReconciliationRepository {
+	return &PostgresReconciliationRepository{db: db}
+}
+
+// FindMissingEvents looks each user up in idx_outbox_aggregate_id. Insert
+// commits the user and its UserCreated together, so a single statement never
+// sees one without the other while a write is in flight.
+func (r *PostgresReconciliationRepository) FindMissingEvents(ctx context.Context, limit int) (entity.DiscrepancySample, error) {
+	const query = `
+		SELECT u.id, '', '', '', u.created_at, COUNT(*) OVER ()
+		FROM users u
+		WHERE NOT EXISTS (SELECT 1 FROM outbox o WHERE o.aggregate_id = u.id::text)
+		ORDER BY u.created_at, u.id
+		LIMIT $1
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return entity.DiscrepancySample{}, fmt.Errorf("find %s: %w", entity.DiscrepancyMissingEvent, err)
+	}
+	return scanDiscrepancies(rows, entity.DiscrepancyMissingEvent)
+}
+
+// FindOrphanEvents treats an event without an aggregate_id as an orphan.
+func (r *PostgresReconciliationRepository) FindOrphanEvents(ctx context.Context, limit int) (entity.DiscrepancySample, error) {
+	const query = `
+		SELECT COALESCE(o.aggregate_id, ''), o.id, o.type, o.status, o.created_at, COUNT(*) OVER ()
+		FROM outbox o
+		LEFT JOIN users u ON u.id::text = o.aggregate_id
+		WHERE u.id IS NULL
+		ORDER BY o.created_at, o.id
+		LIMIT $1
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return entity.DiscrepancySample{}, fmt.Errorf("find %s: %w", entity.DiscrepancyOrphanEvent, err)
+	}
+	return scanDiscrepancies(rows, entity.DiscrepancyOrphanEvent)
+}
+
+// FindStuckEvents measures how long an event sat from its creation: the
+// outbox does not record when it entered PROCESSING or FAILED.
+func (r *PostgresReconciliationRepository) FindStuckEvents(ctx context.Context, status entity.OutboxStatus, before time.Time, limit int) (entity.DiscrepancySample, error) {
+	const query = `
+		SELECT COALESCE(aggregate_id, ''), id, type, status, created_at, COUNT(*) OVER ()
+		FROM outbox
+		WHERE status = $1 AND created_at < $2
+		ORDER BY created_at, id
+		LIMIT $3
+	`
+	kind := map[entity.OutboxStatus]string{
+		entity.OutboxStatusPending:    entity.DiscrepancyStuckPending,
+		entity.OutboxStatusProcessing: entity.DiscrepancyStuckProcessing,
+		entity.OutboxStatusFailed:     entity.DiscrepancyStuckFailed,
+	}[status]
+	if kind == "" {
+		return entity.DiscrepancySample{}, fmt.Errorf("no stuck check for outbox status %s", status)
+	}
+	rows, err := r.db.QueryContext(ctx, query, string(status), before, limit)
+	if err != nil {
+		return entity.DiscrepancySample{}, fmt.Errorf("find %s events: %w", kind, err)
+	}
+	return scanDiscrepancies(rows, kind)
+}
+
+// scanDiscrepancies reads rows of (aggregate_id, event_id, event_type,
+// status, created_at, total count).
+func scanDiscrepancies(rows *sql.Rows, kind string) (entity.DiscrepancySample, error) {
+	defer rows.Close()
+
+	var s entity.DiscrepancySample
+	for rows.Next() {
+		d := entity.Discrepancy{Kind: kind}
+		if err := rows.Scan(&d.AggregateID, &d.EventID, &d.EventType, &d.Status, &d.Since, &s.Count); err != nil {
+			return entity.DiscrepancySample{}, fmt.Errorf("scan %s: %w", kind, err)
+		}
+		s.Items = append(s.Items, d)
+	}
+	return s, rows.Err()
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"users-service/internal/core/domain/entity"
 )
@@ -23,20 +24,47 @@ func (r *PostgresUserRepository) Insert(ctx context.Context, user *entity.User,
 	defer func() { _ = tx.Rollback() }()
 
 	const insertUser = `
-		INSERT INTO users (id, email, password, created_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO users (id, email, password, password_scheme, created_at)
+		VALUES ($1, $2, $3, $4, $5)
 	`
-	if _, err := tx.ExecContext(ctx, insertUser, user.ID, user.Email, user.Password, user.CreatedAt); err != nil {
+	if _, err := tx.ExecContext(ctx, insertUser, user.ID, user.Email, user.PasswordHash, user.PasswordScheme, user.CreatedAt); err != nil {
 		return err
 	}
 
 	const insertOutbox = `
-		INSERT INTO outbox (id, type, payload, status, created_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO outbox (id, type, aggregate_id, payload, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
-	if _, err := tx.ExecContext(ctx, insertOutbox, outbox.ID, outbox.Type, outbox.Payload, string(outbox.Status), outbox.CreatedAt); err != nil {
+	if _, err := tx.ExecContext(ctx, insertOutbox, outbox.ID, outbox.Type, outbox.AggregateID, outbox.Payload, string(outbox.Status), outbox.CreatedAt); err != nil {
 		return err
 	}
 
 	return tx.Commit()
 }
+
+func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	var u entity.User
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, email, password, password_scheme, created_at
+		FROM users
+		WHERE email = $1
+	`, email).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.PasswordScheme, &u.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// UpdatePassword compares the stored password with the one user was read
+// with, so a concurrent change is never overwritten by an older login.
+func (r *PostgresUserRepository) UpdatePassword(ctx context.Context, user *entity.User, hash, scheme string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET password = $2, password_scheme = $3
+		WHERE id = $1 AND password = $4
+	`, user.ID, hash, scheme, user.PasswordHash)
+	return err
+}
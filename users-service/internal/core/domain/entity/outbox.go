@@ -18,18 +18,20 @@ const (
 type Outbox struct {
 	ID          string
 	Type        string
+	AggregateID string
 	Payload     string
 	Status      OutboxStatus
 	CreatedAt   time.Time
 	ProcessedAt *time.Time
 }
 
-func NewOutbox(eventType, payload string) *Outbox {
+func NewOutbox(eventType, aggregateID, payload string) *Outbox {
 	return &Outbox{
-		ID:        uuid.NewString(),
-		Type:      eventType,
-		Payload:   payload,
-		Status:    OutboxStatusPending,
-		CreatedAt: time.Now().UTC(),
+		ID:          uuid.NewString(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     payload,
+		Status:      OutboxStatusPending,
+		CreatedAt:   time.Now().UTC(),
 	}
 }
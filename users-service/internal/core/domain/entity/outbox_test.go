@@ -7,7 +7,7 @@ import (
 )
 
 func TestNewOutbox_DefaultFields(t *testing.T) {
-	outbox := entity.NewOutbox("UserCreated", `{"userId":"u-1"}`)
+	outbox := entity.NewOutbox("UserCreated", "u-1", `{"userId":"u-1"}`)
 
 	if outbox.ID == "" {
 		t.Fatal("expected non-empty ID")
@@ -15,6 +15,9 @@ func TestNewOutbox_DefaultFields(t *testing.T) {
 	if outbox.Type != "UserCreated" {
 		t.Fatalf("expected type 'UserCreated', got '%s'", outbox.Type)
 	}
+	if outbox.AggregateID != "u-1" {
+		t.Fatalf("expected aggregate ID 'u-1', got '%s'", outbox.AggregateID)
+	}
 	if outbox.Payload != `{"userId":"u-1"}` {
 		t.Fatalf("unexpected payload: %s", outbox.Payload)
 	}
@@ -30,8 +33,8 @@ func TestNewOutbox_DefaultFields(t *testing.T) {
 }
 
 func TestNewOutbox_UniqueIDs(t *testing.T) {
-	o1 := entity.NewOutbox("UserCreated", "{}")
-	o2 := entity.NewOutbox("UserCreated", "{}")
+	o1 := entity.NewOutbox("UserCreated", "u-1", "{}")
+	o2 := entity.NewOutbox("UserCreated", "u-1", "{}")
 
 	if o1.ID == o2.ID {
 		t.Fatal("expected unique IDs for each outbox event")
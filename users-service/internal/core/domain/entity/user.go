@@ -14,15 +14,23 @@ var (
 	ErrPasswordTooShort = errors.New("password must be at least 8 characters")
 )
 
+// PasswordSchemePlaintext flags the rows written before passwords were
+// hashed. Their PasswordHash is the password itself until the owner's next
+// successful login rehashes it.
+const PasswordSchemePlaintext = "plaintext"
+
 type User struct {
-	ID        string
-	Email     string
-	Password  string
-	CreatedAt time.Time
+	ID             string
+	Email          string
+	PasswordHash   string
+	PasswordScheme string
+	CreatedAt      time.Time
 }
 
+// NewUser validates the email and password. It never keeps the password:
+// the caller stores its hash in PasswordHash and PasswordScheme.
 func NewUser(email, password string) (*User, error) {
-	email = strings.TrimSpace(strings.ToLower(email))
+	email = NormalizeEmail(email)
 
 	if email == "" {
 		return nil, ErrEmailRequired
@@ -39,7 +47,11 @@ func NewUser(email, password string) (*User, error) {
 	return &User{
 		ID:        uuid.NewString(),
 		Email:     email,
-		Password:  password,
 		CreatedAt: time.Now().UTC(),
 	}, nil
 }
+
+// NormalizeEmail returns email the way it is stored.
+func NormalizeEmail(email string) string {
+	return strings.TrimSpace(strings.ToLower(email))
+}
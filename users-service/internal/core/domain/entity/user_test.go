@@ -21,6 +21,9 @@ func TestNewUser_Success(t *testing.T) {
 	if user.CreatedAt.IsZero() {
 		t.Fatal("expected non-zero CreatedAt")
 	}
+	if user.PasswordHash != "" || user.PasswordScheme != "" {
+		t.Fatal("expected the password to be left for the caller to hash")
+	}
 }
 
 func TestNewUser_NormalizesEmail(t *testing.T) {
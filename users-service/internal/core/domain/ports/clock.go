@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time and waits, so pacing can be tested without sleeping.
+type Clock interface {
+	Now() time.Time
+	// Sleep waits for d, or returns ctx's error once it is done.
+	Sleep(ctx context.Context, d time.Duration) error
+}
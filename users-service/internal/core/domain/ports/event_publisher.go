@@ -2,10 +2,15 @@ package ports
 
 import (
 	"context"
+	"errors"
 
 	"users-service/internal/core/domain/entity"
 )
 
+// ErrUnroutable is returned by a publisher when the broker handed the event
+// back because no queue was bound to take it.
+var ErrUnroutable = errors.New("event returned unroutable")
+
 type EventPublisher interface {
 	Publish(ctx context.Context, event *entity.Outbox) error
 }
@@ -0,0 +1,11 @@
+package ports
+
+// PasswordHasher turns passwords into the hashes stored with each user.
+type PasswordHasher interface {
+	// Scheme names the hashes Hash returns, stored next to each of them.
+	Scheme() string
+	Hash(password string) (string, error)
+	// Verify reports whether password matches hash, and whether hash was
+	// made with other parameters than Hash uses now and should be redone.
+	Verify(hash, password string) (match, stale bool, err error)
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"users-service/internal/core/domain/entity"
+)
+
+// ReconciliationRepository finds the rows that break the outbox pattern.
+// Each method returns the total count and at most limit rows, oldest first.
+type ReconciliationRepository interface {
+	// FindMissingEvents returns users without any outbox event.
+	FindMissingEvents(ctx context.Context, limit int) (entity.DiscrepancySample, error)
+	// FindOrphanEvents returns outbox events whose user does not exist.
+	FindOrphanEvents(ctx context.Context, limit int) (entity.DiscrepancySample, error)
+	// FindStuckEvents returns events in status created before the cutoff.
+	FindStuckEvents(ctx context.Context, status entity.OutboxStatus, before time.Time, limit int) (entity.DiscrepancySample, error)
+}
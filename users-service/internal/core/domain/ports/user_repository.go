@@ -8,4 +8,9 @@ import (
 
 type UserRepository interface {
 	Insert(ctx context.Context, user *entity.User, outbox *entity.Outbox) error
+	// FindByEmail returns nil when no user has the email.
+	FindByEmail(ctx context.Context, email string) (*entity.User, error)
+	// UpdatePassword stores a new hash of the user's password. It leaves the
+	// row alone when its password changed since user was read.
+	UpdatePassword(ctx context.Context, user *entity.User, hash, scheme string) error
 }
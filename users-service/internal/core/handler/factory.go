@@ -3,5 +3,5 @@ package handler
 import "users-service/internal/core/usecase"
 
 func NewHandlerFactory(f *usecase.Factory) *Handler {
-	return NewHandler(f.Create)
+	return NewHandler(f.Create, f.Authenticate)
 }
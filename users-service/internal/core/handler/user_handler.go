@@ -11,7 +11,8 @@ import (
 type (
 	// Handler serves the user HTTP endpoints.
 	Handler struct {
-		create *usecase.CreateUserUseCase
+		create       *usecase.CreateUserUseCase
+		authenticate *usecase.AuthenticateUserUseCase
 		Base
 	}
 
@@ -20,14 +21,21 @@ type (
 		Email    string `json:"email"    example:"user@example.com"`
 		Password string `json:"password" example:"s3cur3p@ss"`
 	}
+
+	// LoginReq is the request body for checking a user's credentials.
+	LoginReq struct {
+		Email    string `json:"email"    example:"user@example.com"`
+		Password string `json:"password" example:"s3cur3p@ss"`
+	}
 )
 
-func NewHandler(create *usecase.CreateUserUseCase) *Handler {
-	return &Handler{create: create}
+func NewHandler(create *usecase.CreateUserUseCase, authenticate *usecase.AuthenticateUserUseCase) *Handler {
+	return &Handler{create: create, authenticate: authenticate}
 }
 
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /api/v1/users", h.wrap(h.handleCreate))
+	mux.HandleFunc("POST /api/v1/users/login", h.wrap(h.handleLogin))
 }
 
 // handleCreate godoc
@@ -62,6 +70,39 @@ func (h *Handler) handleCreate(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+// handleLogin godoc
+// @Summary      Log in
+// @Description  Checks a user's email and password. A password stored before hashing, or hashed with older costs, is rehashed on success.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        request  body      LoginReq  true  "Credentials"
+// @Success      200      {object}  Response
+// @Failure      400      {object}  ErrResponse
+// @Failure      401      {object}  ErrResponse
+// @Failure      500      {object}  ErrResponse
+// @Router       /api/v1/users/login [post]
+func (h *Handler) handleLogin(w http.ResponseWriter, r *http.Request) error {
+	var req LoginReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.RespondWithError(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return nil
+	}
+
+	out, err := h.authenticate.Execute(r.Context(), usecase.AuthenticateInput{
+		Email:    req.Email,
+		Password: req.Password,
+	})
+	if err != nil {
+		return err
+	}
+
+	h.RespondWithSuccess(w, http.StatusOK, "user authenticated", map[string]string{
+		"id": out.ID,
+	})
+	return nil
+}
+
 func (h *Handler) wrap(fn func(http.ResponseWriter, *http.Request) error) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		err := fn(w, r)
@@ -9,10 +9,12 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"users-service/internal/core/domain/entity"
 	"users-service/internal/core/handler"
+	"users-service/internal/core/password"
 	"users-service/internal/core/usecase"
 )
 
@@ -21,7 +23,9 @@ func testLogger() *slog.Logger {
 }
 
 type stubUserRepository struct {
-	insertFn func(ctx context.Context, user *entity.User, outbox *entity.Outbox) error
+	insertFn         func(ctx context.Context, user *entity.User, outbox *entity.Outbox) error
+	findByEmailFn    func(ctx context.Context, email string) (*entity.User, error)
+	updatePasswordFn func(ctx context.Context, user *entity.User, hash, scheme string) error
 }
 
 func (s *stubUserRepository) Insert(_ context.Context, user *entity.User, outbox *entity.Outbox) error {
@@ -31,8 +35,31 @@ func (s *stubUserRepository) Insert(_ context.Context, user *entity.User, outbox
 	return nil
 }
 
+func (s *stubUserRepository) FindByEmail(_ context.Context, email string) (*entity.User, error) {
+	if s.findByEmailFn != nil {
+		return s.findByEmailFn(context.Background(), email)
+	}
+	return nil, nil
+}
+
+func (s *stubUserRepository) UpdatePassword(_ context.Context, user *entity.User, hash, scheme string) error {
+	if s.updatePasswordFn != nil {
+		return s.updatePasswordFn(context.Background(), user, hash, scheme)
+	}
+	return nil
+}
+
+// testHasher is a real Argon2id hasher with costs low enough for tests.
+func testHasher() *password.Argon2id {
+	h, err := password.NewArgon2id(password.Params{Memory: 64, Iterations: 1, Parallelism: 1})
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
 func newTestHandler(repo *stubUserRepository) *handler.Handler {
-	f := usecase.NewFactory(repo, testLogger())
+	f := usecase.NewFactory(repo, testHasher(), testLogger())
 	return handler.NewHandlerFactory(f)
 }
 
@@ -131,3 +158,106 @@ func TestHandleCreate_ShortPassword_Returns400(t *testing.T) {
 		t.Fatalf("expected 400, got %d", rec.Code)
 	}
 }
+
+func TestHandleCreate_StoresArgon2idHash(t *testing.T) {
+	var captured *entity.User
+	h := newTestHandler(&stubUserRepository{
+		insertFn: func(_ context.Context, user *entity.User, _ *entity.Outbox) error {
+			captured = user
+			return nil
+		},
+	})
+
+	body, _ := json.Marshal(map[string]any{
+		"email":    "user@example.com",
+		"password": "strongpass",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rec.Code)
+	}
+	if captured.PasswordScheme != password.Scheme || !strings.HasPrefix(captured.PasswordHash, "$argon2id$") {
+		t.Fatalf("expected an argon2id hash, got %q (%s)", captured.PasswordHash, captured.PasswordScheme)
+	}
+}
+
+func loginRepo(t *testing.T) *stubUserRepository {
+	t.Helper()
+	hash, err := testHasher().Hash("strongpass")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	return &stubUserRepository{
+		findByEmailFn: func(_ context.Context, email string) (*entity.User, error) {
+			if email != "user@example.com" {
+				return nil, nil
+			}
+			return &entity.User{ID: "user-1", Email: email, PasswordHash: hash, PasswordScheme: password.Scheme}, nil
+		},
+	}
+}
+
+func postLogin(h *handler.Handler, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/login", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandleLogin_Returns200(t *testing.T) {
+	h := newTestHandler(loginRepo(t))
+
+	body, _ := json.Marshal(map[string]any{
+		"email":    "user@example.com",
+		"password": "strongpass",
+	})
+	rec := postLogin(h, body)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp handler.Response
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if data, _ := resp.Data.(map[string]any); data["id"] != "user-1" {
+		t.Fatalf("expected user-1, got %+v", resp.Data)
+	}
+}
+
+func TestHandleLogin_WrongCredentials_Returns401(t *testing.T) {
+	for _, email := range []string{"user@example.com", "nobody@example.com"} {
+		h := newTestHandler(loginRepo(t))
+
+		body, _ := json.Marshal(map[string]any{
+			"email":    email,
+			"password": "wrongpass",
+		})
+		rec := postLogin(h, body)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("%s: expected 401, got %d", email, rec.Code)
+		}
+		if strings.Contains(rec.Body.String(), "wrongpass") {
+			t.Fatalf("%s: expected the password to stay out of the response", email)
+		}
+	}
+}
+
+func TestHandleLogin_InvalidBody_Returns400(t *testing.T) {
+	h := newTestHandler(loginRepo(t))
+
+	if rec := postLogin(h, []byte("not-json")); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	if rec := postLogin(h, []byte(`{"email":"user@example.com"}`)); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without a password, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,106 @@
+package password_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"users-service/internal/core/password"
+)
+
+// cheap keeps the tests fast; production costs come from config.
+var cheap = password.Params{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func newHasher(t *testing.T, p password.Params) *password.Argon2id {
+	t.Helper()
+	h, err := password.NewArgon2id(p)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return h
+}
+
+func TestArgon2id_HashAndVerify(t *testing.T) {
+	h := newHasher(t, cheap)
+
+	hash, err := h.Hash("password123")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") || strings.Contains(hash, "password123") {
+		t.Fatalf("unexpected hash %q", hash)
+	}
+
+	match, stale, err := h.Verify(hash, "password123")
+	if err != nil || !match || stale {
+		t.Fatalf("expected a fresh match, got match=%v stale=%v err=%v", match, stale, err)
+	}
+
+	match, _, err = h.Verify(hash, "password124")
+	if err != nil || match {
+		t.Fatalf("expected no match, got match=%v err=%v", match, err)
+	}
+}
+
+func TestArgon2id_SaltsEveryHash(t *testing.T) {
+	h := newHasher(t, cheap)
+
+	a, _ := h.Hash("password123")
+	b, _ := h.Hash("password123")
+
+	if a == b {
+		t.Fatal("expected two hashes of the same password to differ")
+	}
+}
+
+func TestArgon2id_ReportsHashesOfOtherParamsAsStale(t *testing.T) {
+	old, _ := newHasher(t, cheap).Hash("password123")
+	h := newHasher(t, password.Params{Memory: 128, Iterations: 2, Parallelism: 1})
+
+	match, stale, err := h.Verify(old, "password123")
+
+	if err != nil || !match || !stale {
+		t.Fatalf("expected a stale match, got match=%v stale=%v err=%v", match, stale, err)
+	}
+}
+
+func TestArgon2id_RejectsMalformedHashes(t *testing.T) {
+	h := newHasher(t, cheap)
+	cases := map[string]string{
+		"plaintext":      "password123",
+		"other scheme":   "$2a$10$abcdefghijklmnopqrstuv",
+		"bad version":    "$argon2id$v=x$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5",
+		"bad params":     "$argon2id$v=19$m=64$c2FsdHNhbHQ$a2V5a2V5",
+		"zero threads":   "$argon2id$v=19$m=64,t=1,p=0$c2FsdHNhbHQ$a2V5a2V5",
+		"bad salt":       "$argon2id$v=19$m=64,t=1,p=1$!!$a2V5a2V5",
+		"empty key":      "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$",
+		"missing fields": "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ",
+	}
+	for name, hash := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := h.Verify(hash, "password123")
+			if !errors.Is(err, password.ErrMalformedHash) {
+				t.Fatalf("expected ErrMalformedHash, got %v", err)
+			}
+		})
+	}
+
+	if _, _, err := h.Verify("$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5", "password123"); err == nil {
+		t.Fatal("expected an error for an unsupported version")
+	}
+}
+
+func TestNewArgon2id_InvalidParams(t *testing.T) {
+	cases := map[string]password.Params{
+		"no iterations":  {Memory: 64, Iterations: 0, Parallelism: 1},
+		"no parallelism": {Memory: 64, Iterations: 1, Parallelism: 0},
+		"too little mem": {Memory: 15, Iterations: 1, Parallelism: 2},
+	}
+	for name, p := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := password.NewArgon2id(p); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
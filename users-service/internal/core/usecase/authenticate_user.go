@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+
+	"users-service/internal/core/domain/entity"
+	"users-service/internal/core/domain/ports"
+	apperrors "users-service/internal/core/errors"
+)
+
+type (
+	AuthenticateInput struct {
+		Email    string
+		Password string
+	}
+
+	AuthenticateOutput struct {
+		ID string
+	}
+
+	AuthenticateUserUseCase struct {
+		userRepo ports.UserRepository
+		hasher   ports.PasswordHasher
+		logger   *slog.Logger
+		// decoy is verified when no user has the email, so an unknown email
+		// costs as much as a wrong password and cannot be told apart by time.
+		decoy string
+	}
+)
+
+func NewAuthenticateUserUseCase(userRepo ports.UserRepository, hasher ports.PasswordHasher, logger *slog.Logger) *AuthenticateUserUseCase {
+	decoy, _ := hasher.Hash("decoy password")
+	return &AuthenticateUserUseCase{userRepo: userRepo, hasher: hasher, logger: logger, decoy: decoy}
+}
+
+// Execute checks the user's credentials. A plaintext password left from
+// before hashing, or a hash made with older costs, is rehashed on success.
+func (uc *AuthenticateUserUseCase) Execute(ctx context.Context, input AuthenticateInput) (*AuthenticateOutput, error) {
+	uc.logger.InfoContext(ctx, "authenticate user request")
+
+	email := entity.NormalizeEmail(input.Email)
+	if email == "" || input.Password == "" {
+		uc.logger.WarnContext(ctx, "authenticate validation failed", slog.String("reason", "missing credentials"))
+		return nil, apperrors.BadRequest(apperrors.WithMessage("email and password are required"))
+	}
+
+	user, err := uc.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "find user failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if user == nil {
+		_, _, _ = uc.hasher.Verify(uc.decoy, input.Password)
+		uc.logger.WarnContext(ctx, "authentication failed", slog.String("reason", "unknown email"))
+		return nil, errInvalidCredentials()
+	}
+
+	match, rehash, err := uc.verify(user, input.Password)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "verify password failed",
+			slog.String("user_id", user.ID),
+			slog.String("error", err.Error()),
+		)
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	if !match {
+		uc.logger.WarnContext(ctx, "authentication failed",
+			slog.String("user_id", user.ID),
+			slog.String("reason", "wrong password"),
+		)
+		return nil, errInvalidCredentials()
+	}
+
+	if rehash {
+		uc.rehash(ctx, user, input.Password)
+	}
+
+	uc.logger.InfoContext(ctx, "user authenticated", slog.String("user_id", user.ID))
+
+	return &AuthenticateOutput{ID: user.ID}, nil
+}
+
+// verify reports whether password is the user's, and whether the stored
+// password should be rehashed.
+func (uc *AuthenticateUserUseCase) verify(user *entity.User, password string) (bool, bool, error) {
+	switch user.PasswordScheme {
+	case entity.PasswordSchemePlaintext:
+		match := subtle.ConstantTimeCompare([]byte(user.PasswordHash), []byte(password)) == 1
+		return match, match, nil
+	case uc.hasher.Scheme():
+		return uc.hasher.Verify(user.PasswordHash, password)
+	default:
+		return false, false, fmt.Errorf("unknown password scheme %q", user.PasswordScheme)
+	}
+}
+
+// rehash is best effort: the login already succeeded, and the next one
+// tries again.
+func (uc *AuthenticateUserUseCase) rehash(ctx context.Context, user *entity.User, password string) {
+	hash, err := uc.hasher.Hash(password)
+	if err == nil {
+		err = uc.userRepo.UpdatePassword(ctx, user, hash, uc.hasher.Scheme())
+	}
+	if err != nil {
+		uc.logger.WarnContext(ctx, "rehash password failed",
+			slog.String("user_id", user.ID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	uc.logger.InfoContext(ctx, "password rehashed",
+		slog.String("user_id", user.ID),
+		slog.String("from_scheme", user.PasswordScheme),
+	)
+}
+
+// errInvalidCredentials is the same for an unknown email and a wrong
+// password, so the response does not reveal which emails are registered.
+func errInvalidCredentials() error {
+	return apperrors.Unauthorized(apperrors.WithMessage("invalid email or password"))
+}
@@ -0,0 +1,212 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"users-service/internal/core/domain/entity"
+	"users-service/internal/core/usecase"
+)
+
+type rehashCall struct {
+	user   *entity.User
+	hash   string
+	scheme string
+}
+
+// storedUser returns a repository holding one user, recording rehashes.
+func storedUser(hash, scheme string, rehashes *[]rehashCall) *mockUserRepository {
+	return &mockUserRepository{
+		findByEmailFn: func(_ context.Context, email string) (*entity.User, error) {
+			if email != "test@example.com" {
+				return nil, nil
+			}
+			return &entity.User{ID: "user-1", Email: email, PasswordHash: hash, PasswordScheme: scheme}, nil
+		},
+		updatePasswordFn: func(_ context.Context, user *entity.User, hash, scheme string) error {
+			*rehashes = append(*rehashes, rehashCall{user, hash, scheme})
+			return nil
+		},
+	}
+}
+
+func TestAuthenticateUserUseCase_Success(t *testing.T) {
+	var rehashes []rehashCall
+	uc := usecase.NewAuthenticateUserUseCase(storedUser("hashed:password123", "mock", &rehashes), &mockPasswordHasher{}, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.AuthenticateInput{
+		Email:    "  TEST@example.com ",
+		Password: "password123",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if out.ID != "user-1" {
+		t.Fatalf("expected user-1, got %q", out.ID)
+	}
+	if len(rehashes) != 0 {
+		t.Fatalf("expected a current hash to be kept, got %+v", rehashes)
+	}
+}
+
+func TestAuthenticateUserUseCase_RehashesLegacyPlaintext(t *testing.T) {
+	var rehashes []rehashCall
+	uc := usecase.NewAuthenticateUserUseCase(storedUser("password123", entity.PasswordSchemePlaintext, &rehashes), &mockPasswordHasher{}, testLogger())
+
+	if _, err := uc.Execute(context.Background(), usecase.AuthenticateInput{Email: "test@example.com", Password: "password123"}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if len(rehashes) != 1 {
+		t.Fatalf("expected one rehash, got %d", len(rehashes))
+	}
+	r := rehashes[0]
+	if r.user.ID != "user-1" || r.user.PasswordHash != "password123" || r.hash != "hashed:password123" || r.scheme != "mock" {
+		t.Fatalf("unexpected rehash: %+v", r)
+	}
+}
+
+func TestAuthenticateUserUseCase_RehashesStaleHash(t *testing.T) {
+	var rehashes []rehashCall
+	hasher := &mockPasswordHasher{
+		verifyFn: func(string, string) (bool, bool, error) { return true, true, nil },
+	}
+	uc := usecase.NewAuthenticateUserUseCase(storedUser("hashed-with-old-costs", "mock", &rehashes), hasher, testLogger())
+
+	if _, err := uc.Execute(context.Background(), usecase.AuthenticateInput{Email: "test@example.com", Password: "password123"}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if len(rehashes) != 1 || rehashes[0].hash != "hashed:password123" {
+		t.Fatalf("expected the stale hash to be redone, got %+v", rehashes)
+	}
+}
+
+func TestAuthenticateUserUseCase_RehashFailureStillAuthenticates(t *testing.T) {
+	repo := &mockUserRepository{
+		findByEmailFn: func(_ context.Context, email string) (*entity.User, error) {
+			return &entity.User{ID: "user-1", Email: email, PasswordHash: "password123", PasswordScheme: entity.PasswordSchemePlaintext}, nil
+		},
+		updatePasswordFn: func(context.Context, *entity.User, string, string) error {
+			return errors.New("db error")
+		},
+	}
+	uc := usecase.NewAuthenticateUserUseCase(repo, &mockPasswordHasher{}, testLogger())
+
+	out, err := uc.Execute(context.Background(), usecase.AuthenticateInput{Email: "test@example.com", Password: "password123"})
+
+	if err != nil || out.ID != "user-1" {
+		t.Fatalf("expected user-1 to be authenticated, got %+v, %v", out, err)
+	}
+}
+
+func TestAuthenticateUserUseCase_WrongPassword(t *testing.T) {
+	cases := map[string]*mockUserRepository{
+		"hashed":    storedUser("hashed:password123", "mock", new([]rehashCall)),
+		"plaintext": storedUser("password123", entity.PasswordSchemePlaintext, new([]rehashCall)),
+	}
+	for name, repo := range cases {
+		t.Run(name, func(t *testing.T) {
+			uc := usecase.NewAuthenticateUserUseCase(repo, &mockPasswordHasher{}, testLogger())
+
+			_, err := uc.Execute(context.Background(), usecase.AuthenticateInput{Email: "test@example.com", Password: "password124"})
+
+			exc := assertException(t, err, http.StatusUnauthorized)
+			if exc.Message != "invalid email or password" {
+				t.Fatalf("unexpected message %q", exc.Message)
+			}
+		})
+	}
+}
+
+func TestAuthenticateUserUseCase_UnknownEmailVerifiesDecoy(t *testing.T) {
+	var verified []string
+	hasher := &mockPasswordHasher{
+		verifyFn: func(hash, _ string) (bool, bool, error) {
+			verified = append(verified, hash)
+			return false, false, nil
+		},
+	}
+	uc := usecase.NewAuthenticateUserUseCase(&mockUserRepository{}, hasher, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.AuthenticateInput{Email: "nobody@example.com", Password: "password123"})
+
+	exc := assertException(t, err, http.StatusUnauthorized)
+	if exc.Message != "invalid email or password" {
+		t.Fatalf("expected the wrong-password message, got %q", exc.Message)
+	}
+	if len(verified) != 1 || verified[0] != "hashed:decoy password" {
+		t.Fatalf("expected the decoy hash to be verified once, got %v", verified)
+	}
+}
+
+func TestAuthenticateUserUseCase_MissingCredentials(t *testing.T) {
+	called := false
+	repo := &mockUserRepository{
+		findByEmailFn: func(context.Context, string) (*entity.User, error) {
+			called = true
+			return nil, nil
+		},
+	}
+	uc := usecase.NewAuthenticateUserUseCase(repo, &mockPasswordHasher{}, testLogger())
+
+	for _, in := range []usecase.AuthenticateInput{{Email: "  ", Password: "password123"}, {Email: "test@example.com"}} {
+		_, err := uc.Execute(context.Background(), in)
+		_ = assertException(t, err, http.StatusBadRequest)
+	}
+	if called {
+		t.Fatal("repository should not be called when validation fails")
+	}
+}
+
+func TestAuthenticateUserUseCase_Errors(t *testing.T) {
+	cases := map[string]struct {
+		repo   *mockUserRepository
+		hasher *mockPasswordHasher
+	}{
+		"repository error": {
+			repo: &mockUserRepository{
+				findByEmailFn: func(context.Context, string) (*entity.User, error) { return nil, errors.New("db error") },
+			},
+			hasher: &mockPasswordHasher{},
+		},
+		"unknown scheme": {
+			repo:   storedUser("$2a$10$abc", "bcrypt", new([]rehashCall)),
+			hasher: &mockPasswordHasher{},
+		},
+		"malformed hash": {
+			repo: storedUser("garbage", "mock", new([]rehashCall)),
+			hasher: &mockPasswordHasher{
+				verifyFn: func(string, string) (bool, bool, error) { return false, false, errors.New("malformed hash") },
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			uc := usecase.NewAuthenticateUserUseCase(tc.repo, tc.hasher, testLogger())
+
+			_, err := uc.Execute(context.Background(), usecase.AuthenticateInput{Email: "test@example.com", Password: "password123"})
+
+			_ = assertException(t, err, http.StatusInternalServerError)
+		})
+	}
+}
+
+func TestAuthenticateUserUseCase_NeverLogsThePassword(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	uc := usecase.NewAuthenticateUserUseCase(storedUser("s3cr3t-legacy", entity.PasswordSchemePlaintext, new([]rehashCall)), &mockPasswordHasher{}, logger)
+
+	_, _ = uc.Execute(context.Background(), usecase.AuthenticateInput{Email: "test@example.com", Password: "s3cr3t-legacy"})
+	_, _ = uc.Execute(context.Background(), usecase.AuthenticateInput{Email: "test@example.com", Password: "s3cr3t-wrong"})
+
+	if strings.Contains(logs.String(), "s3cr3t") {
+		t.Fatalf("expected no password in the logs, got:\n%s", logs.String())
+	}
+}
@@ -22,12 +22,13 @@ type (
 
 	CreateUserUseCase struct {
 		userRepo ports.UserRepository
+		hasher   ports.PasswordHasher
 		logger   *slog.Logger
 	}
 )
 
-func NewCreateUserUseCase(userRepo ports.UserRepository, logger *slog.Logger) *CreateUserUseCase {
-	return &CreateUserUseCase{userRepo: userRepo, logger: logger}
+func NewCreateUserUseCase(userRepo ports.UserRepository, hasher ports.PasswordHasher, logger *slog.Logger) *CreateUserUseCase {
+	return &CreateUserUseCase{userRepo: userRepo, hasher: hasher, logger: logger}
 }
 
 func (uc *CreateUserUseCase) Execute(ctx context.Context, input CreateUserInput) (*CreateUserOutput, error) {
@@ -39,7 +40,14 @@ func (uc *CreateUserUseCase) Execute(ctx context.Context, input CreateUserInput)
 		return nil, apperrors.BadRequest(apperrors.WithMessage(err.Error()))
 	}
 
-	outbox := entity.NewOutbox("UserCreated", fmt.Sprintf(`{"userId":%q}`, user.ID))
+	hash, err := uc.hasher.Hash(input.Password)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "hash password failed", slog.String("error", err.Error()))
+		return nil, apperrors.Unexpected(apperrors.WithError(err))
+	}
+	user.PasswordHash, user.PasswordScheme = hash, uc.hasher.Scheme()
+
+	outbox := entity.NewOutbox("UserCreated", user.ID, fmt.Sprintf(`{"userId":%q}`, user.ID))
 
 	if err := uc.userRepo.Insert(ctx, user, outbox); err != nil {
 		uc.logger.ErrorContext(ctx, "persist user failed", slog.String("error", err.Error()))
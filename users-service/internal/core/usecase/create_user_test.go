@@ -6,6 +6,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 	"testing"
 
 	"users-service/internal/core/domain/entity"
@@ -18,7 +19,9 @@ func testLogger() *slog.Logger {
 }
 
 type mockUserRepository struct {
-	insertFn func(ctx context.Context, user *entity.User, outbox *entity.Outbox) error
+	insertFn         func(ctx context.Context, user *entity.User, outbox *entity.Outbox) error
+	findByEmailFn    func(ctx context.Context, email string) (*entity.User, error)
+	updatePasswordFn func(ctx context.Context, user *entity.User, hash, scheme string) error
 }
 
 func (m *mockUserRepository) Insert(_ context.Context, user *entity.User, outbox *entity.Outbox) error {
@@ -28,6 +31,44 @@ func (m *mockUserRepository) Insert(_ context.Context, user *entity.User, outbox
 	return nil
 }
 
+func (m *mockUserRepository) FindByEmail(_ context.Context, email string) (*entity.User, error) {
+	if m.findByEmailFn != nil {
+		return m.findByEmailFn(context.Background(), email)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepository) UpdatePassword(_ context.Context, user *entity.User, hash, scheme string) error {
+	if m.updatePasswordFn != nil {
+		return m.updatePasswordFn(context.Background(), user, hash, scheme)
+	}
+	return nil
+}
+
+// mockPasswordHasher hashes to "hashed:<password>" unless overridden.
+type mockPasswordHasher struct {
+	hashFn   func(password string) (string, error)
+	verifyFn func(hash, password string) (bool, bool, error)
+}
+
+func (m *mockPasswordHasher) Scheme() string {
+	return "mock"
+}
+
+func (m *mockPasswordHasher) Hash(password string) (string, error) {
+	if m.hashFn != nil {
+		return m.hashFn(password)
+	}
+	return "hashed:" + password, nil
+}
+
+func (m *mockPasswordHasher) Verify(hash, password string) (bool, bool, error) {
+	if m.verifyFn != nil {
+		return m.verifyFn(hash, password)
+	}
+	return hash == "hashed:"+password, false, nil
+}
+
 func assertException(t *testing.T, err error, expectedCode int) *apperrors.Exception {
 	t.Helper()
 	exc, ok := err.(*apperrors.Exception)
@@ -42,7 +83,7 @@ func assertException(t *testing.T, err error, expectedCode int) *apperrors.Excep
 
 func TestCreateUserUseCase_Success(t *testing.T) {
 	repo := &mockUserRepository{}
-	uc := usecase.NewCreateUserUseCase(repo, testLogger())
+	uc := usecase.NewCreateUserUseCase(repo, &mockPasswordHasher{}, testLogger())
 
 	out, err := uc.Execute(context.Background(), usecase.CreateUserInput{
 		Email:    "test@example.com",
@@ -66,7 +107,7 @@ func TestCreateUserUseCase_SavesOutboxEvent(t *testing.T) {
 			return nil
 		},
 	}
-	uc := usecase.NewCreateUserUseCase(repo, testLogger())
+	uc := usecase.NewCreateUserUseCase(repo, &mockPasswordHasher{}, testLogger())
 
 	_, err := uc.Execute(context.Background(), usecase.CreateUserInput{
 		Email:    "test@example.com",
@@ -87,9 +128,60 @@ func TestCreateUserUseCase_SavesOutboxEvent(t *testing.T) {
 	}
 }
 
+func TestCreateUserUseCase_StoresOnlyThePasswordHash(t *testing.T) {
+	var captured *entity.User
+	var capturedOutbox *entity.Outbox
+	repo := &mockUserRepository{
+		insertFn: func(_ context.Context, user *entity.User, outbox *entity.Outbox) error {
+			captured, capturedOutbox = user, outbox
+			return nil
+		},
+	}
+	uc := usecase.NewCreateUserUseCase(repo, &mockPasswordHasher{}, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.CreateUserInput{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if captured.PasswordHash != "hashed:password123" || captured.PasswordScheme != "mock" {
+		t.Fatalf("expected the hashed password and its scheme, got %q (%s)", captured.PasswordHash, captured.PasswordScheme)
+	}
+	if strings.Contains(capturedOutbox.Payload, "password123") {
+		t.Fatalf("expected the UserCreated payload to leave the password out, got %s", capturedOutbox.Payload)
+	}
+}
+
+func TestCreateUserUseCase_HashError(t *testing.T) {
+	called := false
+	repo := &mockUserRepository{
+		insertFn: func(context.Context, *entity.User, *entity.Outbox) error {
+			called = true
+			return nil
+		},
+	}
+	hasher := &mockPasswordHasher{
+		hashFn: func(string) (string, error) { return "", errors.New("entropy exhausted") },
+	}
+	uc := usecase.NewCreateUserUseCase(repo, hasher, testLogger())
+
+	_, err := uc.Execute(context.Background(), usecase.CreateUserInput{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+
+	_ = assertException(t, err, http.StatusInternalServerError)
+	if called {
+		t.Fatal("repository should not be called when hashing fails")
+	}
+}
+
 func TestCreateUserUseCase_InvalidEmail(t *testing.T) {
 	repo := &mockUserRepository{}
-	uc := usecase.NewCreateUserUseCase(repo, testLogger())
+	uc := usecase.NewCreateUserUseCase(repo, &mockPasswordHasher{}, testLogger())
 
 	_, err := uc.Execute(context.Background(), usecase.CreateUserInput{
 		Email:    "invalid-email",
@@ -101,7 +193,7 @@ func TestCreateUserUseCase_InvalidEmail(t *testing.T) {
 
 func TestCreateUserUseCase_ShortPassword(t *testing.T) {
 	repo := &mockUserRepository{}
-	uc := usecase.NewCreateUserUseCase(repo, testLogger())
+	uc := usecase.NewCreateUserUseCase(repo, &mockPasswordHasher{}, testLogger())
 
 	_, err := uc.Execute(context.Background(), usecase.CreateUserInput{
 		Email:    "test@example.com",
@@ -117,7 +209,7 @@ func TestCreateUserUseCase_RepositoryError(t *testing.T) {
 			return errors.New("database unavailable")
 		},
 	}
-	uc := usecase.NewCreateUserUseCase(repo, testLogger())
+	uc := usecase.NewCreateUserUseCase(repo, &mockPasswordHasher{}, testLogger())
 
 	_, err := uc.Execute(context.Background(), usecase.CreateUserInput{
 		Email:    "test@example.com",
@@ -135,7 +227,7 @@ func TestCreateUserUseCase_DoesNotCallRepositoryOnValidationError(t *testing.T)
 			return nil
 		},
 	}
-	uc := usecase.NewCreateUserUseCase(repo, testLogger())
+	uc := usecase.NewCreateUserUseCase(repo, &mockPasswordHasher{}, testLogger())
 
 	_, _ = uc.Execute(context.Background(), usecase.CreateUserInput{
 		Email:    "",
@@ -149,9 +241,12 @@ func TestCreateUserUseCase_DoesNotCallRepositoryOnValidationError(t *testing.T)
 
 func TestNewFactory_WiresAllUseCases(t *testing.T) {
 	repo := &mockUserRepository{}
-	f := usecase.NewFactory(repo, testLogger())
+	f := usecase.NewFactory(repo, &mockPasswordHasher{}, testLogger())
 
 	if f.Create == nil {
 		t.Fatal("expected Create use case to be non-nil")
 	}
+	if f.Authenticate == nil {
+		t.Fatal("expected Authenticate use case to be non-nil")
+	}
 }
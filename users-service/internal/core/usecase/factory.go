@@ -7,11 +7,13 @@ import (
 )
 
 type Factory struct {
-	Create *CreateUserUseCase
+	Create       *CreateUserUseCase
+	Authenticate *AuthenticateUserUseCase
 }
 
-func NewFactory(repo ports.UserRepository, logger *slog.Logger) *Factory {
+func NewFactory(repo ports.UserRepository, hasher ports.PasswordHasher, logger *slog.Logger) *Factory {
 	return &Factory{
-		Create: NewCreateUserUseCase(repo, logger),
+		Create:       NewCreateUserUseCase(repo, hasher, logger),
+		Authenticate: NewAuthenticateUserUseCase(repo, hasher, logger),
 	}
 }
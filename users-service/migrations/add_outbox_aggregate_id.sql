@@ -0,0 +1,14 @@
+ALTER TABLE outbox
+    ADD COLUMN IF NOT EXISTS aggregate_id VARCHAR(255) NULL;
+
+-- Events written before the column existed carry the aggregate in the payload.
+UPDATE outbox
+SET aggregate_id = payload::jsonb ->> 'userId'
+WHERE aggregate_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_aggregate_id
+    ON outbox (aggregate_id);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_replay
+    ON outbox (type, created_at)
+    WHERE status = 'PROCESSED';
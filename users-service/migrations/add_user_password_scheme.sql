@@ -0,0 +1,11 @@
+-- Passwords are stored hashed, and password_scheme names the hash of each
+-- row. Rows written before keep their plaintext password and are flagged
+-- 'plaintext' until their owner's next successful login rehashes them. The
+-- default keeps flagging rows written by an older release during a rollout.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS password_scheme VARCHAR(20) NOT NULL DEFAULT 'plaintext';
+
+-- Finds the rows still waiting to be rehashed.
+CREATE INDEX IF NOT EXISTS idx_users_password_plaintext
+    ON users (created_at)
+    WHERE password_scheme = 'plaintext';
@@ -4,6 +4,7 @@ package e2e_test
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -13,16 +14,24 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 
 	"users-service/infra/repository"
+	"users-service/internal/core/domain/entity"
 	"users-service/internal/core/handler"
+	"users-service/internal/core/password"
 	"users-service/internal/core/usecase"
 )
 
-var testServer *httptest.Server
+var (
+	testServer *httptest.Server
+	testDB     *sql.DB
+)
 
 func TestMain(m *testing.M) {
 	db, err := connectDB()
@@ -31,6 +40,7 @@ func TestMain(m *testing.M) {
 		os.Exit(1)
 	}
 	defer db.Close()
+	testDB = db
 
 	if err := runMigrations(db); err != nil {
 		fmt.Fprintf(os.Stderr, "e2e: run migrations: %v\n", err)
@@ -39,7 +49,12 @@ func TestMain(m *testing.M) {
 
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	repo := repository.NewUserRepository(db)
-	f := usecase.NewFactory(repo, logger)
+	hasher, err := password.NewArgon2id(password.Params{Memory: 64, Iterations: 1, Parallelism: 1})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: password hasher: %v\n", err)
+		os.Exit(1)
+	}
+	f := usecase.NewFactory(repo, hasher, logger)
 	h := handler.NewHandlerFactory(f)
 
 	mux := http.NewServeMux()
@@ -77,6 +92,8 @@ var migrationOrder = []string{
 	"create_user_table.sql",
 	"create_outbox_table.sql",
 	"add_outbox_retry.sql",
+	"add_outbox_aggregate_id.sql",
+	"add_user_password_scheme.sql",
 }
 
 func runMigrations(db *sql.DB) error {
@@ -211,3 +228,141 @@ func TestE2E_CreateUser_PersistsInDatabase(t *testing.T) {
 		t.Fatalf("expected 500 on duplicate email, got %d", resp2.StatusCode)
 	}
 }
+
+func reconcile(t *testing.T) *entity.ReconciliationReport {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	uc := usecase.NewReconcileOutboxUseCase(repository.NewReconciliationRepository(testDB), logger)
+	report, err := uc.Execute(context.Background(), usecase.ReconcileInput{})
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	return report
+}
+
+func TestE2E_Reconcile_CountsBrokenOutboxRows(t *testing.T) {
+	before := reconcile(t)
+
+	resp := doPost(t, "/api/v1/users", map[string]any{
+		"email":    "e2e-reconcile-" + uuid.NewString() + "@example.com",
+		"password": "reconcilepassword123",
+	})
+	userID, _ := decodeResponse(t, resp).Data["id"].(string)
+	if resp.StatusCode != http.StatusCreated || userID == "" {
+		t.Fatalf("expected a created user, got %d", resp.StatusCode)
+	}
+
+	if _, err := testDB.Exec(`
+		INSERT INTO users (id, email, password) VALUES ($1, $2, 'not-a-hash')
+	`, uuid.NewString(), "e2e-silent-"+uuid.NewString()+"@example.com"); err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	events := []struct {
+		aggregateID, status string
+		age                 time.Duration
+	}{
+		{uuid.NewString(), "PROCESSED", 0},
+		{userID, "PENDING", time.Hour},
+	}
+	for _, e := range events {
+		if _, err := testDB.Exec(`
+			INSERT INTO outbox (id, type, aggregate_id, payload, status, created_at)
+			VALUES ($1, 'UserCreated', $2, '{}', $3, $4)
+		`, uuid.NewString(), e.aggregateID, e.status, time.Now().UTC().Add(-e.age)); err != nil {
+			t.Fatalf("insert outbox event: %v", err)
+		}
+	}
+
+	after := reconcile(t)
+	want := map[string]int64{
+		entity.DiscrepancyMissingEvent: 1,
+		entity.DiscrepancyOrphanEvent:  1,
+		entity.DiscrepancyStuckPending: 1,
+	}
+	for kind, n := range want {
+		if got := after.Counts[kind] - before.Counts[kind]; got != n {
+			t.Fatalf("%s: expected %d new discrepancies, got %d", kind, n, got)
+		}
+	}
+}
+
+func storedPassword(t *testing.T, email string) (hash, scheme string) {
+	t.Helper()
+	if err := testDB.QueryRow(`SELECT password, password_scheme FROM users WHERE email = $1`, email).Scan(&hash, &scheme); err != nil {
+		t.Fatalf("read password: %v", err)
+	}
+	return hash, scheme
+}
+
+func login(t *testing.T, email, pass string) int {
+	t.Helper()
+	resp := doPost(t, "/api/v1/users/login", map[string]any{"email": email, "password": pass})
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestE2E_Login_StoresHashAndAuthenticates(t *testing.T) {
+	email := "e2e-login-" + uuid.NewString() + "@example.com"
+	resp := doPost(t, "/api/v1/users", map[string]any{"email": email, "password": "loginpassword123"})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+
+	hash, scheme := storedPassword(t, email)
+	if scheme != password.Scheme || !strings.HasPrefix(hash, "$argon2id$") || strings.Contains(hash, "loginpassword123") {
+		t.Fatalf("expected an argon2id hash, got %q (%s)", hash, scheme)
+	}
+
+	var payload string
+	if err := testDB.QueryRow(`
+		SELECT o.payload FROM outbox o JOIN users u ON u.id::text = o.aggregate_id WHERE u.email = $1
+	`, email).Scan(&payload); err != nil {
+		t.Fatalf("read outbox payload: %v", err)
+	}
+	if strings.Contains(payload, "loginpassword123") || strings.Contains(payload, hash) {
+		t.Fatalf("expected the UserCreated payload to leave the password out, got %s", payload)
+	}
+
+	if code := login(t, strings.ToUpper(email), "loginpassword123"); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if code := login(t, email, "wrongpassword123"); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 on a wrong password, got %d", code)
+	}
+	if code := login(t, "e2e-nobody-"+uuid.NewString()+"@example.com", "loginpassword123"); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 on an unknown email, got %d", code)
+	}
+}
+
+func TestE2E_Login_RehashesLegacyPlaintext(t *testing.T) {
+	email := "e2e-legacy-" + uuid.NewString() + "@example.com"
+	// A row written before passwords were hashed: the migration flags it.
+	if _, err := testDB.Exec(`
+		INSERT INTO users (id, email, password) VALUES ($1, $2, 'legacypassword123')
+	`, uuid.NewString(), email); err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	if _, scheme := storedPassword(t, email); scheme != "plaintext" {
+		t.Fatalf("expected a legacy row to be flagged plaintext, got %s", scheme)
+	}
+
+	if code := login(t, email, "wrongpassword123"); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 on a wrong password, got %d", code)
+	}
+	if _, scheme := storedPassword(t, email); scheme != "plaintext" {
+		t.Fatalf("expected a failed login to leave the row alone, got %s", scheme)
+	}
+
+	if code := login(t, email, "legacypassword123"); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	hash, scheme := storedPassword(t, email)
+	if scheme != password.Scheme || !strings.HasPrefix(hash, "$argon2id$") {
+		t.Fatalf("expected the password to be rehashed, got %q (%s)", hash, scheme)
+	}
+
+	if code := login(t, email, "legacypassword123"); code != http.StatusOK {
+		t.Fatalf("expected 200 against the new hash, got %d", code)
+	}
+}